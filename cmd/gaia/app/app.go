@@ -125,10 +125,6 @@ func NewGaiaApp(logger log.Logger, db dbm.DB, traceStore io.Writer, loadLatest b
 		app.bankKeeper, app.paramsKeeper.Subspace(staking.DefaultParamspace),
 		staking.DefaultCodespace,
 	)
-	app.mintKeeper = mint.NewKeeper(app.cdc, app.keyMint,
-		app.paramsKeeper.Subspace(mint.DefaultParamspace),
-		&stakingKeeper, app.feeCollectionKeeper,
-	)
 	app.distrKeeper = distr.NewKeeper(
 		app.cdc,
 		app.keyDistr,
@@ -136,16 +132,33 @@ func NewGaiaApp(logger log.Logger, db dbm.DB, traceStore io.Writer, loadLatest b
 		app.bankKeeper, &stakingKeeper, app.feeCollectionKeeper,
 		distr.DefaultCodespace,
 	)
+	app.mintKeeper = mint.NewKeeper(app.cdc, app.keyMint,
+		app.paramsKeeper.Subspace(mint.DefaultParamspace),
+		&stakingKeeper, app.feeCollectionKeeper, app.distrKeeper,
+	)
 	app.slashingKeeper = slashing.NewKeeper(
 		app.cdc,
 		app.keySlashing,
 		&stakingKeeper, app.paramsKeeper.Subspace(slashing.DefaultParamspace),
 		slashing.DefaultCodespace,
 	)
+	app.mintKeeper.SetSlashRiskEstimator(func(ctx sdk.Context, valAddr sdk.ValAddress) (sdk.Dec, int64) {
+		return app.slashingKeeper.GetDowntimeSlashRisk(ctx, valAddr), app.slashingKeeper.SignedBlocksWindow(ctx)
+	})
 
+	// register the staking hooks
+	// NOTE: The stakingKeeper above is passed by reference, so that it can be
+	// modified like below:
+	app.stakingKeeper = *stakingKeeper.SetHooks(
+		NewStakingHooks(app.distrKeeper.Hooks(), app.slashingKeeper.Hooks(), app.mintKeeper.Hooks()),
+	).SetInflationEstimator(app.mintKeeper.EstimateNextInflation)
+
+	// NOTE: the staking proposal handler must be given the keeper after its
+	// hooks have been set
 	govRouter := gov.NewRouter()
 	govRouter.AddRoute(gov.RouterKey, gov.ProposalHandler).
-		AddRoute(params.RouterKey, params.NewParamChangeProposalHandler(app.paramsKeeper))
+		AddRoute(params.RouterKey, params.NewParamChangeProposalHandler(app.paramsKeeper)).
+		AddRoute(staking.RouterKey, staking.NewProposalHandler(app.stakingKeeper))
 
 	app.govKeeper = gov.NewKeeper(
 		app.cdc,
@@ -161,13 +174,6 @@ func NewGaiaApp(logger log.Logger, db dbm.DB, traceStore io.Writer, loadLatest b
 		app.feeCollectionKeeper,
 	)
 
-	// register the staking hooks
-	// NOTE: The stakingKeeper above is passed by reference, so that it can be
-	// modified like below:
-	app.stakingKeeper = *stakingKeeper.SetHooks(
-		NewStakingHooks(app.distrKeeper.Hooks(), app.slashingKeeper.Hooks()),
-	)
-
 	// register the crisis routes
 	bank.RegisterInvariants(&app.crisisKeeper, app.accountKeeper)
 	distr.RegisterInvariants(&app.crisisKeeper, app.distrKeeper, app.stakingKeeper)
@@ -187,7 +193,8 @@ func NewGaiaApp(logger log.Logger, db dbm.DB, traceStore io.Writer, loadLatest b
 		AddRoute(distr.QuerierRoute, distr.NewQuerier(app.distrKeeper)).
 		AddRoute(gov.QuerierRoute, gov.NewQuerier(app.govKeeper)).
 		AddRoute(slashing.QuerierRoute, slashing.NewQuerier(app.slashingKeeper, app.cdc)).
-		AddRoute(staking.QuerierRoute, staking.NewQuerier(app.stakingKeeper, app.cdc)).
+		AddRoute(staking.QuerierRoute, staking.NewQuerier(app.stakingKeeper, app.cdc, staking.InvariantRoutes(
+			app.stakingKeeper, app.feeCollectionKeeper, app.distrKeeper, app.accountKeeper))).
 		AddRoute(mint.QuerierRoute, mint.NewQuerier(app.mintKeeper))
 
 	// initialize BaseApp
@@ -250,6 +257,7 @@ func (app *GaiaApp) BeginBlocker(ctx sdk.Context, req abci.RequestBeginBlock) ab
 // nolint: unparam
 func (app *GaiaApp) EndBlocker(ctx sdk.Context, req abci.RequestEndBlock) abci.ResponseEndBlock {
 	tags := gov.EndBlocker(ctx, app.govKeeper)
+	distr.EndBlocker(ctx, app.distrKeeper)
 	validatorUpdates, endBlockerTags := staking.EndBlocker(ctx, app.stakingKeeper)
 	tags = append(tags, endBlockerTags...)
 
@@ -274,8 +282,9 @@ func (app *GaiaApp) initFromGenesisState(ctx sdk.Context, genesisState GenesisSt
 		app.accountKeeper.SetAccount(ctx, acc)
 	}
 
-	// initialize distribution (must happen before staking)
+	// initialize distribution and mint (must happen before staking)
 	distr.InitGenesis(ctx, app.distrKeeper, genesisState.DistrData)
+	mint.InitGenesis(ctx, app.mintKeeper, genesisState.MintData)
 
 	// load the initial staking information
 	validators, err := staking.InitGenesis(ctx, app.stakingKeeper, genesisState.StakingData)
@@ -289,7 +298,6 @@ func (app *GaiaApp) initFromGenesisState(ctx sdk.Context, genesisState GenesisSt
 	slashing.InitGenesis(ctx, app.slashingKeeper, genesisState.SlashingData, genesisState.StakingData.Validators.ToSDKValidators())
 	gov.InitGenesis(ctx, app.govKeeper, genesisState.GovData)
 	crisis.InitGenesis(ctx, app.crisisKeeper, genesisState.CrisisData)
-	mint.InitGenesis(ctx, app.mintKeeper, genesisState.MintData)
 
 	// validate genesis state
 	if err := GaiaValidateGenesisState(genesisState); err != nil {
@@ -361,55 +369,66 @@ func (app *GaiaApp) LoadHeight(height int64) error {
 
 var _ sdk.StakingHooks = StakingHooks{}
 
-// StakingHooks contains combined distribution and slashing hooks needed for the
-// staking module.
+// StakingHooks contains combined distribution, slashing and mint hooks needed
+// for the staking module.
 type StakingHooks struct {
 	dh distr.Hooks
 	sh slashing.Hooks
+	mh mint.Hooks
 }
 
-func NewStakingHooks(dh distr.Hooks, sh slashing.Hooks) StakingHooks {
-	return StakingHooks{dh, sh}
+func NewStakingHooks(dh distr.Hooks, sh slashing.Hooks, mh mint.Hooks) StakingHooks {
+	return StakingHooks{dh, sh, mh}
 }
 
 // nolint
 func (h StakingHooks) AfterValidatorCreated(ctx sdk.Context, valAddr sdk.ValAddress) {
 	h.dh.AfterValidatorCreated(ctx, valAddr)
 	h.sh.AfterValidatorCreated(ctx, valAddr)
+	h.mh.AfterValidatorCreated(ctx, valAddr)
 }
 func (h StakingHooks) BeforeValidatorModified(ctx sdk.Context, valAddr sdk.ValAddress) {
 	h.dh.BeforeValidatorModified(ctx, valAddr)
 	h.sh.BeforeValidatorModified(ctx, valAddr)
+	h.mh.BeforeValidatorModified(ctx, valAddr)
 }
 func (h StakingHooks) AfterValidatorRemoved(ctx sdk.Context, consAddr sdk.ConsAddress, valAddr sdk.ValAddress) {
 	h.dh.AfterValidatorRemoved(ctx, consAddr, valAddr)
 	h.sh.AfterValidatorRemoved(ctx, consAddr, valAddr)
+	h.mh.AfterValidatorRemoved(ctx, consAddr, valAddr)
 }
 func (h StakingHooks) AfterValidatorBonded(ctx sdk.Context, consAddr sdk.ConsAddress, valAddr sdk.ValAddress) {
 	h.dh.AfterValidatorBonded(ctx, consAddr, valAddr)
 	h.sh.AfterValidatorBonded(ctx, consAddr, valAddr)
+	h.mh.AfterValidatorBonded(ctx, consAddr, valAddr)
 }
 func (h StakingHooks) AfterValidatorBeginUnbonding(ctx sdk.Context, consAddr sdk.ConsAddress, valAddr sdk.ValAddress) {
 	h.dh.AfterValidatorBeginUnbonding(ctx, consAddr, valAddr)
 	h.sh.AfterValidatorBeginUnbonding(ctx, consAddr, valAddr)
+	h.mh.AfterValidatorBeginUnbonding(ctx, consAddr, valAddr)
 }
 func (h StakingHooks) BeforeDelegationCreated(ctx sdk.Context, delAddr sdk.AccAddress, valAddr sdk.ValAddress) {
 	h.dh.BeforeDelegationCreated(ctx, delAddr, valAddr)
 	h.sh.BeforeDelegationCreated(ctx, delAddr, valAddr)
+	h.mh.BeforeDelegationCreated(ctx, delAddr, valAddr)
 }
 func (h StakingHooks) BeforeDelegationSharesModified(ctx sdk.Context, delAddr sdk.AccAddress, valAddr sdk.ValAddress) {
 	h.dh.BeforeDelegationSharesModified(ctx, delAddr, valAddr)
 	h.sh.BeforeDelegationSharesModified(ctx, delAddr, valAddr)
+	h.mh.BeforeDelegationSharesModified(ctx, delAddr, valAddr)
 }
 func (h StakingHooks) BeforeDelegationRemoved(ctx sdk.Context, delAddr sdk.AccAddress, valAddr sdk.ValAddress) {
 	h.dh.BeforeDelegationRemoved(ctx, delAddr, valAddr)
 	h.sh.BeforeDelegationRemoved(ctx, delAddr, valAddr)
+	h.mh.BeforeDelegationRemoved(ctx, delAddr, valAddr)
 }
 func (h StakingHooks) AfterDelegationModified(ctx sdk.Context, delAddr sdk.AccAddress, valAddr sdk.ValAddress) {
 	h.dh.AfterDelegationModified(ctx, delAddr, valAddr)
 	h.sh.AfterDelegationModified(ctx, delAddr, valAddr)
+	h.mh.AfterDelegationModified(ctx, delAddr, valAddr)
 }
 func (h StakingHooks) BeforeValidatorSlashed(ctx sdk.Context, valAddr sdk.ValAddress, fraction sdk.Dec) {
 	h.dh.BeforeValidatorSlashed(ctx, valAddr, fraction)
 	h.sh.BeforeValidatorSlashed(ctx, valAddr, fraction)
+	h.mh.BeforeValidatorSlashed(ctx, valAddr, fraction)
 }
@@ -8,10 +8,12 @@ import (
 	"github.com/cosmos/cosmos-sdk/x/crisis"
 
 	"github.com/stretchr/testify/require"
+	"github.com/tendermint/tendermint/crypto/ed25519"
 	"github.com/tendermint/tendermint/libs/db"
 	"github.com/tendermint/tendermint/libs/log"
 
 	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/x/auth"
 	distr "github.com/cosmos/cosmos-sdk/x/distribution"
 	"github.com/cosmos/cosmos-sdk/x/gov"
@@ -63,3 +65,72 @@ func TestGaiadExport(t *testing.T) {
 	_, _, err := newGapp.ExportAppStateAndValidators(false, []string{})
 	require.NoError(t, err, "ExportAppStateAndValidators should not have an error")
 }
+
+// Migrating a validator's owner runs the removal and creation hooks of the
+// distribution and slashing modules back to back, so check them together.
+func TestMigrateValidatorOwnerHooks(t *testing.T) {
+	gapp := NewGaiaApp(log.NewNopLogger(), db.NewMemDB(), nil, true, 0)
+	setGenesis(gapp)
+
+	header := abci.Header{Height: gapp.LastBlockHeight() + 1}
+	gapp.BeginBlock(abci.RequestBeginBlock{Header: header})
+	ctx := gapp.NewContext(false, header)
+
+	consPk := ed25519.GenPrivKey().PubKey()
+	oldOwner := sdk.ValAddress(ed25519.GenPrivKey().PubKey().Address())
+	newOwner := sdk.ValAddress(ed25519.GenPrivKey().PubKey().Address())
+	bondAmt := sdk.TokensFromTendermintPower(10)
+
+	pool := gapp.stakingKeeper.GetPool(ctx)
+	_, err := gapp.bankKeeper.AddCoins(ctx, sdk.AccAddress(oldOwner), sdk.Coins{sdk.NewCoin(sdk.DefaultBondDenom, bondAmt)})
+	require.NoError(t, err)
+	pool.NotBondedTokens = pool.NotBondedTokens.Add(bondAmt)
+	gapp.stakingKeeper.SetPool(ctx, pool)
+
+	handler := staking.NewHandler(gapp.stakingKeeper)
+	msgCreateValidator := staking.NewTestMsgCreateValidatorWithCommission(oldOwner, consPk, bondAmt, sdk.NewDecWithPrec(1, 1))
+	got := handler(ctx, msgCreateValidator)
+	require.True(t, got.IsOK(), "expected no error, %v", got)
+	staking.EndBlocker(ctx, gapp.stakingKeeper)
+
+	// accrue rewards and commission, and pay out the self-delegation rewards
+	ctx = ctx.WithBlockHeight(ctx.BlockHeight() + 1)
+	tokens := sdk.DecCoins{sdk.NewDecCoinFromDec(sdk.DefaultBondDenom, sdk.NewDec(100))}
+	gapp.distrKeeper.AllocateTokensToValidator(ctx, gapp.stakingKeeper.Validator(ctx, oldOwner), tokens)
+	rewards, err := gapp.distrKeeper.WithdrawDelegationRewards(ctx, sdk.AccAddress(oldOwner), oldOwner)
+	require.NoError(t, err)
+	require.Equal(t, sdk.Coins{sdk.NewInt64Coin(sdk.DefaultBondDenom, 90)}, rewards)
+	commission := gapp.distrKeeper.GetValidatorAccumulatedCommission(ctx, oldOwner)
+	require.Equal(t, sdk.DecCoins{sdk.NewDecCoinFromDec(sdk.DefaultBondDenom, sdk.NewDec(10))}, commission)
+	balance := gapp.accountKeeper.GetAccount(ctx, sdk.AccAddress(oldOwner)).GetCoins()
+
+	got = handler(ctx, staking.NewMsgMigrateValidatorOwner(oldOwner, newOwner))
+	require.True(t, got.IsOK(), "expected no error, %v", got)
+
+	// the accumulated commission is paid to the old owner
+	paid := gapp.accountKeeper.GetAccount(ctx, sdk.AccAddress(oldOwner)).GetCoins().Sub(balance)
+	require.Equal(t, sdk.Coins{sdk.NewInt64Coin(sdk.DefaultBondDenom, 10)}, paid)
+
+	// the consensus key still resolves, so signatures are handled as before
+	votes := []abci.VoteInfo{{
+		Validator:       abci.Validator{Address: consPk.Address(), Power: 10},
+		SignedLastBlock: true,
+	}}
+	require.NotPanics(t, func() {
+		slashing.BeginBlocker(ctx, abci.RequestBeginBlock{LastCommitInfo: abci.LastCommitInfo{Votes: votes}}, gapp.slashingKeeper)
+	})
+
+	// the new owner starts without rewards or commission
+	require.True(t, gapp.distrKeeper.GetValidatorAccumulatedCommission(ctx, newOwner).IsZero())
+	require.True(t, gapp.distrKeeper.GetValidatorOutstandingRewards(ctx, newOwner).IsZero())
+	rewards, err = gapp.distrKeeper.WithdrawDelegationRewards(ctx, sdk.AccAddress(newOwner), newOwner)
+	require.NoError(t, err)
+	require.True(t, rewards.IsZero())
+
+	// and earns what is allocated from then on
+	ctx = ctx.WithBlockHeight(ctx.BlockHeight() + 1)
+	gapp.distrKeeper.AllocateTokensToValidator(ctx, gapp.stakingKeeper.Validator(ctx, newOwner), tokens)
+	rewards, err = gapp.distrKeeper.WithdrawDelegationRewards(ctx, sdk.AccAddress(newOwner), newOwner)
+	require.NoError(t, err)
+	require.Equal(t, sdk.Coins{sdk.NewInt64Coin(sdk.DefaultBondDenom, 90)}, rewards)
+}
@@ -84,7 +84,7 @@ func (app *GaiaApp) prepForZeroHeightGenesis(ctx sdk.Context, jailWhiteList []st
 
 	// withdraw all validator commission
 	app.stakingKeeper.IterateValidators(ctx, func(_ int64, val sdk.Validator) (stop bool) {
-		_, _ = app.distrKeeper.WithdrawValidatorCommission(ctx, val.GetOperator())
+		_, _ = app.distrKeeper.WithdrawValidatorCommission(ctx, val.GetOperator(), nil)
 		return false
 	})
 
@@ -160,6 +160,9 @@ func (app *GaiaApp) prepForZeroHeightGenesis(ctx sdk.Context, jailWhiteList []st
 			panic("expected validator, not found")
 		}
 
+		// the bond height is part of the power index key, so re-index
+		app.stakingKeeper.DeleteValidatorByPowerIndex(ctx, validator)
+		validator.BondHeight = 0
 		validator.UnbondingHeight = 0
 		valConsAddrs = append(valConsAddrs, validator.ConsAddress())
 		if applyWhiteList && !whiteListMap[addr.String()] {
@@ -167,6 +170,7 @@ func (app *GaiaApp) prepForZeroHeightGenesis(ctx sdk.Context, jailWhiteList []st
 		}
 
 		app.stakingKeeper.SetValidator(ctx, validator)
+		app.stakingKeeper.SetValidatorByPowerIndex(ctx, validator)
 		counter++
 	}
 
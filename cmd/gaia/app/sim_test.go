@@ -206,6 +206,7 @@ func appStateRandomizedFn(r *rand.Rand, accs []simulation.Account, genesisTimest
 			DowntimeJailDuration:    simulation.ModuleParamSimulator["DowntimeJailDuration"](r).(time.Duration),
 			SlashFractionDoubleSign: simulation.ModuleParamSimulator["SlashFractionDoubleSign"](r).(sdk.Dec),
 			SlashFractionDowntime:   simulation.ModuleParamSimulator["SlashFractionDowntime"](r).(sdk.Dec),
+			DowntimeGracePeriod:     simulation.ModuleParamSimulator["DowntimeGracePeriod"](r).(int64),
 		},
 	}
 	fmt.Printf("Selected randomly generated slashing parameters:\n\t%+v\n", slashingGenesis)
@@ -220,6 +221,13 @@ func appStateRandomizedFn(r *rand.Rand, accs []simulation.Account, genesisTimest
 			simulation.ModuleParamSimulator["InflationMin"](r).(sdk.Dec),
 			simulation.ModuleParamSimulator["GoalBonded"](r).(sdk.Dec),
 			uint64(60*60*8766/5),
+			uint64(r.Intn(100)),
+			simulation.ModuleParamSimulator["BondedProvisionsFraction"](r).(sdk.Dec),
+			uint64(1+r.Intn(10)),
+			0,
+			0,
+			r.Intn(2) == 0,
+			uint64(r.Intn(100)),
 		),
 	)
 	fmt.Printf("Selected randomly generated minting parameters:\n\t%+v\n", mintGenesis)
@@ -235,7 +243,7 @@ func appStateRandomizedFn(r *rand.Rand, accs []simulation.Account, genesisTimest
 		validator := staking.NewValidator(valAddr, accs[i].PubKey, staking.Description{})
 		validator.Tokens = sdk.NewInt(amount)
 		validator.DelegatorShares = sdk.NewDec(amount)
-		delegation := staking.Delegation{accs[i].Address, valAddr, sdk.NewDec(amount)}
+		delegation := staking.Delegation{DelegatorAddress: accs[i].Address, ValidatorAddress: valAddr, Shares: sdk.NewDec(amount)}
 		validators = append(validators, validator)
 		delegations = append(delegations, delegation)
 	}
@@ -438,7 +446,8 @@ func TestGaiaImportExport(t *testing.T) {
 		{app.keyMain, newApp.keyMain, [][]byte{}},
 		{app.keyAccount, newApp.keyAccount, [][]byte{}},
 		{app.keyStaking, newApp.keyStaking, [][]byte{staking.UnbondingQueueKey,
-			staking.RedelegationQueueKey, staking.ValidatorQueueKey}}, // ordering may change but it doesn't matter
+			staking.RedelegationQueueKey, staking.ValidatorQueueKey, // ordering may change but it doesn't matter
+			staking.ValidatorUpdatesHistoryKey}}, // history is not exported
 		{app.keySlashing, newApp.keySlashing, [][]byte{}},
 		{app.keyMint, newApp.keyMint, [][]byte{}},
 		{app.keyDistr, newApp.keyDistr, [][]byte{}},
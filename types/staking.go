@@ -131,6 +131,7 @@ type Delegation interface {
 	GetDelegatorAddr() AccAddress // delegator AccAddress for the bond
 	GetValidatorAddr() ValAddress // validator operator address
 	GetShares() Dec               // amount of validator's shares held in this delegation
+	GetBondHeight() int64         // height at which the delegation was created
 }
 
 // properties for the set of all delegations for a particular
@@ -31,3 +31,8 @@ func BeginBlocker(ctx sdk.Context, req abci.RequestBeginBlock, k keeper.Keeper)
 	k.SetPreviousProposerConsAddr(ctx, consAddr)
 
 }
+
+// re-delegate the rewards of the delegations which auto-compound them
+func EndBlocker(ctx sdk.Context, k keeper.Keeper) {
+	k.CompoundDelegationRewards(ctx)
+}
@@ -23,6 +23,7 @@ var (
 	flagOnlyFromValidator = "only-from-validator"
 	flagIsValidator       = "is-validator"
 	flagComission         = "commission"
+	flagCommissionAmount  = "commission-amount"
 )
 
 // GetTxCmd returns the transaction commands for this module
@@ -49,6 +50,7 @@ func GetCmdWithdrawRewards(cdc *codec.Codec) *cobra.Command {
 
 $ gaiacli tx distr withdraw-rewards cosmosvaloper1gghjut3ccd8ay0zduzj64hwre2fxs9ldmqhffj --from mykey
 $ gaiacli tx distr withdraw-rewards cosmosvaloper1gghjut3ccd8ay0zduzj64hwre2fxs9ldmqhffj --from mykey --commission
+$ gaiacli tx distr withdraw-rewards cosmosvaloper1gghjut3ccd8ay0zduzj64hwre2fxs9ldmqhffj --from mykey --commission --commission-amount 10stake
 `),
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -65,13 +67,18 @@ $ gaiacli tx distr withdraw-rewards cosmosvaloper1gghjut3ccd8ay0zduzj64hwre2fxs9
 
 			msgs := []sdk.Msg{types.NewMsgWithdrawDelegatorReward(delAddr, valAddr)}
 			if viper.GetBool(flagComission) {
-				msgs = append(msgs, types.NewMsgWithdrawValidatorCommission(valAddr))
+				amount, err := sdk.ParseCoins(viper.GetString(flagCommissionAmount))
+				if err != nil {
+					return err
+				}
+				msgs = append(msgs, types.NewMsgWithdrawValidatorCommission(valAddr, amount))
 			}
 
 			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, msgs)
 		},
 	}
 	cmd.Flags().Bool(flagComission, false, "also withdraw validator's commission")
+	cmd.Flags().String(flagCommissionAmount, "", "amount of commission to withdraw, defaults to the full accumulated commission")
 	return cmd
 }
 
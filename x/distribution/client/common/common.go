@@ -129,7 +129,7 @@ func WithdrawAllDelegatorRewards(cliCtx context.CLIContext, cdc *codec.Codec,
 // used to withdraw both validation's commission and self-delegation reward.
 func WithdrawValidatorRewardsAndCommission(validatorAddr sdk.ValAddress) ([]sdk.Msg, error) {
 
-	commissionMsg := distr.NewMsgWithdrawValidatorCommission(validatorAddr)
+	commissionMsg := distr.NewMsgWithdrawValidatorCommission(validatorAddr, nil)
 	if err := commissionMsg.ValidateBasic(); err != nil {
 		return nil, err
 	}
@@ -64,7 +64,7 @@ func handleMsgWithdrawDelegatorReward(ctx sdk.Context, msg types.MsgWithdrawDele
 }
 
 func handleMsgWithdrawValidatorCommission(ctx sdk.Context, msg types.MsgWithdrawValidatorCommission, k keeper.Keeper) sdk.Result {
-	commission, err := k.WithdrawValidatorCommission(ctx, msg.ValidatorAddress)
+	commission, err := k.WithdrawValidatorCommission(ctx, msg.ValidatorAddress, msg.Amount)
 	if err != nil {
 		return err.Result()
 	}
@@ -115,6 +115,21 @@ func (k Keeper) calculateDelegationRewards(ctx sdk.Context, val sdk.Validator, d
 	return rewards
 }
 
+// split the rewards of a delegation into the part it earns given how long it
+// has been bonded and the part it forfeits
+func (k Keeper) applyLoyaltyMultiplier(ctx sdk.Context, del sdk.Delegation,
+	rewards sdk.DecCoins) (earned, forfeited sdk.DecCoins) {
+
+	multiplier := k.stakingKeeper.LoyaltyMultiplier(ctx, del)
+	if multiplier.GTE(sdk.OneDec()) {
+		return rewards, sdk.DecCoins{}
+	}
+	if multiplier.IsPositive() {
+		earned = rewards.MulDecTruncate(multiplier)
+	}
+	return earned, rewards.Sub(earned)
+}
+
 func (k Keeper) withdrawDelegationRewards(ctx sdk.Context, val sdk.Validator, del sdk.Delegation) (sdk.Coins, sdk.Error) {
 	// check existence of delegator starting info
 	if !k.HasDelegatorStartingInfo(ctx, del.GetValidatorAddr(), del.GetDelegatorAddr()) {
@@ -141,12 +156,13 @@ func (k Keeper) withdrawDelegationRewards(ctx sdk.Context, val sdk.Validator, de
 	startingPeriod := startingInfo.PreviousPeriod
 	k.decrementReferenceCount(ctx, del.GetValidatorAddr(), startingPeriod)
 
-	// truncate coins, return remainder to community pool
-	coins, remainder := rewards.TruncateDecimal()
+	// truncate coins, return remainder and forfeited rewards to community pool
+	earned, forfeited := k.applyLoyaltyMultiplier(ctx, del, rewards)
+	coins, remainder := earned.TruncateDecimal()
 
 	k.SetValidatorOutstandingRewards(ctx, del.GetValidatorAddr(), outstanding.Sub(rewards))
 	feePool := k.GetFeePool(ctx)
-	feePool.CommunityPool = feePool.CommunityPool.Add(remainder)
+	feePool.CommunityPool = feePool.CommunityPool.Add(remainder).Add(forfeited)
 	k.SetFeePool(ctx, feePool)
 
 	// add coins to user account
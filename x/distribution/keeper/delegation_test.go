@@ -317,7 +317,7 @@ func TestWithdrawDelegationRewardsBasic(t *testing.T) {
 	)
 
 	// withdraw commission
-	_, err = k.WithdrawValidatorCommission(ctx, valOpAddr1)
+	_, err = k.WithdrawValidatorCommission(ctx, valOpAddr1, nil)
 	require.Nil(t, err)
 
 	// assert correct balance
@@ -328,6 +328,50 @@ func TestWithdrawDelegationRewardsBasic(t *testing.T) {
 	)
 }
 
+func TestWithdrawDelegationRewardsToWithdrawAddr(t *testing.T) {
+	ctx, ak, k, sk, _ := CreateTestInputDefault(t, false, 1000)
+	sh := staking.NewHandler(sk)
+
+	// create validator with 50% commission
+	valTokens := sdk.TokensFromTendermintPower(100)
+	commission := staking.NewCommissionMsg(sdk.NewDecWithPrec(5, 1), sdk.NewDecWithPrec(5, 1), sdk.NewDec(0))
+	msg := staking.NewMsgCreateValidator(
+		valOpAddr1, valConsPk1,
+		sdk.NewCoin(sdk.DefaultBondDenom, valTokens),
+		staking.Description{}, commission, sdk.OneInt(),
+	)
+	require.True(t, sh(ctx, msg).IsOK())
+
+	// end block to bond validator
+	staking.EndBlocker(ctx, sk)
+
+	// next block
+	ctx = ctx.WithBlockHeight(ctx.BlockHeight() + 1)
+
+	// send the rewards of the self-delegation to another address
+	delAddr := sdk.AccAddress(valOpAddr1)
+	require.Equal(t, delAddr, k.GetDelegatorWithdrawAddr(ctx, delAddr))
+	k.SetWithdrawAddrEnabled(ctx, true)
+	require.Nil(t, k.SetWithdrawAddr(ctx, delAddr, delAddr2))
+	require.Equal(t, delAddr2, k.GetDelegatorWithdrawAddr(ctx, delAddr))
+	delBalance := ak.GetAccount(ctx, delAddr).GetCoins()
+	withdrawBalance := ak.GetAccount(ctx, delAddr2).GetCoins()
+
+	// allocate some rewards
+	initial := sdk.TokensFromTendermintPower(10)
+	tokens := sdk.DecCoins{sdk.NewDecCoin(sdk.DefaultBondDenom, initial)}
+	k.AllocateTokensToValidator(ctx, sk.Validator(ctx, valOpAddr1), tokens)
+
+	// withdraw rewards
+	rewards, err := k.WithdrawDelegationRewards(ctx, delAddr, valOpAddr1)
+	require.Nil(t, err)
+	require.Equal(t, sdk.Coins{sdk.NewCoin(sdk.DefaultBondDenom, initial.QuoRaw(2))}, rewards)
+
+	// the rewards are credited to the withdraw address only
+	require.Equal(t, withdrawBalance.Add(rewards), ak.GetAccount(ctx, delAddr2).GetCoins())
+	require.Equal(t, delBalance, ak.GetAccount(ctx, delAddr).GetCoins())
+}
+
 func TestCalculateRewardsAfterManySlashesInSameBlock(t *testing.T) {
 	ctx, _, k, sk, _ := CreateTestInputDefault(t, false, 1000)
 	sh := staking.NewHandler(sk)
@@ -530,7 +574,7 @@ func TestCalculateRewardsMultiDelegatorMultWithdraw(t *testing.T) {
 	require.Equal(t, uint64(3), k.GetValidatorHistoricalReferenceCount(ctx))
 
 	// validator withdraws commission
-	k.WithdrawValidatorCommission(ctx, valOpAddr1)
+	k.WithdrawValidatorCommission(ctx, valOpAddr1, nil)
 
 	// end period
 	endingPeriod := k.incrementValidatorPeriod(ctx, val)
@@ -588,7 +632,7 @@ func TestCalculateRewardsMultiDelegatorMultWithdraw(t *testing.T) {
 	k.AllocateTokensToValidator(ctx, val, tokens)
 
 	// withdraw commission
-	k.WithdrawValidatorCommission(ctx, valOpAddr1)
+	k.WithdrawValidatorCommission(ctx, valOpAddr1, nil)
 
 	// end period
 	endingPeriod = k.incrementValidatorPeriod(ctx, val)
@@ -608,3 +652,58 @@ func TestCalculateRewardsMultiDelegatorMultWithdraw(t *testing.T) {
 	// commission should be zero
 	require.True(t, k.GetValidatorAccumulatedCommission(ctx, valOpAddr1).IsZero())
 }
+
+func TestWithdrawDelegationRewardsLoyaltyWeighting(t *testing.T) {
+	ctx, ak, k, sk, _ := CreateTestInputDefault(t, false, 1000)
+	sh := staking.NewHandler(sk)
+
+	// half of the rewards of a new delegation are withheld, linearly released
+	// over 100 blocks
+	params := sk.GetParams(ctx)
+	params.LoyaltyWeighting = sdk.NewDecWithPrec(5, 1)
+	params.LoyaltyPeriod = 100
+	sk.SetParams(ctx, params)
+
+	// create validator with no commission
+	commission := staking.NewCommissionMsg(sdk.ZeroDec(), sdk.ZeroDec(), sdk.ZeroDec())
+	valTokens := sdk.TokensFromTendermintPower(100)
+	msg := staking.NewMsgCreateValidator(valOpAddr1, valConsPk1,
+		sdk.NewCoin(sdk.DefaultBondDenom, valTokens), staking.Description{}, commission, sdk.OneInt())
+	require.True(t, sh(ctx, msg).IsOK())
+	staking.EndBlocker(ctx, sk)
+
+	// two delegators of equal stake, bonded 50 blocks apart
+	delTokens := sdk.TokensFromTendermintPower(100)
+	msgDelegate := staking.NewMsgDelegate(delAddr1, valOpAddr1, sdk.NewCoin(sdk.DefaultBondDenom, delTokens))
+	require.True(t, sh(ctx, msgDelegate).IsOK())
+
+	ctx = ctx.WithBlockHeight(50)
+	msgDelegate = staking.NewMsgDelegate(delAddr2, valOpAddr1, sdk.NewCoin(sdk.DefaultBondDenom, delTokens))
+	require.True(t, sh(ctx, msgDelegate).IsOK())
+	staking.EndBlocker(ctx, sk)
+
+	// allocate rewards, the validator holds a third of its stake itself
+	ctx = ctx.WithBlockHeight(100)
+	val := sk.Validator(ctx, valOpAddr1)
+	initial := sdk.TokensFromTendermintPower(30)
+	k.AllocateTokensToValidator(ctx, val, sdk.DecCoins{sdk.NewDecCoin(sdk.DefaultBondDenom, initial)})
+	communityPool := k.GetFeePoolCommunityCoins(ctx)
+
+	balance1 := ak.GetAccount(ctx, delAddr1).GetCoins().AmountOf(sdk.DefaultBondDenom)
+	balance2 := ak.GetAccount(ctx, delAddr2).GetCoins().AmountOf(sdk.DefaultBondDenom)
+	_, err := k.WithdrawDelegationRewards(ctx, delAddr1, valOpAddr1)
+	require.Nil(t, err)
+	_, err = k.WithdrawDelegationRewards(ctx, delAddr2, valOpAddr1)
+	require.Nil(t, err)
+	earned1 := ak.GetAccount(ctx, delAddr1).GetCoins().AmountOf(sdk.DefaultBondDenom).Sub(balance1)
+	earned2 := ak.GetAccount(ctx, delAddr2).GetCoins().AmountOf(sdk.DefaultBondDenom).Sub(balance2)
+
+	// the longer-staked delegator earns its full rewards, the other three
+	// quarters of them with the rest going to the community pool
+	reward := initial.QuoRaw(3)
+	require.True(t, earned1.GT(earned2))
+	require.True(t, reward.Equal(earned1), "expected %v, got %v", reward, earned1)
+	require.True(t, reward.MulRaw(3).QuoRaw(4).Equal(earned2), "expected %v, got %v", reward.MulRaw(3).QuoRaw(4), earned2)
+	forfeited := k.GetFeePoolCommunityCoins(ctx).AmountOf(sdk.DefaultBondDenom).Sub(communityPool.AmountOf(sdk.DefaultBondDenom))
+	require.True(t, reward.QuoRaw(4).ToDec().Equal(forfeited), "expected %v, got %v", reward.QuoRaw(4), forfeited)
+}
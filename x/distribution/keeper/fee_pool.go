@@ -23,3 +23,10 @@ func (k Keeper) DistributeFeePool(ctx sdk.Context, amount sdk.Coins, receiveAddr
 	k.SetFeePool(ctx, feePool)
 	return nil
 }
+
+// AddToCommunityPool adds newly minted funds to the community pool
+func (k Keeper) AddToCommunityPool(ctx sdk.Context, amount sdk.Coins) {
+	feePool := k.GetFeePool(ctx)
+	feePool.CommunityPool = feePool.CommunityPool.Add(sdk.NewDecCoins(amount))
+	k.SetFeePool(ctx, feePool)
+}
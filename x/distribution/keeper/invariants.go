@@ -76,7 +76,7 @@ func CanWithdrawInvariant(k Keeper, sk types.StakingKeeper) sdk.Invariant {
 
 		// iterate over all validators
 		sk.IterateValidators(ctx, func(_ int64, val sdk.Validator) (stop bool) {
-			_, _ = k.WithdrawValidatorCommission(ctx, val.GetOperator())
+			_, _ = k.WithdrawValidatorCommission(ctx, val.GetOperator(), nil)
 
 			delegationAddrs, ok := valDelegationAddrs[val.GetOperator().String()]
 			if ok {
@@ -75,15 +75,63 @@ func (k Keeper) WithdrawDelegationRewards(ctx sdk.Context, delAddr sdk.AccAddres
 	return rewards, nil
 }
 
-// withdraw validator commission
-func (k Keeper) WithdrawValidatorCommission(ctx sdk.Context, valAddr sdk.ValAddress) (sdk.Coins, sdk.Error) {
+// CompoundDelegationRewards withdraws the rewards of each delegation which
+// auto-compounds them and delegates them to the same validator, once every
+// staking AutoCompoundInterval blocks. Delegations whose rewards are withdrawn
+// to another address are skipped, and so are those the validator can't take a
+// delegation for, leaving their rewards in place.
+func (k Keeper) CompoundDelegationRewards(ctx sdk.Context) {
+	interval := k.stakingKeeper.AutoCompoundInterval(ctx)
+	if interval <= 0 || ctx.BlockHeight()%interval != 0 {
+		return
+	}
+
+	var delegations []sdk.Delegation
+	k.stakingKeeper.IterateAutoCompoundDelegations(ctx, func(_ int64, del sdk.Delegation) (stop bool) {
+		delegations = append(delegations, del)
+		return false
+	})
+
+	for _, del := range delegations {
+		delAddr, valAddr := del.GetDelegatorAddr(), del.GetValidatorAddr()
+		if !k.GetDelegatorWithdrawAddr(ctx, delAddr).Equals(delAddr) {
+			continue
+		}
+
+		// only withdraw the rewards if they can be delegated
+		cacheCtx, write := ctx.CacheContext()
+		rewards, err := k.WithdrawDelegationRewards(cacheCtx, delAddr, valAddr)
+		if err != nil {
+			continue
+		}
+		if _, err := k.stakingKeeper.CompoundDelegation(cacheCtx, delAddr, valAddr, rewards); err != nil {
+			continue
+		}
+		write()
+	}
+}
+
+// withdraw validator commission, an empty amount withdraws the full
+// accumulated commission
+func (k Keeper) WithdrawValidatorCommission(ctx sdk.Context, valAddr sdk.ValAddress, amount sdk.Coins) (sdk.Coins, sdk.Error) {
 	// fetch validator accumulated commission
 	commission := k.GetValidatorAccumulatedCommission(ctx, valAddr)
 	if commission.IsZero() {
 		return nil, types.ErrNoValidatorCommission(k.codespace)
 	}
 
-	coins, remainder := commission.TruncateDecimal()
+	var coins sdk.Coins
+	var remainder sdk.DecCoins
+	if amount.Empty() {
+		coins, remainder = commission.TruncateDecimal()
+	} else {
+		var hasNeg bool
+		remainder, hasNeg = commission.SafeSub(sdk.NewDecCoins(amount))
+		if hasNeg {
+			return nil, types.ErrInsufficientCommission(k.codespace)
+		}
+		coins = amount
+	}
 	k.SetValidatorAccumulatedCommission(ctx, valAddr, remainder) // leave remainder to withdraw later
 
 	// update outstanding
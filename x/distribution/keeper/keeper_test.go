@@ -6,6 +6,8 @@ import (
 	"github.com/stretchr/testify/require"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/distribution/types"
+	"github.com/cosmos/cosmos-sdk/x/staking"
 )
 
 func TestSetWithdrawAddr(t *testing.T) {
@@ -44,7 +46,7 @@ func TestWithdrawValidatorCommission(t *testing.T) {
 	keeper.SetValidatorAccumulatedCommission(ctx, valOpAddr3, valCommission)
 
 	// withdraw commission
-	keeper.WithdrawValidatorCommission(ctx, valOpAddr3)
+	keeper.WithdrawValidatorCommission(ctx, valOpAddr3, nil)
 
 	// check balance increase
 	balance = ak.GetAccount(ctx, sdk.AccAddress(valOpAddr3)).GetCoins()
@@ -62,3 +64,103 @@ func TestWithdrawValidatorCommission(t *testing.T) {
 
 	require.True(t, true)
 }
+
+func TestWithdrawValidatorCommissionPartial(t *testing.T) {
+	ctx, ak, keeper, _, _ := CreateTestInputDefault(t, false, 1000)
+
+	valCommission := sdk.DecCoins{
+		sdk.NewDecCoinFromDec("mytoken", sdk.NewDec(5).Quo(sdk.NewDec(4))),
+		sdk.NewDecCoinFromDec("stake", sdk.NewDec(3).Quo(sdk.NewDec(2))),
+	}
+	keeper.SetValidatorOutstandingRewards(ctx, valOpAddr3, valCommission)
+	keeper.SetValidatorAccumulatedCommission(ctx, valOpAddr3, valCommission)
+	expTokens := sdk.TokensFromTendermintPower(1000)
+
+	// withdrawing more than the accumulated commission fails and changes nothing
+	over := sdk.Coins{sdk.NewInt64Coin("stake", 2)}
+	_, err := keeper.WithdrawValidatorCommission(ctx, valOpAddr3, over)
+	require.NotNil(t, err)
+	require.Equal(t, types.CodeInsufficientCommission, err.Code())
+	require.Equal(t, valCommission, keeper.GetValidatorAccumulatedCommission(ctx, valOpAddr3))
+	require.Equal(t, valCommission, keeper.GetValidatorOutstandingRewards(ctx, valOpAddr3))
+
+	// withdraw part of the commission of a single denomination
+	partial := sdk.Coins{sdk.NewInt64Coin("stake", 1)}
+	coins, err := keeper.WithdrawValidatorCommission(ctx, valOpAddr3, partial)
+	require.Nil(t, err)
+	require.Equal(t, partial, coins)
+
+	balance := ak.GetAccount(ctx, sdk.AccAddress(valOpAddr3)).GetCoins()
+	require.Equal(t, sdk.Coins{sdk.NewCoin("stake", expTokens.AddRaw(1))}, balance)
+
+	expRemainder := sdk.DecCoins{
+		sdk.NewDecCoinFromDec("mytoken", sdk.NewDec(5).Quo(sdk.NewDec(4))),
+		sdk.NewDecCoinFromDec("stake", sdk.NewDec(1).Quo(sdk.NewDec(2))),
+	}
+	require.Equal(t, expRemainder, keeper.GetValidatorAccumulatedCommission(ctx, valOpAddr3))
+	require.Equal(t, expRemainder, keeper.GetValidatorOutstandingRewards(ctx, valOpAddr3))
+
+	// withdraw the rest
+	coins, err = keeper.WithdrawValidatorCommission(ctx, valOpAddr3, nil)
+	require.Nil(t, err)
+	require.Equal(t, sdk.Coins{sdk.NewInt64Coin("mytoken", 1)}, coins)
+	require.Equal(t, sdk.DecCoins{
+		sdk.NewDecCoinFromDec("mytoken", sdk.NewDec(1).Quo(sdk.NewDec(4))),
+		sdk.NewDecCoinFromDec("stake", sdk.NewDec(1).Quo(sdk.NewDec(2))),
+	}, keeper.GetValidatorAccumulatedCommission(ctx, valOpAddr3))
+}
+
+func TestCompoundDelegationRewards(t *testing.T) {
+	ctx, ak, k, sk, _ := CreateTestInputDefault(t, false, 1000)
+	sh := staking.NewHandler(sk)
+
+	// create validator with no commission
+	commission := staking.NewCommissionMsg(sdk.ZeroDec(), sdk.ZeroDec(), sdk.ZeroDec())
+	valTokens := sdk.TokensFromTendermintPower(100)
+	msg := staking.NewMsgCreateValidator(valOpAddr1, valConsPk1,
+		sdk.NewCoin(sdk.DefaultBondDenom, valTokens), staking.Description{}, commission, sdk.OneInt())
+	require.True(t, sh(ctx, msg).IsOK())
+	staking.EndBlocker(ctx, sk)
+
+	// two delegators of equal stake, only the first one compounding
+	delTokens := sdk.TokensFromTendermintPower(100)
+	for _, delAddr := range []sdk.AccAddress{delAddr1, delAddr2} {
+		msgDelegate := staking.NewMsgDelegate(delAddr, valOpAddr1, sdk.NewCoin(sdk.DefaultBondDenom, delTokens))
+		require.True(t, sh(ctx, msgDelegate).IsOK())
+	}
+	require.True(t, sh(ctx, staking.NewMsgSetAutoCompound(delAddr1, valOpAddr1, true)).IsOK())
+	staking.EndBlocker(ctx, sk)
+	params := sk.GetParams(ctx)
+	params.AutoCompoundInterval = 10
+	sk.SetParams(ctx, params)
+
+	// allocate rewards
+	ctx = ctx.WithBlockHeight(9)
+	val := sk.Validator(ctx, valOpAddr1)
+	initial := sdk.TokensFromTendermintPower(30)
+	k.AllocateTokensToValidator(ctx, val, sdk.DecCoins{sdk.NewDecCoin(sdk.DefaultBondDenom, initial)})
+
+	// nothing is compounded between the intervals
+	shares1 := sk.Delegation(ctx, delAddr1, valOpAddr1).GetShares()
+	shares2 := sk.Delegation(ctx, delAddr2, valOpAddr1).GetShares()
+	balance1 := ak.GetAccount(ctx, delAddr1).GetCoins()
+	k.CompoundDelegationRewards(ctx)
+	require.Equal(t, shares1, sk.Delegation(ctx, delAddr1, valOpAddr1).GetShares())
+
+	ctx = ctx.WithBlockHeight(10)
+	k.CompoundDelegationRewards(ctx)
+
+	// the rewards of the compounding delegation are delegated, the account
+	// balance is unchanged
+	require.True(t, sk.Delegation(ctx, delAddr1, valOpAddr1).GetShares().GT(shares1))
+	require.Equal(t, balance1, ak.GetAccount(ctx, delAddr1).GetCoins())
+	rewards, err := k.WithdrawDelegationRewards(ctx, delAddr1, valOpAddr1)
+	require.Nil(t, err)
+	require.True(t, rewards.IsZero())
+
+	// the other delegation is untouched
+	require.Equal(t, shares2, sk.Delegation(ctx, delAddr2, valOpAddr1).GetShares())
+	rewards, err = k.WithdrawDelegationRewards(ctx, delAddr2, valOpAddr1)
+	require.Nil(t, err)
+	require.False(t, rewards.IsZero())
+}
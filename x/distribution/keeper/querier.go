@@ -221,6 +221,7 @@ func queryDelegationRewards(ctx sdk.Context, _ []string, req abci.RequestQuery,
 
 	endingPeriod := k.incrementValidatorPeriod(ctx, val)
 	rewards := k.calculateDelegationRewards(ctx, val, del, endingPeriod)
+	rewards, _ = k.applyLoyaltyMultiplier(ctx, del, rewards)
 
 	bz, err := codec.MarshalJSONIndent(k.cdc, rewards)
 	if err != nil {
@@ -262,6 +263,7 @@ func queryDelegatorTotalRewards(ctx sdk.Context, _ []string, req abci.RequestQue
 			val := k.stakingKeeper.Validator(ctx, valAddr)
 			endingPeriod := k.incrementValidatorPeriod(ctx, val)
 			delReward := k.calculateDelegationRewards(ctx, val, del, endingPeriod)
+			delReward, _ = k.applyLoyaltyMultiplier(ctx, del, delReward)
 
 			delRewards = append(delRewards, types.NewDelegationDelegatorReward(valAddr, delReward))
 			total = total.Add(delReward)
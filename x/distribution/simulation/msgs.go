@@ -68,7 +68,7 @@ func SimulateMsgWithdrawValidatorCommission(m auth.AccountKeeper, k distribution
 		accs []simulation.Account) (opMsg simulation.OperationMsg, fOps []simulation.FutureOperation, err error) {
 
 		account := simulation.RandomAcc(r, accs)
-		msg := distribution.NewMsgWithdrawValidatorCommission(sdk.ValAddress(account.Address))
+		msg := distribution.NewMsgWithdrawValidatorCommission(sdk.ValAddress(account.Address), nil)
 
 		if msg.ValidateBasic() != nil {
 			return simulation.NoOpMsg(), nil, fmt.Errorf("expected msg to pass ValidateBasic: %s", msg.GetSignBytes())
@@ -13,6 +13,7 @@ const (
 	CodeNoDistributionInfo      CodeType          = 104
 	CodeNoValidatorCommission   CodeType          = 105
 	CodeSetWithdrawAddrDisabled CodeType          = 106
+	CodeInsufficientCommission  CodeType          = 107
 )
 
 func ErrNilDelegatorAddr(codespace sdk.CodespaceType) sdk.Error {
@@ -33,6 +34,12 @@ func ErrNoValidatorDistInfo(codespace sdk.CodespaceType) sdk.Error {
 func ErrNoValidatorCommission(codespace sdk.CodespaceType) sdk.Error {
 	return sdk.NewError(codespace, CodeNoValidatorCommission, "no validator commission to withdraw")
 }
+func ErrInvalidCommissionAmount(codespace sdk.CodespaceType) sdk.Error {
+	return sdk.NewError(codespace, CodeInvalidInput, "commission withdrawal amount must be valid and positive")
+}
+func ErrInsufficientCommission(codespace sdk.CodespaceType) sdk.Error {
+	return sdk.NewError(codespace, CodeInsufficientCommission, "withdrawal amount exceeds accumulated commission")
+}
 func ErrSetWithdrawAddrDisabled(codespace sdk.CodespaceType) sdk.Error {
 	return sdk.NewError(codespace, CodeSetWithdrawAddrDisabled, "set withdraw address disabled")
 }
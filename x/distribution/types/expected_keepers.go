@@ -11,6 +11,11 @@ type StakingKeeper interface {
 	ValidatorByConsAddr(ctx sdk.Context, consAddr sdk.ConsAddress) sdk.Validator
 	GetLastTotalPower(ctx sdk.Context) sdk.Int
 	GetLastValidatorPower(ctx sdk.Context, valAddr sdk.ValAddress) int64
+	LoyaltyMultiplier(ctx sdk.Context, del sdk.Delegation) sdk.Dec
+	IterateAutoCompoundDelegations(ctx sdk.Context,
+		fn func(index int64, delegation sdk.Delegation) (stop bool))
+	CompoundDelegation(ctx sdk.Context, delAddr sdk.AccAddress, valAddr sdk.ValAddress, coins sdk.Coins) (sdk.Int, sdk.Error)
+	AutoCompoundInterval(ctx sdk.Context) int64
 
 	// used for invariants
 	IterateValidators(ctx sdk.Context,
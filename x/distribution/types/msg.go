@@ -84,14 +84,17 @@ func (msg MsgWithdrawDelegatorReward) ValidateBasic() sdk.Error {
 	return nil
 }
 
-// msg struct for validator withdraw
+// msg struct for validator withdraw, an empty amount withdraws the full
+// accumulated commission
 type MsgWithdrawValidatorCommission struct {
 	ValidatorAddress sdk.ValAddress `json:"validator_address"`
+	Amount           sdk.Coins      `json:"amount"`
 }
 
-func NewMsgWithdrawValidatorCommission(valAddr sdk.ValAddress) MsgWithdrawValidatorCommission {
+func NewMsgWithdrawValidatorCommission(valAddr sdk.ValAddress, amount sdk.Coins) MsgWithdrawValidatorCommission {
 	return MsgWithdrawValidatorCommission{
 		ValidatorAddress: valAddr,
+		Amount:           amount,
 	}
 }
 
@@ -114,5 +117,8 @@ func (msg MsgWithdrawValidatorCommission) ValidateBasic() sdk.Error {
 	if msg.ValidatorAddress.Empty() {
 		return ErrNilValidatorAddr(DefaultCodespace)
 	}
+	if !msg.Amount.IsValid() {
+		return ErrInvalidCommissionAmount(DefaultCodespace)
+	}
 	return nil
 }
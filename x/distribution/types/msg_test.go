@@ -58,13 +58,16 @@ func TestMsgWithdrawDelegatorReward(t *testing.T) {
 func TestMsgWithdrawValidatorCommission(t *testing.T) {
 	tests := []struct {
 		validatorAddr sdk.ValAddress
+		amount        sdk.Coins
 		expectPass    bool
 	}{
-		{valAddr1, true},
-		{emptyValAddr, false},
+		{valAddr1, nil, true},
+		{valAddr1, sdk.Coins{sdk.NewInt64Coin("stake", 10)}, true},
+		{valAddr1, sdk.Coins{sdk.NewInt64Coin("stake", 0)}, false},
+		{emptyValAddr, nil, false},
 	}
 	for i, tc := range tests {
-		msg := NewMsgWithdrawValidatorCommission(tc.validatorAddr)
+		msg := NewMsgWithdrawValidatorCommission(tc.validatorAddr, tc.amount)
 		if tc.expectPass {
 			require.Nil(t, msg.ValidateBasic(), "test index: %v", i)
 		} else {
@@ -168,7 +168,7 @@ var (
 		ed25519.GenPrivKey().PubKey(),
 	}
 
-	testDescription   = staking.NewDescription("T", "E", "S", "T")
+	testDescription   = staking.NewDescription("T", "E", "S", "T", "")
 	testCommissionMsg = staking.NewCommissionMsg(sdk.ZeroDec(), sdk.ZeroDec(), sdk.ZeroDec())
 )
 
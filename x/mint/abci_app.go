@@ -14,13 +14,12 @@ func BeginBlocker(ctx sdk.Context, k Keeper) {
 	// recalculate inflation rate
 	totalSupply := k.sk.TotalTokens(ctx)
 	bondedRatio := k.sk.BondedRatio(ctx)
-	minter.Inflation = minter.NextInflationRate(params, bondedRatio)
+	minter.Inflation = k.NextInflationRate(ctx, minter, params, bondedRatio)
 	minter.AnnualProvisions = minter.NextAnnualProvisions(params, totalSupply)
-	k.SetMinter(ctx, minter)
 
-	// mint coins, add to collected fees, update supply
-	mintedCoin := minter.BlockProvision(params)
-	k.fck.AddCollectedFees(ctx, sdk.Coins{mintedCoin})
-	k.sk.InflateSupply(ctx, mintedCoin.Amount)
+	k.SetMinter(ctx, minter)
+	k.RecordInflation(ctx, minter.Inflation, params.InflationHistory)
 
+	// mint the provisions of the block
+	k.ProcessProvisions(ctx)
 }
@@ -2,12 +2,15 @@ package rest
 
 import (
 	"fmt"
+	"math"
 	"net/http"
+	"strconv"
 
 	"github.com/gorilla/mux"
 
 	"github.com/cosmos/cosmos-sdk/client/context"
 	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/types/rest"
 	"github.com/cosmos/cosmos-sdk/x/mint"
 )
@@ -23,6 +26,16 @@ func registerQueryRoutes(cliCtx context.CLIContext, r *mux.Router, cdc *codec.Co
 		queryInflationHandlerFn(cdc, cliCtx),
 	).Methods("GET")
 
+	r.HandleFunc(
+		"/minting/inflation/history",
+		queryInflationHistoryHandlerFn(cdc, cliCtx),
+	).Methods("GET")
+
+	r.HandleFunc(
+		"/minting/validators/{validatorAddr}/apr",
+		queryDelegatorAPRHandlerFn(cdc, cliCtx),
+	).Methods("GET")
+
 	r.HandleFunc(
 		"/minting/annual-provisions",
 		queryAnnualProvisionsHandlerFn(cdc, cliCtx),
@@ -70,3 +83,68 @@ func queryAnnualProvisionsHandlerFn(cdc *codec.Codec, cliCtx context.CLIContext)
 		rest.PostProcessResponse(w, cdc, res, cliCtx.Indent)
 	}
 }
+
+func queryInflationHistoryHandlerFn(cdc *codec.Codec, cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		from, to := int64(0), int64(math.MaxInt64)
+
+		var err error
+		if s := r.URL.Query().Get("from"); s != "" {
+			if from, err = strconv.ParseInt(s, 10, 64); err != nil {
+				rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+				return
+			}
+		}
+		if s := r.URL.Query().Get("to"); s != "" {
+			if to, err = strconv.ParseInt(s, 10, 64); err != nil {
+				rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+				return
+			}
+		}
+		if from > to {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, "from height must not exceed to height")
+			return
+		}
+
+		bz, err := cdc.MarshalJSON(mint.NewQueryInflationHistoryParams(from, to))
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		route := fmt.Sprintf("custom/%s/%s", mint.QuerierRoute, mint.QueryInflationHistory)
+		res, err := cliCtx.QueryWithData(route, bz)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		rest.PostProcessResponse(w, cdc, res, cliCtx.Indent)
+	}
+}
+
+func queryDelegatorAPRHandlerFn(cdc *codec.Codec, cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		valAddr, err := sdk.ValAddressFromBech32(mux.Vars(r)["validatorAddr"])
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		riskAdjusted := r.URL.Query().Get("risk_adjusted") == "true"
+		bz, err := cdc.MarshalJSON(mint.NewQueryDelegatorAPRParams(valAddr, riskAdjusted))
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		route := fmt.Sprintf("custom/%s/%s", mint.QuerierRoute, mint.QueryDelegatorAPR)
+		res, err := cliCtx.QueryWithData(route, bz)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		rest.PostProcessResponse(w, cdc, res, cliCtx.Indent)
+	}
+}
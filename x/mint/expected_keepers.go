@@ -7,11 +7,22 @@ import (
 // expected staking keeper
 type StakingKeeper interface {
 	TotalTokens(ctx sdk.Context) sdk.Int
+	TotalBondedTokens(ctx sdk.Context) sdk.Int
 	BondedRatio(ctx sdk.Context) sdk.Dec
 	InflateSupply(ctx sdk.Context, newTokens sdk.Int)
+	BondDenom(ctx sdk.Context) string
+	Validator(ctx sdk.Context, valAddr sdk.ValAddress) sdk.Validator
+	IterateBondedValidatorsByPower(ctx sdk.Context, fn func(index int64, validator sdk.Validator) (stop bool))
+	Delegation(ctx sdk.Context, delAddr sdk.AccAddress, valAddr sdk.ValAddress) sdk.Delegation
+	GetValidatorSlashedTokensSince(ctx sdk.Context, valAddr sdk.ValAddress, height int64) sdk.Int
 }
 
 // expected fee collection keeper interface
 type FeeCollectionKeeper interface {
 	AddCollectedFees(sdk.Context, sdk.Coins) sdk.Coins
 }
+
+// expected distribution keeper
+type DistributionKeeper interface {
+	AddToCommunityPool(ctx sdk.Context, amount sdk.Coins)
+}
@@ -28,6 +28,16 @@ func DefaultGenesisState() GenesisState {
 
 // new mint genesis
 func InitGenesis(ctx sdk.Context, keeper Keeper, data GenesisState) {
+	// counters missing from the genesis start at zero
+	if data.Minter.Reserve.IsNil() {
+		data.Minter.Reserve = sdk.ZeroInt()
+	}
+	if data.Minter.CumulativeProvisions.IsNil() {
+		data.Minter.CumulativeProvisions = sdk.ZeroInt()
+	}
+	if data.Minter.ProvisionsRemainder.IsNil() {
+		data.Minter.ProvisionsRemainder = sdk.ZeroDec()
+	}
 	keeper.SetMinter(ctx, data.Minter)
 	keeper.SetParams(ctx, data.Params)
 }
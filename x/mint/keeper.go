@@ -1,12 +1,47 @@
 package mint
 
 import (
+	"encoding/binary"
+	"math"
+
 	"github.com/cosmos/cosmos-sdk/codec"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/x/params"
 )
 
-var minterKey = []byte{0x00} // the one key to use for the keeper store
+var (
+	minterKey           = []byte{0x00} // the one key to use for the minter
+	inflationHistoryKey = []byte{0x01} // prefix for the inflation rate per block height
+	provisionsIndexKey  = []byte{0x02} // prefix for the cumulative provisions per bonded token per block height
+
+	validatorProvisionsKey = []byte{0x03} // prefix for the provisions credited to each bonded validator per block height
+	delegationSharesKey    = []byte{0x04} // prefix for the shares of each delegation per block height they changed at
+)
+
+// get the key for the inflation rate at a block height
+func getInflationHistoryKey(height int64) []byte {
+	return append(inflationHistoryKey, sdk.Uint64ToBigEndian(uint64(height))...)
+}
+
+// get the key for the provisions index at a block height
+func getProvisionsIndexKey(height int64) []byte {
+	return append(provisionsIndexKey, sdk.Uint64ToBigEndian(uint64(height))...)
+}
+
+// get the key for the provisions credited to a validator at a block height
+func getValidatorProvisionsKey(height int64, valAddr sdk.ValAddress) []byte {
+	return append(append(validatorProvisionsKey, sdk.Uint64ToBigEndian(uint64(height))...), valAddr.Bytes()...)
+}
+
+// get the prefix for the share records of a delegation
+func getDelegationSharesPrefix(valAddr sdk.ValAddress, delAddr sdk.AccAddress) []byte {
+	return append(append(delegationSharesKey, valAddr.Bytes()...), delAddr.Bytes()...)
+}
+
+// get the key for the shares of a delegation at a block height
+func getDelegationSharesKey(valAddr sdk.ValAddress, delAddr sdk.AccAddress, height int64) []byte {
+	return append(getDelegationSharesPrefix(valAddr, delAddr), sdk.Uint64ToBigEndian(uint64(height))...)
+}
 
 const (
 	// ModuleName is the name of the module
@@ -29,10 +64,24 @@ type Keeper struct {
 	paramSpace params.Subspace
 	sk         StakingKeeper
 	fck        FeeCollectionKeeper
+	dk         DistributionKeeper
+
+	inflationAdjuster  InflationAdjuster
+	slashRiskEstimator SlashRiskEstimator
 }
 
+// InflationAdjuster modifies the inflation rate computed by the minter for the
+// next block, e.g. based on oracle data.
+type InflationAdjuster func(ctx sdk.Context, baseInflation sdk.Dec) sdk.Dec
+
+// SlashRiskEstimator returns the expected fraction of stake a validator loses
+// to slashing over the returned number of blocks, e.g. based on the blocks it
+// missed in the signed blocks window.
+type SlashRiskEstimator func(ctx sdk.Context, valAddr sdk.ValAddress) (risk sdk.Dec, blocks int64)
+
 func NewKeeper(cdc *codec.Codec, key sdk.StoreKey,
-	paramSpace params.Subspace, sk StakingKeeper, fck FeeCollectionKeeper) Keeper {
+	paramSpace params.Subspace, sk StakingKeeper, fck FeeCollectionKeeper,
+	dk DistributionKeeper) Keeper {
 
 	keeper := Keeper{
 		storeKey:   key,
@@ -40,10 +89,29 @@ func NewKeeper(cdc *codec.Codec, key sdk.StoreKey,
 		paramSpace: paramSpace.WithKeyTable(ParamKeyTable()),
 		sk:         sk,
 		fck:        fck,
+		dk:         dk,
 	}
 	return keeper
 }
 
+// Set the inflation adjuster
+func (k *Keeper) SetInflationAdjuster(adjuster InflationAdjuster) *Keeper {
+	if k.inflationAdjuster != nil {
+		panic("cannot set inflation adjuster twice")
+	}
+	k.inflationAdjuster = adjuster
+	return k
+}
+
+// Set the slash risk estimator used for the risk-adjusted APR
+func (k *Keeper) SetSlashRiskEstimator(estimator SlashRiskEstimator) *Keeper {
+	if k.slashRiskEstimator != nil {
+		panic("cannot set slash risk estimator twice")
+	}
+	k.slashRiskEstimator = estimator
+	return k
+}
+
 //______________________________________________________________________
 
 // get the minter
@@ -64,6 +132,423 @@ func (k Keeper) SetMinter(ctx sdk.Context, minter Minter) {
 	store.Set(minterKey, b)
 }
 
+// NextInflationRate returns the inflation rate for the next block computed
+// by the minter, modified by the inflation adjuster if one is set.
+func (k Keeper) NextInflationRate(ctx sdk.Context, minter Minter, params Params, bondedRatio sdk.Dec) sdk.Dec {
+	inflation := minter.NextInflationRate(params, bondedRatio)
+	if k.inflationAdjuster == nil {
+		return inflation
+	}
+	return k.inflationAdjuster(ctx, inflation)
+}
+
+// EstimateNextInflation returns the inflation rate for the next block under
+// the current minter and params if the given fraction of tokens were bonded.
+func (k Keeper) EstimateNextInflation(ctx sdk.Context, bondedRatio sdk.Dec) sdk.Dec {
+	return k.NextInflationRate(ctx, k.GetMinter(ctx), k.GetParams(ctx), bondedRatio)
+}
+
+//______________________________________________________________________
+
+// ProcessProvisions mints the provisions of the current block as coins of the
+// mint denom and adds the bonded part to the collected fees, returning the
+// coins added. The rest is the reserve, which is added to the community pool.
+// The staking token supply is only inflated if the mint denom is the staking
+// bond denom. Both parts count towards the cumulative provisions. If a maximum
+// token supply is set, the provision is tapered so the supply stops exactly at
+// the cap. If the provisions remainder is carried, the fraction of a token
+// truncated from the provision is kept in the minter and added to the provision
+// of the next block.
+func (k Keeper) ProcessProvisions(ctx sdk.Context) sdk.Coin {
+	minter := k.GetMinter(ctx)
+	params := k.GetParams(ctx)
+
+	mintedCoin := minter.BlockProvision(params)
+	if params.CarryProvisionsRemainder {
+		mintedCoin, minter.ProvisionsRemainder = minter.CarriedBlockProvision(params)
+	}
+	if params.MaxTokenSupply > 0 && mintedCoin.Denom == k.sk.BondDenom(ctx) {
+		headroom := sdk.NewInt(params.MaxTokenSupply).Sub(k.sk.TotalTokens(ctx))
+		mintedCoin.Amount = sdk.MinInt(mintedCoin.Amount, sdk.MaxInt(headroom, sdk.ZeroInt()))
+	}
+	bondedCoin, reserve := minter.SplitProvision(params, mintedCoin)
+	minter.Reserve = minter.Reserve.Add(reserve)
+	minter.CumulativeProvisions = minter.CumulativeProvisions.Add(mintedCoin.Amount)
+	k.SetMinter(ctx, minter)
+
+	k.recordProvisionsIndex(ctx, bondedCoin.Amount, params.ProvisionsHistory)
+	k.fck.AddCollectedFees(ctx, sdk.Coins{bondedCoin})
+	if reserve.IsPositive() {
+		k.dk.AddToCommunityPool(ctx, sdk.Coins{sdk.NewCoin(mintedCoin.Denom, reserve)})
+	}
+	if mintedCoin.Denom == k.sk.BondDenom(ctx) {
+		k.sk.InflateSupply(ctx, mintedCoin.Amount)
+	}
+	return bondedCoin
+}
+
+// recordProvisionsIndex adds the provisions paid to bonded holders per bonded
+// token to the index of the previous height and stores it at the current
+// height, along with the part of the provisions credited to each bonded
+// validator. Records older than the given number of blocks are pruned. A zero
+// retention disables the index.
+func (k Keeper) recordProvisionsIndex(ctx sdk.Context, provisions sdk.Int, retention uint64) {
+	if retention == 0 {
+		return
+	}
+
+	store := ctx.KVStore(k.storeKey)
+	height := ctx.BlockHeight()
+	index := k.GetProvisionsIndex(ctx, height-1)
+	if bondedTokens := k.sk.TotalBondedTokens(ctx); bondedTokens.IsPositive() {
+		index = index.Add(provisions.ToDec().QuoInt(bondedTokens))
+
+		k.sk.IterateBondedValidatorsByPower(ctx, func(_ int64, validator sdk.Validator) (stop bool) {
+			credit := validatorProvisions{
+				Provisions:      provisions.ToDec().MulInt(validator.GetBondedTokens()).QuoInt(bondedTokens),
+				DelegatorShares: validator.GetDelegatorShares(),
+			}
+			store.Set(getValidatorProvisionsKey(height, validator.GetOperator()), k.cdc.MustMarshalBinaryLengthPrefixed(credit))
+			return false
+		})
+	}
+	store.Set(getProvisionsIndexKey(height), k.cdc.MustMarshalBinaryLengthPrefixed(index))
+
+	// prune the records which left the retention window
+	cutoff := height - int64(retention) + 1
+	if cutoff <= 0 {
+		return
+	}
+	deleteRange(store, provisionsIndexKey, getProvisionsIndexKey(cutoff))
+	deleteRange(store, validatorProvisionsKey, getValidatorProvisionsKey(cutoff, nil))
+}
+
+// deleteRange deletes the keys between start, inclusive, and end, exclusive
+func deleteRange(store sdk.KVStore, start, end []byte) {
+	iterator := store.Iterator(start, end)
+	var keys [][]byte
+	for ; iterator.Valid(); iterator.Next() {
+		keys = append(keys, iterator.Key())
+	}
+	iterator.Close()
+	for _, key := range keys {
+		store.Delete(key)
+	}
+}
+
+// GetProvisionsIndex returns the provisions paid to bonded holders per bonded
+// token, accumulated up to and including the given height. Snapshots are kept
+// for the number of blocks set by the ProvisionsHistory parameter; heights
+// before the retained snapshots read as zero.
+func (k Keeper) GetProvisionsIndex(ctx sdk.Context, height int64) sdk.Dec {
+	if height < 0 {
+		return sdk.ZeroDec()
+	}
+
+	store := ctx.KVStore(k.storeKey)
+	iterator := store.ReverseIterator(provisionsIndexKey, getProvisionsIndexKey(height+1))
+	defer iterator.Close()
+	if !iterator.Valid() {
+		return sdk.ZeroDec()
+	}
+
+	var index sdk.Dec
+	k.cdc.MustUnmarshalBinaryLengthPrefixed(iterator.Value(), &index)
+	return index
+}
+
+// provisionsIndexRetained returns whether the records of the provisions index
+// at the given height have not been pruned yet, or were never recorded
+// because the height precedes the first block.
+func (k Keeper) provisionsIndexRetained(ctx sdk.Context, height int64) bool {
+	retention := int64(k.GetParams(ctx).ProvisionsHistory)
+	if retention == 0 {
+		return false
+	}
+	cutoff := ctx.BlockHeight() - retention + 1
+	return cutoff <= 1 || height >= cutoff
+}
+
+// validatorProvisions is the part of the provisions paid to bonded holders
+// which a validator was credited with at a block height, along with its
+// delegator shares at the time
+type validatorProvisions struct {
+	Provisions      sdk.Dec `json:"provisions"`
+	DelegatorShares sdk.Dec `json:"delegator_shares"`
+}
+
+// get the provisions credited to the validator at the given height
+func (k Keeper) getValidatorProvisions(ctx sdk.Context, valAddr sdk.ValAddress, height int64) (credit validatorProvisions, found bool) {
+	store := ctx.KVStore(k.storeKey)
+	bz := store.Get(getValidatorProvisionsKey(height, valAddr))
+	if bz == nil {
+		return credit, false
+	}
+	k.cdc.MustUnmarshalBinaryLengthPrefixed(bz, &credit)
+	return credit, true
+}
+
+// GetValidatorProvisionsInRange returns the validator's proportional share of
+// the provisions paid to bonded holders between the given heights, inclusive,
+// including the part owed to its delegators, at the bonded tokens it held at
+// each height. It returns false if the validator does not exist or the range
+// starts before the retained records of the provisions index.
+func (k Keeper) GetValidatorProvisionsInRange(ctx sdk.Context, ownerAddr sdk.ValAddress,
+	fromHeight, toHeight int64) (provisions sdk.Dec, found bool) {
+
+	validator := k.sk.Validator(ctx, ownerAddr)
+	if validator == nil || !k.provisionsIndexRetained(ctx, fromHeight) {
+		return sdk.ZeroDec(), false
+	}
+
+	if fromHeight < 1 {
+		fromHeight = 1
+	}
+	if toHeight > ctx.BlockHeight() {
+		toHeight = ctx.BlockHeight()
+	}
+	provisions = sdk.ZeroDec()
+	for height := fromHeight; height <= toHeight; height++ {
+		if credit, found := k.getValidatorProvisions(ctx, ownerAddr, height); found {
+			provisions = provisions.Add(credit.Provisions)
+		}
+	}
+	return provisions, true
+}
+
+// GetDelegatorNetYield returns the net return of the delegation from the
+// delegator to the validator from the given height up to the current one: the
+// provisions it was credited with, before commission, less its share of the
+// tokens burned by the validator's slashes, relative to the delegation's value
+// at the start of the period. The slashes and the value are taken at the
+// current shares of the delegation. It returns zero if the delegation does not
+// exist or the period starts before the retained records of the provisions
+// index.
+func (k Keeper) GetDelegatorNetYield(ctx sdk.Context, delAddr sdk.AccAddress, valAddr sdk.ValAddress, fromHeight int64) sdk.Dec {
+	validator := k.sk.Validator(ctx, valAddr)
+	delegation := k.sk.Delegation(ctx, delAddr, valAddr)
+	if validator == nil || delegation == nil || !validator.GetDelegatorShares().IsPositive() ||
+		!k.provisionsIndexRetained(ctx, fromHeight) {
+		return sdk.ZeroDec()
+	}
+
+	provisions := sdk.ZeroDec()
+	for _, event := range k.getDelegationProvisionsEvents(ctx, delAddr, valAddr, fromHeight) {
+		provisions = provisions.Add(event.Amount)
+	}
+	share := delegation.GetShares().Quo(validator.GetDelegatorShares())
+	losses := k.sk.GetValidatorSlashedTokensSince(ctx, valAddr, fromHeight).ToDec().Mul(share)
+
+	principal := validator.GetTokens().ToDec().Mul(share).Add(losses)
+	if !principal.IsPositive() {
+		return sdk.ZeroDec()
+	}
+	return provisions.Sub(losses).Quo(principal)
+}
+
+// ProvisionEvent is the share of the provisions paid to bonded holders which a
+// delegation was credited with at a block height
+type ProvisionEvent struct {
+	Height int64   `json:"height"`
+	Amount sdk.Dec `json:"amount"`
+}
+
+// GetDelegationProvisionsEvents returns the provisions the delegation from the
+// delegator to the validator was credited with at each height within the
+// retained records of the provisions index, before commission. Each amount is
+// the validator's credit at that height in proportion to the shares the
+// delegation held at the end of the previous block. Heights without
+// provisions are left out. It returns nil if the delegation does not exist.
+func (k Keeper) GetDelegationProvisionsEvents(ctx sdk.Context, delAddr sdk.AccAddress, valAddr sdk.ValAddress) []ProvisionEvent {
+	if k.sk.Delegation(ctx, delAddr, valAddr) == nil {
+		return nil
+	}
+	return k.getDelegationProvisionsEvents(ctx, delAddr, valAddr, 0)
+}
+
+// get the provisions events of the delegation from the given height, or the
+// first retained one, up to the current height
+func (k Keeper) getDelegationProvisionsEvents(ctx sdk.Context, delAddr sdk.AccAddress,
+	valAddr sdk.ValAddress, fromHeight int64) (events []ProvisionEvent) {
+
+	if retention := int64(k.GetParams(ctx).ProvisionsHistory); ctx.BlockHeight()-retention+1 > fromHeight {
+		fromHeight = ctx.BlockHeight() - retention + 1
+	}
+
+	// the shares recorded at a height are in force from the next one, until
+	// the height of the next record
+	store := ctx.KVStore(k.storeKey)
+	prefix := getDelegationSharesPrefix(valAddr, delAddr)
+	iterator := sdk.KVStorePrefixIterator(store, prefix)
+	defer iterator.Close()
+	for iterator.Valid() {
+		var shares sdk.Dec
+		k.cdc.MustUnmarshalBinaryLengthPrefixed(iterator.Value(), &shares)
+		from := int64(binary.BigEndian.Uint64(iterator.Key()[len(prefix):])) + 1
+		iterator.Next()
+		to := ctx.BlockHeight()
+		if iterator.Valid() {
+			to = int64(binary.BigEndian.Uint64(iterator.Key()[len(prefix):]))
+		}
+
+		if from < fromHeight {
+			from = fromHeight
+		}
+		for height := from; height <= to; height++ {
+			credit, found := k.getValidatorProvisions(ctx, valAddr, height)
+			if !found || !credit.Provisions.IsPositive() || !credit.DelegatorShares.IsPositive() {
+				continue
+			}
+			amount := credit.Provisions.Mul(shares).Quo(credit.DelegatorShares)
+			if amount.IsPositive() {
+				events = append(events, ProvisionEvent{height, amount})
+			}
+		}
+	}
+	return events
+}
+
+// recordDelegationShares stores the current shares of the delegation at the
+// current height, pruning its records which are no longer in force within
+// the retained records of the provisions index. Nothing is recorded while the
+// provisions history is disabled.
+func (k Keeper) recordDelegationShares(ctx sdk.Context, delAddr sdk.AccAddress, valAddr sdk.ValAddress) {
+	retention := k.GetParams(ctx).ProvisionsHistory
+	if retention == 0 {
+		return
+	}
+	delegation := k.sk.Delegation(ctx, delAddr, valAddr)
+	if delegation == nil {
+		return
+	}
+
+	store := ctx.KVStore(k.storeKey)
+	height := ctx.BlockHeight()
+	store.Set(getDelegationSharesKey(valAddr, delAddr, height), k.cdc.MustMarshalBinaryLengthPrefixed(delegation.GetShares()))
+
+	// the last record before the retention window stays in force at its start
+	cutoff := height - int64(retention) + 1
+	if cutoff <= 1 {
+		return
+	}
+	iterator := store.ReverseIterator(getDelegationSharesPrefix(valAddr, delAddr), getDelegationSharesKey(valAddr, delAddr, cutoff))
+	if iterator.Valid() {
+		iterator.Next()
+	}
+	var keys [][]byte
+	for ; iterator.Valid(); iterator.Next() {
+		keys = append(keys, iterator.Key())
+	}
+	iterator.Close()
+	for _, key := range keys {
+		store.Delete(key)
+	}
+}
+
+// deleteDelegationShares deletes all share records of the delegation
+func (k Keeper) deleteDelegationShares(ctx sdk.Context, delAddr sdk.AccAddress, valAddr sdk.ValAddress) {
+	store := ctx.KVStore(k.storeKey)
+	prefix := getDelegationSharesPrefix(valAddr, delAddr)
+	deleteRange(store, prefix, sdk.PrefixEndBytes(prefix))
+}
+
+// GetCumulativeProvisions returns the provisions minted since genesis. Unlike
+// the token supply, it excludes the supply the chain started with.
+func (k Keeper) GetCumulativeProvisions(ctx sdk.Context) sdk.Int {
+	return k.GetMinter(ctx).CumulativeProvisions
+}
+
+// InflationRecord is the inflation rate in effect at a block height
+type InflationRecord struct {
+	Height    int64   `json:"height"`
+	Inflation sdk.Dec `json:"inflation"`
+}
+
+// RecordInflation stores the inflation rate of the current block and prunes
+// the records older than the given number of blocks. A zero retention
+// disables the history.
+func (k Keeper) RecordInflation(ctx sdk.Context, inflation sdk.Dec, retention uint64) {
+	if retention == 0 {
+		return
+	}
+
+	store := ctx.KVStore(k.storeKey)
+	height := ctx.BlockHeight()
+	store.Set(getInflationHistoryKey(height), k.cdc.MustMarshalBinaryLengthPrefixed(inflation))
+
+	// prune the records which left the retention window
+	cutoff := height - int64(retention) + 1
+	if cutoff <= 0 {
+		return
+	}
+	deleteRange(store, inflationHistoryKey, getInflationHistoryKey(cutoff))
+}
+
+// GetInflationHistory returns the recorded inflation rates between the given
+// heights, inclusive, in ascending height order.
+func (k Keeper) GetInflationHistory(ctx sdk.Context, from, to int64) (records []InflationRecord) {
+	if from < 0 {
+		from = 0
+	}
+	if to < from {
+		return records
+	}
+
+	store := ctx.KVStore(k.storeKey)
+	end := sdk.PrefixEndBytes(inflationHistoryKey)
+	if to < math.MaxInt64 {
+		end = getInflationHistoryKey(to + 1)
+	}
+	iterator := store.Iterator(getInflationHistoryKey(from), end)
+	defer iterator.Close()
+
+	for ; iterator.Valid(); iterator.Next() {
+		var inflation sdk.Dec
+		k.cdc.MustUnmarshalBinaryLengthPrefixed(iterator.Value(), &inflation)
+		height := int64(binary.BigEndian.Uint64(iterator.Key()[len(inflationHistoryKey):]))
+		records = append(records, InflationRecord{height, inflation})
+	}
+	return records
+}
+
+// EstimateDelegatorAPR estimates the annual return of delegating to a
+// validator from the current inflation, the bonded ratio and the validator's
+// commission, as inflation / bondedRatio * (1 - commission). Fees and other
+// reward sources are not taken into account. It returns zero if no tokens
+// are bonded or the validator does not exist.
+func (k Keeper) EstimateDelegatorAPR(ctx sdk.Context, valAddr sdk.ValAddress) sdk.Dec {
+	validator := k.sk.Validator(ctx, valAddr)
+	bondedRatio := k.sk.BondedRatio(ctx)
+	if validator == nil || !bondedRatio.IsPositive() {
+		return sdk.ZeroDec()
+	}
+
+	inflation := k.GetMinter(ctx).Inflation
+	return inflation.Quo(bondedRatio).Mul(sdk.OneDec().Sub(validator.GetCommission()))
+}
+
+// EstimateRiskAdjustedAPR estimates the annual return of delegating to a
+// validator as EstimateDelegatorAPR less the expected slashing cost given by
+// the slash risk estimator. The estimated risk is scaled from its period to
+// BlocksPerYear, and capped at the whole stake. Without an estimator it equals
+// the nominal APR.
+func (k Keeper) EstimateRiskAdjustedAPR(ctx sdk.Context, valAddr sdk.ValAddress) sdk.Dec {
+	apr := k.EstimateDelegatorAPR(ctx, valAddr)
+	if k.slashRiskEstimator == nil || k.sk.Validator(ctx, valAddr) == nil {
+		return apr
+	}
+
+	risk, blocks := k.slashRiskEstimator(ctx, valAddr)
+	if blocks <= 0 {
+		return apr
+	}
+	annualRisk := risk.MulInt64(int64(k.GetParams(ctx).BlocksPerYear)).QuoInt64(blocks)
+	if annualRisk.GT(sdk.OneDec()) {
+		annualRisk = sdk.OneDec()
+	}
+	return apr.Sub(annualRisk)
+}
+
 //______________________________________________________________________
 
 // GetParams returns the total set of slashing parameters.
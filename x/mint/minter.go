@@ -10,6 +10,10 @@ import (
 type Minter struct {
 	Inflation        sdk.Dec `json:"inflation"`         // current annual inflation rate
 	AnnualProvisions sdk.Dec `json:"annual_provisions"` // current annual expected provisions
+	Reserve          sdk.Int `json:"reserve"`           // provisions paid to the reserve in the community pool since genesis
+
+	CumulativeProvisions sdk.Int `json:"cumulative_provisions"` // provisions minted since genesis, excluding the genesis supply
+	ProvisionsRemainder  sdk.Dec `json:"provisions_remainder"`  // fraction of a token truncated from the provisions, carried to the next block
 }
 
 // NewMinter returns a new Minter object with the given inflation and annual
@@ -18,6 +22,10 @@ func NewMinter(inflation, annualProvisions sdk.Dec) Minter {
 	return Minter{
 		Inflation:        inflation,
 		AnnualProvisions: annualProvisions,
+		Reserve:          sdk.ZeroInt(),
+
+		CumulativeProvisions: sdk.ZeroInt(),
+		ProvisionsRemainder:  sdk.ZeroDec(),
 	}
 }
 
@@ -42,10 +50,25 @@ func validateMinter(minter Minter) error {
 		return fmt.Errorf("mint parameter Inflation should be positive, is %s",
 			minter.Inflation.String())
 	}
+	if !minter.Reserve.IsNil() && minter.Reserve.IsNegative() {
+		return fmt.Errorf("mint parameter Reserve should be positive, is %s",
+			minter.Reserve.String())
+	}
+	if !minter.CumulativeProvisions.IsNil() && minter.CumulativeProvisions.IsNegative() {
+		return fmt.Errorf("mint parameter CumulativeProvisions should be positive, is %s",
+			minter.CumulativeProvisions.String())
+	}
+	if !minter.ProvisionsRemainder.IsNil() &&
+		(minter.ProvisionsRemainder.IsNegative() || minter.ProvisionsRemainder.GTE(sdk.OneDec())) {
+		return fmt.Errorf("mint parameter ProvisionsRemainder must be between 0 and 1, is %s",
+			minter.ProvisionsRemainder.String())
+	}
 	return nil
 }
 
-// NextInflationRate returns the new inflation rate for the next hour.
+// NextInflationRate returns the new inflation rate for the next hour. With
+// inflation smoothing over N blocks, the inflation only moves by 1/N of the
+// distance to its target each block.
 func (m Minter) NextInflationRate(params Params, bondedRatio sdk.Dec) sdk.Dec {
 	// The target annual inflation rate is recalculated for each previsions cycle. The
 	// inflation is also subject to a rate change (positive or negative) depending on
@@ -68,6 +91,11 @@ func (m Minter) NextInflationRate(params Params, bondedRatio sdk.Dec) sdk.Dec {
 		inflation = params.InflationMin
 	}
 
+	if params.InflationSmoothingBlocks > 1 {
+		step := inflation.Sub(m.Inflation).QuoInt64(int64(params.InflationSmoothingBlocks))
+		inflation = m.Inflation.Add(step)
+	}
+
 	return inflation
 }
 
@@ -78,8 +106,33 @@ func (m Minter) NextAnnualProvisions(_ Params, totalSupply sdk.Int) sdk.Dec {
 }
 
 // BlockProvision returns the provisions for a block based on the annual
-// provisions rate.
+// provisions rate, capped at the maximum annual provisions if one is set.
 func (m Minter) BlockProvision(params Params) sdk.Coin {
-	provisionAmt := m.AnnualProvisions.QuoInt(sdk.NewInt(int64(params.BlocksPerYear)))
-	return sdk.NewCoin(params.MintDenom, provisionAmt.TruncateInt())
+	return sdk.NewCoin(params.MintDenom, m.blockProvisionAmount(params).TruncateInt())
+}
+
+// CarriedBlockProvision returns the provisions for a block together with the
+// remainder carried from the previous blocks, along with the fraction of a
+// token truncated from them which is carried to the next block. Over a year
+// the provisions thereby add up to the annual provisions.
+func (m Minter) CarriedBlockProvision(params Params) (sdk.Coin, sdk.Dec) {
+	provisionAmt := m.blockProvisionAmount(params).Add(m.ProvisionsRemainder)
+	minted := provisionAmt.TruncateInt()
+	return sdk.NewCoin(params.MintDenom, minted), provisionAmt.Sub(minted.ToDec())
+}
+
+func (m Minter) blockProvisionAmount(params Params) sdk.Dec {
+	annualProvisions := m.AnnualProvisions
+	if params.MaxAnnualProvisions > 0 {
+		annualProvisions = sdk.MinDec(annualProvisions, sdk.NewDec(params.MaxAnnualProvisions))
+	}
+	return annualProvisions.QuoInt(sdk.NewInt(int64(params.BlocksPerYear)))
+}
+
+// SplitProvision splits the provision of a block into the part paid to bonded
+// holders and the part paid to the reserve, according to the bonded
+// provisions fraction. Rounding favors the reserve.
+func (m Minter) SplitProvision(params Params, provision sdk.Coin) (bonded sdk.Coin, reserve sdk.Int) {
+	bondedAmt := params.BondedProvisionsFraction.MulInt(provision.Amount).TruncateInt()
+	return sdk.NewCoin(provision.Denom, bondedAmt), provision.Amount.Sub(bondedAmt)
 }
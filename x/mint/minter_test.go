@@ -7,6 +7,8 @@ import (
 	"github.com/stretchr/testify/require"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/cosmos-sdk/x/staking"
 )
 
 func TestNextInflation(t *testing.T) {
@@ -54,6 +56,333 @@ func TestNextInflation(t *testing.T) {
 	}
 }
 
+func TestNextInflationSmoothing(t *testing.T) {
+	params := DefaultParams()
+	params.InflationMax = sdk.NewDecWithPrec(10, 2)
+	bondedRatio := sdk.ZeroDec()
+	target := params.InflationMax
+	start := sdk.NewDecWithPrec(20, 2)
+
+	// without smoothing the inflation jumps to its target
+	minter := InitialMinter(start)
+	require.Equal(t, target, minter.NextInflationRate(params, bondedRatio))
+
+	// with smoothing over 10 blocks it moves a tenth of the way each block
+	params.InflationSmoothingBlocks = 10
+	tolerance := sdk.NewDecWithPrec(1, 5)
+	for i := 0; i < 100; i++ {
+		inflation := minter.NextInflationRate(params, bondedRatio)
+		require.True(t, inflation.LT(minter.Inflation), "inflation should decrease at block %d", i)
+		require.True(t, inflation.GT(target), "inflation should not overshoot at block %d", i)
+		if i == 0 {
+			require.Equal(t, sdk.NewDecWithPrec(19, 2), inflation)
+		}
+		minter.Inflation = inflation
+	}
+	require.True(t, minter.Inflation.Sub(target).LT(tolerance))
+}
+
+func TestNextInflationAdjusted(t *testing.T) {
+	input := newTestInput(t)
+	minter := input.mintKeeper.GetMinter(input.ctx)
+	params := input.mintKeeper.GetParams(input.ctx)
+	bondedRatio := sdk.NewDecWithPrec(5, 1)
+
+	// without an adjuster the base inflation applies
+	base := minter.NextInflationRate(params, bondedRatio)
+	require.Equal(t, base, input.mintKeeper.NextInflationRate(input.ctx, minter, params, bondedRatio))
+
+	// cap the inflation below the base
+	inflationCap := base.Quo(sdk.NewDec(2))
+	input.mintKeeper.SetInflationAdjuster(func(_ sdk.Context, baseInflation sdk.Dec) sdk.Dec {
+		if baseInflation.GT(inflationCap) {
+			return inflationCap
+		}
+		return baseInflation
+	})
+	require.Equal(t, inflationCap, input.mintKeeper.NextInflationRate(input.ctx, minter, params, bondedRatio))
+
+	BeginBlocker(input.ctx, input.mintKeeper)
+	require.Equal(t, inflationCap, input.mintKeeper.GetMinter(input.ctx).Inflation)
+}
+
+func TestBondedProvisionsFraction(t *testing.T) {
+	input := newTestInput(t)
+	params := input.mintKeeper.GetParams(input.ctx)
+	minter := input.mintKeeper.GetMinter(input.ctx)
+	provision := sdk.NewInt64Coin(params.MintDenom, 1000)
+
+	// by default all provisions go to bonded holders
+	bonded, reserve := minter.SplitProvision(params, provision)
+	require.True(t, provision.IsEqual(bonded))
+	require.True(t, reserve.IsZero())
+
+	params.BondedProvisionsFraction = sdk.NewDecWithPrec(9, 1)
+	input.mintKeeper.SetParams(input.ctx, params)
+	bonded, reserve = minter.SplitProvision(params, provision)
+	require.Equal(t, sdk.NewInt(900), bonded.Amount)
+	require.Equal(t, sdk.NewInt(100), reserve)
+
+	// 10% of the minted provision lands in the reserve in the community pool
+	pool := input.stakingKeeper.GetPool(input.ctx)
+	pool.NotBondedTokens = sdk.TokensFromTendermintPower(1000000)
+	input.stakingKeeper.SetPool(input.ctx, pool)
+	BeginBlocker(input.ctx, input.mintKeeper)
+
+	minter = input.mintKeeper.GetMinter(input.ctx)
+	minted := minter.BlockProvision(params).Amount
+	require.True(t, minted.IsPositive())
+	collected := input.mintKeeper.fck.(auth.FeeCollectionKeeper).GetCollectedFees(input.ctx).AmountOf(params.MintDenom)
+	require.Equal(t, minted, collected.Add(minter.Reserve))
+	require.Equal(t, minted.Sub(sdk.NewDecWithPrec(9, 1).MulInt(minted).TruncateInt()), minter.Reserve)
+	communityPool := input.distrKeeper.GetFeePoolCommunityCoins(input.ctx).AmountOf(params.MintDenom)
+	require.Equal(t, minter.Reserve.ToDec(), communityPool)
+
+	// both parts are added to the token supply
+	require.Equal(t, pool.NotBondedTokens.Add(minted), input.stakingKeeper.GetPool(input.ctx).NotBondedTokens)
+}
+
+func TestValidateGenesisUnsetFields(t *testing.T) {
+	input := newTestInput(t)
+
+	// missing counters are treated as zero
+	data := DefaultGenesisState()
+	data.Minter.Reserve = sdk.Int{}
+	data.Minter.CumulativeProvisions = sdk.Int{}
+	data.Minter.ProvisionsRemainder = sdk.Dec{}
+	require.Nil(t, ValidateGenesis(data))
+	InitGenesis(input.ctx, input.mintKeeper, data)
+	require.True(t, input.mintKeeper.GetMinter(input.ctx).Reserve.IsZero())
+	require.True(t, input.mintKeeper.GetMinter(input.ctx).ProvisionsRemainder.IsZero())
+	require.True(t, input.mintKeeper.GetCumulativeProvisions(input.ctx).IsZero())
+
+	// a missing bonded provisions fraction is rejected
+	data = DefaultGenesisState()
+	data.Params.BondedProvisionsFraction = sdk.Dec{}
+	require.NotNil(t, ValidateGenesis(data))
+}
+
+func TestProcessProvisions(t *testing.T) {
+	input := newTestInput(t)
+	params := input.mintKeeper.GetParams(input.ctx)
+	minter := input.mintKeeper.GetMinter(input.ctx)
+	minter.AnnualProvisions = sdk.NewDec(int64(params.BlocksPerYear) * 10)
+	input.mintKeeper.SetMinter(input.ctx, minter)
+	pool := input.stakingKeeper.GetPool(input.ctx)
+
+	// the provisions are minted in the bond denom and inflate the supply
+	minted := input.mintKeeper.ProcessProvisions(input.ctx)
+	require.Equal(t, input.stakingKeeper.BondDenom(input.ctx), minted.Denom)
+	require.Equal(t, sdk.NewInt64Coin(params.MintDenom, 10), minted)
+	collected := input.mintKeeper.fck.(auth.FeeCollectionKeeper).GetCollectedFees(input.ctx)
+	require.Equal(t, sdk.Coins{minted}, collected)
+	require.Equal(t, pool.NotBondedTokens.Add(minted.Amount), input.stakingKeeper.GetPool(input.ctx).NotBondedTokens)
+
+	// coins of another denom leave the staking token supply untouched
+	params.MintDenom = "othertoken"
+	input.mintKeeper.SetParams(input.ctx, params)
+	pool = input.stakingKeeper.GetPool(input.ctx)
+	minted = input.mintKeeper.ProcessProvisions(input.ctx)
+	require.Equal(t, sdk.NewInt64Coin("othertoken", 10), minted)
+	require.Equal(t, pool, input.stakingKeeper.GetPool(input.ctx))
+}
+
+func TestProcessProvisionsMaxTokenSupply(t *testing.T) {
+	input := newTestInput(t)
+	params := input.mintKeeper.GetParams(input.ctx)
+	minter := input.mintKeeper.GetMinter(input.ctx)
+	minter.AnnualProvisions = sdk.NewDec(int64(params.BlocksPerYear) * 10)
+	input.mintKeeper.SetMinter(input.ctx, minter)
+
+	// leave room for two full provisions and a partial one
+	supply := input.stakingKeeper.TotalTokens(input.ctx)
+	params.MaxTokenSupply = supply.Int64() + 24
+	input.mintKeeper.SetParams(input.ctx, params)
+
+	expected := []int64{10, 10, 4, 0, 0}
+	for i, exp := range expected {
+		minted := input.mintKeeper.ProcessProvisions(input.ctx)
+		require.Equal(t, sdk.NewInt64Coin(params.MintDenom, exp), minted, "block %d", i)
+	}
+	require.Equal(t, params.MaxTokenSupply, input.stakingKeeper.TotalTokens(input.ctx).Int64())
+	require.Equal(t, sdk.NewInt(24), input.mintKeeper.GetCumulativeProvisions(input.ctx))
+}
+
+func TestCumulativeProvisions(t *testing.T) {
+	input := newTestInput(t)
+	params := input.mintKeeper.GetParams(input.ctx)
+	minter := input.mintKeeper.GetMinter(input.ctx)
+	minter.AnnualProvisions = sdk.NewDec(int64(params.BlocksPerYear) * 7)
+	input.mintKeeper.SetMinter(input.ctx, minter)
+	require.True(t, input.mintKeeper.GetCumulativeProvisions(input.ctx).IsZero())
+
+	// run provisions for several hours of blocks
+	blocks := int64(params.BlocksPerYear / 8766 * 3)
+	sum := sdk.ZeroInt()
+	for i := int64(0); i < blocks; i++ {
+		ctx := input.ctx.WithBlockHeight(i + 1)
+		sum = sum.Add(input.mintKeeper.GetMinter(ctx).BlockProvision(params).Amount)
+		input.mintKeeper.ProcessProvisions(ctx)
+	}
+	require.Equal(t, sdk.NewInt(7*blocks), sum)
+	require.Equal(t, sum, input.mintKeeper.GetCumulativeProvisions(input.ctx))
+}
+
+func TestCarryProvisionsRemainder(t *testing.T) {
+	input := newTestInput(t)
+	params := input.mintKeeper.GetParams(input.ctx)
+	params.BlocksPerYear = 8766 // hourly blocks
+	params.CarryProvisionsRemainder = true
+	input.mintKeeper.SetParams(input.ctx, params)
+	minter := input.mintKeeper.GetMinter(input.ctx)
+	target := sdk.NewInt(100000)
+	minter.AnnualProvisions = target.ToDec()
+	input.mintKeeper.SetMinter(input.ctx, minter)
+
+	// truncating each block on its own would fall short by thousands of tokens
+	require.Equal(t, sdk.NewInt(11*8766), minter.BlockProvision(params).Amount.MulRaw(8766))
+
+	sum := sdk.ZeroInt()
+	for i := int64(0); i < int64(params.BlocksPerYear); i++ {
+		ctx := input.ctx.WithBlockHeight(i + 1)
+		sum = sum.Add(input.mintKeeper.ProcessProvisions(ctx).Amount)
+	}
+	drift := target.Sub(sum).Int64()
+	require.True(t, drift >= -1 && drift <= 1, "minted %s", sum)
+	require.Equal(t, sum, input.mintKeeper.GetCumulativeProvisions(input.ctx))
+
+	minter = input.mintKeeper.GetMinter(input.ctx)
+	require.False(t, minter.ProvisionsRemainder.IsNegative())
+	require.True(t, minter.ProvisionsRemainder.LT(sdk.OneDec()))
+}
+
+func TestValidatorProvisionsInRange(t *testing.T) {
+	input := newTestInput(t)
+	params := input.mintKeeper.GetParams(input.ctx)
+	params.ProvisionsHistory = 4
+	input.mintKeeper.SetParams(input.ctx, params)
+	minter := input.mintKeeper.GetMinter(input.ctx)
+	minter.AnnualProvisions = sdk.NewDec(int64(params.BlocksPerYear) * 100)
+	input.mintKeeper.SetMinter(input.ctx, minter)
+
+	// a validator holding a quarter of the bonded tokens
+	pool := staking.InitialPool()
+	pool.BondedTokens = sdk.NewInt(1000)
+	input.stakingKeeper.SetPool(input.ctx, pool)
+	valAddr := sdk.ValAddress(pk.Address())
+	validator := staking.NewValidator(valAddr, pk, staking.Description{})
+	validator.Status = sdk.Bonded
+	validator.Tokens = sdk.NewInt(250)
+	input.stakingKeeper.SetValidator(input.ctx, validator)
+	input.stakingKeeper.SetNewValidatorByPowerIndex(input.ctx, validator)
+
+	// the whole history is available until the first records are pruned
+	for height := int64(1); height <= 4; height++ {
+		input.mintKeeper.ProcessProvisions(input.ctx.WithBlockHeight(height))
+	}
+	provisions, found := input.mintKeeper.GetValidatorProvisionsInRange(input.ctx.WithBlockHeight(4), valAddr, 1, 4)
+	require.True(t, found)
+	require.Equal(t, sdk.NewDec(100), provisions)
+
+	for height := int64(5); height <= 6; height++ {
+		input.mintKeeper.ProcessProvisions(input.ctx.WithBlockHeight(height))
+	}
+
+	// each block pays 100 to bonded holders, 25 of which to the validator
+	ctx := input.ctx.WithBlockHeight(6)
+	for _, tc := range []struct {
+		from, to   int64
+		provisions int64
+		found      bool
+	}{
+		{4, 6, 75, true},
+		{5, 5, 25, true},
+		{5, 10, 50, true},
+		{6, 5, 0, true},
+		{3, 6, 100, true},
+		{2, 6, 0, false}, // the records of height 2 were pruned
+		{1, 6, 0, false},
+	} {
+		provisions, found := input.mintKeeper.GetValidatorProvisionsInRange(ctx, valAddr, tc.from, tc.to)
+		require.Equal(t, tc.found, found, "range %d-%d", tc.from, tc.to)
+		require.Equal(t, sdk.NewDec(tc.provisions), provisions, "range %d-%d", tc.from, tc.to)
+	}
+	_, found = input.mintKeeper.GetValidatorProvisionsInRange(ctx, sdk.ValAddress(pk2.Address()), 4, 6)
+	require.False(t, found)
+
+	// only the snapshots of the last blocks are kept
+	require.True(t, input.mintKeeper.GetProvisionsIndex(input.ctx, 2).IsZero())
+	require.Equal(t, sdk.NewDecWithPrec(3, 1), input.mintKeeper.GetProvisionsIndex(input.ctx, 3))
+	require.Equal(t, sdk.NewDecWithPrec(6, 1), input.mintKeeper.GetProvisionsIndex(input.ctx, 6))
+
+	// once the validator holds half of the bonded tokens it is credited 50
+	// per block, without changing what it was credited before
+	validator.Tokens = sdk.NewInt(500)
+	input.stakingKeeper.SetValidator(input.ctx, validator)
+	for height := int64(7); height <= 8; height++ {
+		input.mintKeeper.ProcessProvisions(input.ctx.WithBlockHeight(height))
+	}
+	provisions, found = input.mintKeeper.GetValidatorProvisionsInRange(input.ctx.WithBlockHeight(8), valAddr, 5, 8)
+	require.True(t, found)
+	require.Equal(t, sdk.NewDec(150), provisions)
+
+	// nothing is recorded once the history is disabled, as by default
+	params.ProvisionsHistory = DefaultParams().ProvisionsHistory
+	input.mintKeeper.SetParams(input.ctx, params)
+	input.mintKeeper.ProcessProvisions(input.ctx.WithBlockHeight(9))
+	_, found = input.mintKeeper.getValidatorProvisions(input.ctx, valAddr, 9)
+	require.False(t, found)
+	_, found = input.mintKeeper.GetValidatorProvisionsInRange(input.ctx.WithBlockHeight(9), valAddr, 9, 9)
+	require.False(t, found)
+}
+
+func TestDelegatorNetYield(t *testing.T) {
+	input := newTestInput(t)
+	params := input.mintKeeper.GetParams(input.ctx)
+	params.ProvisionsHistory = 10
+	input.mintKeeper.SetParams(input.ctx, params)
+	minter := input.mintKeeper.GetMinter(input.ctx)
+	minter.AnnualProvisions = sdk.NewDec(int64(params.BlocksPerYear)).MulInt(sdk.TokensFromTendermintPower(1))
+	input.mintKeeper.SetMinter(input.ctx, minter)
+
+	// a validator holding a quarter of the bonded tokens, 40% of which are
+	// delegated by delAddr
+	pool := staking.InitialPool()
+	pool.BondedTokens = sdk.TokensFromTendermintPower(1000)
+	input.stakingKeeper.SetPool(input.ctx, pool)
+	valAddr := sdk.ValAddress(pk.Address())
+	validator := staking.NewValidator(valAddr, pk, staking.Description{})
+	validator.Status = sdk.Bonded
+	validator.Tokens = sdk.TokensFromTendermintPower(250)
+	validator.DelegatorShares = validator.Tokens.ToDec()
+	input.stakingKeeper.SetValidator(input.ctx, validator)
+	input.stakingKeeper.SetValidatorByConsAddr(input.ctx, validator)
+	input.stakingKeeper.SetNewValidatorByPowerIndex(input.ctx, validator)
+	delAddr := sdk.AccAddress(pk2.Address())
+	input.stakingKeeper.SetDelegation(input.ctx, staking.NewDelegation(delAddr, valAddr, sdk.TokensFromTendermintPower(100).ToDec()))
+	input.mintKeeper.Hooks().AfterDelegationModified(input.ctx, delAddr, valAddr)
+
+	// each block pays one power of tokens to bonded holders
+	for height := int64(1); height <= 5; height++ {
+		input.mintKeeper.ProcessProvisions(input.ctx.WithBlockHeight(height))
+	}
+	ctx := input.ctx.WithBlockHeight(5)
+	yield := input.mintKeeper.GetDelegatorNetYield(ctx, delAddr, valAddr, 1)
+	require.Equal(t, sdk.NewDecWithPrec(5, 3), yield, "expected 0.005, got %v", yield)
+
+	// a 10% slash burns 25 power from the validator, 10 of it from the delegation
+	ctx = input.ctx.WithBlockHeight(6)
+	input.stakingKeeper.Slash(ctx, sdk.GetConsAddress(pk), 6, 250, sdk.NewDecWithPrec(1, 1))
+
+	// provisions of 0.5 power, credited at the stake before the slash, less a
+	// 10 power loss on a 100 power principal
+	yield = input.mintKeeper.GetDelegatorNetYield(ctx, delAddr, valAddr, 1)
+	require.Equal(t, sdk.NewDecWithPrec(-95, 3), yield, "expected -0.095, got %v", yield)
+
+	// a missing delegation yields nothing
+	require.True(t, input.mintKeeper.GetDelegatorNetYield(ctx, delAddr, sdk.ValAddress(pk2.Address()), 1).IsZero())
+}
+
 func TestBlockProvision(t *testing.T) {
 	minter := InitialMinter(sdk.NewDecWithPrec(1, 1))
 	params := DefaultParams()
@@ -82,6 +411,24 @@ func TestBlockProvision(t *testing.T) {
 	}
 }
 
+func TestBlockProvisionMaxAnnualProvisions(t *testing.T) {
+	minter := InitialMinter(sdk.NewDecWithPrec(1, 1))
+	params := DefaultParams()
+	blocksPerYear := int64(params.BlocksPerYear)
+	minter.AnnualProvisions = sdk.NewDec(blocksPerYear * 100)
+
+	// unlimited by default
+	require.Equal(t, sdk.NewInt64Coin(params.MintDenom, 100), minter.BlockProvision(params))
+
+	// the per-block provisions are clamped to the cap
+	params.MaxAnnualProvisions = blocksPerYear * 3
+	require.Equal(t, sdk.NewInt64Coin(params.MintDenom, 3), minter.BlockProvision(params))
+
+	// a cap above the annual provisions has no effect
+	params.MaxAnnualProvisions = blocksPerYear * 1000
+	require.Equal(t, sdk.NewInt64Coin(params.MintDenom, 100), minter.BlockProvision(params))
+}
+
 // Benchmarking :)
 // previously using sdk.Int operations:
 // BenchmarkBlockProvision-4 5000000 220 ns/op
@@ -129,3 +476,74 @@ func BenchmarkNextAnnualProvisions(b *testing.B) {
 	}
 
 }
+
+func TestGetDelegationProvisionsEvents(t *testing.T) {
+	input := newTestInput(t)
+	params := input.mintKeeper.GetParams(input.ctx)
+	params.ProvisionsHistory = 10
+	input.mintKeeper.SetParams(input.ctx, params)
+	minter := input.mintKeeper.GetMinter(input.ctx)
+	minter.AnnualProvisions = sdk.NewDec(int64(params.BlocksPerYear)).MulInt(sdk.TokensFromTendermintPower(1))
+	input.mintKeeper.SetMinter(input.ctx, minter)
+
+	// a validator holding a quarter of the bonded tokens, 40% of which are
+	// delegated by delAddr
+	pool := staking.InitialPool()
+	pool.BondedTokens = sdk.TokensFromTendermintPower(1000)
+	input.stakingKeeper.SetPool(input.ctx, pool)
+	valAddr := sdk.ValAddress(pk.Address())
+	validator := staking.NewValidator(valAddr, pk, staking.Description{})
+	validator.Status = sdk.Bonded
+	validator.Tokens = sdk.TokensFromTendermintPower(250)
+	validator.DelegatorShares = validator.Tokens.ToDec()
+	input.stakingKeeper.SetValidator(input.ctx, validator)
+	input.stakingKeeper.SetNewValidatorByPowerIndex(input.ctx, validator)
+	delAddr := sdk.AccAddress(pk2.Address())
+	input.stakingKeeper.SetDelegation(input.ctx, staking.NewDelegation(delAddr, valAddr, sdk.TokensFromTendermintPower(100).ToDec()))
+	input.mintKeeper.Hooks().AfterDelegationModified(input.ctx, delAddr, valAddr)
+	require.Empty(t, input.mintKeeper.GetDelegationProvisionsEvents(input.ctx, delAddr, valAddr))
+
+	// each block pays one power of tokens to bonded holders, a tenth of which
+	// goes to the delegation
+	for height := int64(1); height <= 2; height++ {
+		input.mintKeeper.ProcessProvisions(input.ctx.WithBlockHeight(height))
+	}
+	expected := []ProvisionEvent{
+		{1, sdk.TokensFromTendermintPower(1).ToDec().QuoInt64(10)},
+		{2, sdk.TokensFromTendermintPower(1).ToDec().QuoInt64(10)},
+	}
+	require.Equal(t, expected, input.mintKeeper.GetDelegationProvisionsEvents(input.ctx.WithBlockHeight(2), delAddr, valAddr))
+
+	// once the first records are pruned the events start at the first
+	// retained height
+	for height := int64(3); height <= 12; height++ {
+		input.mintKeeper.ProcessProvisions(input.ctx.WithBlockHeight(height))
+	}
+	events := input.mintKeeper.GetDelegationProvisionsEvents(input.ctx.WithBlockHeight(12), delAddr, valAddr)
+	require.Len(t, events, 10)
+	require.Equal(t, ProvisionEvent{3, sdk.TokensFromTendermintPower(1).ToDec().QuoInt64(10)}, events[0])
+
+	// the delegation grows to 60% of the validator's shares after the
+	// provisions of height 13, so only the next block credits it 0.15 power
+	ctx := input.ctx.WithBlockHeight(13)
+	input.mintKeeper.ProcessProvisions(ctx)
+	input.stakingKeeper.SetDelegation(ctx, staking.NewDelegation(delAddr, valAddr, sdk.TokensFromTendermintPower(150).ToDec()))
+	input.mintKeeper.Hooks().AfterDelegationModified(ctx, delAddr, valAddr)
+	input.mintKeeper.ProcessProvisions(input.ctx.WithBlockHeight(14))
+	events = input.mintKeeper.GetDelegationProvisionsEvents(input.ctx.WithBlockHeight(14), delAddr, valAddr)
+	require.Len(t, events, 10)
+	require.Equal(t, ProvisionEvent{13, sdk.TokensFromTendermintPower(1).ToDec().QuoInt64(10)}, events[8])
+	require.Equal(t, ProvisionEvent{14, sdk.TokensFromTendermintPower(15).ToDec().QuoInt64(100)}, events[9])
+
+	// a missing delegation has no events
+	require.Nil(t, input.mintKeeper.GetDelegationProvisionsEvents(input.ctx, delAddr, sdk.ValAddress(pk2.Address())))
+
+	// the shares of the delegation are not recorded once the history is
+	// disabled
+	params.ProvisionsHistory = 0
+	input.mintKeeper.SetParams(input.ctx, params)
+	ctx = input.ctx.WithBlockHeight(15)
+	input.mintKeeper.Hooks().AfterDelegationModified(ctx, delAddr, valAddr)
+	store := ctx.KVStore(input.mintKeeper.storeKey)
+	require.Nil(t, store.Get(getDelegationSharesKey(valAddr, delAddr, 15)))
+}
@@ -9,22 +9,36 @@ import (
 
 // Parameter store keys
 var (
-	KeyMintDenom           = []byte("MintDenom")
-	KeyInflationRateChange = []byte("InflationRateChange")
-	KeyInflationMax        = []byte("InflationMax")
-	KeyInflationMin        = []byte("InflationMin")
-	KeyGoalBonded          = []byte("GoalBonded")
-	KeyBlocksPerYear       = []byte("BlocksPerYear")
+	KeyMintDenom                = []byte("MintDenom")
+	KeyInflationRateChange      = []byte("InflationRateChange")
+	KeyInflationMax             = []byte("InflationMax")
+	KeyInflationMin             = []byte("InflationMin")
+	KeyGoalBonded               = []byte("GoalBonded")
+	KeyBlocksPerYear            = []byte("BlocksPerYear")
+	KeyInflationHistory         = []byte("InflationHistory")
+	KeyBondedProvisionsFraction = []byte("BondedProvisionsFraction")
+	KeyInflationSmoothingBlocks = []byte("InflationSmoothingBlocks")
+	KeyMaxAnnualProvisions      = []byte("MaxAnnualProvisions")
+	KeyMaxTokenSupply           = []byte("MaxTokenSupply")
+	KeyCarryProvisionsRemainder = []byte("CarryProvisionsRemainder")
+	KeyProvisionsHistory        = []byte("ProvisionsHistory")
 )
 
 // mint parameters
 type Params struct {
-	MintDenom           string  `json:"mint_denom"`            // type of coin to mint
-	InflationRateChange sdk.Dec `json:"inflation_rate_change"` // maximum annual change in inflation rate
-	InflationMax        sdk.Dec `json:"inflation_max"`         // maximum inflation rate
-	InflationMin        sdk.Dec `json:"inflation_min"`         // minimum inflation rate
-	GoalBonded          sdk.Dec `json:"goal_bonded"`           // goal of percent bonded atoms
-	BlocksPerYear       uint64  `json:"blocks_per_year"`       // expected blocks per year
+	MintDenom                string  `json:"mint_denom"`                 // type of coin to mint
+	InflationRateChange      sdk.Dec `json:"inflation_rate_change"`      // maximum annual change in inflation rate
+	InflationMax             sdk.Dec `json:"inflation_max"`              // maximum inflation rate
+	InflationMin             sdk.Dec `json:"inflation_min"`              // minimum inflation rate
+	GoalBonded               sdk.Dec `json:"goal_bonded"`                // goal of percent bonded atoms
+	BlocksPerYear            uint64  `json:"blocks_per_year"`            // expected blocks per year
+	InflationHistory         uint64  `json:"inflation_history"`          // number of blocks for which the inflation rate is kept, zero disables the history
+	BondedProvisionsFraction sdk.Dec `json:"bonded_provisions_fraction"` // fraction of provisions paid to bonded holders, the rest goes to the reserve in the community pool
+	InflationSmoothingBlocks uint64  `json:"inflation_smoothing_blocks"` // number of blocks over which the inflation moves to its target, one applies the target immediately
+	MaxAnnualProvisions      int64   `json:"max_annual_provisions"`      // maximum provisions minted per year regardless of the inflation rate, zero is unlimited
+	MaxTokenSupply           int64   `json:"max_token_supply"`           // token supply past which nothing more is minted, zero is unlimited
+	CarryProvisionsRemainder bool    `json:"carry_provisions_remainder"` // whether the fraction of a token truncated from a block provision is carried to the next block
+	ProvisionsHistory        uint64  `json:"provisions_history"`         // number of blocks for which the provisions index and the validators' credits are kept, zero disables them
 }
 
 // ParamTable for minting module.
@@ -33,27 +47,43 @@ func ParamKeyTable() params.KeyTable {
 }
 
 func NewParams(mintDenom string, inflationRateChange, inflationMax,
-	inflationMin, goalBonded sdk.Dec, blocksPerYear uint64) Params {
+	inflationMin, goalBonded sdk.Dec, blocksPerYear, inflationHistory uint64,
+	bondedProvisionsFraction sdk.Dec, inflationSmoothingBlocks uint64, maxAnnualProvisions, maxTokenSupply int64,
+	carryProvisionsRemainder bool, provisionsHistory uint64) Params {
 
 	return Params{
-		MintDenom:           mintDenom,
-		InflationRateChange: inflationRateChange,
-		InflationMax:        inflationMax,
-		InflationMin:        inflationMin,
-		GoalBonded:          goalBonded,
-		BlocksPerYear:       blocksPerYear,
+		MintDenom:                mintDenom,
+		InflationRateChange:      inflationRateChange,
+		InflationMax:             inflationMax,
+		InflationMin:             inflationMin,
+		GoalBonded:               goalBonded,
+		BlocksPerYear:            blocksPerYear,
+		InflationHistory:         inflationHistory,
+		BondedProvisionsFraction: bondedProvisionsFraction,
+		InflationSmoothingBlocks: inflationSmoothingBlocks,
+		MaxAnnualProvisions:      maxAnnualProvisions,
+		MaxTokenSupply:           maxTokenSupply,
+		CarryProvisionsRemainder: carryProvisionsRemainder,
+		ProvisionsHistory:        provisionsHistory,
 	}
 }
 
 // default minting module parameters
 func DefaultParams() Params {
 	return Params{
-		MintDenom:           sdk.DefaultBondDenom,
-		InflationRateChange: sdk.NewDecWithPrec(13, 2),
-		InflationMax:        sdk.NewDecWithPrec(20, 2),
-		InflationMin:        sdk.NewDecWithPrec(7, 2),
-		GoalBonded:          sdk.NewDecWithPrec(67, 2),
-		BlocksPerYear:       uint64(60 * 60 * 8766 / 5), // assuming 5 second block times
+		MintDenom:                sdk.DefaultBondDenom,
+		InflationRateChange:      sdk.NewDecWithPrec(13, 2),
+		InflationMax:             sdk.NewDecWithPrec(20, 2),
+		InflationMin:             sdk.NewDecWithPrec(7, 2),
+		GoalBonded:               sdk.NewDecWithPrec(67, 2),
+		BlocksPerYear:            uint64(60 * 60 * 8766 / 5), // assuming 5 second block times
+		InflationHistory:         0,
+		BondedProvisionsFraction: sdk.OneDec(),
+		InflationSmoothingBlocks: 1,
+		MaxAnnualProvisions:      0,
+		MaxTokenSupply:           0,
+		CarryProvisionsRemainder: false,
+		ProvisionsHistory:        0,
 	}
 }
 
@@ -67,6 +97,22 @@ func validateParams(params Params) error {
 	if params.InflationMax.LT(params.InflationMin) {
 		return fmt.Errorf("mint parameter Max inflation must be greater than or equal to min inflation")
 	}
+	if params.BondedProvisionsFraction.IsNil() {
+		return fmt.Errorf("mint parameter BondedProvisionsFraction must be set")
+	}
+	if params.BondedProvisionsFraction.IsNegative() || params.BondedProvisionsFraction.GT(sdk.OneDec()) {
+		return fmt.Errorf("mint parameter BondedProvisionsFraction must be between 0 and 1, is %s",
+			params.BondedProvisionsFraction.String())
+	}
+	if params.InflationSmoothingBlocks == 0 {
+		return fmt.Errorf("mint parameter InflationSmoothingBlocks must be a positive integer")
+	}
+	if params.MaxAnnualProvisions < 0 {
+		return fmt.Errorf("mint parameter MaxAnnualProvisions should be positive, is %d", params.MaxAnnualProvisions)
+	}
+	if params.MaxTokenSupply < 0 {
+		return fmt.Errorf("mint parameter MaxTokenSupply should be positive, is %d", params.MaxTokenSupply)
+	}
 	if params.MintDenom == "" {
 		return fmt.Errorf("mint parameter MintDenom can't be an empty string")
 	}
@@ -75,15 +121,24 @@ func validateParams(params Params) error {
 
 func (p Params) String() string {
 	return fmt.Sprintf(`Minting Params:
-  Mint Denom:             %s
-  Inflation Rate Change:  %s
-  Inflation Max:          %s
-  Inflation Min:          %s
-  Goal Bonded:            %s
-  Blocks Per Year:        %d
+  Mint Denom:                 %s
+  Inflation Rate Change:      %s
+  Inflation Max:              %s
+  Inflation Min:              %s
+  Goal Bonded:                %s
+  Blocks Per Year:            %d
+  Inflation History:          %d
+  Bonded Provisions Fraction: %s
+  Inflation Smoothing Blocks: %d
+  Max Annual Provisions:      %d
+  Max Token Supply:           %d
+  Carry Provisions Remainder: %t
+  Provisions History:         %d
 `,
 		p.MintDenom, p.InflationRateChange, p.InflationMax,
-		p.InflationMin, p.GoalBonded, p.BlocksPerYear,
+		p.InflationMin, p.GoalBonded, p.BlocksPerYear, p.InflationHistory,
+		p.BondedProvisionsFraction, p.InflationSmoothingBlocks, p.MaxAnnualProvisions,
+		p.MaxTokenSupply, p.CarryProvisionsRemainder, p.ProvisionsHistory,
 	)
 }
 
@@ -96,5 +151,12 @@ func (p *Params) ParamSetPairs() params.ParamSetPairs {
 		{KeyInflationMin, &p.InflationMin},
 		{KeyGoalBonded, &p.GoalBonded},
 		{KeyBlocksPerYear, &p.BlocksPerYear},
+		{KeyInflationHistory, &p.InflationHistory},
+		{KeyBondedProvisionsFraction, &p.BondedProvisionsFraction},
+		{KeyInflationSmoothingBlocks, &p.InflationSmoothingBlocks},
+		{KeyMaxAnnualProvisions, &p.MaxAnnualProvisions},
+		{KeyMaxTokenSupply, &p.MaxTokenSupply},
+		{KeyCarryProvisionsRemainder, &p.CarryProvisionsRemainder},
+		{KeyProvisionsHistory, &p.ProvisionsHistory},
 	}
 }
@@ -14,11 +14,37 @@ const (
 	QueryParameters       = "parameters"
 	QueryInflation        = "inflation"
 	QueryAnnualProvisions = "annual_provisions"
+	QueryInflationHistory = "inflation_history"
+	QueryDelegatorAPR     = "delegator_apr"
 )
 
+// QueryInflationHistoryParams defines the block height range, inclusive, for
+// the inflation history query
+type QueryInflationHistoryParams struct {
+	From int64 `json:"from"`
+	To   int64 `json:"to"`
+}
+
+// NewQueryInflationHistoryParams creates a new QueryInflationHistoryParams
+func NewQueryInflationHistoryParams(from, to int64) QueryInflationHistoryParams {
+	return QueryInflationHistoryParams{from, to}
+}
+
+// QueryDelegatorAPRParams defines the validator for the delegator APR query
+// and whether the expected slashing cost is subtracted
+type QueryDelegatorAPRParams struct {
+	ValidatorAddress sdk.ValAddress `json:"validator_address"`
+	RiskAdjusted     bool           `json:"risk_adjusted"`
+}
+
+// NewQueryDelegatorAPRParams creates a new QueryDelegatorAPRParams
+func NewQueryDelegatorAPRParams(valAddr sdk.ValAddress, riskAdjusted bool) QueryDelegatorAPRParams {
+	return QueryDelegatorAPRParams{valAddr, riskAdjusted}
+}
+
 // NewQuerier returns a minting Querier handler.
 func NewQuerier(k Keeper) sdk.Querier {
-	return func(ctx sdk.Context, path []string, _ abci.RequestQuery) ([]byte, sdk.Error) {
+	return func(ctx sdk.Context, path []string, req abci.RequestQuery) ([]byte, sdk.Error) {
 		switch path[0] {
 		case QueryParameters:
 			return queryParams(ctx, k)
@@ -29,6 +55,12 @@ func NewQuerier(k Keeper) sdk.Querier {
 		case QueryAnnualProvisions:
 			return queryAnnualProvisions(ctx, k)
 
+		case QueryInflationHistory:
+			return queryInflationHistory(ctx, req, k)
+
+		case QueryDelegatorAPR:
+			return queryDelegatorAPR(ctx, req, k)
+
 		default:
 			return nil, sdk.ErrUnknownRequest(fmt.Sprintf("unknown minting query endpoint: %s", path[0]))
 		}
@@ -67,3 +99,45 @@ func queryAnnualProvisions(ctx sdk.Context, k Keeper) ([]byte, sdk.Error) {
 
 	return res, nil
 }
+
+func queryInflationHistory(ctx sdk.Context, req abci.RequestQuery, k Keeper) ([]byte, sdk.Error) {
+	var params QueryInflationHistoryParams
+	if err := k.cdc.UnmarshalJSON(req.Data, &params); err != nil {
+		return nil, sdk.ErrUnknownRequest(sdk.AppendMsgToErr("failed to parse params", err.Error()))
+	}
+
+	records := k.GetInflationHistory(ctx, params.From, params.To)
+	if records == nil {
+		records = []InflationRecord{}
+	}
+
+	res, err := codec.MarshalJSONIndent(k.cdc, records)
+	if err != nil {
+		return nil, sdk.ErrInternal(sdk.AppendMsgToErr("failed to marshal JSON", err.Error()))
+	}
+
+	return res, nil
+}
+
+func queryDelegatorAPR(ctx sdk.Context, req abci.RequestQuery, k Keeper) ([]byte, sdk.Error) {
+	var params QueryDelegatorAPRParams
+	if err := k.cdc.UnmarshalJSON(req.Data, &params); err != nil {
+		return nil, sdk.ErrUnknownRequest(sdk.AppendMsgToErr("failed to parse params", err.Error()))
+	}
+
+	if k.sk.Validator(ctx, params.ValidatorAddress) == nil {
+		return nil, sdk.ErrUnknownRequest(fmt.Sprintf("validator %s does not exist", params.ValidatorAddress))
+	}
+
+	apr := k.EstimateDelegatorAPR(ctx, params.ValidatorAddress)
+	if params.RiskAdjusted {
+		apr = k.EstimateRiskAdjustedAPR(ctx, params.ValidatorAddress)
+	}
+
+	res, err := codec.MarshalJSONIndent(k.cdc, apr)
+	if err != nil {
+		return nil, sdk.ErrInternal(sdk.AppendMsgToErr("failed to marshal JSON", err.Error()))
+	}
+
+	return res, nil
+}
@@ -6,8 +6,15 @@ import (
 	"github.com/stretchr/testify/require"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/staking"
 
 	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+)
+
+var (
+	pk  = ed25519.GenPrivKey().PubKey()
+	pk2 = ed25519.GenPrivKey().PubKey()
 )
 
 func TestNewQuerier(t *testing.T) {
@@ -73,3 +80,126 @@ func TestQueryAnnualProvisions(t *testing.T) {
 
 	require.Equal(t, input.mintKeeper.GetMinter(input.ctx).AnnualProvisions, annualProvisions)
 }
+
+func TestQueryInflationHistory(t *testing.T) {
+	input := newTestInput(t)
+	querier := NewQuerier(input.mintKeeper)
+
+	// the history is off by default
+	params := input.mintKeeper.GetParams(input.ctx)
+	require.Zero(t, params.InflationHistory)
+	params.InflationHistory = 3
+	input.mintKeeper.SetParams(input.ctx, params)
+
+	// process provisions across several blocks
+	var expected []InflationRecord
+	for height := int64(1); height <= 5; height++ {
+		ctx := input.ctx.WithBlockHeight(height)
+		BeginBlocker(ctx, input.mintKeeper)
+		expected = append(expected, InflationRecord{height, input.mintKeeper.GetMinter(ctx).Inflation})
+	}
+
+	query := func(from, to int64) (records []InflationRecord) {
+		bz, err := input.cdc.MarshalJSON(NewQueryInflationHistoryParams(from, to))
+		require.NoError(t, err)
+		res, sdkErr := querier(input.ctx, []string{QueryInflationHistory}, abci.RequestQuery{Data: bz})
+		require.NoError(t, sdkErr)
+		require.NoError(t, input.cdc.UnmarshalJSON(res, &records))
+		return records
+	}
+
+	// only the records within the retention window are kept
+	require.Equal(t, expected[2:], query(0, 10))
+	require.Equal(t, expected[3:4], query(4, 4))
+	require.Empty(t, query(1, 2))
+}
+
+func TestQueryDelegatorAPR(t *testing.T) {
+	input := newTestInput(t)
+	querier := NewQuerier(input.mintKeeper)
+
+	// half of the supply is bonded
+	pool := staking.InitialPool()
+	pool.BondedTokens = sdk.NewInt(500)
+	pool.NotBondedTokens = sdk.NewInt(500)
+	input.stakingKeeper.SetPool(input.ctx, pool)
+
+	minter := input.mintKeeper.GetMinter(input.ctx)
+	minter.Inflation = sdk.NewDecWithPrec(10, 2)
+	input.mintKeeper.SetMinter(input.ctx, minter)
+
+	valAddr := sdk.ValAddress(pk.Address())
+	validator := staking.NewValidator(valAddr, pk, staking.Description{})
+	validator.Commission = staking.NewCommission(sdk.NewDecWithPrec(10, 2), sdk.OneDec(), sdk.ZeroDec())
+	input.stakingKeeper.SetValidator(input.ctx, validator)
+
+	// 0.10 / 0.5 * (1 - 0.10)
+	expected := sdk.NewDecWithPrec(18, 2)
+	require.Equal(t, expected, input.mintKeeper.EstimateDelegatorAPR(input.ctx, valAddr))
+
+	bz, err := input.cdc.MarshalJSON(NewQueryDelegatorAPRParams(valAddr, false))
+	require.NoError(t, err)
+	res, sdkErr := querier(input.ctx, []string{QueryDelegatorAPR}, abci.RequestQuery{Data: bz})
+	require.NoError(t, sdkErr)
+
+	var apr sdk.Dec
+	require.NoError(t, input.cdc.UnmarshalJSON(res, &apr))
+	require.Equal(t, expected, apr)
+
+	// unknown validators are rejected
+	bz, err = input.cdc.MarshalJSON(NewQueryDelegatorAPRParams(sdk.ValAddress(pk2.Address()), false))
+	require.NoError(t, err)
+	_, sdkErr = querier(input.ctx, []string{QueryDelegatorAPR}, abci.RequestQuery{Data: bz})
+	require.Error(t, sdkErr)
+}
+
+func TestEstimateRiskAdjustedAPR(t *testing.T) {
+	input := newTestInput(t)
+
+	pool := staking.InitialPool()
+	pool.BondedTokens = sdk.NewInt(500)
+	pool.NotBondedTokens = sdk.NewInt(500)
+	input.stakingKeeper.SetPool(input.ctx, pool)
+
+	minter := input.mintKeeper.GetMinter(input.ctx)
+	minter.Inflation = sdk.NewDecWithPrec(10, 2)
+	input.mintKeeper.SetMinter(input.ctx, minter)
+
+	valAddr := sdk.ValAddress(pk.Address())
+	validator := staking.NewValidator(valAddr, pk, staking.Description{})
+	input.stakingKeeper.SetValidator(input.ctx, validator)
+
+	// without an estimator the estimate is the nominal APR
+	nominal := input.mintKeeper.EstimateDelegatorAPR(input.ctx, valAddr)
+	require.Equal(t, sdk.NewDecWithPrec(20, 2), nominal)
+	require.Equal(t, nominal, input.mintKeeper.EstimateRiskAdjustedAPR(input.ctx, valAddr))
+
+	// a validator which missed a fifth of its blocks with a 1% downtime slash
+	// loses 0.2% of its stake per 1000 blocks window, so 2% over a year of ten
+	// windows
+	params := input.mintKeeper.GetParams(input.ctx)
+	params.BlocksPerYear = 10000
+	input.mintKeeper.SetParams(input.ctx, params)
+	window := int64(1000)
+	input.mintKeeper.SetSlashRiskEstimator(func(_ sdk.Context, addr sdk.ValAddress) (sdk.Dec, int64) {
+		require.Equal(t, valAddr, addr)
+		return sdk.NewDecWithPrec(2, 1).Mul(sdk.NewDecWithPrec(1, 2)), window
+	})
+	riskAdjusted := input.mintKeeper.EstimateRiskAdjustedAPR(input.ctx, valAddr)
+	require.Equal(t, sdk.NewDecWithPrec(18, 2), riskAdjusted)
+
+	// the annual risk is capped at the whole stake
+	window = 1
+	require.Equal(t, sdk.NewDecWithPrec(-80, 2), input.mintKeeper.EstimateRiskAdjustedAPR(input.ctx, valAddr))
+	window = 1000
+
+	querier := NewQuerier(input.mintKeeper)
+	bz, err := input.cdc.MarshalJSON(NewQueryDelegatorAPRParams(valAddr, true))
+	require.NoError(t, err)
+	res, sdkErr := querier(input.ctx, []string{QueryDelegatorAPR}, abci.RequestQuery{Data: bz})
+	require.NoError(t, sdkErr)
+
+	var apr sdk.Dec
+	require.NoError(t, input.cdc.UnmarshalJSON(res, &apr))
+	require.Equal(t, riskAdjusted, apr)
+}
@@ -17,14 +17,17 @@ import (
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/x/auth"
 	"github.com/cosmos/cosmos-sdk/x/bank"
+	distr "github.com/cosmos/cosmos-sdk/x/distribution"
 	"github.com/cosmos/cosmos-sdk/x/params"
 	"github.com/cosmos/cosmos-sdk/x/staking"
 )
 
 type testInput struct {
-	ctx        sdk.Context
-	cdc        *codec.Codec
-	mintKeeper Keeper
+	ctx           sdk.Context
+	cdc           *codec.Codec
+	mintKeeper    Keeper
+	stakingKeeper staking.Keeper
+	distrKeeper   distr.Keeper
 }
 
 func createTestCodec() *codec.Codec {
@@ -44,6 +47,7 @@ func newTestInput(t *testing.T) testInput {
 	tkeyParams := sdk.NewTransientStoreKey(params.TStoreKey)
 	keyFeeCollection := sdk.NewKVStoreKey(auth.FeeStoreKey)
 	keyMint := sdk.NewKVStoreKey(StoreKey)
+	keyDistr := sdk.NewKVStoreKey(distr.StoreKey)
 
 	ms := store.NewCommitMultiStore(db)
 	ms.MountStoreWithDB(keyAcc, sdk.StoreTypeIAVL, db)
@@ -52,6 +56,7 @@ func newTestInput(t *testing.T) testInput {
 	ms.MountStoreWithDB(keyFeeCollection, sdk.StoreTypeIAVL, db)
 	ms.MountStoreWithDB(keyParams, sdk.StoreTypeIAVL, db)
 	ms.MountStoreWithDB(keyMint, sdk.StoreTypeIAVL, db)
+	ms.MountStoreWithDB(keyDistr, sdk.StoreTypeIAVL, db)
 	ms.MountStoreWithDB(tkeyParams, sdk.StoreTypeTransient, db)
 	err := ms.LoadLatestVersion()
 	require.Nil(t, err)
@@ -63,14 +68,22 @@ func newTestInput(t *testing.T) testInput {
 	stakingKeeper := staking.NewKeeper(
 		cdc, keyStaking, tkeyStaking, bankKeeper, paramsKeeper.Subspace(staking.DefaultParamspace), staking.DefaultCodespace,
 	)
+	distrKeeper := distr.NewKeeper(
+		cdc, keyDistr, paramsKeeper.Subspace(distr.DefaultParamspace), bankKeeper, &stakingKeeper, feeCollectionKeeper,
+		distr.DefaultCodespace,
+	)
 	mintKeeper := NewKeeper(
-		cdc, keyMint, paramsKeeper.Subspace(DefaultParamspace), &stakingKeeper, feeCollectionKeeper,
+		cdc, keyMint, paramsKeeper.Subspace(DefaultParamspace), &stakingKeeper, feeCollectionKeeper, distrKeeper,
 	)
+	stakingKeeper.SetHooks(mintKeeper.Hooks())
 
 	ctx := sdk.NewContext(ms, abci.Header{Time: time.Unix(0, 0)}, false, log.NewTMLogger(os.Stdout))
 
+	stakingKeeper.SetPool(ctx, staking.InitialPool())
+	stakingKeeper.SetParams(ctx, staking.DefaultParams())
 	mintKeeper.SetParams(ctx, DefaultParams())
 	mintKeeper.SetMinter(ctx, DefaultInitialMinter())
+	distrKeeper.SetFeePool(ctx, distr.InitialFeePool())
 
-	return testInput{ctx, cdc, mintKeeper}
+	return testInput{ctx, cdc, mintKeeper, stakingKeeper, distrKeeper}
 }
@@ -85,6 +85,9 @@ var (
 		"DowntimeJailDuration": func(r *rand.Rand) interface{} {
 			return time.Duration(RandIntBetween(r, 60, 60*60*24)) * time.Second
 		},
+		"DowntimeGracePeriod": func(r *rand.Rand) interface{} {
+			return int64(r.Intn(100))
+		},
 		"SlashFractionDoubleSign": func(r *rand.Rand) interface{} {
 			return sdk.NewDec(1).Quo(sdk.NewDec(int64(r.Intn(50) + 1)))
 		},
@@ -103,6 +106,9 @@ var (
 		"GoalBonded": func(r *rand.Rand) interface{} {
 			return sdk.NewDecWithPrec(67, 2)
 		},
+		"BondedProvisionsFraction": func(r *rand.Rand) interface{} {
+			return sdk.NewDecWithPrec(int64(r.Intn(11)+90), 2)
+		},
 	}
 )
 
@@ -19,6 +19,11 @@ func registerQueryRoutes(cliCtx context.CLIContext, r *mux.Router, cdc *codec.Co
 		signingInfoHandlerFn(cliCtx, cdc),
 	).Methods("GET")
 
+	r.HandleFunc(
+		"/slashing/validators/{validatorAddr}/jail_remaining",
+		jailRemainingHandlerFn(cliCtx, cdc),
+	).Methods("GET")
+
 	r.HandleFunc(
 		"/slashing/signing_infos",
 		signingInfoHandlerListFn(cliCtx, cdc),
@@ -30,6 +35,35 @@ func registerQueryRoutes(cliCtx context.CLIContext, r *mux.Router, cdc *codec.Co
 	).Methods("GET")
 }
 
+// http request handler to query the time left until a validator may be unjailed
+func jailRemainingHandlerFn(cliCtx context.CLIContext, cdc *codec.Codec) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		valAddr, err := sdk.ValAddressFromBech32(vars["validatorAddr"])
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		params := slashing.NewQueryJailRemainingParams(valAddr)
+
+		bz, err := cdc.MarshalJSON(params)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		route := fmt.Sprintf("custom/%s/%s", slashing.QuerierRoute, slashing.QueryJailRemaining)
+		res, err := cliCtx.QueryWithData(route, bz)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		rest.PostProcessResponse(w, cdc, res, cliCtx.Indent)
+	}
+}
+
 // http request handler to query signing info
 func signingInfoHandlerFn(cliCtx context.CLIContext, cdc *codec.Codec) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
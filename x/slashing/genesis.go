@@ -61,6 +61,11 @@ func ValidateGenesis(data GenesisState) error {
 		return fmt.Errorf("Signed blocks window must be at least 10, is %d", signedWindow)
 	}
 
+	gracePeriod := data.Params.DowntimeGracePeriod
+	if gracePeriod < 0 {
+		return fmt.Errorf("Downtime grace period cannot be negative, is %d", gracePeriod)
+	}
+
 	return nil
 }
 
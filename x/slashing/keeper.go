@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"time"
 
+	abci "github.com/tendermint/tendermint/abci/types"
 	"github.com/tendermint/tendermint/crypto"
 	"github.com/tendermint/tendermint/libs/log"
+	tmtypes "github.com/tendermint/tendermint/types"
 
 	"github.com/cosmos/cosmos-sdk/codec"
 	sdk "github.com/cosmos/cosmos-sdk/types"
@@ -38,6 +40,50 @@ func NewKeeper(cdc *codec.Codec, key sdk.StoreKey, vs sdk.ValidatorSet, paramspa
 // Logger returns a module-specific logger.
 func (k Keeper) Logger(ctx sdk.Context) log.Logger { return ctx.Logger().With("module", "x/slashing") }
 
+// HandleEvidenceBatch processes the evidence of a block, handling each
+// validator at most once. Among several pieces of evidence against the same
+// validator, only the most severe one is applied: the one with the highest
+// power, and of those the earliest infraction, which reaches the most
+// unbonding and redelegating stake. Validators are handled in the order their
+// first evidence appears; evidence of unknown type, past the max evidence age
+// or against an unknown validator is ignored before the most severe one is
+// chosen, so that it cannot displace evidence which can be handled.
+func (k Keeper) HandleEvidenceBatch(ctx sdk.Context, evidences []abci.Evidence) {
+	maxAge := k.MaxEvidenceAge(ctx)
+	var order []string
+	mostSevere := make(map[string]abci.Evidence)
+	for _, evidence := range evidences {
+		if evidence.Type != tmtypes.ABCIEvidenceTypeDuplicateVote {
+			k.Logger(ctx).Error(fmt.Sprintf("ignored unknown evidence type: %s", evidence.Type))
+			continue
+		}
+		if age := ctx.BlockHeader().Time.Sub(evidence.Time); age > maxAge {
+			k.Logger(ctx).Info(fmt.Sprintf("Ignored double sign from %s at height %d, age of %d past max age of %d",
+				evidence.Validator.Address, evidence.Height, age, maxAge))
+			continue
+		}
+		if _, err := k.getPubkey(ctx, evidence.Validator.Address); err != nil {
+			continue
+		}
+
+		key := string(evidence.Validator.Address)
+		current, found := mostSevere[key]
+		switch {
+		case !found:
+			order = append(order, key)
+		case evidence.Validator.Power < current.Validator.Power,
+			evidence.Validator.Power == current.Validator.Power && evidence.Height >= current.Height:
+			continue
+		}
+		mostSevere[key] = evidence
+	}
+
+	for _, key := range order {
+		evidence := mostSevere[key]
+		k.handleDoubleSign(ctx, evidence.Validator.Address, evidence.Height, evidence.Time, evidence.Validator.Power)
+	}
+}
+
 // handle a validator signing two blocks at the same height
 // power: power of the double-signing validator at the height of infraction
 func (k Keeper) handleDoubleSign(ctx sdk.Context, addr crypto.Address, infractionHeight int64, timestamp time.Time, power int64) {
@@ -85,9 +131,13 @@ func (k Keeper) handleDoubleSign(ctx sdk.Context, addr crypto.Address, infractio
 		panic(fmt.Sprintf("Expected signing info for validator %s but not found", consAddr))
 	}
 
-	// validator is already tombstoned
+	// validator is already tombstoned, it was slashed for an earlier double
+	// sign and must not be slashed again, but it has to stay jailed
 	if signInfo.Tombstoned {
 		logger.Info(fmt.Sprintf("Ignored double sign from %s at height %d, validator already tombstoned", pubkey.Address(), infractionHeight))
+		if !validator.IsJailed() {
+			k.validatorSet.Jail(ctx, consAddr)
+		}
 		return
 	}
 
@@ -153,6 +203,12 @@ func (k Keeper) handleValidatorSignature(ctx sdk.Context, addr crypto.Address, p
 	// That way we avoid needing to read/write the whole array each time
 	previous := k.getValidatorMissedBlockBitArray(ctx, consAddr, index)
 	missed := !signed
+
+	// missed blocks are not counted while a new validator is within its grace period
+	if missed && height-signInfo.StartHeight < k.DowntimeGracePeriod(ctx) {
+		logger.Info(fmt.Sprintf("Absent validator %s (%v) at height %d within downtime grace period", addr, pubkey, height))
+		missed = false
+	}
 	switch {
 	case !previous && missed:
 		// Array value has changed from not missed to missed, increment counter
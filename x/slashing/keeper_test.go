@@ -6,6 +6,7 @@ import (
 
 	"github.com/stretchr/testify/require"
 	abci "github.com/tendermint/tendermint/abci/types"
+	tmtypes "github.com/tendermint/tendermint/types"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/x/staking"
@@ -81,6 +82,115 @@ func TestHandleDoubleSign(t *testing.T) {
 	require.True(t, res.IsOK())
 }
 
+// Test that further evidence against a tombstoned validator, even for earlier
+// heights, does not slash it again
+func TestHandleDoubleSignTombstoned(t *testing.T) {
+	ctx, _, sk, _, keeper := createTestInput(t, keeperTestParams())
+	ctx = ctx.WithBlockHeight(-1)
+	power := int64(100)
+	amt := sdk.TokensFromTendermintPower(power)
+	operatorAddr, val := addrs[0], pks[0]
+	got := staking.NewHandler(sk)(ctx, NewTestMsgCreateValidator(operatorAddr, val, amt))
+	require.True(t, got.IsOK())
+	staking.EndBlocker(ctx, sk)
+	keeper.handleValidatorSignature(ctx, val.Address(), amt.Int64(), true)
+
+	ctx = ctx.WithBlockHeight(10)
+	oldTokens := sk.Validator(ctx, operatorAddr).GetTokens()
+
+	// first evidence slashes, jails and tombstones
+	keeper.handleDoubleSign(ctx, val.Address(), 5, time.Unix(0, 0), power)
+	slashedTokens := sk.Validator(ctx, operatorAddr).GetTokens()
+	require.True(t, slashedTokens.LT(oldTokens))
+	require.True(t, sk.Validator(ctx, operatorAddr).IsJailed())
+	info, found := keeper.getValidatorSigningInfo(ctx, sdk.ConsAddress(val.Address()))
+	require.True(t, found)
+	require.True(t, info.Tombstoned)
+	require.Len(t, sk.GetValidatorSlashEvents(ctx, operatorAddr), 1)
+
+	// second evidence at an earlier height is ignored
+	keeper.handleDoubleSign(ctx, val.Address(), 3, time.Unix(0, 0), power)
+	require.True(t, sk.Validator(ctx, operatorAddr).GetTokens().Equal(slashedTokens))
+	require.True(t, sk.Validator(ctx, operatorAddr).IsJailed())
+	require.Len(t, sk.GetValidatorSlashEvents(ctx, operatorAddr), 1)
+}
+
+func TestHandleEvidenceBatch(t *testing.T) {
+	ctx, _, sk, _, keeper := createTestInput(t, keeperTestParams())
+	ctx = ctx.WithBlockHeight(-1)
+	power := int64(100)
+	amt := sdk.TokensFromTendermintPower(power)
+	for i := 0; i < 2; i++ {
+		got := staking.NewHandler(sk)(ctx, NewTestMsgCreateValidator(addrs[i], pks[i], amt))
+		require.True(t, got.IsOK())
+	}
+	staking.EndBlocker(ctx, sk)
+	for i := 0; i < 2; i++ {
+		keeper.handleValidatorSignature(ctx, pks[i].Address(), amt.Int64(), true)
+	}
+
+	ctx = ctx.WithBlockHeight(10)
+	oldTokens := sk.Validator(ctx, addrs[0]).GetTokens()
+
+	// two pieces of evidence against the first validator, the later one more severe
+	evidence := func(i int, height, power int64) abci.Evidence {
+		return abci.Evidence{
+			Type:      tmtypes.ABCIEvidenceTypeDuplicateVote,
+			Validator: abci.Validator{Address: pks[i].Address(), Power: power},
+			Height:    height,
+			Time:      time.Unix(0, 0),
+		}
+	}
+	keeper.HandleEvidenceBatch(ctx, []abci.Evidence{
+		evidence(0, 5, 60),
+		evidence(1, 5, power),
+		evidence(0, 6, power),
+	})
+
+	// the first validator is slashed once, at the most severe power
+	require.Len(t, sk.GetValidatorSlashEvents(ctx, addrs[0]), 1)
+	slashAmt := keeper.SlashFractionDoubleSign(ctx).MulInt(amt).TruncateInt()
+	require.True(t, oldTokens.Sub(slashAmt).Equal(sk.Validator(ctx, addrs[0]).GetTokens()))
+	require.True(t, sk.Validator(ctx, addrs[0]).IsJailed())
+
+	// the second validator is handled as well
+	require.Len(t, sk.GetValidatorSlashEvents(ctx, addrs[1]), 1)
+	require.True(t, sk.Validator(ctx, addrs[1]).IsJailed())
+}
+
+func TestHandleEvidenceBatchSkipsExpiredEvidence(t *testing.T) {
+	ctx, _, sk, _, keeper := createTestInput(t, keeperTestParams())
+	ctx = ctx.WithBlockHeight(-1)
+	power := int64(100)
+	amt := sdk.TokensFromTendermintPower(power)
+	operatorAddr, val := addrs[0], pks[0]
+	got := staking.NewHandler(sk)(ctx, NewTestMsgCreateValidator(operatorAddr, val, amt))
+	require.True(t, got.IsOK())
+	staking.EndBlocker(ctx, sk)
+	keeper.handleValidatorSignature(ctx, val.Address(), power, true)
+
+	now := time.Unix(1, 0).Add(keeper.MaxEvidenceAge(ctx))
+	ctx = ctx.WithBlockHeader(abci.Header{Time: now}).WithBlockHeight(10)
+
+	// the earlier evidence has expired, the later one at the same power has not
+	evidence := func(height int64, timestamp time.Time) abci.Evidence {
+		return abci.Evidence{
+			Type:      tmtypes.ABCIEvidenceTypeDuplicateVote,
+			Validator: abci.Validator{Address: val.Address(), Power: power},
+			Height:    height,
+			Time:      timestamp,
+		}
+	}
+	keeper.HandleEvidenceBatch(ctx, []abci.Evidence{
+		evidence(5, time.Unix(0, 0)),
+		evidence(6, now),
+	})
+
+	// the validator is slashed exactly once, for the fresh evidence
+	require.Len(t, sk.GetValidatorSlashEvents(ctx, operatorAddr), 1)
+	require.True(t, sk.Validator(ctx, operatorAddr).IsJailed())
+}
+
 // ______________________________________________________________
 
 // Test that a validator is slashed correctly
@@ -315,6 +425,51 @@ func TestHandleNewValidator(t *testing.T) {
 	require.Equal(t, expTokens, pool.BondedTokens)
 }
 
+// Test a new validator missing blocks during its downtime grace period
+// Ensure that those missed blocks don't count towards jailing
+func TestHandleNewValidatorGracePeriod(t *testing.T) {
+	// initial setup
+	params := keeperTestParams()
+	params.DowntimeGracePeriod = 600
+	ctx, _, sk, _, keeper := createTestInput(t, params)
+	power := int64(100)
+	amt := sdk.TokensFromTendermintPower(power)
+	addr, val := addrs[0], pks[0]
+	got := staking.NewHandler(sk)(ctx, NewTestMsgCreateValidator(addr, val, amt))
+	require.True(t, got.IsOK())
+	staking.EndBlocker(ctx, sk)
+
+	// all blocks of the grace period missed
+	height := int64(0)
+	for ; height < keeper.DowntimeGracePeriod(ctx); height++ {
+		ctx = ctx.WithBlockHeight(height)
+		keeper.handleValidatorSignature(ctx, val.Address(), power, false)
+	}
+	info, found := keeper.getValidatorSigningInfo(ctx, sdk.ConsAddress(val.Address()))
+	require.True(t, found)
+	require.Equal(t, keeper.DowntimeGracePeriod(ctx), info.IndexOffset)
+	require.Equal(t, int64(0), info.MissedBlocksCounter)
+
+	// blocks signed until past the signed blocks window
+	for ; height < keeper.SignedBlocksWindow(ctx)+1; height++ {
+		ctx = ctx.WithBlockHeight(height)
+		keeper.handleValidatorSignature(ctx, val.Address(), power, true)
+	}
+
+	// validator should be bonded still, should not have been jailed or slashed
+	staking.EndBlocker(ctx, sk)
+	validator, _ := sk.GetValidatorByConsAddr(ctx, sdk.GetConsAddress(val))
+	require.Equal(t, sdk.Bonded, validator.GetStatus())
+	require.Equal(t, amt, validator.GetTokens())
+
+	// missed blocks are counted again after the grace period
+	ctx = ctx.WithBlockHeight(height)
+	keeper.handleValidatorSignature(ctx, val.Address(), power, false)
+	info, found = keeper.getValidatorSigningInfo(ctx, sdk.ConsAddress(val.Address()))
+	require.True(t, found)
+	require.Equal(t, int64(1), info.MissedBlocksCounter)
+}
+
 // Test a jailed validator being "down" twice
 // Ensure that they're only slashed once
 func TestHandleAlreadyJailed(t *testing.T) {
@@ -471,3 +626,64 @@ func TestValidatorDippingInAndOut(t *testing.T) {
 	require.Equal(t, sdk.Unbonding, validator.Status)
 
 }
+
+// Test the time left until a jailed validator may be unjailed
+func TestGetJailRemaining(t *testing.T) {
+	ctx, _, sk, _, keeper := createTestInput(t, keeperTestParams())
+	power := int64(100)
+	amt := sdk.TokensFromTendermintPower(power)
+	addr, val := addrs[0], pks[0]
+	got := staking.NewHandler(sk)(ctx, NewTestMsgCreateValidator(addr, val, amt))
+	require.True(t, got.IsOK())
+	staking.EndBlocker(ctx, sk)
+
+	// not jailed
+	_, found := keeper.GetJailRemaining(ctx, addr)
+	require.False(t, found)
+
+	// jail with a future JailedUntil
+	now := time.Unix(1000, 0).UTC()
+	ctx = ctx.WithBlockHeader(abci.Header{Time: now})
+	info := NewValidatorSigningInfo(sdk.ConsAddress(val.Address()), 0, 0, now.Add(time.Hour), false, 0)
+	keeper.SetValidatorSigningInfo(ctx, sdk.ConsAddress(val.Address()), info)
+	sk.Jail(ctx, sdk.ConsAddress(val.Address()))
+
+	remaining, found := keeper.GetJailRemaining(ctx, addr)
+	require.True(t, found)
+	require.Equal(t, time.Hour, remaining)
+
+	// half way through
+	ctx = ctx.WithBlockHeader(abci.Header{Time: now.Add(30 * time.Minute)})
+	remaining, found = keeper.GetJailRemaining(ctx, addr)
+	require.True(t, found)
+	require.Equal(t, 30*time.Minute, remaining)
+
+	// past JailedUntil
+	ctx = ctx.WithBlockHeader(abci.Header{Time: now.Add(2 * time.Hour)})
+	remaining, found = keeper.GetJailRemaining(ctx, addr)
+	require.True(t, found)
+	require.Equal(t, time.Duration(0), remaining)
+}
+
+func TestGetDowntimeSlashRisk(t *testing.T) {
+	ctx, _, sk, _, keeper := createTestInput(t, keeperTestParams())
+	power := int64(100)
+	amt := sdk.TokensFromTendermintPower(power)
+	addr, val := addrs[0], pks[0]
+	got := staking.NewHandler(sk)(ctx, NewTestMsgCreateValidator(addr, val, amt))
+	require.True(t, got.IsOK())
+	staking.EndBlocker(ctx, sk)
+	require.True(t, keeper.GetDowntimeSlashRisk(ctx, addr).IsZero())
+
+	// miss a tenth of the signed blocks window
+	window := keeper.SignedBlocksWindow(ctx)
+	for height := int64(0); height < window; height++ {
+		ctx = ctx.WithBlockHeight(height)
+		keeper.handleValidatorSignature(ctx, val.Address(), power, height%10 != 0)
+	}
+	expected := sdk.NewDecWithPrec(1, 1).Mul(keeper.SlashFractionDowntime(ctx))
+	require.Equal(t, expected, keeper.GetDowntimeSlashRisk(ctx, addr))
+
+	// unknown validators carry no risk
+	require.True(t, keeper.GetDowntimeSlashRisk(ctx, sdk.ValAddress(addrs[1])).IsZero())
+}
@@ -14,6 +14,7 @@ const (
 	DefaultMaxEvidenceAge       time.Duration = 60 * 2 * time.Second
 	DefaultSignedBlocksWindow   int64         = 100
 	DefaultDowntimeJailDuration time.Duration = 60 * 10 * time.Second
+	DefaultDowntimeGracePeriod  int64         = 0
 )
 
 // The Double Sign Jail period ends at Max Time supported by Amino (Dec 31, 9999 - 23:59:59 GMT)
@@ -32,6 +33,7 @@ var (
 	KeyDowntimeJailDuration    = []byte("DowntimeJailDuration")
 	KeySlashFractionDoubleSign = []byte("SlashFractionDoubleSign")
 	KeySlashFractionDowntime   = []byte("SlashFractionDowntime")
+	KeyDowntimeGracePeriod     = []byte("DowntimeGracePeriod")
 )
 
 // ParamKeyTable for slashing module
@@ -47,6 +49,7 @@ type Params struct {
 	DowntimeJailDuration    time.Duration `json:"downtime_jail_duration"`
 	SlashFractionDoubleSign sdk.Dec       `json:"slash_fraction_double_sign"`
 	SlashFractionDowntime   sdk.Dec       `json:"slash_fraction_downtime"`
+	DowntimeGracePeriod     int64         `json:"downtime_grace_period"`
 }
 
 func (p Params) String() string {
@@ -56,10 +59,11 @@ func (p Params) String() string {
   MinSignedPerWindow:      %s
   DowntimeJailDuration:    %s
   SlashFractionDoubleSign: %s
-  SlashFractionDowntime:   %s`, p.MaxEvidenceAge,
+  SlashFractionDowntime:   %s
+  DowntimeGracePeriod:     %d`, p.MaxEvidenceAge,
 		p.SignedBlocksWindow, p.MinSignedPerWindow,
 		p.DowntimeJailDuration, p.SlashFractionDoubleSign,
-		p.SlashFractionDowntime)
+		p.SlashFractionDowntime, p.DowntimeGracePeriod)
 }
 
 // Implements params.ParamSet
@@ -71,6 +75,7 @@ func (p *Params) ParamSetPairs() params.ParamSetPairs {
 		{KeyDowntimeJailDuration, &p.DowntimeJailDuration},
 		{KeySlashFractionDoubleSign, &p.SlashFractionDoubleSign},
 		{KeySlashFractionDowntime, &p.SlashFractionDowntime},
+		{KeyDowntimeGracePeriod, &p.DowntimeGracePeriod},
 	}
 }
 
@@ -83,6 +88,7 @@ func DefaultParams() Params {
 		DowntimeJailDuration:    DefaultDowntimeJailDuration,
 		SlashFractionDoubleSign: DefaultSlashFractionDoubleSign,
 		SlashFractionDowntime:   DefaultSlashFractionDowntime,
+		DowntimeGracePeriod:     DefaultDowntimeGracePeriod,
 	}
 }
 
@@ -127,6 +133,13 @@ func (k Keeper) SlashFractionDowntime(ctx sdk.Context) (res sdk.Dec) {
 	return
 }
 
+// DowntimeGracePeriod - number of blocks after a validator starts signing
+// during which its missed blocks are not counted
+func (k Keeper) DowntimeGracePeriod(ctx sdk.Context) (res int64) {
+	k.paramspace.Get(ctx, KeyDowntimeGracePeriod, &res)
+	return
+}
+
 // GetParams returns the total set of slashing parameters.
 func (k Keeper) GetParams(ctx sdk.Context) (params Params) {
 	k.paramspace.GetParamSet(ctx, &params)
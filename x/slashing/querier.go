@@ -11,9 +11,10 @@ import (
 
 // Query endpoints supported by the slashing querier
 const (
-	QueryParameters   = "parameters"
-	QuerySigningInfo  = "signingInfo"
-	QuerySigningInfos = "signingInfos"
+	QueryParameters    = "parameters"
+	QuerySigningInfo   = "signingInfo"
+	QuerySigningInfos  = "signingInfos"
+	QueryJailRemaining = "jailRemaining"
 )
 
 // NewQuerier creates a new querier for slashing clients.
@@ -29,6 +30,9 @@ func NewQuerier(k Keeper, cdc *codec.Codec) sdk.Querier {
 		case QuerySigningInfos:
 			return querySigningInfos(ctx, cdc, req, k)
 
+		case QueryJailRemaining:
+			return queryJailRemaining(ctx, cdc, req, k)
+
 		default:
 			return nil, sdk.ErrUnknownRequest("unknown staking query endpoint")
 		}
@@ -128,3 +132,34 @@ func querySigningInfos(ctx sdk.Context, cdc *codec.Codec, req abci.RequestQuery,
 
 	return res, nil
 }
+
+// QueryJailRemainingParams defines the params for the following queries:
+// - 'custom/slashing/jailRemaining'
+type QueryJailRemainingParams struct {
+	ValidatorAddress sdk.ValAddress
+}
+
+func NewQueryJailRemainingParams(valAddr sdk.ValAddress) QueryJailRemainingParams {
+	return QueryJailRemainingParams{valAddr}
+}
+
+func queryJailRemaining(ctx sdk.Context, cdc *codec.Codec, req abci.RequestQuery, k Keeper) ([]byte, sdk.Error) {
+	var params QueryJailRemainingParams
+
+	err := cdc.UnmarshalJSON(req.Data, &params)
+	if err != nil {
+		return nil, sdk.ErrInternal(fmt.Sprintf("failed to parse params: %s", err))
+	}
+
+	remaining, found := k.GetJailRemaining(ctx, params.ValidatorAddress)
+	if !found {
+		return nil, ErrValidatorNotJailed(DefaultCodespace)
+	}
+
+	res, err := codec.MarshalJSONIndent(cdc, remaining)
+	if err != nil {
+		return nil, sdk.ErrInternal(sdk.AppendMsgToErr("failed to JSON marshal result: %s", err.Error()))
+	}
+
+	return res, nil
+}
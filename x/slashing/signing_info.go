@@ -20,6 +20,45 @@ func (k Keeper) getValidatorSigningInfo(ctx sdk.Context, address sdk.ConsAddress
 	return
 }
 
+// GetJailRemaining returns how long until the jailed validator with the given
+// operator address may be unjailed based on the current block time, zero if it
+// already may be, or false if the validator is not jailed.
+func (k Keeper) GetJailRemaining(ctx sdk.Context, ownerAddr sdk.ValAddress) (time.Duration, bool) {
+	validator := k.validatorSet.Validator(ctx, ownerAddr)
+	if validator == nil || !validator.IsJailed() {
+		return 0, false
+	}
+
+	info, found := k.getValidatorSigningInfo(ctx, validator.GetConsAddr())
+	if !found {
+		return 0, true
+	}
+	remaining := info.JailedUntil.Sub(ctx.BlockHeader().Time)
+	if remaining < 0 {
+		remaining = 0
+	}
+	return remaining, true
+}
+
+// GetDowntimeSlashRisk returns the expected fraction of stake lost to downtime
+// slashing over one signed blocks window for the validator with the given
+// operator address, as the ratio of blocks it missed in the window times the
+// downtime slash fraction. It returns zero if the validator or its signing
+// info is unknown.
+func (k Keeper) GetDowntimeSlashRisk(ctx sdk.Context, ownerAddr sdk.ValAddress) sdk.Dec {
+	validator := k.validatorSet.Validator(ctx, ownerAddr)
+	if validator == nil {
+		return sdk.ZeroDec()
+	}
+
+	info, found := k.getValidatorSigningInfo(ctx, validator.GetConsAddr())
+	if !found {
+		return sdk.ZeroDec()
+	}
+	missedRatio := sdk.NewDec(info.MissedBlocksCounter).QuoInt64(k.SignedBlocksWindow(ctx))
+	return missedRatio.Mul(k.SlashFractionDowntime(ctx))
+}
+
 // Stored by *validator* address (not operator address)
 func (k Keeper) IterateValidatorSigningInfos(ctx sdk.Context, handler func(address sdk.ConsAddress, info ValidatorSigningInfo) (stop bool)) {
 	store := ctx.KVStore(k.storeKey)
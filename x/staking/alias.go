@@ -8,34 +8,60 @@ import (
 )
 
 type (
-	Keeper                  = keeper.Keeper
-	FeeCollectionKeeper     = types.FeeCollectionKeeper
-	BankKeeper              = types.BankKeeper
-	DistributionKeeper      = types.DistributionKeeper
-	Validator               = types.Validator
-	Validators              = types.Validators
-	Description             = types.Description
-	Commission              = types.Commission
-	CommissionMsg           = types.CommissionMsg
-	Delegation              = types.Delegation
-	Delegations             = types.Delegations
-	UnbondingDelegation     = types.UnbondingDelegation
-	UnbondingDelegations    = types.UnbondingDelegations
-	Redelegation            = types.Redelegation
-	Redelegations           = types.Redelegations
-	Params                  = types.Params
-	Pool                    = types.Pool
-	MsgCreateValidator      = types.MsgCreateValidator
-	MsgEditValidator        = types.MsgEditValidator
-	MsgDelegate             = types.MsgDelegate
-	MsgUndelegate           = types.MsgUndelegate
-	MsgBeginRedelegate      = types.MsgBeginRedelegate
-	GenesisState            = types.GenesisState
-	QueryDelegatorParams    = querier.QueryDelegatorParams
-	QueryValidatorParams    = querier.QueryValidatorParams
-	QueryBondsParams        = querier.QueryBondsParams
-	QueryRedelegationParams = querier.QueryRedelegationParams
-	QueryValidatorsParams   = querier.QueryValidatorsParams
+	Keeper                   = keeper.Keeper
+	FeeCollectionKeeper      = types.FeeCollectionKeeper
+	BankKeeper               = types.BankKeeper
+	DistributionKeeper       = types.DistributionKeeper
+	Validator                = types.Validator
+	Validators               = types.Validators
+	Description              = types.Description
+	Commission               = types.Commission
+	CommissionMsg            = types.CommissionMsg
+	Delegation               = types.Delegation
+	DelegationResponse       = types.DelegationResponse
+	DelegationSnapshot       = types.DelegationSnapshot
+	DelegatorStake           = types.DelegatorStake
+	BondedRatioRecord        = types.BondedRatioRecord
+	Delegations              = types.Delegations
+	DelegateResult           = types.DelegateResult
+	UnbondingDelegation      = types.UnbondingDelegation
+	UnbondingDelegations     = types.UnbondingDelegations
+	Redelegation             = types.Redelegation
+	Redelegations            = types.Redelegations
+	Params                   = types.Params
+	ParamChangeImpact        = types.ParamChangeImpact
+	BondingStatus            = types.BondingStatus
+	ShareRoundingMode        = types.ShareRoundingMode
+	TieBreakMode             = types.TieBreakMode
+	Pool                     = types.Pool
+	MsgCreateValidator       = types.MsgCreateValidator
+	MsgEditValidator         = types.MsgEditValidator
+	MsgMigrateValidatorOwner = types.MsgMigrateValidatorOwner
+	MsgSignalExit            = types.MsgSignalExit
+	MsgDelegate              = types.MsgDelegate
+	MsgUndelegate            = types.MsgUndelegate
+	MsgBeginRedelegate       = types.MsgBeginRedelegate
+	MsgRebalance             = types.MsgRebalance
+	MsgSetAutoCompound       = types.MsgSetAutoCompound
+	FreezeValidatorProposal  = types.FreezeValidatorProposal
+	SlashEvent               = types.SlashEvent
+	SlashEvents              = types.SlashEvents
+	GenesisState             = types.GenesisState
+	GenesisDelegation        = types.GenesisDelegation
+	InvariantRoute           = keeper.InvariantRoute
+	InvariantCheck           = keeper.InvariantCheck
+	InflationEstimator       = keeper.InflationEstimator
+	QueryDelegatorParams     = querier.QueryDelegatorParams
+	QueryValidatorParams     = querier.QueryValidatorParams
+	QueryBondsParams         = querier.QueryBondsParams
+	QueryRedelegationParams  = querier.QueryRedelegationParams
+	QueryValidatorsParams    = querier.QueryValidatorsParams
+
+	MaxValidatorsScheduleEntry       = types.MaxValidatorsScheduleEntry
+	CompleteUnbondingsProposal       = types.CompleteUnbondingsProposal
+	VerifyValidatorIdentityProposal  = types.VerifyValidatorIdentityProposal
+	QueryDelegationsAboveValueParams = querier.QueryDelegationsAboveValueParams
+	QueryBondedRatioHistoryParams    = querier.QueryBondedRatioHistoryParams
 )
 
 var (
@@ -52,6 +78,8 @@ var (
 	ValidatorsKey                = keeper.ValidatorsKey
 	ValidatorsByConsAddrKey      = keeper.ValidatorsByConsAddrKey
 	ValidatorsByPowerIndexKey    = keeper.ValidatorsByPowerIndexKey
+	ValidatorSlashEventsKey      = keeper.ValidatorSlashEventsKey
+	GetValidatorSlashEventsKey   = keeper.GetValidatorSlashEventsKey
 	DelegationKey                = keeper.DelegationKey
 	GetUBDKey                    = keeper.GetUBDKey
 	GetUBDByValIndexKey          = keeper.GetUBDByValIndexKey
@@ -68,7 +96,10 @@ var (
 	UnbondingQueueKey            = keeper.UnbondingQueueKey
 	RedelegationQueueKey         = keeper.RedelegationQueueKey
 	ValidatorQueueKey            = keeper.ValidatorQueueKey
+	ValidatorUpdatesHistoryKey   = keeper.ValidatorUpdatesHistoryKey
 	RegisterInvariants           = keeper.RegisterInvariants
+	InvariantRoutes              = keeper.InvariantRoutes
+	CheckInvariants              = keeper.CheckInvariants
 	AllInvariants                = keeper.AllInvariants
 	SupplyInvariants             = keeper.SupplyInvariants
 	NonNegativePowerInvariant    = keeper.NonNegativePowerInvariant
@@ -80,28 +111,63 @@ var (
 	KeyMaxValidators  = types.KeyMaxValidators
 	KeyBondDenom      = types.KeyBondDenom
 
+	KeyShareRoundingMode = types.KeyShareRoundingMode
+	KeyInstantUnbond     = types.KeyInstantUnbond
+
+	KeyValidatorUpdatesHistory      = types.KeyValidatorUpdatesHistory
+	KeyMaxValidatorsCreatedPerBlock = types.KeyMaxValidatorsCreatedPerBlock
+	KeyMaxDelegatorPowerShare       = types.KeyMaxDelegatorPowerShare
+	KeyMaxValidatorsSchedule        = types.KeyMaxValidatorsSchedule
+	KeyLoyaltyWeighting             = types.KeyLoyaltyWeighting
+	KeyLoyaltyPeriod                = types.KeyLoyaltyPeriod
+	KeyMinSlashTokens               = types.KeyMinSlashTokens
+	KeyBondedRatioHistory           = types.KeyBondedRatioHistory
+	KeyTieBreakMode                 = types.KeyTieBreakMode
+	KeyAllowedPubKeyTypes           = types.KeyAllowedPubKeyTypes
+	KeyMinCommissionRate            = types.KeyMinCommissionRate
+	KeyMaxSlashPerInfraction        = types.KeyMaxSlashPerInfraction
+	KeySlashEventsHistory           = types.KeySlashEventsHistory
+	KeyBondedTokensHistory          = types.KeyBondedTokensHistory
+	KeyExRateHistory                = types.KeyExRateHistory
+	KeyAutoCompoundInterval         = types.KeyAutoCompoundInterval
+
 	DefaultParams         = types.DefaultParams
 	InitialPool           = types.InitialPool
 	NewValidator          = types.NewValidator
 	NewDescription        = types.NewDescription
+	NewDelegation         = types.NewDelegation
 	NewCommission         = types.NewCommission
 	NewCommissionMsg      = types.NewCommissionMsg
 	NewCommissionWithTime = types.NewCommissionWithTime
+	NewSlashEvent         = types.NewSlashEvent
 	NewGenesisState       = types.NewGenesisState
+	NewGenesisDelegation  = types.NewGenesisDelegation
 	DefaultGenesisState   = types.DefaultGenesisState
 	RegisterCodec         = types.RegisterCodec
 
-	NewMsgCreateValidator = types.NewMsgCreateValidator
-	NewMsgEditValidator   = types.NewMsgEditValidator
-	NewMsgDelegate        = types.NewMsgDelegate
-	NewMsgUndelegate      = types.NewMsgUndelegate
-	NewMsgBeginRedelegate = types.NewMsgBeginRedelegate
+	NewMsgCreateValidator       = types.NewMsgCreateValidator
+	NewMsgEditValidator         = types.NewMsgEditValidator
+	NewMsgMigrateValidatorOwner = types.NewMsgMigrateValidatorOwner
+	NewMsgSignalExit            = types.NewMsgSignalExit
+	NewMsgDelegate              = types.NewMsgDelegate
+	NewMsgUndelegate            = types.NewMsgUndelegate
+	NewMsgBeginRedelegate       = types.NewMsgBeginRedelegate
+	NewMsgRebalance             = types.NewMsgRebalance
+	NewMsgSetAutoCompound       = types.NewMsgSetAutoCompound
+
+	NewFreezeValidatorProposal = types.NewFreezeValidatorProposal
+
+	NewCompleteUnbondingsProposal      = types.NewCompleteUnbondingsProposal
+	NewVerifyValidatorIdentityProposal = types.NewVerifyValidatorIdentityProposal
 
 	NewQuerier               = querier.NewQuerier
 	NewQueryDelegatorParams  = querier.NewQueryDelegatorParams
 	NewQueryValidatorParams  = querier.NewQueryValidatorParams
 	NewQueryBondsParams      = querier.NewQueryBondsParams
 	NewQueryValidatorsParams = querier.NewQueryValidatorsParams
+
+	NewQueryDelegationsAboveValueParams = querier.NewQueryDelegationsAboveValueParams
+	NewQueryBondedRatioHistoryParams    = querier.NewQueryBondedRatioHistoryParams
 )
 
 const (
@@ -110,6 +176,15 @@ const (
 	QueryValidatorDelegations          = querier.QueryValidatorDelegations
 	QueryValidatorRedelegations        = querier.QueryValidatorRedelegations
 	QueryValidatorUnbondingDelegations = querier.QueryValidatorUnbondingDelegations
+	QueryValidatorSlashEvents          = querier.QueryValidatorSlashEvents
+	QueryValidatorEntryCost            = querier.QueryValidatorEntryCost
+	QueryValidatorBondingStatus        = querier.QueryValidatorBondingStatus
+	QueryValidatorConcentration        = querier.QueryValidatorConcentration
+	QueryGenesisValidators             = querier.QueryGenesisValidators
+	QueryValidatorsUnbonding           = querier.QueryValidatorsUnbonding
+	QueryJailedValidators              = querier.QueryJailedValidators
+	QueryDelegationsAboveValue         = querier.QueryDelegationsAboveValue
+	QueryDelegationCount               = querier.QueryDelegationCount
 	QueryDelegation                    = querier.QueryDelegation
 	QueryUnbondingDelegation           = querier.QueryUnbondingDelegation
 	QueryDelegatorDelegations          = querier.QueryDelegatorDelegations
@@ -118,7 +193,16 @@ const (
 	QueryDelegatorValidators           = querier.QueryDelegatorValidators
 	QueryDelegatorValidator            = querier.QueryDelegatorValidator
 	QueryPool                          = querier.QueryPool
+	QueryBondedRatioHistory            = querier.QueryBondedRatioHistory
 	QueryParameters                    = querier.QueryParameters
+	QueryInvariants                    = querier.QueryInvariants
+)
+
+const (
+	ShareRoundDown    = types.ShareRoundDown
+	ShareRoundNearest = types.ShareRoundNearest
+	TieBreakStakeAge  = types.TieBreakStakeAge
+	TieBreakTxOrder   = types.TieBreakTxOrder
 )
 
 const (
@@ -140,13 +224,18 @@ var (
 	ErrNilValidatorAddr               = types.ErrNilValidatorAddr
 	ErrNoValidatorFound               = types.ErrNoValidatorFound
 	ErrValidatorOwnerExists           = types.ErrValidatorOwnerExists
+	ErrMaxValidatorsCreatedPerBlock   = types.ErrMaxValidatorsCreatedPerBlock
+	ErrMaxDelegatorPowerShare         = types.ErrMaxDelegatorPowerShare
+	ErrValidatorFrozen                = types.ErrValidatorFrozen
 	ErrValidatorPubKeyExists          = types.ErrValidatorPubKeyExists
 	ErrValidatorPubKeyTypeUnsupported = types.ErrValidatorPubKeyTypeNotSupported
 	ErrValidatorJailed                = types.ErrValidatorJailed
 	ErrBadRemoveValidator             = types.ErrBadRemoveValidator
 	ErrDescriptionLength              = types.ErrDescriptionLength
+	ErrInvalidSecurityContact         = types.ErrInvalidSecurityContact
 	ErrCommissionNegative             = types.ErrCommissionNegative
 	ErrCommissionHuge                 = types.ErrCommissionHuge
+	ErrCommissionBelowMinimum         = types.ErrCommissionBelowMinimum
 
 	ErrNilDelegatorAddr          = types.ErrNilDelegatorAddr
 	ErrBadDenom                  = types.ErrBadDenom
@@ -162,6 +251,7 @@ var (
 
 	ErrNotMature             = types.ErrNotMature
 	ErrNoUnbondingDelegation = types.ErrNoUnbondingDelegation
+	ErrInstantUnbondDisabled = types.ErrInstantUnbondDisabled
 	ErrNoRedelegation        = types.ErrNoRedelegation
 	ErrBadRedelegationDst    = types.ErrBadRedelegationDst
 
@@ -172,4 +262,7 @@ var (
 	ErrMinSelfDelegationInvalid   = types.ErrMinSelfDelegationInvalid
 	ErrMinSelfDelegationDecreased = types.ErrMinSelfDelegationDecreased
 	ErrSelfDelegationBelowMinimum = types.ErrSelfDelegationBelowMinimum
+
+	ErrMaxTotalDelegationInvalid  = types.ErrMaxTotalDelegationInvalid
+	ErrMaxTotalDelegationExceeded = types.ErrMaxTotalDelegationExceeded
 )
@@ -117,7 +117,7 @@ func TestStakingMsgs(t *testing.T) {
 	mock.CheckBalance(t, mApp, addr2, sdk.Coins{genCoin})
 
 	// create validator
-	description := NewDescription("foo_moniker", "", "", "")
+	description := NewDescription("foo_moniker", "", "", "", "")
 	createValidatorMsg := NewMsgCreateValidator(
 		sdk.ValAddress(addr1), priv1.PubKey(), bondCoin, description, commissionMsg, sdk.OneInt(),
 	)
@@ -138,8 +138,8 @@ func TestStakingMsgs(t *testing.T) {
 	mApp.BeginBlock(abci.RequestBeginBlock{Header: header})
 
 	// edit the validator
-	description = NewDescription("bar_moniker", "", "", "")
-	editValidatorMsg := NewMsgEditValidator(sdk.ValAddress(addr1), description, nil, nil)
+	description = NewDescription("bar_moniker", "", "", "", "")
+	editValidatorMsg := NewMsgEditValidator(sdk.ValAddress(addr1), description, nil, nil, nil, nil)
 
 	header = abci.Header{Height: mApp.LastBlockHeight() + 1}
 	mock.SignCheckDeliver(t, mApp.Cdc, mApp.BaseApp, header, []sdk.Msg{editValidatorMsg}, []uint64{0}, []uint64{1}, true, true, priv1)
@@ -16,16 +16,19 @@ const (
 	FlagSharesAmount        = "shares-amount"
 	FlagSharesFraction      = "shares-fraction"
 
-	FlagMoniker  = "moniker"
-	FlagIdentity = "identity"
-	FlagWebsite  = "website"
-	FlagDetails  = "details"
+	FlagMoniker         = "moniker"
+	FlagIdentity        = "identity"
+	FlagWebsite         = "website"
+	FlagSecurityContact = "security-contact"
+	FlagDetails         = "details"
 
 	FlagCommissionRate          = "commission-rate"
 	FlagCommissionMaxRate       = "commission-max-rate"
 	FlagCommissionMaxChangeRate = "commission-max-change-rate"
 
-	FlagMinSelfDelegation = "min-self-delegation"
+	FlagMinSelfDelegation  = "min-self-delegation"
+	FlagMaxTotalDelegation = "max-total-delegation"
+	FlagUnbondingTime      = "unbonding-time"
 
 	FlagGenesisFormat = "genesis-format"
 	FlagNodeID        = "node-id"
@@ -41,6 +44,8 @@ var (
 	FsCommissionCreate  = flag.NewFlagSet("", flag.ContinueOnError)
 	fsCommissionUpdate  = flag.NewFlagSet("", flag.ContinueOnError)
 	FsMinSelfDelegation = flag.NewFlagSet("", flag.ContinueOnError)
+	fsMaxDelegation     = flag.NewFlagSet("", flag.ContinueOnError)
+	fsUnbondingTime     = flag.NewFlagSet("", flag.ContinueOnError)
 	fsDescriptionEdit   = flag.NewFlagSet("", flag.ContinueOnError)
 	fsValidator         = flag.NewFlagSet("", flag.ContinueOnError)
 	fsDelegator         = flag.NewFlagSet("", flag.ContinueOnError)
@@ -55,15 +60,19 @@ func init() {
 	fsDescriptionCreate.String(FlagMoniker, "", "The validator's name")
 	fsDescriptionCreate.String(FlagIdentity, "", "The optional identity signature (ex. UPort or Keybase)")
 	fsDescriptionCreate.String(FlagWebsite, "", "The validator's (optional) website")
+	fsDescriptionCreate.String(FlagSecurityContact, "", "The validator's (optional) security contact email or URL")
 	fsDescriptionCreate.String(FlagDetails, "", "The validator's (optional) details")
 	fsCommissionUpdate.String(FlagCommissionRate, "", "The new commission rate percentage")
 	FsCommissionCreate.String(FlagCommissionRate, "", "The initial commission rate percentage")
 	FsCommissionCreate.String(FlagCommissionMaxRate, "", "The maximum commission rate percentage")
 	FsCommissionCreate.String(FlagCommissionMaxChangeRate, "", "The maximum commission change rate percentage (per day)")
 	FsMinSelfDelegation.String(FlagMinSelfDelegation, "", "The minimum self delegation required on the validator")
+	fsMaxDelegation.String(FlagMaxTotalDelegation, "", "The maximum total delegator shares accepted by the validator (0 for no cap)")
+	fsUnbondingTime.String(FlagUnbondingTime, "", "The unbonding time of the validator, at least the unbonding time param (ex. 504h, 0 to use the param)")
 	fsDescriptionEdit.String(FlagMoniker, types.DoNotModifyDesc, "The validator's name")
 	fsDescriptionEdit.String(FlagIdentity, types.DoNotModifyDesc, "The (optional) identity signature (ex. UPort or Keybase)")
 	fsDescriptionEdit.String(FlagWebsite, types.DoNotModifyDesc, "The validator's (optional) website")
+	fsDescriptionEdit.String(FlagSecurityContact, types.DoNotModifyDesc, "The validator's (optional) security contact email or URL")
 	fsDescriptionEdit.String(FlagDetails, types.DoNotModifyDesc, "The validator's (optional) details")
 	fsValidator.String(FlagAddressValidator, "", "The Bech32 address of the validator")
 	fsRedelegation.String(FlagAddressValidatorSrc, "", "The Bech32 address of the source validator")
@@ -2,7 +2,9 @@ package cli
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/cosmos/cosmos-sdk/x/auth"
 
@@ -70,10 +72,11 @@ func GetCmdEditValidator(cdc *codec.Codec) *cobra.Command {
 
 			valAddr := cliCtx.GetFromAddress()
 			description := staking.Description{
-				Moniker:  viper.GetString(FlagMoniker),
-				Identity: viper.GetString(FlagIdentity),
-				Website:  viper.GetString(FlagWebsite),
-				Details:  viper.GetString(FlagDetails),
+				Moniker:         viper.GetString(FlagMoniker),
+				Identity:        viper.GetString(FlagIdentity),
+				Website:         viper.GetString(FlagWebsite),
+				SecurityContact: viper.GetString(FlagSecurityContact),
+				Details:         viper.GetString(FlagDetails),
 			}
 
 			var newRate *sdk.Dec
@@ -99,7 +102,32 @@ func GetCmdEditValidator(cdc *codec.Codec) *cobra.Command {
 				newMinSelfDelegation = &msb
 			}
 
-			msg := staking.NewMsgEditValidator(sdk.ValAddress(valAddr), description, newRate, newMinSelfDelegation)
+			var newMaxTotalDelegation *sdk.Dec
+
+			maxTotalDelegationString := viper.GetString(FlagMaxTotalDelegation)
+			if maxTotalDelegationString != "" {
+				mtd, err := sdk.NewDecFromStr(maxTotalDelegationString)
+				if err != nil {
+					return fmt.Errorf("invalid new max total delegation: %v", err)
+				}
+				newMaxTotalDelegation = &mtd
+			}
+
+			var newUnbondingTime *time.Duration
+
+			unbondingTimeString := viper.GetString(FlagUnbondingTime)
+			if unbondingTimeString != "" {
+				ut, err := time.ParseDuration(unbondingTimeString)
+				if err != nil {
+					return fmt.Errorf("invalid new unbonding time: %v", err)
+				}
+				newUnbondingTime = &ut
+			}
+
+			msg := staking.NewMsgEditValidator(
+				sdk.ValAddress(valAddr), description, newRate, newMinSelfDelegation, newMaxTotalDelegation,
+				newUnbondingTime,
+			)
 
 			// build and sign the transaction, then broadcast to Tendermint
 			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
@@ -108,10 +136,74 @@ func GetCmdEditValidator(cdc *codec.Codec) *cobra.Command {
 
 	cmd.Flags().AddFlagSet(fsDescriptionEdit)
 	cmd.Flags().AddFlagSet(fsCommissionUpdate)
+	cmd.Flags().AddFlagSet(fsMaxDelegation)
+	cmd.Flags().AddFlagSet(fsUnbondingTime)
 
 	return cmd
 }
 
+// GetCmdMigrateValidatorOwner implements the command moving a validator to a
+// new operator address.
+func GetCmdMigrateValidatorOwner(cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "migrate-owner [new-owner-addr]",
+		Args:  cobra.ExactArgs(1),
+		Short: "move your validator and its self-delegation to a new operator address",
+		Long: strings.TrimSpace(`Move the validator operated by your key, along with its self-delegation, to a
+new operator address. The validator must not have other delegators nor pending
+unbondings or redelegations:
+
+$ gaiacli tx staking migrate-owner cosmosvaloper1l2rsakp388kuv9k8qzq6lrm9taddae7fpx59wm --from mykey
+`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			txBldr := authtxb.NewTxBuilderFromCLI().WithTxEncoder(auth.DefaultTxEncoder(cdc))
+			cliCtx := context.NewCLIContext().
+				WithCodec(cdc).
+				WithAccountDecoder(cdc)
+
+			newOwnerAddr, err := sdk.ValAddressFromBech32(args[0])
+			if err != nil {
+				return err
+			}
+
+			valAddr := cliCtx.GetFromAddress()
+			msg := staking.NewMsgMigrateValidatorOwner(sdk.ValAddress(valAddr), newOwnerAddr)
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
+		},
+	}
+}
+
+// GetCmdSignalExit implements the command announcing the height at which
+// a validator intends to leave.
+func GetCmdSignalExit(cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "signal-exit [exit-height]",
+		Args:  cobra.ExactArgs(1),
+		Short: "announce the height at which your validator intends to leave",
+		Long: strings.TrimSpace(`Announce the height at which the validator operated by your key intends to
+leave, so its delegators can redelegate in time. The signal is advisory and does
+not change the validator's power. A height of 0 withdraws it:
+
+$ gaiacli tx staking signal-exit 1500000 --from mykey
+`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			txBldr := authtxb.NewTxBuilderFromCLI().WithTxEncoder(auth.DefaultTxEncoder(cdc))
+			cliCtx := context.NewCLIContext().
+				WithCodec(cdc).
+				WithAccountDecoder(cdc)
+
+			exitHeight, err := strconv.ParseInt(args[0], 10, 64)
+			if err != nil {
+				return err
+			}
+
+			valAddr := cliCtx.GetFromAddress()
+			msg := staking.NewMsgSignalExit(sdk.ValAddress(valAddr), exitHeight)
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
+		},
+	}
+}
+
 // GetCmdDelegate implements the delegate command.
 func GetCmdDelegate(cdc *codec.Codec) *cobra.Command {
 	return &cobra.Command{
@@ -183,6 +275,84 @@ $ gaiacli tx staking redelegate cosmosvaloper1gghjut3ccd8ay0zduzj64hwre2fxs9ldmq
 	}
 }
 
+// GetCmdRebalance implements the command delegating to one validator and
+// unbonding from another in a single transaction.
+func GetCmdRebalance(cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "rebalance [src-validator-addr] [dst-validator-addr] [amount]",
+		Short: "delegate liquid tokens to one validator and unbond the same amount from another",
+		Args:  cobra.ExactArgs(3),
+		Long: strings.TrimSpace(`Delegate an amount of liquid coins to the destination validator and begin
+unbonding the same amount from the source validator. Either both happen or
+neither does:
+
+$ gaiacli tx staking rebalance cosmosvaloper1gghjut3ccd8ay0zduzj64hwre2fxs9ldmqhffj cosmosvaloper1l2rsakp388kuv9k8qzq6lrm9taddae7fpx59wm 100stake --from mykey
+`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			txBldr := authtxb.NewTxBuilderFromCLI().WithTxEncoder(auth.DefaultTxEncoder(cdc))
+			cliCtx := context.NewCLIContext().
+				WithCodec(cdc).
+				WithAccountDecoder(cdc)
+
+			delAddr := cliCtx.GetFromAddress()
+			valSrcAddr, err := sdk.ValAddressFromBech32(args[0])
+			if err != nil {
+				return err
+			}
+
+			valDstAddr, err := sdk.ValAddressFromBech32(args[1])
+			if err != nil {
+				return err
+			}
+
+			amount, err := sdk.ParseCoin(args[2])
+			if err != nil {
+				return err
+			}
+
+			msg := staking.NewMsgRebalance(delAddr, valSrcAddr, valDstAddr, amount)
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
+		},
+	}
+}
+
+// GetCmdSetAutoCompound implements the command enabling or disabling the
+// auto-compounding of a delegation's rewards.
+func GetCmdSetAutoCompound(cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "set-auto-compound [validator-addr] [true|false]",
+		Args:  cobra.ExactArgs(2),
+		Short: "enable or disable re-delegating your rewards from a validator every AutoCompoundInterval blocks",
+		Long: strings.TrimSpace(`Enable or disable the auto-compounding of your delegation to a validator. While
+enabled, the rewards of the delegation are withdrawn and delegated to the same
+validator once every AutoCompoundInterval blocks, a staking parameter which
+defaults to one day of blocks. A zero interval disables the compounding:
+
+$ gaiacli tx staking set-auto-compound cosmosvaloper1l2rsakp388kuv9k8qzq6lrm9taddae7fpx59wm true --from mykey
+`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			txBldr := authtxb.NewTxBuilderFromCLI().WithTxEncoder(auth.DefaultTxEncoder(cdc))
+			cliCtx := context.NewCLIContext().
+				WithCodec(cdc).
+				WithAccountDecoder(cdc)
+
+			valAddr, err := sdk.ValAddressFromBech32(args[0])
+			if err != nil {
+				return err
+			}
+
+			enabled, err := strconv.ParseBool(args[1])
+			if err != nil {
+				return err
+			}
+
+			delAddr := cliCtx.GetFromAddress()
+			msg := staking.NewMsgSetAutoCompound(delAddr, valAddr, enabled)
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
+		},
+	}
+}
+
 // GetCmdUnbond implements the unbond validator command.
 func GetCmdUnbond(storeName string, cdc *codec.Codec) *cobra.Command {
 	return &cobra.Command{
@@ -237,6 +407,7 @@ func BuildCreateValidatorMsg(cliCtx context.CLIContext, txBldr authtxb.TxBuilder
 		viper.GetString(FlagIdentity),
 		viper.GetString(FlagWebsite),
 		viper.GetString(FlagDetails),
+		viper.GetString(FlagSecurityContact),
 	)
 
 	// get the initial validator commission parameters
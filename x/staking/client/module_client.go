@@ -54,9 +54,13 @@ func (mc ModuleClient) GetTxCmd() *cobra.Command {
 	stakingTxCmd.AddCommand(client.PostCommands(
 		cli.GetCmdCreateValidator(mc.cdc),
 		cli.GetCmdEditValidator(mc.cdc),
+		cli.GetCmdMigrateValidatorOwner(mc.cdc),
+		cli.GetCmdSignalExit(mc.cdc),
 		cli.GetCmdDelegate(mc.cdc),
 		cli.GetCmdRedelegate(mc.storeKey, mc.cdc),
 		cli.GetCmdUnbond(mc.storeKey, mc.cdc),
+		cli.GetCmdRebalance(mc.cdc),
+		cli.GetCmdSetAutoCompound(mc.cdc),
 	)...)
 
 	return stakingTxCmd
@@ -2,7 +2,9 @@ package rest
 
 import (
 	"fmt"
+	"math"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/cosmos/cosmos-sdk/client/context"
@@ -57,6 +59,12 @@ func registerQueryRoutes(cliCtx context.CLIContext, r *mux.Router, cdc *codec.Co
 		unbondingDelegationHandlerFn(cliCtx, cdc),
 	).Methods("GET")
 
+	// Get all delegations worth more than a token amount (paginated)
+	r.HandleFunc(
+		"/staking/delegations",
+		delegationsAboveValueHandlerFn(cliCtx, cdc),
+	).Methods("GET")
+
 	// Query redelegations (filters in query params)
 	r.HandleFunc(
 		"/staking/redelegations",
@@ -69,6 +77,30 @@ func registerQueryRoutes(cliCtx context.CLIContext, r *mux.Router, cdc *codec.Co
 		validatorsHandlerFn(cliCtx, cdc),
 	).Methods("GET")
 
+	// Get the tokens needed to displace the cliff validator
+	r.HandleFunc(
+		"/staking/validators/entry_cost",
+		validatorEntryCostHandlerFn(cliCtx, cdc),
+	).Methods("GET")
+
+	// Get the validator set as it existed at genesis
+	r.HandleFunc(
+		"/staking/validators/genesis",
+		genesisValidatorsHandlerFn(cliCtx, cdc),
+	).Methods("GET")
+
+	// Get the validators which are unbonding
+	r.HandleFunc(
+		"/staking/validators/unbonding",
+		validatorsUnbondingHandlerFn(cliCtx, cdc),
+	).Methods("GET")
+
+	// Get the validators which are jailed
+	r.HandleFunc(
+		"/staking/validators/jailed",
+		jailedValidatorsHandlerFn(cliCtx, cdc),
+	).Methods("GET")
+
 	// Get a single validator info
 	r.HandleFunc(
 		"/staking/validators/{validatorAddr}",
@@ -87,18 +119,54 @@ func registerQueryRoutes(cliCtx context.CLIContext, r *mux.Router, cdc *codec.Co
 		validatorUnbondingDelegationsHandlerFn(cliCtx, cdc),
 	).Methods("GET")
 
+	// Get all slash events of a validator
+	r.HandleFunc(
+		"/staking/validators/{validatorAddr}/slash_events",
+		validatorSlashEventsHandlerFn(cliCtx, cdc),
+	).Methods("GET")
+
+	// Get the reasons a validator is or is not eligible to bond
+	r.HandleFunc(
+		"/staking/validators/{validatorAddr}/bonding_status",
+		validatorBondingStatusHandlerFn(cliCtx, cdc),
+	).Methods("GET")
+
+	// Get the largest single delegation's share of a validator
+	r.HandleFunc(
+		"/staking/validators/{validatorAddr}/delegation_concentration",
+		validatorConcentrationHandlerFn(cliCtx, cdc),
+	).Methods("GET")
+
+	// Get the total number of delegations
+	r.HandleFunc(
+		"/staking/delegations/count",
+		delegationCountHandlerFn(cliCtx, cdc),
+	).Methods("GET")
+
 	// Get the current state of the staking pool
 	r.HandleFunc(
 		"/staking/pool",
 		poolHandlerFn(cliCtx, cdc),
 	).Methods("GET")
 
+	// Get the recorded bonded ratio per block height
+	r.HandleFunc(
+		"/staking/bonded_ratio/history",
+		bondedRatioHistoryHandlerFn(cliCtx, cdc),
+	).Methods("GET")
+
 	// Get the current staking parameter values
 	r.HandleFunc(
 		"/staking/parameters",
 		paramsHandlerFn(cliCtx, cdc),
 	).Methods("GET")
 
+	// Get the result of every staking invariant
+	r.HandleFunc(
+		"/staking/invariants",
+		invariantsHandlerFn(cliCtx, cdc),
+	).Methods("GET")
+
 }
 
 // HTTP request handler to query a delegator delegations
@@ -241,6 +309,38 @@ func delegatorValidatorHandlerFn(cliCtx context.CLIContext, cdc *codec.Codec) ht
 	return queryBonds(cliCtx, cdc, "custom/staking/delegatorValidator")
 }
 
+// HTTP request handler to query the delegations worth more than a token amount
+func delegationsAboveValueHandlerFn(cliCtx context.CLIContext, cdc *codec.Codec) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		_, page, limit, err := rest.ParseHTTPArgs(r)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		minTokens, ok := sdk.NewIntFromString(r.FormValue("min_tokens"))
+		if !ok {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, "min_tokens must be an integer amount of tokens")
+			return
+		}
+
+		params := staking.NewQueryDelegationsAboveValueParams(page, limit, minTokens)
+		bz, err := cdc.MarshalJSON(params)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		route := fmt.Sprintf("custom/%s/%s", staking.QuerierRoute, staking.QueryDelegationsAboveValue)
+		res, err := cliCtx.QueryWithData(route, bz)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		rest.PostProcessResponse(w, cdc, res, cliCtx.Indent)
+	}
+}
+
 // HTTP request handler to query list of validators
 func validatorsHandlerFn(cliCtx context.CLIContext, cdc *codec.Codec) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -287,6 +387,69 @@ func validatorUnbondingDelegationsHandlerFn(cliCtx context.CLIContext, cdc *code
 	return queryValidator(cliCtx, cdc, "custom/staking/validatorUnbondingDelegations")
 }
 
+// HTTP request handler to query the slash events of a validator
+func validatorSlashEventsHandlerFn(cliCtx context.CLIContext, cdc *codec.Codec) http.HandlerFunc {
+	return queryValidator(cliCtx, cdc, "custom/staking/validatorSlashEvents")
+}
+
+// HTTP request handler to query the bonding eligibility of a validator
+func validatorBondingStatusHandlerFn(cliCtx context.CLIContext, cdc *codec.Codec) http.HandlerFunc {
+	return queryValidator(cliCtx, cdc, "custom/staking/validatorBondingStatus")
+}
+
+// HTTP request handler to query the delegation concentration of a validator
+func validatorConcentrationHandlerFn(cliCtx context.CLIContext, cdc *codec.Codec) http.HandlerFunc {
+	return queryValidator(cliCtx, cdc, "custom/staking/validatorConcentration")
+}
+
+// HTTP request handler to query the entry cost of the bonded validator set
+func validatorEntryCostHandlerFn(cliCtx context.CLIContext, cdc *codec.Codec) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		res, err := cliCtx.QueryWithData("custom/staking/validatorEntryCost", nil)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		rest.PostProcessResponse(w, cdc, res, cliCtx.Indent)
+	}
+}
+
+// HTTP request handler to query the validator set at genesis
+func genesisValidatorsHandlerFn(cliCtx context.CLIContext, cdc *codec.Codec) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		res, err := cliCtx.QueryWithData("custom/staking/genesisValidators", nil)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		rest.PostProcessResponse(w, cdc, res, cliCtx.Indent)
+	}
+}
+
+// HTTP request handler to query the validators which are unbonding
+func validatorsUnbondingHandlerFn(cliCtx context.CLIContext, cdc *codec.Codec) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		res, err := cliCtx.QueryWithData("custom/staking/validatorsUnbonding", nil)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		rest.PostProcessResponse(w, cdc, res, cliCtx.Indent)
+	}
+}
+
+// HTTP request handler to query the validators which are jailed
+func jailedValidatorsHandlerFn(cliCtx context.CLIContext, cdc *codec.Codec) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		res, err := cliCtx.QueryWithData("custom/staking/jailedValidators", nil)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		rest.PostProcessResponse(w, cdc, res, cliCtx.Indent)
+	}
+}
+
 // HTTP request handler to query the pool information
 func poolHandlerFn(cliCtx context.CLIContext, cdc *codec.Codec) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -299,6 +462,57 @@ func poolHandlerFn(cliCtx context.CLIContext, cdc *codec.Codec) http.HandlerFunc
 	}
 }
 
+// HTTP request handler to query the recorded bonded ratio per block height
+func bondedRatioHistoryHandlerFn(cliCtx context.CLIContext, cdc *codec.Codec) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		from, to := int64(0), int64(math.MaxInt64)
+
+		var err error
+		if s := r.URL.Query().Get("from"); s != "" {
+			if from, err = strconv.ParseInt(s, 10, 64); err != nil {
+				rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+				return
+			}
+		}
+		if s := r.URL.Query().Get("to"); s != "" {
+			if to, err = strconv.ParseInt(s, 10, 64); err != nil {
+				rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+				return
+			}
+		}
+		if from > to {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, "from height must not exceed to height")
+			return
+		}
+
+		bz, err := cdc.MarshalJSON(staking.NewQueryBondedRatioHistoryParams(from, to))
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		route := fmt.Sprintf("custom/%s/%s", staking.QuerierRoute, staking.QueryBondedRatioHistory)
+		res, err := cliCtx.QueryWithData(route, bz)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		rest.PostProcessResponse(w, cdc, res, cliCtx.Indent)
+	}
+}
+
+// HTTP request handler to query the total number of delegations
+func delegationCountHandlerFn(cliCtx context.CLIContext, cdc *codec.Codec) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		res, err := cliCtx.QueryWithData("custom/staking/delegationCount", nil)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		rest.PostProcessResponse(w, cdc, res, cliCtx.Indent)
+	}
+}
+
 // HTTP request handler to query the staking params values
 func paramsHandlerFn(cliCtx context.CLIContext, cdc *codec.Codec) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -310,3 +524,15 @@ func paramsHandlerFn(cliCtx context.CLIContext, cdc *codec.Codec) http.HandlerFu
 		rest.PostProcessResponse(w, cdc, res, cliCtx.Indent)
 	}
 }
+
+// HTTP request handler to query the staking invariant results
+func invariantsHandlerFn(cliCtx context.CLIContext, cdc *codec.Codec) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		res, err := cliCtx.QueryWithData("custom/staking/invariants", nil)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		rest.PostProcessResponse(w, cdc, res, cliCtx.Indent)
+	}
+}
@@ -13,7 +13,10 @@ import (
 // InitGenesis sets the pool and parameters for the provided keeper.  For each
 // validator in data, it sets that validator in the keeper along with manually
 // setting the indexes. In addition, it also sets any delegations found in
-// data. Finally, it updates the bonded validators.
+// data and bonds the tokens of the genesis delegations, which are exported as
+// regular delegations. Finally, it updates the bonded validators. Validator
+// tokens and delegator shares are stored as given, without passing through
+// the exchange-rate logic, so exported state re-imports exactly.
 // Returns final validator set after applying all declaration and delegations
 func InitGenesis(ctx sdk.Context, keeper Keeper, data types.GenesisState) (res []abci.ValidatorUpdate, err error) {
 
@@ -24,8 +27,20 @@ func InitGenesis(ctx sdk.Context, keeper Keeper, data types.GenesisState) (res [
 	// genesis.json are in block 0.
 	ctx = ctx.WithBlockHeight(1 - sdk.ValidatorUpdateDelay)
 
-	keeper.SetPool(ctx, data.Pool)
+	// a pool without the unbonding bucket counts the balances of unbonding
+	// delegations among its not-bonded tokens
+	pool := data.Pool
+	if pool.UnbondingTokens.IsNil() {
+		pool.UnbondingTokens = sdk.ZeroInt()
+		for _, ubd := range data.UnbondingDelegations {
+			for _, entry := range ubd.Entries {
+				pool = pool.NotBondedTokensToUnbonding(entry.Balance)
+			}
+		}
+	}
+	keeper.SetPool(ctx, pool)
 	keeper.SetParams(ctx, data.Params)
+	keeper.ApplyTieBreakMode(ctx)
 	keeper.SetLastTotalPower(ctx, data.LastTotalPower)
 
 	for _, validator := range data.Validators {
@@ -58,6 +73,11 @@ func InitGenesis(ctx sdk.Context, keeper Keeper, data types.GenesisState) (res [
 		}
 	}
 
+	// bond the tokens of the genesis delegations, e.g. of an airdrop
+	if err = InitGenesisDelegations(ctx, keeper, data.GenesisDelegations); err != nil {
+		return nil, err
+	}
+
 	for _, ubd := range data.UnbondingDelegations {
 		keeper.SetUnbondingDelegation(ctx, ubd)
 		for _, entry := range ubd.Entries {
@@ -72,6 +92,13 @@ func InitGenesis(ctx sdk.Context, keeper Keeper, data types.GenesisState) (res [
 		}
 	}
 
+	for _, valEvents := range data.ValidatorSlashEvents {
+		keeper.SetValidatorSlashEvents(ctx, valEvents.ValidatorAddress, valEvents.Events)
+	}
+
+	// refuse to start from an inconsistent state
+	keeper.AssertInvariants(ctx)
+
 	// don't need to run Tendermint updates if we exported
 	if data.Exported {
 		for _, lv := range data.LastValidatorPowers {
@@ -88,18 +115,108 @@ func InitGenesis(ctx sdk.Context, keeper Keeper, data types.GenesisState) (res [
 		res = keeper.ApplyAndReturnValidatorSetUpdates(ctx)
 	}
 
+	keeper.SnapshotGenesisValidators(ctx)
 	return
 }
 
+// InitGenesisDelegations bonds the given amounts to existing validators,
+// issuing delegator shares and updating the pool accordingly. Unlike the
+// delegate handler, each validator, its power index and the pool are written
+// once for the whole batch, which keeps large airdrops cheap. The tokens are
+// not deducted from any account but added to the supply of the pool. All
+// entries are validated before any state is written, so an error leaves the
+// store untouched.
+func InitGenesisDelegations(ctx sdk.Context, keeper Keeper, dels []types.GenesisDelegation) error {
+	pool := keeper.GetPool(ctx)
+	rounding := keeper.ShareRoundingMode(ctx)
+
+	// validators and delegations touched, in order of first appearance to
+	// keep the store writes deterministic
+	var valAddrs []sdk.ValAddress
+	original := make(map[string]types.Validator)
+	validators := make(map[string]types.Validator)
+	var delKeys []string
+	delegations := make(map[string]types.Delegation)
+	existing := make(map[string]bool)
+
+	for _, del := range dels {
+		if del.Amount.BigInt() == nil || !del.Amount.IsPositive() {
+			return fmt.Errorf("invalid genesis delegation amount %v from %s to %s",
+				del.Amount, del.DelegatorAddress, del.ValidatorAddress)
+		}
+
+		valKey := string(del.ValidatorAddress)
+		validator, ok := validators[valKey]
+		if !ok {
+			var found bool
+			validator, found = keeper.GetValidator(ctx, del.ValidatorAddress)
+			if !found {
+				return fmt.Errorf("genesis delegation to unknown validator %s", del.ValidatorAddress)
+			}
+			valAddrs = append(valAddrs, del.ValidatorAddress)
+			original[valKey] = validator
+		}
+		if validator.InvalidExRate() {
+			return fmt.Errorf("genesis delegation to validator %s with invalid exchange rate", del.ValidatorAddress)
+		}
+
+		var newShares sdk.Dec
+		pool.NotBondedTokens = pool.NotBondedTokens.Add(del.Amount)
+		validator, pool, newShares = validator.AddTokensFromDel(pool, del.Amount, rounding)
+		validators[valKey] = validator
+
+		delKey := string(GetDelegationKey(del.DelegatorAddress, del.ValidatorAddress))
+		delegation, ok := delegations[delKey]
+		if !ok {
+			var found bool
+			delegation, found = keeper.GetDelegation(ctx, del.DelegatorAddress, del.ValidatorAddress)
+			if !found {
+				delegation = types.NewDelegation(del.DelegatorAddress, del.ValidatorAddress, sdk.ZeroDec())
+				delegation.BondHeight = ctx.BlockHeight()
+			}
+			delKeys = append(delKeys, delKey)
+			existing[delKey] = found
+		}
+		delegation.Shares = delegation.Shares.Add(newShares)
+		delegation.Height = ctx.BlockHeight()
+		delegations[delKey] = delegation
+	}
+
+	for _, valAddr := range valAddrs {
+		keeper.DeleteValidatorByPowerIndex(ctx, original[string(valAddr)])
+		validator := validators[string(valAddr)]
+		keeper.SetValidator(ctx, validator)
+		keeper.SetValidatorByPowerIndex(ctx, validator)
+	}
+	keeper.SetPool(ctx, pool)
+
+	// the hooks run once the validators are final, so that the distribution
+	// of each delegation is initialized against its final stake
+	for _, delKey := range delKeys {
+		delegation := delegations[delKey]
+		if existing[delKey] {
+			keeper.BeforeDelegationSharesModified(ctx, delegation.DelegatorAddress, delegation.ValidatorAddress)
+		} else {
+			keeper.BeforeDelegationCreated(ctx, delegation.DelegatorAddress, delegation.ValidatorAddress)
+		}
+		keeper.SetDelegation(ctx, delegation)
+		keeper.AfterDelegationModified(ctx, delegation.DelegatorAddress, delegation.ValidatorAddress)
+	}
+
+	return nil
+}
+
 // ExportGenesis returns a GenesisState for a given context and keeper. The
 // GenesisState will contain the pool, params, validators, and bonds found in
-// the keeper.
+// the keeper. Delegations are sorted by delegator and then validator address
+// so the export does not depend on how the state was built.
 func ExportGenesis(ctx sdk.Context, keeper Keeper) types.GenesisState {
 	pool := keeper.GetPool(ctx)
 	params := keeper.GetParams(ctx)
 	lastTotalPower := keeper.GetLastTotalPower(ctx)
 	validators := keeper.GetAllValidators(ctx)
 	delegations := keeper.GetAllDelegations(ctx)
+	types.Delegations(delegations).Sort()
 	var unbondingDelegations []types.UnbondingDelegation
 	keeper.IterateUnbondingDelegations(ctx, func(_ int64, ubd types.UnbondingDelegation) (stop bool) {
 		unbondingDelegations = append(unbondingDelegations, ubd)
@@ -110,6 +227,11 @@ func ExportGenesis(ctx sdk.Context, keeper Keeper) types.GenesisState {
 		redelegations = append(redelegations, red)
 		return false
 	})
+	var validatorSlashEvents []types.ValidatorSlashEvents
+	keeper.IterateValidatorSlashEvents(ctx, func(addr sdk.ValAddress, events types.SlashEvents) (stop bool) {
+		validatorSlashEvents = append(validatorSlashEvents, types.ValidatorSlashEvents{ValidatorAddress: addr, Events: events})
+		return false
+	})
 	var lastValidatorPowers []types.LastValidatorPower
 	keeper.IterateLastValidatorPowers(ctx, func(addr sdk.ValAddress, power int64) (stop bool) {
 		lastValidatorPowers = append(lastValidatorPowers, types.LastValidatorPower{addr, power})
@@ -125,6 +247,7 @@ func ExportGenesis(ctx sdk.Context, keeper Keeper) types.GenesisState {
 		Delegations:          delegations,
 		UnbondingDelegations: unbondingDelegations,
 		Redelegations:        redelegations,
+		ValidatorSlashEvents: validatorSlashEvents,
 		Exported:             true,
 	}
 }
@@ -1,6 +1,7 @@
 package staking
 
 import (
+	"bytes"
 	"fmt"
 	"testing"
 
@@ -30,13 +31,13 @@ func TestInitGenesis(t *testing.T) {
 	// initialize the validators
 	validators[0].OperatorAddress = sdk.ValAddress(keep.Addrs[0])
 	validators[0].ConsPubKey = keep.PKs[0]
-	validators[0].Description = NewDescription("hoop", "", "", "")
+	validators[0].Description = NewDescription("hoop", "", "", "", "")
 	validators[0].Status = sdk.Bonded
 	validators[0].Tokens = valTokens
 	validators[0].DelegatorShares = valTokens.ToDec()
 	validators[1].OperatorAddress = sdk.ValAddress(keep.Addrs[1])
 	validators[1].ConsPubKey = keep.PKs[1]
-	validators[1].Description = NewDescription("bloop", "", "", "")
+	validators[1].Description = NewDescription("bloop", "", "", "", "")
 	validators[1].Status = sdk.Bonded
 	validators[1].Tokens = valTokens
 	validators[1].DelegatorShares = valTokens.ToDec()
@@ -68,6 +69,223 @@ func TestInitGenesis(t *testing.T) {
 	require.Equal(t, abcivals, vals)
 }
 
+func TestInitGenesisDelegations(t *testing.T) {
+	ctx, _, keeper := keep.CreateTestInput(t, false, 1000)
+
+	numVals := 4
+	var oldPowerKeys [][]byte
+	for i := 0; i < numVals; i++ {
+		validator := NewValidator(sdk.ValAddress(keep.Addrs[i]), keep.PKs[i], Description{})
+		keeper.SetValidator(ctx, validator)
+		keeper.SetValidatorByPowerIndex(ctx, validator)
+		oldPowerKeys = append(oldPowerKeys, keep.GetValidatorsByPowerIndexKey(validator, keeper.GetPowerIndexTieBreak(ctx)))
+	}
+	poolBefore := keeper.GetPool(ctx)
+
+	// 1000 delegations from 500 delegators, each delegating twice
+	dels := make([]GenesisDelegation, 1000)
+	total := sdk.ZeroInt()
+	for i := range dels {
+		delAddr := sdk.AccAddress(ed25519.GenPrivKeyFromSecret([]byte(fmt.Sprintf("airdrop%d", i/2))).PubKey().Address())
+		valAddr := sdk.ValAddress(keep.Addrs[i%numVals])
+		amount := sdk.TokensFromTendermintPower(int64(i%10 + 1))
+		dels[i] = NewGenesisDelegation(delAddr, valAddr, amount)
+		total = total.Add(amount)
+	}
+
+	require.NoError(t, InitGenesisDelegations(ctx, keeper, dels))
+
+	pool := keeper.GetPool(ctx)
+	require.True(sdk.IntEq(t, poolBefore.NotBondedTokens.Add(total), pool.NotBondedTokens))
+	require.True(sdk.IntEq(t, poolBefore.BondedTokens, pool.BondedTokens))
+
+	valTokens := sdk.ZeroInt()
+	for i := 0; i < numVals; i++ {
+		validator, found := keeper.GetValidator(ctx, sdk.ValAddress(keep.Addrs[i]))
+		require.True(t, found)
+		valTokens = valTokens.Add(validator.Tokens)
+
+		delShares := sdk.ZeroDec()
+		for _, del := range keeper.GetValidatorDelegations(ctx, validator.OperatorAddress) {
+			delShares = delShares.Add(del.Shares)
+		}
+		require.True(sdk.DecEq(t, validator.DelegatorShares, delShares))
+	}
+	require.True(sdk.IntEq(t, total, valTokens))
+	require.Len(t, keeper.GetAllDelegations(ctx), len(dels))
+
+	// the power index reflects the new validator tokens
+	for i := 0; i < numVals; i++ {
+		validator, _ := keeper.GetValidator(ctx, sdk.ValAddress(keep.Addrs[i]))
+		require.True(t, keep.ValidatorByPowerIndexExists(ctx, keeper, keep.GetValidatorsByPowerIndexKey(validator, keeper.GetPowerIndexTieBreak(ctx))))
+		require.False(t, keep.ValidatorByPowerIndexExists(ctx, keeper, oldPowerKeys[i]))
+	}
+
+	// delegating to an unknown validator fails without writing any state
+	invalid := []GenesisDelegation{
+		NewGenesisDelegation(keep.Addrs[0], sdk.ValAddress(keep.Addrs[0]), sdk.NewInt(1)),
+		NewGenesisDelegation(keep.Addrs[0], sdk.ValAddress(keep.Addrs[numVals]), sdk.NewInt(1)),
+	}
+	require.Error(t, InitGenesisDelegations(ctx, keeper, invalid))
+	require.Equal(t, pool, keeper.GetPool(ctx))
+}
+
+func TestInitGenesisWithGenesisDelegations(t *testing.T) {
+	ctx, _, keeper := keep.CreateTestInput(t, false, 1000)
+
+	pool := keeper.GetPool(ctx)
+	valTokens := sdk.TokensFromTendermintPower(1)
+	pool.BondedTokens = valTokens
+	validator := NewValidator(sdk.ValAddress(keep.Addrs[0]), keep.PKs[0], Description{})
+	validator.Status = sdk.Bonded
+	validator.Tokens = valTokens
+	validator.DelegatorShares = valTokens.ToDec()
+	selfDelegation := types.NewDelegation(keep.Addrs[0], validator.OperatorAddress, valTokens.ToDec())
+
+	genesisState := types.NewGenesisState(pool, keeper.GetParams(ctx), []Validator{validator}, []Delegation{selfDelegation})
+	delTokens := sdk.TokensFromTendermintPower(2)
+	genesisState.GenesisDelegations = []GenesisDelegation{NewGenesisDelegation(keep.Addrs[1], validator.OperatorAddress, delTokens)}
+	vals, err := InitGenesis(ctx, keeper, genesisState)
+	require.NoError(t, err)
+
+	// the genesis delegation is bonded along with the validator
+	require.Len(t, vals, 1)
+	require.Equal(t, int64(3), vals[0].Power)
+	delegation, found := keeper.GetDelegation(ctx, keep.Addrs[1], validator.OperatorAddress)
+	require.True(t, found)
+	require.True(sdk.DecEq(t, delTokens.ToDec(), delegation.Shares))
+	require.True(sdk.IntEq(t, valTokens.Add(delTokens), keeper.GetPool(ctx).BondedTokens))
+
+	// and exported as a regular delegation
+	exported := ExportGenesis(ctx, keeper)
+	require.Empty(t, exported.GenesisDelegations)
+	require.Len(t, exported.Delegations, 2)
+
+	// a genesis delegation to an unknown validator is rejected
+	ctx, _, keeper = keep.CreateTestInput(t, false, 1000)
+	genesisState.GenesisDelegations = []GenesisDelegation{NewGenesisDelegation(keep.Addrs[1], sdk.ValAddress(keep.Addrs[2]), delTokens)}
+	_, err = InitGenesis(ctx, keeper, genesisState)
+	require.Error(t, err)
+}
+
+func TestInitGenesisExportRoundTrip(t *testing.T) {
+	ctx, _, keeper := keep.CreateTestInput(t, false, 1000)
+
+	// validator tokens exceed delegator shares, as they would after provisions
+	shares := sdk.TokensFromTendermintPower(2).ToDec()
+	tokens := sdk.TokensFromTendermintPower(3)
+
+	pool := keeper.GetPool(ctx)
+	pool.BondedTokens = tokens.MulRaw(2)
+
+	validators := make([]Validator, 2)
+	var delegations []Delegation
+	for i := range validators {
+		validators[i] = NewValidator(sdk.ValAddress(keep.Addrs[i]), keep.PKs[i], Description{})
+		validators[i].Status = sdk.Bonded
+		validators[i].Tokens = tokens
+		validators[i].DelegatorShares = shares
+		delegations = append(delegations,
+			types.NewDelegation(keep.Addrs[i], validators[i].OperatorAddress, shares))
+	}
+
+	genesisState := types.NewGenesisState(pool, keeper.GetParams(ctx), validators, delegations)
+	_, err := InitGenesis(ctx, keeper, genesisState)
+	require.NoError(t, err)
+	slashEvents := types.SlashEvents{types.NewSlashEvent(1, sdk.NewDecWithPrec(1, 2), sdk.NewInt(100))}
+	keeper.SetValidatorSlashEvents(ctx, validators[0].OperatorAddress, slashEvents)
+	exported := ExportGenesis(ctx, keeper)
+	exportedBz, err := types.MsgCdc.MarshalJSON(exported)
+	require.NoError(t, err)
+
+	// re-import the exported state into a fresh store and export again
+	ctx2, _, keeper2 := keep.CreateTestInput(t, false, 1000)
+	var imported types.GenesisState
+	require.NoError(t, types.MsgCdc.UnmarshalJSON(exportedBz, &imported))
+	_, err = InitGenesis(ctx2, keeper2, imported)
+	require.NoError(t, err)
+	reexported := ExportGenesis(ctx2, keeper2)
+	reexportedBz, err := types.MsgCdc.MarshalJSON(reexported)
+	require.NoError(t, err)
+
+	require.Equal(t, string(exportedBz), string(reexportedBz))
+	require.Equal(t, slashEvents, keeper2.GetValidatorSlashEvents(ctx2, validators[0].OperatorAddress))
+	for i, validator := range reexported.Validators {
+		original := exported.Validators[i]
+		require.True(t, validator.Tokens.Equal(original.Tokens))
+		require.True(t, validator.DelegatorShares.Equal(original.DelegatorShares))
+		require.True(t, validator.TokensFromShares(sdk.OneDec()).Equal(original.TokensFromShares(sdk.OneDec())))
+		require.True(t, validator.TokensFromShares(sdk.OneDec()).Equal(sdk.NewDecWithPrec(15, 1)))
+	}
+}
+
+func TestExportGenesisDelegationOrder(t *testing.T) {
+	valAddrs := []sdk.ValAddress{sdk.ValAddress(keep.Addrs[3]), sdk.ValAddress(keep.Addrs[4])}
+	var delegations []Delegation
+	for i := 0; i < 3; i++ {
+		for j, valAddr := range valAddrs {
+			shares := sdk.NewDec(int64(10*i + j + 1))
+			delegations = append(delegations, types.NewDelegation(keep.Addrs[i], valAddr, shares))
+		}
+	}
+
+	// build the same delegations in forward and reverse order
+	ctx1, _, keeper1 := keep.CreateTestInput(t, false, 1000)
+	for _, delegation := range delegations {
+		keeper1.SetDelegation(ctx1, delegation)
+	}
+	ctx2, _, keeper2 := keep.CreateTestInput(t, false, 1000)
+	for i := len(delegations) - 1; i >= 0; i-- {
+		keeper2.SetDelegation(ctx2, delegations[i])
+	}
+
+	exported1 := ExportGenesis(ctx1, keeper1).Delegations
+	exported2 := ExportGenesis(ctx2, keeper2).Delegations
+	require.Len(t, exported1, len(delegations))
+	require.Equal(t, types.MsgCdc.MustMarshalJSON(exported1), types.MsgCdc.MustMarshalJSON(exported2))
+
+	for i := 1; i < len(exported1); i++ {
+		prev, cur := exported1[i-1], exported1[i]
+		c := bytes.Compare(prev.DelegatorAddress, cur.DelegatorAddress)
+		require.True(t, c < 0 || (c == 0 && bytes.Compare(prev.ValidatorAddress, cur.ValidatorAddress) < 0))
+	}
+}
+
+func TestGenesisValidatorsSnapshot(t *testing.T) {
+	ctx, _, keeper := keep.CreateTestInput(t, false, 1000)
+
+	pool := keeper.GetPool(ctx)
+	pool.BondedTokens = sdk.TokensFromTendermintPower(2)
+	valTokens := sdk.TokensFromTendermintPower(1)
+
+	validators := make([]Validator, 2)
+	for i := range validators {
+		validators[i] = NewValidator(sdk.ValAddress(keep.Addrs[i]), keep.PKs[i], Description{})
+		validators[i].Status = sdk.Bonded
+		validators[i].Tokens = valTokens
+		validators[i].DelegatorShares = valTokens.ToDec()
+	}
+
+	genesisState := types.NewGenesisState(pool, keeper.GetParams(ctx), validators, nil)
+	_, err := InitGenesis(ctx, keeper, genesisState)
+	require.NoError(t, err)
+
+	snapshot := keeper.GetAllValidators(ctx)
+	require.Len(t, snapshot, 2)
+	require.Equal(t, snapshot, keeper.GetGenesisValidators(ctx))
+
+	// change the validator set after genesis
+	keeper.AddValidatorTokensAndShares(ctx, snapshot[0], sdk.TokensFromTendermintPower(5))
+	keeper.Jail(ctx, sdk.ConsAddress(keep.PKs[1].Address()))
+	newValidator := NewValidator(sdk.ValAddress(keep.Addrs[2]), keep.PKs[2], Description{})
+	keeper.SetValidator(ctx, newValidator)
+	keeper.ApplyAndReturnValidatorSetUpdates(ctx)
+	require.NotEqual(t, snapshot, keeper.GetAllValidators(ctx))
+
+	// the snapshot is unaffected
+	require.Equal(t, snapshot, keeper.GetGenesisValidators(ctx))
+}
+
 func TestInitGenesisLargeValidatorSet(t *testing.T) {
 	size := 200
 	require.True(t, size > 100)
@@ -85,7 +303,7 @@ func TestInitGenesisLargeValidatorSet(t *testing.T) {
 
 	for i := range validators {
 		validators[i] = NewValidator(sdk.ValAddress(keep.Addrs[i]),
-			keep.PKs[i], NewDescription(fmt.Sprintf("#%d", i), "", "", ""))
+			keep.PKs[i], NewDescription(fmt.Sprintf("#%d", i), "", "", "", ""))
 
 		validators[i].Status = sdk.Bonded
 
@@ -109,10 +327,30 @@ func TestInitGenesisLargeValidatorSet(t *testing.T) {
 	require.Equal(t, abcivals, vals)
 }
 
+func TestInitGenesisInconsistentPool(t *testing.T) {
+	ctx, _, keeper := keep.CreateTestInput(t, false, 1000)
+
+	// the pool claims more bonded tokens than the bonded validators hold
+	pool := keeper.GetPool(ctx)
+	pool.BondedTokens = sdk.TokensFromTendermintPower(3)
+	valTokens := sdk.TokensFromTendermintPower(1)
+
+	validator := NewValidator(sdk.ValAddress(keep.Addrs[0]), keep.PKs[0], Description{})
+	validator.Status = sdk.Bonded
+	validator.Tokens = valTokens
+	validator.DelegatorShares = valTokens.ToDec()
+
+	genesisState := types.NewGenesisState(pool, keeper.GetParams(ctx), []Validator{validator}, nil)
+	expected := fmt.Sprintf("staking invariant bonded-tokens broken: bonded token invariance:\n"+
+		"\tpool.BondedTokens: %v\n"+
+		"\tsum of account tokens: %v", pool.BondedTokens, valTokens.ToDec())
+	require.PanicsWithValue(t, expected, func() { InitGenesis(ctx, keeper, genesisState) })
+}
+
 func TestValidateGenesis(t *testing.T) {
 	genValidators1 := make([]types.Validator, 1, 5)
 	pk := ed25519.GenPrivKey().PubKey()
-	genValidators1[0] = types.NewValidator(sdk.ValAddress(pk.Address()), pk, types.NewDescription("", "", "", ""))
+	genValidators1[0] = types.NewValidator(sdk.ValAddress(pk.Address()), pk, types.NewDescription("", "", "", "", ""))
 	genValidators1[0].Tokens = sdk.OneInt()
 	genValidators1[0].DelegatorShares = sdk.OneDec()
 
@@ -2,9 +2,11 @@ package staking
 
 import (
 	"fmt"
+	"strconv"
 	"time"
 
 	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/crypto"
 	"github.com/tendermint/tendermint/libs/common"
 	tmtypes "github.com/tendermint/tendermint/types"
 
@@ -24,6 +26,12 @@ func NewHandler(k keeper.Keeper) sdk.Handler {
 		case types.MsgEditValidator:
 			return handleMsgEditValidator(ctx, msg, k)
 
+		case types.MsgMigrateValidatorOwner:
+			return handleMsgMigrateValidatorOwner(ctx, msg, k)
+
+		case types.MsgSignalExit:
+			return handleMsgSignalExit(ctx, msg, k)
+
 		case types.MsgDelegate:
 			return handleMsgDelegate(ctx, msg, k)
 
@@ -33,6 +41,12 @@ func NewHandler(k keeper.Keeper) sdk.Handler {
 		case types.MsgUndelegate:
 			return handleMsgUndelegate(ctx, msg, k)
 
+		case types.MsgRebalance:
+			return handleMsgRebalance(ctx, msg, k)
+
+		case types.MsgSetAutoCompound:
+			return handleMsgSetAutoCompound(ctx, msg, k)
+
 		default:
 			errMsg := fmt.Sprintf("unrecognized staking message type: %T", msg)
 			return sdk.ErrUnknownRequest(errMsg).Result()
@@ -44,6 +58,17 @@ func NewHandler(k keeper.Keeper) sdk.Handler {
 func EndBlocker(ctx sdk.Context, k keeper.Keeper) ([]abci.ValidatorUpdate, sdk.Tags) {
 	resTags := sdk.NewTags()
 
+	// Apply the parameter changes that take effect in this block.
+	k.ApplyMaxValidatorsSchedule(ctx)
+	k.ApplyTieBreakMode(ctx)
+
+	// Collect the validators bonded before this block's set changes.
+	lastBonded := make(map[string]bool)
+	k.IterateLastValidatorPowers(ctx, func(valAddr sdk.ValAddress, _ int64) bool {
+		lastBonded[string(valAddr)] = true
+		return false
+	})
+
 	// Calculate validator set changes.
 	//
 	// NOTE: ApplyAndReturnValidatorSetUpdates has to come before
@@ -54,10 +79,22 @@ func EndBlocker(ctx sdk.Context, k keeper.Keeper) ([]abci.ValidatorUpdate, sdk.T
 	// ApplyAndReturnValidatorSetUpdates and then Unbonding -> Unbonded during
 	// UnbondAllMatureValidatorQueue).
 	validatorUpdates := k.ApplyAndReturnValidatorSetUpdates(ctx)
+	k.RecordValidatorSetUpdates(ctx, validatorUpdates)
+	resTags = resTags.AppendTags(bondedSetChangeTags(ctx, k, lastBonded, validatorUpdates))
 
 	// Unbond all mature validators from the unbonding queue.
 	k.UnbondAllMatureValidatorQueue(ctx)
 
+	// Tag the unbonding delegations and redelegations completed by a
+	// governance proposal, whose end blocker runs before this one.
+	completedUnbonds, completedRedelegations := k.GetUnbondingsCompletedInBlock(ctx)
+	for _, dvPair := range completedUnbonds {
+		resTags = resTags.AppendTags(completeUnbondingTags(dvPair))
+	}
+	for _, dvvTriplet := range completedRedelegations {
+		resTags = resTags.AppendTags(completeRedelegationTags(dvvTriplet))
+	}
+
 	// Remove all mature unbonding delegations from the ubd queue.
 	matureUnbonds := k.DequeueAllMatureUBDQueue(ctx, ctx.BlockHeader().Time)
 	for _, dvPair := range matureUnbonds {
@@ -66,11 +103,7 @@ func EndBlocker(ctx sdk.Context, k keeper.Keeper) ([]abci.ValidatorUpdate, sdk.T
 			continue
 		}
 
-		resTags = resTags.AppendTags(sdk.NewTags(
-			tags.Action, tags.ActionCompleteUnbonding,
-			tags.Delegator, dvPair.DelegatorAddress.String(),
-			tags.SrcValidator, dvPair.ValidatorAddress.String(),
-		))
+		resTags = resTags.AppendTags(completeUnbondingTags(dvPair))
 	}
 
 	// Remove all mature redelegations from the red queue.
@@ -82,16 +115,104 @@ func EndBlocker(ctx sdk.Context, k keeper.Keeper) ([]abci.ValidatorUpdate, sdk.T
 			continue
 		}
 
+		resTags = resTags.AppendTags(completeRedelegationTags(dvvTriplet))
+	}
+
+	k.RecordBondedRatio(ctx)
+	k.RecordValidatorBondedTokens(ctx)
+	k.RecordValidatorExRates(ctx)
+	k.RecordValidatorSetSize(ctx)
+
+	return validatorUpdates, resTags
+}
+
+// completeUnbondingTags returns the tags announcing a completed unbonding
+// delegation
+func completeUnbondingTags(dvPair types.DVPair) sdk.Tags {
+	return sdk.NewTags(
+		tags.Action, tags.ActionCompleteUnbonding,
+		tags.Delegator, dvPair.DelegatorAddress.String(),
+		tags.SrcValidator, dvPair.ValidatorAddress.String(),
+	)
+}
+
+// completeRedelegationTags returns the tags announcing a completed
+// redelegation
+func completeRedelegationTags(dvvTriplet types.DVVTriplet) sdk.Tags {
+	return sdk.NewTags(
+		tags.Action, tags.ActionCompleteRedelegation,
+		tags.Category, tags.TxCategory,
+		tags.Delegator, dvvTriplet.DelegatorAddress.String(),
+		tags.SrcValidator, dvvTriplet.ValidatorSrcAddress.String(),
+		tags.DstValidator, dvvTriplet.ValidatorDstAddress.String(),
+	)
+}
+
+// bondedSetChangeTags returns the tags announcing the validators which entered
+// or left the bonded set with the given validator set updates, given the
+// operator addresses of the bonded set before the updates were applied.
+func bondedSetChangeTags(ctx sdk.Context, k keeper.Keeper, lastBonded map[string]bool,
+	updates []abci.ValidatorUpdate) sdk.Tags {
+
+	resTags := sdk.NewTags()
+	for _, update := range updates {
+		pubKey, err := tmtypes.PB2TM.PubKey(update.PubKey)
+		if err != nil {
+			panic(err)
+		}
+		validator, found := k.GetValidatorByConsAddr(ctx, sdk.ConsAddress(pubKey.Address()))
+		if !found {
+			continue
+		}
+
+		action := tags.ActionValidatorBonded
+		if update.Power == 0 {
+			action = tags.ActionValidatorUnbonded
+		} else if lastBonded[string(validator.OperatorAddress)] {
+			// a power change within the bonded set
+			continue
+		}
 		resTags = resTags.AppendTags(sdk.NewTags(
-			tags.Action, tags.ActionCompleteRedelegation,
-			tags.Category, tags.TxCategory,
-			tags.Delegator, dvvTriplet.DelegatorAddress.String(),
-			tags.SrcValidator, dvvTriplet.ValidatorSrcAddress.String(),
-			tags.DstValidator, dvvTriplet.ValidatorDstAddress.String(),
+			tags.Action, action,
+			tags.Validator, validator.OperatorAddress.String(),
+			tags.ConsPubKey, sdk.MustBech32ifyConsPub(validator.ConsPubKey),
+			tags.Status, validator.Status.String(),
 		))
 	}
+	return resTags
+}
 
-	return validatorUpdates, resTags
+// validatePubKeyUnique returns an error if the consensus pubkey is already
+// used by a validator other than exceptOwner. Pass a nil exceptOwner when no
+// validator may hold the key yet, e.g. on creation; pass the validator's own
+// operator address when (re)setting its key so a no-op re-set is allowed.
+func validatePubKeyUnique(ctx sdk.Context, k keeper.Keeper, pubkey crypto.PubKey, exceptOwner sdk.ValAddress) sdk.Error {
+	validator, found := k.GetValidatorByConsAddr(ctx, sdk.GetConsAddress(pubkey))
+	if !found {
+		return nil
+	}
+	if exceptOwner != nil && validator.OperatorAddress.Equals(exceptOwner) {
+		return nil
+	}
+	return ErrValidatorPubKeyExists(k.Codespace())
+}
+
+// validatePubKeyType returns an error if the type of the consensus pubkey is
+// not accepted by the consensus params or, if it is set, by the
+// AllowedPubKeyTypes param.
+func validatePubKeyType(ctx sdk.Context, k keeper.Keeper, pubkey crypto.PubKey) sdk.Error {
+	keyType := tmtypes.TM2PB.PubKey(pubkey).Type
+	if ctx.ConsensusParams() != nil {
+		supportedTypes := ctx.ConsensusParams().Validator.PubKeyTypes
+		if !common.StringInSlice(keyType, supportedTypes) {
+			return ErrValidatorPubKeyTypeUnsupported(k.Codespace(), keyType, supportedTypes)
+		}
+	}
+	allowedTypes := k.AllowedPubKeyTypes(ctx)
+	if len(allowedTypes) > 0 && !common.StringInSlice(keyType, allowedTypes) {
+		return ErrValidatorPubKeyTypeUnsupported(k.Codespace(), keyType, allowedTypes)
+	}
+	return nil
 }
 
 // These functions assume everything has been authenticated,
@@ -103,25 +224,32 @@ func handleMsgCreateValidator(ctx sdk.Context, msg types.MsgCreateValidator, k k
 		return ErrValidatorOwnerExists(k.Codespace()).Result()
 	}
 
-	if _, found := k.GetValidatorByConsAddr(ctx, sdk.GetConsAddress(msg.PubKey)); found {
-		return ErrValidatorPubKeyExists(k.Codespace()).Result()
+	if err := validatePubKeyUnique(ctx, k, msg.PubKey, nil); err != nil {
+		return err.Result()
 	}
 
-	if msg.Value.Denom != k.GetParams(ctx).BondDenom {
+	if msg.Value.Denom != k.BondDenom(ctx) {
 		return ErrBadDenom(k.Codespace()).Result()
 	}
 
+	// genesis transactions are not capped
+	maxCreated := k.MaxValidatorsCreatedPerBlock(ctx)
+	if maxCreated > 0 && ctx.BlockHeight() > 0 && k.GetValidatorsCreatedInBlock(ctx) >= maxCreated {
+		return ErrMaxValidatorsCreatedPerBlock(k.Codespace(), maxCreated).Result()
+	}
+
 	if _, err := msg.Description.EnsureLength(); err != nil {
 		return err.Result()
 	}
+	if err := msg.Description.ValidateSecurityContact(); err != nil {
+		return err.Result()
+	}
 
-	if ctx.ConsensusParams() != nil {
-		tmPubKey := tmtypes.TM2PB.PubKey(msg.PubKey)
-		if !common.StringInSlice(tmPubKey.Type, ctx.ConsensusParams().Validator.PubKeyTypes) {
-			return ErrValidatorPubKeyTypeUnsupported(k.Codespace(),
-				tmPubKey.Type,
-				ctx.ConsensusParams().Validator.PubKeyTypes).Result()
-		}
+	// the identity can only be verified by an external check
+	msg.Description.IdentityVerified = false
+
+	if err := validatePubKeyType(ctx, k, msg.PubKey); err != nil {
+		return err.Result()
 	}
 
 	validator := NewValidator(msg.ValidatorAddress, msg.PubKey, msg.Description)
@@ -133,8 +261,12 @@ func handleMsgCreateValidator(ctx sdk.Context, msg types.MsgCreateValidator, k k
 	if err != nil {
 		return err.Result()
 	}
+	if minRate := k.MinCommissionRate(ctx); commission.Rate.LT(minRate) {
+		return ErrCommissionBelowMinimum(k.Codespace(), minRate).Result()
+	}
 
 	validator.MinSelfDelegation = msg.MinSelfDelegation
+	validator.BondHeight = ctx.BlockHeight()
 
 	k.SetValidator(ctx, validator)
 	k.SetValidatorByConsAddr(ctx, validator)
@@ -149,6 +281,7 @@ func handleMsgCreateValidator(ctx sdk.Context, msg types.MsgCreateValidator, k k
 	if err != nil {
 		return err.Result()
 	}
+	k.IncrementValidatorsCreatedInBlock(ctx)
 
 	resTags := sdk.NewTags(
 		tags.Category, tags.TxCategory,
@@ -186,6 +319,13 @@ func handleMsgEditValidator(ctx sdk.Context, msg types.MsgEditValidator, k keepe
 		k.BeforeValidatorModified(ctx, msg.ValidatorAddress)
 
 		validator.Commission = commission
+	} else if minRate := k.MinCommissionRate(ctx); validator.Commission.Rate.LT(minRate) {
+		// a validator below a raised minimum is brought up to it on its next
+		// edit, bypassing the limits on the rate of change
+		k.BeforeValidatorModified(ctx, msg.ValidatorAddress)
+
+		validator.Commission.Rate = minRate
+		validator.Commission.MaxRate = sdk.MaxDec(validator.Commission.MaxRate, minRate)
 	}
 
 	if msg.MinSelfDelegation != nil {
@@ -198,11 +338,53 @@ func handleMsgEditValidator(ctx sdk.Context, msg types.MsgEditValidator, k keepe
 		validator.MinSelfDelegation = (*msg.MinSelfDelegation)
 	}
 
+	if msg.MaxTotalDelegation != nil {
+		validator.MaxTotalDelegation = (*msg.MaxTotalDelegation)
+	}
+
 	k.SetValidator(ctx, validator)
 
+	if msg.UnbondingTime != nil {
+		if err := k.SetValidatorUnbondingTime(ctx, msg.ValidatorAddress, *msg.UnbondingTime); err != nil {
+			return err.Result()
+		}
+	}
+
+	resTags := sdk.NewTags(
+		tags.Category, tags.TxCategory,
+		tags.Sender, msg.ValidatorAddress.String(),
+	)
+
+	return sdk.Result{
+		Tags: resTags,
+	}
+}
+
+func handleMsgMigrateValidatorOwner(ctx sdk.Context, msg types.MsgMigrateValidatorOwner, k keeper.Keeper) sdk.Result {
+	if err := k.MigrateValidatorOwner(ctx, msg.ValidatorAddress, msg.NewOwnerAddress); err != nil {
+		return err.Result()
+	}
+
+	resTags := sdk.NewTags(
+		tags.Category, tags.TxCategory,
+		tags.Sender, msg.ValidatorAddress.String(),
+		tags.DstValidator, msg.NewOwnerAddress.String(),
+	)
+
+	return sdk.Result{
+		Tags: resTags,
+	}
+}
+
+func handleMsgSignalExit(ctx sdk.Context, msg types.MsgSignalExit, k keeper.Keeper) sdk.Result {
+	if err := k.SignalValidatorExit(ctx, msg.ValidatorAddress, msg.ExitHeight); err != nil {
+		return err.Result()
+	}
+
 	resTags := sdk.NewTags(
 		tags.Category, tags.TxCategory,
 		tags.Sender, msg.ValidatorAddress.String(),
+		tags.ExitHeight, strconv.FormatInt(msg.ExitHeight, 10),
 	)
 
 	return sdk.Result{
@@ -216,15 +398,23 @@ func handleMsgDelegate(ctx sdk.Context, msg types.MsgDelegate, k keeper.Keeper)
 		return ErrNoValidatorFound(k.Codespace()).Result()
 	}
 
-	if msg.Amount.Denom != k.GetParams(ctx).BondDenom {
+	if msg.Amount.Denom != k.BondDenom(ctx) {
 		return ErrBadDenom(k.Codespace()).Result()
 	}
 
+	if err := k.ValidateDelegation(ctx, msg.DelegatorAddress, validator, msg.Amount.Amount); err != nil {
+		return err.Result()
+	}
+
 	_, err := k.Delegate(ctx, msg.DelegatorAddress, msg.Amount.Amount, validator, true)
 	if err != nil {
 		return err.Result()
 	}
 
+	var result types.DelegateResult
+	result.ValidatorStatus, result.ValidatorPower = k.ProjectedValidatorStatus(ctx, msg.ValidatorAddress)
+	resData := types.MsgCdc.MustMarshalJSON(result)
+
 	resTags := sdk.NewTags(
 		tags.Category, tags.TxCategory,
 		tags.Sender, msg.DelegatorAddress.String(),
@@ -232,11 +422,16 @@ func handleMsgDelegate(ctx sdk.Context, msg types.MsgDelegate, k keeper.Keeper)
 	)
 
 	return sdk.Result{
+		Data: resData,
 		Tags: resTags,
 	}
 }
 
 func handleMsgUndelegate(ctx sdk.Context, msg types.MsgUndelegate, k keeper.Keeper) sdk.Result {
+	if k.IsValidatorFrozen(ctx, msg.ValidatorAddress) {
+		return ErrValidatorFrozen(k.Codespace(), msg.ValidatorAddress).Result()
+	}
+
 	shares, err := k.ValidateUnbondAmount(
 		ctx, msg.DelegatorAddress, msg.ValidatorAddress, msg.Amount.Amount,
 	)
@@ -260,7 +455,56 @@ func handleMsgUndelegate(ctx sdk.Context, msg types.MsgUndelegate, k keeper.Keep
 	return sdk.Result{Data: finishTime, Tags: resTags}
 }
 
+// handleMsgRebalance delegates to the destination validator and begins to
+// unbond from the source validator. Both legs run against a cached context
+// which is only written if neither fails, so a failing unbonding also undoes
+// the delegation.
+func handleMsgRebalance(ctx sdk.Context, msg types.MsgRebalance, k keeper.Keeper) sdk.Result {
+	cacheCtx, write := ctx.CacheContext()
+
+	delegateRes := handleMsgDelegate(cacheCtx,
+		types.NewMsgDelegate(msg.DelegatorAddress, msg.ValidatorDstAddress, msg.Amount), k)
+	if !delegateRes.IsOK() {
+		return delegateRes
+	}
+
+	undelegateRes := handleMsgUndelegate(cacheCtx,
+		types.NewMsgUndelegate(msg.DelegatorAddress, msg.ValidatorSrcAddress, msg.Amount), k)
+	if !undelegateRes.IsOK() {
+		return undelegateRes
+	}
+
+	write()
+	return sdk.Result{
+		Data: undelegateRes.Data,
+		Tags: delegateRes.Tags.AppendTags(undelegateRes.Tags),
+	}
+}
+
+func handleMsgSetAutoCompound(ctx sdk.Context, msg types.MsgSetAutoCompound, k keeper.Keeper) sdk.Result {
+	err := k.SetDelegationAutoCompound(ctx, msg.DelegatorAddress, msg.ValidatorAddress, msg.Enabled)
+	if err != nil {
+		return err.Result()
+	}
+
+	resTags := sdk.NewTags(
+		tags.Category, tags.TxCategory,
+		tags.Sender, msg.DelegatorAddress.String(),
+		tags.DstValidator, msg.ValidatorAddress.String(),
+	)
+
+	return sdk.Result{
+		Tags: resTags,
+	}
+}
+
 func handleMsgBeginRedelegate(ctx sdk.Context, msg types.MsgBeginRedelegate, k keeper.Keeper) sdk.Result {
+	for _, valAddr := range []sdk.ValAddress{msg.ValidatorSrcAddress, msg.ValidatorDstAddress} {
+		if k.IsValidatorFrozen(ctx, valAddr) {
+			return ErrValidatorFrozen(k.Codespace(), valAddr).Result()
+		}
+	}
+
 	shares, err := k.ValidateUnbondAmount(
 		ctx, msg.DelegatorAddress, msg.ValidatorSrcAddress, msg.Amount.Amount,
 	)
@@ -268,6 +512,16 @@ func handleMsgBeginRedelegate(ctx sdk.Context, msg types.MsgBeginRedelegate, k k
 		return err.Result()
 	}
 
+	srcValidator, _ := k.GetValidator(ctx, msg.ValidatorSrcAddress)
+	dstValidator, found := k.GetValidator(ctx, msg.ValidatorDstAddress)
+	if !found {
+		return ErrBadRedelegationDst(k.Codespace()).Result()
+	}
+	err = k.ValidateRedelegation(ctx, msg.DelegatorAddress, srcValidator, dstValidator, msg.Amount.Amount)
+	if err != nil {
+		return err.Result()
+	}
+
 	completionTime, err := k.BeginRedelegation(
 		ctx, msg.DelegatorAddress, msg.ValidatorSrcAddress, msg.ValidatorDstAddress, shares,
 	)
@@ -1,6 +1,7 @@
 package staking
 
 import (
+	"math"
 	"strings"
 	"testing"
 	"time"
@@ -12,8 +13,10 @@ import (
 	"github.com/tendermint/tendermint/crypto/secp256k1"
 	tmtypes "github.com/tendermint/tendermint/types"
 
+	"github.com/cosmos/cosmos-sdk/codec"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	keep "github.com/cosmos/cosmos-sdk/x/staking/keeper"
+	"github.com/cosmos/cosmos-sdk/x/staking/tags"
 	"github.com/cosmos/cosmos-sdk/x/staking/types"
 )
 
@@ -55,7 +58,7 @@ func TestValidatorByPowerIndex(t *testing.T) {
 	// verify that the by power index exists
 	validator, found := keeper.GetValidator(ctx, validatorAddr)
 	require.True(t, found)
-	power := keep.GetValidatorsByPowerIndexKey(validator)
+	power := keep.GetValidatorsByPowerIndexKey(validator, keeper.GetPowerIndexTieBreak(ctx))
 	require.True(t, keep.ValidatorByPowerIndexExists(ctx, keeper, power))
 
 	// create a second validator keep it bonded
@@ -85,11 +88,11 @@ func TestValidatorByPowerIndex(t *testing.T) {
 	// but the new power record should have been created
 	validator, found = keeper.GetValidator(ctx, validatorAddr)
 	require.True(t, found)
-	power2 := GetValidatorsByPowerIndexKey(validator)
+	power2 := GetValidatorsByPowerIndexKey(validator, keeper.GetPowerIndexTieBreak(ctx))
 	require.True(t, keep.ValidatorByPowerIndexExists(ctx, keeper, power2))
 
 	// now the new record power index should be the same as the original record
-	power3 := GetValidatorsByPowerIndexKey(validator)
+	power3 := GetValidatorsByPowerIndexKey(validator, keeper.GetPowerIndexTieBreak(ctx))
 	require.Equal(t, power2, power3)
 
 	// unbond self-delegation
@@ -113,6 +116,34 @@ func TestValidatorByPowerIndex(t *testing.T) {
 	require.False(t, keep.ValidatorByPowerIndexExists(ctx, keeper, power3))
 }
 
+func TestValidatePubKeyUnique(t *testing.T) {
+	ctx, _, keeper := keep.CreateTestInput(t, false, 1000)
+
+	addr1, addr2 := sdk.ValAddress(keep.Addrs[0]), sdk.ValAddress(keep.Addrs[1])
+	pk1, pk2 := keep.PKs[0], keep.PKs[1]
+
+	valTokens := sdk.TokensFromTendermintPower(10)
+	got := handleMsgCreateValidator(ctx, NewTestMsgCreateValidator(addr1, pk1, valTokens), keeper)
+	require.True(t, got.IsOK(), "%v", got)
+
+	// an unused pubkey is accepted
+	require.Nil(t, validatePubKeyUnique(ctx, keeper, pk2, nil))
+	require.Nil(t, validatePubKeyUnique(ctx, keeper, pk2, addr2))
+
+	// creation rejects a pubkey already owned by another validator
+	err := validatePubKeyUnique(ctx, keeper, pk1, nil)
+	require.NotNil(t, err)
+	require.Equal(t, CodeInvalidValidator, err.Code())
+	got = handleMsgCreateValidator(ctx, NewTestMsgCreateValidator(addr2, pk1, valTokens), keeper)
+	require.False(t, got.IsOK(), "%v", got)
+
+	// setting another validator's key is rejected
+	require.NotNil(t, validatePubKeyUnique(ctx, keeper, pk1, addr2))
+
+	// re-setting one's own key is a no-op
+	require.Nil(t, validatePubKeyUnique(ctx, keeper, pk1, addr1))
+}
+
 func TestDuplicatesMsgCreateValidator(t *testing.T) {
 	ctx, _, keeper := keep.CreateTestInput(t, false, 1000)
 
@@ -184,6 +215,34 @@ func TestInvalidPubKeyTypeMsgCreateValidator(t *testing.T) {
 	require.True(t, got.IsOK(), "%v", got)
 }
 
+func TestAllowedPubKeyTypesMsgCreateValidator(t *testing.T) {
+	ctx, _, keeper := keep.CreateTestInput(t, false, 1000)
+	ctx = ctx.WithConsensusParams(&abci.ConsensusParams{
+		Validator: &abci.ValidatorParams{PubKeyTypes: []string{tmtypes.ABCIPubKeyTypeEd25519, tmtypes.ABCIPubKeyTypeSecp256k1}},
+	})
+
+	params := keeper.GetParams(ctx)
+	params.AllowedPubKeyTypes = []string{tmtypes.ABCIPubKeyTypeEd25519}
+	keeper.SetParams(ctx, params)
+
+	// an allowed key type is accepted
+	addr1 := sdk.ValAddress(keep.Addrs[0])
+	require.Nil(t, validatePubKeyType(ctx, keeper, keep.PKs[0]))
+	got := handleMsgCreateValidator(ctx, NewTestMsgCreateValidator(addr1, keep.PKs[0], sdk.NewInt(10)), keeper)
+	require.True(t, got.IsOK(), "%v", got)
+
+	// a key type the consensus params accept but the param does not is rejected
+	addr2 := sdk.ValAddress(keep.Addrs[1])
+	secpPk := secp256k1.GenPrivKey().PubKey()
+	err := validatePubKeyType(ctx, keeper, secpPk)
+	require.NotNil(t, err)
+	require.Equal(t, CodeInvalidValidator, err.Code())
+	got = handleMsgCreateValidator(ctx, NewTestMsgCreateValidator(addr2, secpPk, sdk.NewInt(10)), keeper)
+	require.False(t, got.IsOK(), "%v", got)
+	_, found := keeper.GetValidator(ctx, addr2)
+	require.False(t, found)
+}
+
 func TestLegacyValidatorDelegations(t *testing.T) {
 	ctx, _, keeper := keep.CreateTestInput(t, false, int64(1000))
 	setInstantUnbondPeriod(keeper, ctx)
@@ -344,6 +403,46 @@ func TestIncrementsMsgDelegate(t *testing.T) {
 	}
 }
 
+func TestMinCommissionRate(t *testing.T) {
+	ctx, _, keeper := keep.CreateTestInput(t, false, 1000)
+	valTokens := sdk.TokensFromTendermintPower(10)
+	newMsg := func(i int, rate sdk.Dec) MsgCreateValidator {
+		return types.NewMsgCreateValidator(
+			sdk.ValAddress(keep.Addrs[i]), keep.PKs[i], sdk.NewCoin(sdk.DefaultBondDenom, valTokens),
+			Description{}, NewCommissionMsg(rate, sdk.NewDecWithPrec(2, 1), sdk.NewDecWithPrec(1, 1)), sdk.OneInt(),
+		)
+	}
+
+	// a validator created before the minimum is raised
+	got := handleMsgCreateValidator(ctx, newMsg(0, sdk.NewDecWithPrec(5, 2)), keeper)
+	require.True(t, got.IsOK(), "%v", got)
+
+	params := keeper.GetParams(ctx)
+	params.MinCommissionRate = sdk.NewDecWithPrec(1, 1)
+	keeper.SetParams(ctx, params)
+
+	// creation below the minimum is rejected, at the minimum allowed
+	got = handleMsgCreateValidator(ctx, newMsg(1, sdk.NewDecWithPrec(9, 2)), keeper)
+	require.False(t, got.IsOK(), "%v", got)
+	require.Equal(t, CodeInvalidValidator, got.Code)
+	got = handleMsgCreateValidator(ctx, newMsg(1, sdk.NewDecWithPrec(1, 1)), keeper)
+	require.True(t, got.IsOK(), "%v", got)
+
+	// an edit to a rate below the minimum is rejected
+	ctx = ctx.WithBlockTime(ctx.BlockHeader().Time.Add(25 * time.Hour))
+	valAddr := sdk.ValAddress(keep.Addrs[0])
+	rate := sdk.NewDecWithPrec(8, 2)
+	got = handleMsgEditValidator(ctx, NewMsgEditValidator(valAddr, Description{}, &rate, nil, nil, nil), keeper)
+	require.False(t, got.IsOK(), "%v", got)
+
+	// any other edit raises the commission to the minimum
+	got = handleMsgEditValidator(ctx, NewMsgEditValidator(valAddr, Description{Moniker: "val"}, nil, nil, nil, nil), keeper)
+	require.True(t, got.IsOK(), "%v", got)
+	validator, found := keeper.GetValidator(ctx, valAddr)
+	require.True(t, found)
+	require.Equal(t, sdk.NewDecWithPrec(1, 1), validator.Commission.Rate)
+}
+
 func TestEditValidatorDecreaseMinSelfDelegation(t *testing.T) {
 	validatorAddr := sdk.ValAddress(keep.Addrs[0])
 
@@ -371,7 +470,7 @@ func TestEditValidatorDecreaseMinSelfDelegation(t *testing.T) {
 		initBond, gotBond, bond)
 
 	newMinSelfDelegation := sdk.OneInt()
-	msgEditValidator := NewMsgEditValidator(validatorAddr, Description{}, nil, &newMinSelfDelegation)
+	msgEditValidator := NewMsgEditValidator(validatorAddr, Description{}, nil, &newMinSelfDelegation, nil, nil)
 	got = handleMsgEditValidator(ctx, msgEditValidator, keeper)
 	require.False(t, got.IsOK(), "should not be able to decrease minSelfDelegation")
 }
@@ -403,11 +502,319 @@ func TestEditValidatorIncreaseMinSelfDelegationBeyondCurrentBond(t *testing.T) {
 		initBond, gotBond, bond)
 
 	newMinSelfDelegation := initBond.Add(sdk.OneInt())
-	msgEditValidator := NewMsgEditValidator(validatorAddr, Description{}, nil, &newMinSelfDelegation)
+	msgEditValidator := NewMsgEditValidator(validatorAddr, Description{}, nil, &newMinSelfDelegation, nil, nil)
 	got = handleMsgEditValidator(ctx, msgEditValidator, keeper)
 	require.False(t, got.IsOK(), "should not be able to increase minSelfDelegation above current self delegation")
 }
 
+func TestMaxValidatorsCreatedPerBlock(t *testing.T) {
+	ctx, _, keeper := keep.CreateTestInput(t, false, 1000)
+	ctx = ctx.WithBlockHeight(1)
+	params := keeper.GetParams(ctx)
+	params.MaxValidatorsCreatedPerBlock = 2
+	keeper.SetParams(ctx, params)
+
+	for i := 0; i < 4; i++ {
+		valAddr := sdk.ValAddress(keep.Addrs[i])
+		msg := NewTestMsgCreateValidator(valAddr, keep.PKs[i], sdk.TokensFromTendermintPower(10))
+		got := handleMsgCreateValidator(ctx, msg, keeper)
+
+		_, found := keeper.GetValidator(ctx, valAddr)
+		if i < 2 {
+			require.True(t, got.IsOK(), "expected create-validator %d to be ok, got %v", i, got)
+			require.True(t, found)
+			continue
+		}
+		require.False(t, got.IsOK(), "expected create-validator %d over the cap to fail", i)
+		require.Equal(t, ErrMaxValidatorsCreatedPerBlock(keeper.Codespace(), 2).Result().Code, got.Code)
+		require.False(t, found)
+	}
+	require.Equal(t, uint16(2), keeper.GetValidatorsCreatedInBlock(ctx))
+}
+
+func TestMaxValidatorsSchedule(t *testing.T) {
+	ctx, _, keeper := keep.CreateTestInput(t, false, 1000)
+	params := keeper.GetParams(ctx)
+	params.MaxValidators = 2
+	params.MaxValidatorsSchedule = []types.MaxValidatorsScheduleEntry{{Height: 5, Max: 4}, {Height: 7, Max: 3}}
+	keeper.SetParams(ctx, params)
+
+	for i := 0; i < 4; i++ {
+		msg := NewTestMsgCreateValidator(sdk.ValAddress(keep.Addrs[i]), keep.PKs[i], sdk.TokensFromTendermintPower(int64(10+i)))
+		got := handleMsgCreateValidator(ctx, msg, keeper)
+		require.True(t, got.IsOK(), "expected ok, got %v", got)
+	}
+
+	// only the two strongest validators bond before the scheduled height
+	ctx = ctx.WithBlockHeight(4)
+	updates, _ := EndBlocker(ctx, keeper)
+	require.Len(t, updates, 2)
+	require.Equal(t, uint16(2), keeper.MaxValidators(ctx))
+	require.Len(t, keeper.GetLastValidators(ctx), 2)
+
+	// the cap grows at the scheduled height and the remaining validators bond
+	ctx = ctx.WithBlockHeight(5)
+	updates, _ = EndBlocker(ctx, keeper)
+	require.Len(t, updates, 2)
+	require.Equal(t, uint16(4), keeper.MaxValidators(ctx))
+	require.Len(t, keeper.GetLastValidators(ctx), 4)
+	for i := 0; i < 4; i++ {
+		validator, found := keeper.GetValidator(ctx, sdk.ValAddress(keep.Addrs[i]))
+		require.True(t, found)
+		require.Equal(t, sdk.Bonded, validator.Status)
+	}
+
+	// an entry whose height was never processed still applies
+	ctx = ctx.WithBlockHeight(9)
+	updates, _ = EndBlocker(ctx, keeper)
+	require.Len(t, updates, 1)
+	require.Equal(t, uint16(3), keeper.MaxValidators(ctx))
+	require.Len(t, keeper.GetLastValidators(ctx), 3)
+}
+
+func TestEditValidatorSecurityContact(t *testing.T) {
+	validatorAddr := sdk.ValAddress(keep.Addrs[0])
+	ctx, _, keeper := keep.CreateTestInput(t, false, 100)
+
+	// create validator claiming a verified identity
+	msgCreateValidator := NewTestMsgCreateValidator(validatorAddr, keep.PKs[0], sdk.TokensFromTendermintPower(10))
+	msgCreateValidator.Description = Description{Identity: "key", IdentityVerified: true}
+	got := handleMsgCreateValidator(ctx, msgCreateValidator, keeper)
+	require.True(t, got.IsOK(), "expected create-validator to be ok, got %v", got)
+	validator, _ := keeper.GetValidator(ctx, validatorAddr)
+	require.False(t, validator.Description.IdentityVerified)
+
+	// the identity is verified by governance
+	hdlr := NewProposalHandler(keeper)
+	require.Nil(t, hdlr(ctx, NewVerifyValidatorIdentityProposal("title", "description", validatorAddr, true)))
+	require.NotNil(t, hdlr(ctx, NewVerifyValidatorIdentityProposal("title", "description", sdk.ValAddress(keep.Addrs[1]), true)))
+
+	// an invalid security contact is rejected
+	description := NewDescription(types.DoNotModifyDesc, types.DoNotModifyDesc, types.DoNotModifyDesc, types.DoNotModifyDesc, "security")
+	got = handleMsgEditValidator(ctx, NewMsgEditValidator(validatorAddr, description, nil, nil, nil, nil), keeper)
+	require.False(t, got.IsOK(), "expected edit-validator with an invalid security contact to fail")
+	require.Equal(t, ErrInvalidSecurityContact(keeper.Codespace(), "").Result().Code, got.Code)
+
+	// a valid security contact is stored and the identity stays verified
+	description.SecurityContact = "security@validator.cosmos"
+	got = handleMsgEditValidator(ctx, NewMsgEditValidator(validatorAddr, description, nil, nil, nil, nil), keeper)
+	require.True(t, got.IsOK(), "expected edit-validator to be ok, got %v", got)
+	validator, _ = keeper.GetValidator(ctx, validatorAddr)
+	require.Equal(t, "security@validator.cosmos", validator.Description.SecurityContact)
+	require.Equal(t, "key", validator.Description.Identity)
+	require.True(t, validator.Description.IdentityVerified)
+}
+
+func TestEditValidatorUnbondingTime(t *testing.T) {
+	validatorAddr, delegatorAddr := sdk.ValAddress(keep.Addrs[0]), keep.Addrs[1]
+	ctx, _, keeper := keep.CreateTestInput(t, false, 100)
+
+	msgCreateValidator := NewTestMsgCreateValidator(validatorAddr, keep.PKs[0], sdk.TokensFromTendermintPower(10))
+	got := handleMsgCreateValidator(ctx, msgCreateValidator, keeper)
+	require.True(t, got.IsOK(), "expected create-validator to be ok, got %v", got)
+	EndBlocker(ctx, keeper)
+	msgDelegate := NewTestMsgDelegate(delegatorAddr, validatorAddr, sdk.TokensFromTendermintPower(10))
+	got = handleMsgDelegate(ctx, msgDelegate, keeper)
+	require.True(t, got.IsOK(), "expected delegation to be ok, got %v", got)
+
+	// an unbonding time shorter than the param is rejected
+	unbondingTime := keeper.UnbondingTime(ctx) - time.Second
+	msgEditValidator := NewMsgEditValidator(validatorAddr, Description{}, nil, nil, nil, &unbondingTime)
+	got = handleMsgEditValidator(ctx, msgEditValidator, keeper)
+	require.False(t, got.IsOK(), "expected edit-validator with a short unbonding time to fail")
+
+	// a longer unbonding time applies to unbonding delegations
+	unbondingTime = keeper.UnbondingTime(ctx) + time.Hour
+	msgEditValidator = NewMsgEditValidator(validatorAddr, Description{}, nil, nil, nil, &unbondingTime)
+	got = handleMsgEditValidator(ctx, msgEditValidator, keeper)
+	require.True(t, got.IsOK(), "expected edit-validator to be ok, got %v", got)
+
+	unbondAmt := sdk.NewCoin(sdk.DefaultBondDenom, sdk.TokensFromTendermintPower(5))
+	got = handleMsgUndelegate(ctx, NewMsgUndelegate(delegatorAddr, validatorAddr, unbondAmt), keeper)
+	require.True(t, got.IsOK(), "expected undelegation to be ok, got %v", got)
+	var finishTime time.Time
+	types.MsgCdc.MustUnmarshalBinaryLengthPrefixed(got.Data, &finishTime)
+	require.True(t, ctx.BlockHeader().Time.Add(unbondingTime).Equal(finishTime))
+}
+
+func TestDelegateMaxTotalDelegation(t *testing.T) {
+	validatorAddr, delegatorAddr := sdk.ValAddress(keep.Addrs[0]), keep.Addrs[1]
+
+	initPower := int64(100)
+	initBond := sdk.TokensFromTendermintPower(10)
+	ctx, _, keeper := keep.CreateTestInput(t, false, initPower)
+
+	// create validator
+	msgCreateValidator := NewTestMsgCreateValidator(validatorAddr, keep.PKs[0], initBond)
+	got := handleMsgCreateValidator(ctx, msgCreateValidator, keeper)
+	require.True(t, got.IsOK(), "expected create-validator to be ok, got %v", got)
+
+	// cap total delegator shares at twice the self-delegation
+	maxTotalDelegation := initBond.MulRaw(2).ToDec()
+	msgEditValidator := NewMsgEditValidator(validatorAddr, Description{}, nil, nil, &maxTotalDelegation, nil)
+	got = handleMsgEditValidator(ctx, msgEditValidator, keeper)
+	require.True(t, got.IsOK(), "expected edit-validator to be ok, got %v", got)
+
+	// delegating up to the cap succeeds
+	msgDelegate := NewTestMsgDelegate(delegatorAddr, validatorAddr, initBond)
+	got = handleMsgDelegate(ctx, msgDelegate, keeper)
+	require.True(t, got.IsOK(), "expected delegation up to the cap to be ok, got %v", got)
+
+	// delegating one more token is rejected
+	msgDelegate = NewTestMsgDelegate(delegatorAddr, validatorAddr, sdk.OneInt())
+	got = handleMsgDelegate(ctx, msgDelegate, keeper)
+	require.False(t, got.IsOK(), "expected delegation over the cap to fail")
+	require.Equal(t, ErrMaxTotalDelegationExceeded(keeper.Codespace()).Result().Code, got.Code)
+
+	// the operator's self-delegation is exempt from the cap
+	msgDelegate = NewTestMsgDelegate(sdk.AccAddress(validatorAddr), validatorAddr, sdk.OneInt())
+	got = handleMsgDelegate(ctx, msgDelegate, keeper)
+	require.True(t, got.IsOK(), "expected self-delegation over the cap to be ok, got %v", got)
+
+	// redelegating into the capped validator is rejected as well
+	otherValidatorAddr := sdk.ValAddress(keep.Addrs[2])
+	msgCreateValidator = NewTestMsgCreateValidator(otherValidatorAddr, keep.PKs[2], initBond)
+	got = handleMsgCreateValidator(ctx, msgCreateValidator, keeper)
+	require.True(t, got.IsOK(), "expected create-validator to be ok, got %v", got)
+	msgDelegate = NewTestMsgDelegate(delegatorAddr, otherValidatorAddr, initBond)
+	got = handleMsgDelegate(ctx, msgDelegate, keeper)
+	require.True(t, got.IsOK(), "expected delegation to be ok, got %v", got)
+	redelegateAmt := sdk.NewCoin(sdk.DefaultBondDenom, sdk.OneInt())
+	msgRedelegate := NewMsgBeginRedelegate(delegatorAddr, otherValidatorAddr, validatorAddr, redelegateAmt)
+	got = handleMsgBeginRedelegate(ctx, msgRedelegate, keeper)
+	require.False(t, got.IsOK(), "expected redelegation over the cap to fail")
+	require.Equal(t, ErrMaxTotalDelegationExceeded(keeper.Codespace()).Result().Code, got.Code)
+}
+
+func TestFreezeValidatorProposal(t *testing.T) {
+	ctx, _, keeper := keep.CreateTestInput(t, false, 1000)
+	hdlr := NewProposalHandler(keeper)
+	validatorAddr, validatorAddr2, delegatorAddr := sdk.ValAddress(keep.Addrs[0]), sdk.ValAddress(keep.Addrs[1]), keep.Addrs[2]
+
+	params := keeper.GetParams(ctx)
+	params.UnbondingTime = 7 * time.Second
+	keeper.SetParams(ctx, params)
+
+	// create the validators and delegate
+	valTokens := sdk.TokensFromTendermintPower(10)
+	for i, valAddr := range []sdk.ValAddress{validatorAddr, validatorAddr2} {
+		got := handleMsgCreateValidator(ctx, NewTestMsgCreateValidator(valAddr, keep.PKs[i], valTokens), keeper)
+		require.True(t, got.IsOK(), "expected create-validator to be ok, got %v", got)
+	}
+	EndBlocker(ctx, keeper)
+	got := handleMsgDelegate(ctx, NewTestMsgDelegate(delegatorAddr, validatorAddr, valTokens), keeper)
+	require.True(t, got.IsOK(), "expected delegation to be ok, got %v", got)
+
+	// begin unbonding before the validator is frozen
+	unbondAmt := sdk.NewCoin(sdk.DefaultBondDenom, sdk.TokensFromTendermintPower(5))
+	got = handleMsgUndelegate(ctx, NewMsgUndelegate(delegatorAddr, validatorAddr, unbondAmt), keeper)
+	require.True(t, got.IsOK(), "expected undelegation to be ok, got %v", got)
+
+	err := hdlr(ctx, NewFreezeValidatorProposal("title", "description", validatorAddr, true))
+	require.Nil(t, err)
+	require.True(t, keeper.IsValidatorFrozen(ctx, validatorAddr))
+
+	// new delegations, unbondings and redelegations are rejected
+	frozenCode := ErrValidatorFrozen(keeper.Codespace(), validatorAddr).Result().Code
+	got = handleMsgDelegate(ctx, NewTestMsgDelegate(delegatorAddr, validatorAddr, valTokens), keeper)
+	require.False(t, got.IsOK(), "expected delegation to a frozen validator to fail")
+	require.Equal(t, frozenCode, got.Code)
+	got = handleMsgUndelegate(ctx, NewMsgUndelegate(delegatorAddr, validatorAddr, unbondAmt), keeper)
+	require.False(t, got.IsOK(), "expected undelegation from a frozen validator to fail")
+	require.Equal(t, frozenCode, got.Code)
+	got = handleMsgBeginRedelegate(ctx, NewMsgBeginRedelegate(delegatorAddr, validatorAddr, validatorAddr2, unbondAmt), keeper)
+	require.False(t, got.IsOK(), "expected redelegation from a frozen validator to fail")
+	require.Equal(t, frozenCode, got.Code)
+	got = handleMsgDelegate(ctx, NewTestMsgDelegate(delegatorAddr, validatorAddr2, valTokens), keeper)
+	require.True(t, got.IsOK(), "expected delegation to another validator to be ok, got %v", got)
+	got = handleMsgBeginRedelegate(ctx, NewMsgBeginRedelegate(delegatorAddr, validatorAddr2, validatorAddr, unbondAmt), keeper)
+	require.False(t, got.IsOK(), "expected redelegation to a frozen validator to fail")
+	require.Equal(t, frozenCode, got.Code)
+
+	// the existing unbonding still matures
+	ctx = ctx.WithBlockTime(ctx.BlockHeader().Time.Add(params.UnbondingTime))
+	EndBlocker(ctx, keeper)
+	_, found := keeper.GetUnbondingDelegation(ctx, delegatorAddr, validatorAddr)
+	require.False(t, found, "should have unbonded")
+
+	// unfrozen validators accept delegations again
+	err = hdlr(ctx, NewFreezeValidatorProposal("title", "description", validatorAddr, false))
+	require.Nil(t, err)
+	got = handleMsgDelegate(ctx, NewTestMsgDelegate(delegatorAddr, validatorAddr, valTokens), keeper)
+	require.True(t, got.IsOK(), "expected delegation to be ok, got %v", got)
+
+	// proposals for unknown validators fail
+	err = hdlr(ctx, NewFreezeValidatorProposal("title", "description", sdk.ValAddress(keep.Addrs[3]), true))
+	require.NotNil(t, err)
+}
+
+func TestCompleteUnbondingsProposal(t *testing.T) {
+	ctx, accMapper, keeper := keep.CreateTestInput(t, false, 1000)
+	hdlr := NewProposalHandler(keeper)
+	validatorAddr, validatorAddr2 := sdk.ValAddress(keep.Addrs[0]), sdk.ValAddress(keep.Addrs[1])
+	delegatorAddrs := []sdk.AccAddress{keep.Addrs[2], keep.Addrs[3]}
+
+	// create the validators and delegate
+	valTokens := sdk.TokensFromTendermintPower(10)
+	for i, valAddr := range []sdk.ValAddress{validatorAddr, validatorAddr2} {
+		got := handleMsgCreateValidator(ctx, NewTestMsgCreateValidator(valAddr, keep.PKs[i], valTokens), keeper)
+		require.True(t, got.IsOK(), "expected create-validator to be ok, got %v", got)
+	}
+	EndBlocker(ctx, keeper)
+	for _, delAddr := range delegatorAddrs {
+		got := handleMsgDelegate(ctx, NewTestMsgDelegate(delAddr, validatorAddr, valTokens), keeper)
+		require.True(t, got.IsOK(), "expected delegation to be ok, got %v", got)
+	}
+
+	// begin several unbondings and a redelegation
+	unbondAmt := sdk.NewCoin(sdk.DefaultBondDenom, sdk.TokensFromTendermintPower(2))
+	var balances []sdk.Coins
+	for _, delAddr := range delegatorAddrs {
+		for i := 0; i < 2; i++ {
+			got := handleMsgUndelegate(ctx, NewMsgUndelegate(delAddr, validatorAddr, unbondAmt), keeper)
+			require.True(t, got.IsOK(), "expected undelegation to be ok, got %v", got)
+		}
+		balances = append(balances, accMapper.GetAccount(ctx, delAddr).GetCoins())
+	}
+	got := handleMsgBeginRedelegate(ctx, NewMsgBeginRedelegate(delegatorAddrs[0], validatorAddr, validatorAddr2, unbondAmt), keeper)
+	require.True(t, got.IsOK(), "expected redelegation to be ok, got %v", got)
+	require.Len(t, keeper.GetAllUnbondingDelegations(ctx, delegatorAddrs[0]), 1)
+	require.Len(t, keeper.GetAllRedelegations(ctx, delegatorAddrs[0], nil, nil), 1)
+
+	err := hdlr(ctx, NewCompleteUnbondingsProposal("title", "description"))
+	require.Nil(t, err)
+
+	// every unbonding is completed and credited
+	for i, delAddr := range delegatorAddrs {
+		require.Empty(t, keeper.GetAllUnbondingDelegations(ctx, delAddr))
+		expCoins := balances[i].Add(sdk.Coins{sdk.NewCoin(sdk.DefaultBondDenom, unbondAmt.Amount.MulRaw(2))})
+		require.Equal(t, expCoins, accMapper.GetAccount(ctx, delAddr).GetCoins())
+	}
+	require.Empty(t, keeper.GetAllRedelegations(ctx, delegatorAddrs[0], nil, nil))
+
+	// the end blocker tags each completed unbonding and redelegation
+	_, resTags := EndBlocker(ctx, keeper)
+	var unbondings, redelegations int
+	for _, tag := range resTags {
+		if string(tag.Key) != tags.Action {
+			continue
+		}
+		switch string(tag.Value) {
+		case tags.ActionCompleteUnbonding:
+			unbondings++
+		case tags.ActionCompleteRedelegation:
+			redelegations++
+		}
+	}
+	require.Equal(t, len(delegatorAddrs), unbondings)
+	require.Equal(t, 1, redelegations)
+
+	// the queues are empty
+	ctx = ctx.WithBlockTime(ctx.BlockHeader().Time.Add(keeper.UnbondingTime(ctx)))
+	require.Empty(t, keeper.DequeueAllMatureUBDQueue(ctx, ctx.BlockHeader().Time))
+	require.Empty(t, keeper.DequeueAllMatureRedelegationQueue(ctx, ctx.BlockHeader().Time))
+}
+
 func TestIncrementsMsgUnbond(t *testing.T) {
 	initPower := int64(1000)
 	initBond := sdk.TokensFromTendermintPower(initPower)
@@ -617,6 +1024,125 @@ func TestMultipleMsgDelegate(t *testing.T) {
 	}
 }
 
+func TestDelegateResultReportsValidatorStatus(t *testing.T) {
+	ctx, _, keeper := keep.CreateTestInput(t, false, 1000)
+	params := keeper.GetParams(ctx)
+	params.MaxValidators = 1
+	keeper.SetParams(ctx, params)
+
+	// the first validator fills the bonded set
+	valAddr1, valAddr2 := sdk.ValAddress(keep.Addrs[0]), sdk.ValAddress(keep.Addrs[1])
+	got := handleMsgCreateValidator(ctx, NewTestMsgCreateValidator(valAddr1, keep.PKs[0], sdk.TokensFromTendermintPower(10)), keeper)
+	require.True(t, got.IsOK(), "%v", got)
+	got = handleMsgCreateValidator(ctx, NewTestMsgCreateValidator(valAddr2, keep.PKs[1], sdk.TokensFromTendermintPower(5)), keeper)
+	require.True(t, got.IsOK(), "%v", got)
+	EndBlocker(ctx, keeper)
+
+	// a small delegation leaves the second validator out of the set
+	delAddr := keep.Addrs[2]
+	got = handleMsgDelegate(ctx, NewTestMsgDelegate(delAddr, valAddr2, sdk.TokensFromTendermintPower(1)), keeper)
+	require.True(t, got.IsOK(), "%v", got)
+
+	var result DelegateResult
+	types.MsgCdc.MustUnmarshalJSON(got.Data, &result)
+	require.Equal(t, sdk.Unbonded, result.ValidatorStatus)
+	require.Equal(t, int64(0), result.ValidatorPower)
+
+	// delegating enough to pass the first validator bonds the second one
+	got = handleMsgDelegate(ctx, NewTestMsgDelegate(delAddr, valAddr2, sdk.TokensFromTendermintPower(5)), keeper)
+	require.True(t, got.IsOK(), "%v", got)
+
+	types.MsgCdc.MustUnmarshalJSON(got.Data, &result)
+	require.Equal(t, sdk.Bonded, result.ValidatorStatus)
+	require.Equal(t, int64(11), result.ValidatorPower)
+
+	// the projection matches the state after the end of the block
+	EndBlocker(ctx, keeper)
+	validator, found := keeper.GetValidator(ctx, valAddr2)
+	require.True(t, found)
+	require.Equal(t, sdk.Bonded, validator.Status)
+	require.Equal(t, result.ValidatorPower, validator.TendermintPower())
+}
+
+func TestDelegatePowerOverflow(t *testing.T) {
+	ctx, _, keeper := keep.CreateTestInput(t, false, 1000)
+	valAddr := sdk.ValAddress(keep.Addrs[0])
+
+	got := handleMsgCreateValidator(ctx, NewTestMsgCreateValidator(valAddr, keep.PKs[0], sdk.TokensFromTendermintPower(10)), keeper)
+	require.True(t, got.IsOK(), "%v", got)
+	EndBlocker(ctx, keeper)
+	pool := keeper.GetPool(ctx)
+
+	// delegating close to the int64 limit of the tendermint power is rejected
+	delAddr := keep.Addrs[1]
+	bondAmt := sdk.TokensFromTendermintPower(math.MaxInt64 - 1)
+	got = handleMsgDelegate(ctx, NewTestMsgDelegate(delAddr, valAddr, bondAmt), keeper)
+	require.False(t, got.IsOK())
+	require.Equal(t, types.CodeInvalidDelegation, got.Code)
+
+	// as is any delegation pushing the validator over the max total voting power
+	bondAmt = sdk.TokensFromTendermintPower(tmtypes.MaxTotalVotingPower - 9)
+	got = handleMsgDelegate(ctx, NewTestMsgDelegate(delAddr, valAddr, bondAmt), keeper)
+	require.False(t, got.IsOK())
+	require.Equal(t, types.CodeInvalidDelegation, got.Code)
+
+	// the pool and validator are left untouched
+	require.Equal(t, pool, keeper.GetPool(ctx))
+	validator, found := keeper.GetValidator(ctx, valAddr)
+	require.True(t, found)
+	require.Equal(t, sdk.TokensFromTendermintPower(10), validator.Tokens)
+	_, found = keeper.GetDelegation(ctx, delAddr, valAddr)
+	require.False(t, found)
+}
+
+func TestValidatorSetUpdatesHistory(t *testing.T) {
+	ctx, _, keeper := keep.CreateTestInput(t, false, 1000)
+	require.Zero(t, keeper.GetParams(ctx).ValidatorUpdatesHistory)
+	params := keeper.GetParams(ctx)
+	params.ValidatorUpdatesHistory = 100
+	keeper.SetParams(ctx, params)
+
+	// each block bonds a new validator
+	expected := make(map[int64][]abci.ValidatorUpdate)
+	for i := int64(0); i < 3; i++ {
+		ctx = ctx.WithBlockHeight(i + 1)
+		valAddr := sdk.ValAddress(keep.Addrs[i])
+		valTokens := sdk.TokensFromTendermintPower(10 + i)
+		got := handleMsgCreateValidator(ctx, NewTestMsgCreateValidator(valAddr, keep.PKs[i], valTokens), keeper)
+		require.True(t, got.IsOK(), "%v", got)
+
+		updates, _ := EndBlocker(ctx, keeper)
+		require.Len(t, updates, 1)
+		expected[ctx.BlockHeight()] = updates
+	}
+
+	// a block without any update is not recorded
+	ctx = ctx.WithBlockHeight(4)
+	updates, _ := EndBlocker(ctx, keeper)
+	require.Len(t, updates, 0)
+
+	require.Equal(t, expected, keeper.GetValidatorSetUpdatesInRange(ctx, 1, 4))
+	require.Equal(t, map[int64][]abci.ValidatorUpdate{2: expected[2]}, keeper.GetValidatorSetUpdatesInRange(ctx, 2, 2))
+	require.Empty(t, keeper.GetValidatorSetUpdatesInRange(ctx, 4, 10))
+
+	// updates older than the history are pruned
+	params.ValidatorUpdatesHistory = 2
+	keeper.SetParams(ctx, params)
+	ctx = ctx.WithBlockHeight(5)
+	EndBlocker(ctx, keeper)
+	require.Empty(t, keeper.GetValidatorSetUpdatesInRange(ctx, 1, 5))
+
+	// a disabled history records nothing
+	params.ValidatorUpdatesHistory = 0
+	keeper.SetParams(ctx, params)
+	ctx = ctx.WithBlockHeight(6)
+	got := handleMsgCreateValidator(ctx, NewTestMsgCreateValidator(sdk.ValAddress(keep.Addrs[3]), keep.PKs[3], sdk.TokensFromTendermintPower(13)), keeper)
+	require.True(t, got.IsOK(), "%v", got)
+	updates, _ = EndBlocker(ctx, keeper)
+	require.Len(t, updates, 1)
+	require.Empty(t, keeper.GetValidatorSetUpdatesInRange(ctx, 6, 6))
+}
+
 func TestJailValidator(t *testing.T) {
 	ctx, _, keeper := keep.CreateTestInput(t, false, 1000)
 	validatorAddr, delegatorAddr := sdk.ValAddress(keep.Addrs[0]), keep.Addrs[1]
@@ -663,6 +1189,123 @@ func TestJailValidator(t *testing.T) {
 	require.True(t, got.IsOK(), "expected ok, got %v", got)
 }
 
+func TestDelegateToJailedValidator(t *testing.T) {
+	ctx, _, keeper := keep.CreateTestInput(t, false, 1000)
+	validatorAddr, delegatorAddr := sdk.ValAddress(keep.Addrs[0]), keep.Addrs[1]
+	bondAmt := sdk.TokensFromTendermintPower(10)
+
+	// create and jail the validator
+	msgCreateValidator := NewTestMsgCreateValidator(validatorAddr, keep.PKs[0], bondAmt)
+	got := handleMsgCreateValidator(ctx, msgCreateValidator, keeper)
+	require.True(t, got.IsOK(), "expected no error on runMsgCreateValidator")
+	EndBlocker(ctx, keeper)
+	keeper.Jail(ctx, sdk.ConsAddress(keep.PKs[0].Address()))
+
+	// third-party delegations are rejected
+	msgDelegate := NewTestMsgDelegate(delegatorAddr, validatorAddr, bondAmt)
+	got = handleMsgDelegate(ctx, msgDelegate, keeper)
+	require.False(t, got.IsOK(), "expected delegation to a jailed validator to fail")
+	require.Equal(t, types.ErrValidatorJailed(keeper.Codespace()).Code(), got.Code)
+	_, found := keeper.GetDelegation(ctx, delegatorAddr, validatorAddr)
+	require.False(t, found)
+
+	// so are redelegations into it
+	otherValidatorAddr := sdk.ValAddress(keep.Addrs[2])
+	msgCreateValidator = NewTestMsgCreateValidator(otherValidatorAddr, keep.PKs[2], bondAmt)
+	got = handleMsgCreateValidator(ctx, msgCreateValidator, keeper)
+	require.True(t, got.IsOK(), "expected ok, got %v", got)
+	got = handleMsgDelegate(ctx, NewTestMsgDelegate(delegatorAddr, otherValidatorAddr, bondAmt), keeper)
+	require.True(t, got.IsOK(), "expected ok, got %v", got)
+	redelegateAmt := sdk.NewCoin(sdk.DefaultBondDenom, bondAmt)
+	msgRedelegate := NewMsgBeginRedelegate(delegatorAddr, otherValidatorAddr, validatorAddr, redelegateAmt)
+	got = handleMsgBeginRedelegate(ctx, msgRedelegate, keeper)
+	require.False(t, got.IsOK(), "expected redelegation to a jailed validator to fail")
+	require.Equal(t, types.ErrValidatorJailed(keeper.Codespace()).Code(), got.Code)
+
+	// the operator can still self-delegate
+	msgSelfDelegate := NewTestMsgDelegate(sdk.AccAddress(validatorAddr), validatorAddr, bondAmt)
+	got = handleMsgDelegate(ctx, msgSelfDelegate, keeper)
+	require.True(t, got.IsOK(), "expected ok, got %v", got)
+	validator, found := keeper.GetValidator(ctx, validatorAddr)
+	require.True(t, found)
+	require.True(t, validator.Jailed)
+	require.True(t, bondAmt.MulRaw(2).Equal(validator.Tokens))
+
+	// delegations are accepted again once unjailed
+	keeper.Unjail(ctx, sdk.ConsAddress(keep.PKs[0].Address()))
+	got = handleMsgDelegate(ctx, msgDelegate, keeper)
+	require.True(t, got.IsOK(), "expected ok, got %v", got)
+}
+
+func TestMaxDelegatorPowerShare(t *testing.T) {
+	ctx, _, keeper := keep.CreateTestInput(t, false, 1000)
+	delegatorAddr := keep.Addrs[2]
+
+	params := keeper.GetParams(ctx)
+	params.MaxValidators = 2
+	keeper.SetParams(ctx, params)
+
+	// create two bonded validators and an unbonded one
+	for i, power := range []int64{100, 100, 10} {
+		valAddr := sdk.ValAddress(keep.Addrs[i])
+		if i == 2 {
+			valAddr = sdk.ValAddress(keep.Addrs[3])
+		}
+		msg := NewTestMsgCreateValidator(valAddr, keep.PKs[i], sdk.TokensFromTendermintPower(power))
+		got := handleMsgCreateValidator(ctx, msg, keeper)
+		require.True(t, got.IsOK(), "expected ok, got %v", got)
+	}
+	EndBlocker(ctx, keeper)
+
+	// bring the delegator close to the cap, 20 of 220 bonded tokens
+	validatorAddr := sdk.ValAddress(keep.Addrs[0])
+	msgDelegate := NewTestMsgDelegate(delegatorAddr, validatorAddr, sdk.TokensFromTendermintPower(20))
+	got := handleMsgDelegate(ctx, msgDelegate, keeper)
+	require.True(t, got.IsOK(), "expected ok, got %v", got)
+	unbondedValidatorAddr := sdk.ValAddress(keep.Addrs[3])
+	msgDelegate = NewTestMsgDelegate(delegatorAddr, unbondedValidatorAddr, sdk.TokensFromTendermintPower(10))
+	got = handleMsgDelegate(ctx, msgDelegate, keeper)
+	require.True(t, got.IsOK(), "expected ok, got %v", got)
+
+	params = keeper.GetParams(ctx)
+	params.MaxDelegatorPowerShare = sdk.NewDecWithPrec(1, 1)
+	keeper.SetParams(ctx, params)
+
+	// 25 of 225 bonded tokens exceeds the cap, whichever validator is used
+	otherValidatorAddr := sdk.ValAddress(keep.Addrs[1])
+	msgDelegate = NewTestMsgDelegate(delegatorAddr, otherValidatorAddr, sdk.TokensFromTendermintPower(5))
+	got = handleMsgDelegate(ctx, msgDelegate, keeper)
+	require.False(t, got.IsOK(), "expected delegation over the cap to fail")
+	require.Equal(t, ErrMaxDelegatorPowerShare(keeper.Codespace(), params.MaxDelegatorPowerShare).Result().Code, got.Code)
+	_, found := keeper.GetDelegation(ctx, delegatorAddr, otherValidatorAddr)
+	require.False(t, found)
+
+	// 22 of 222 bonded tokens stays under the cap
+	msgDelegate = NewTestMsgDelegate(delegatorAddr, otherValidatorAddr, sdk.TokensFromTendermintPower(2))
+	got = handleMsgDelegate(ctx, msgDelegate, keeper)
+	require.True(t, got.IsOK(), "expected ok, got %v", got)
+	require.True(t, sdk.TokensFromTendermintPower(22).Equal(keeper.GetDelegatorBondedTokens(ctx, delegatorAddr)))
+
+	// compounding rewards is capped the same way
+	rewards := sdk.NewCoins(sdk.NewCoin(sdk.DefaultBondDenom, sdk.TokensFromTendermintPower(5)))
+	_, err := keeper.CompoundDelegation(ctx, delegatorAddr, otherValidatorAddr, rewards)
+	require.NotNil(t, err)
+	require.Equal(t, ErrMaxDelegatorPowerShare(keeper.Codespace(), params.MaxDelegatorPowerShare).Code(), err.Code())
+
+	// redelegating from the unbonded validator bonds 27 of 227 tokens
+	redelegateAmt := sdk.NewCoin(sdk.DefaultBondDenom, sdk.TokensFromTendermintPower(5))
+	msgRedelegate := NewMsgBeginRedelegate(delegatorAddr, unbondedValidatorAddr, otherValidatorAddr, redelegateAmt)
+	got = handleMsgBeginRedelegate(ctx, msgRedelegate, keeper)
+	require.False(t, got.IsOK(), "expected redelegation over the cap to fail")
+	require.Equal(t, ErrMaxDelegatorPowerShare(keeper.Codespace(), params.MaxDelegatorPowerShare).Result().Code, got.Code)
+
+	// redelegating between bonded validators keeps the bonded tokens
+	msgRedelegate = NewMsgBeginRedelegate(delegatorAddr, validatorAddr, otherValidatorAddr, redelegateAmt)
+	got = handleMsgBeginRedelegate(ctx, msgRedelegate, keeper)
+	require.True(t, got.IsOK(), "expected ok, got %v", got)
+	require.True(t, sdk.TokensFromTendermintPower(22).Equal(keeper.GetDelegatorBondedTokens(ctx, delegatorAddr)))
+}
+
 func TestValidatorQueue(t *testing.T) {
 	ctx, _, keeper := keep.CreateTestInput(t, false, 1000)
 	validatorAddr, delegatorAddr := sdk.ValAddress(keep.Addrs[0]), keep.Addrs[1]
@@ -911,6 +1554,162 @@ func TestTransitiveRedelegation(t *testing.T) {
 	require.True(t, got.IsOK(), "expected no error")
 }
 
+func TestCanRedelegate(t *testing.T) {
+	ctx, _, keeper := keep.CreateTestInput(t, false, 1000)
+	validatorAddr := sdk.ValAddress(keep.Addrs[0])
+	validatorAddr2 := sdk.ValAddress(keep.Addrs[1])
+	validatorAddr3 := sdk.ValAddress(keep.Addrs[2])
+	delAddr := sdk.AccAddress(validatorAddr)
+
+	// set the unbonding time
+	params := keeper.GetParams(ctx)
+	params.UnbondingTime = 7 * time.Second
+	keeper.SetParams(ctx, params)
+
+	// create the validators
+	for i, valAddr := range []sdk.ValAddress{validatorAddr, validatorAddr2, validatorAddr3} {
+		msgCreateValidator := NewTestMsgCreateValidator(valAddr, keep.PKs[i], sdk.TokensFromTendermintPower(10))
+		got := handleMsgCreateValidator(ctx, msgCreateValidator, keeper)
+		require.True(t, got.IsOK(), "expected no error on runMsgCreateValidator")
+	}
+	EndBlocker(ctx, keeper)
+
+	// nothing has been redelegated into the second validator yet
+	ok, _ := keeper.CanRedelegate(ctx, delAddr, validatorAddr2)
+	require.True(t, ok)
+
+	// redelegate into the second validator
+	redAmt := sdk.NewCoin(sdk.DefaultBondDenom, sdk.TokensFromTendermintPower(5))
+	msgBeginRedelegate := NewMsgBeginRedelegate(delAddr, validatorAddr, validatorAddr2, redAmt)
+	got := handleMsgBeginRedelegate(ctx, msgBeginRedelegate, keeper)
+	require.True(t, got.IsOK(), "expected no error, %v", got)
+	completionTime := ctx.BlockHeader().Time.Add(params.UnbondingTime)
+
+	// the second validator is ineligible as a source until maturity
+	ok, eligibleAt := keeper.CanRedelegate(ctx, delAddr, validatorAddr2)
+	require.False(t, ok)
+	require.True(t, completionTime.Equal(eligibleAt))
+	ok, _ = keeper.CanRedelegate(ctx, delAddr, validatorAddr)
+	require.True(t, ok)
+
+	msgBeginRedelegate = NewMsgBeginRedelegate(delAddr, validatorAddr2, validatorAddr3, redAmt)
+	got = handleMsgBeginRedelegate(ctx, msgBeginRedelegate, keeper)
+	require.False(t, got.IsOK(), "expected an error, msg: %v", msgBeginRedelegate)
+
+	// mature the redelegation
+	ctx = ctx.WithBlockTime(eligibleAt)
+	EndBlocker(ctx, keeper)
+
+	ok, _ = keeper.CanRedelegate(ctx, delAddr, validatorAddr2)
+	require.True(t, ok)
+	got = handleMsgBeginRedelegate(ctx, msgBeginRedelegate, keeper)
+	require.True(t, got.IsOK(), "expected no error, %v", got)
+}
+
+func TestMigrateValidatorOwner(t *testing.T) {
+	ctx, _, keeper := keep.CreateTestInput(t, false, 1000)
+	oldOwner := sdk.ValAddress(keep.Addrs[0])
+	newOwner := sdk.ValAddress(keep.Addrs[1])
+	otherOwner := sdk.ValAddress(keep.Addrs[2])
+	bondAmt := sdk.TokensFromTendermintPower(10)
+
+	for i, valAddr := range []sdk.ValAddress{oldOwner, otherOwner} {
+		msgCreateValidator := NewTestMsgCreateValidator(valAddr, keep.PKs[i], bondAmt)
+		got := handleMsgCreateValidator(ctx, msgCreateValidator, keeper)
+		require.True(t, got.IsOK(), "expected no error on runMsgCreateValidator")
+	}
+	EndBlocker(ctx, keeper)
+
+	// only the operator may sign the migration
+	msgMigrate := NewMsgMigrateValidatorOwner(oldOwner, newOwner)
+	require.Equal(t, []sdk.AccAddress{sdk.AccAddress(oldOwner)}, msgMigrate.GetSigners())
+
+	// cannot migrate onto an existing validator
+	got := handleMsgMigrateValidatorOwner(ctx, NewMsgMigrateValidatorOwner(oldOwner, otherOwner), keeper)
+	require.False(t, got.IsOK())
+
+	got = handleMsgMigrateValidatorOwner(ctx, msgMigrate, keeper)
+	require.True(t, got.IsOK(), "expected no error, %v", got)
+
+	_, found := keeper.GetValidator(ctx, oldOwner)
+	require.False(t, found)
+	_, found = keeper.GetDelegation(ctx, sdk.AccAddress(oldOwner), oldOwner)
+	require.False(t, found)
+
+	validator, found := keeper.GetValidator(ctx, newOwner)
+	require.True(t, found)
+	require.Equal(t, newOwner, validator.OperatorAddress)
+	require.Equal(t, sdk.Bonded, validator.Status)
+	require.True(t, bondAmt.Equal(validator.Tokens))
+	byConsAddr, found := keeper.GetValidatorByConsAddr(ctx, validator.ConsAddress())
+	require.True(t, found)
+	require.Equal(t, newOwner, byConsAddr.OperatorAddress)
+	delegation, found := keeper.GetDelegation(ctx, sdk.AccAddress(newOwner), newOwner)
+	require.True(t, found)
+	require.True(t, validator.DelegatorShares.Equal(delegation.Shares))
+
+	// the validator set is unchanged by the migration
+	updates, _ := EndBlocker(ctx, keeper)
+	require.Equal(t, 0, len(updates))
+	require.Equal(t, validator.GetTendermintPower(), keeper.GetLastValidatorPower(ctx, newOwner))
+
+	// the migrated validator keeps accepting delegations
+	msgDelegate := NewTestMsgDelegate(keep.Addrs[3], newOwner, bondAmt)
+	got = handleMsgDelegate(ctx, msgDelegate, keeper)
+	require.True(t, got.IsOK(), "expected no error, %v", got)
+
+	// a validator with other delegators cannot be migrated
+	got = handleMsgMigrateValidatorOwner(ctx, NewMsgMigrateValidatorOwner(newOwner, oldOwner), keeper)
+	require.False(t, got.IsOK())
+}
+
+// Multi-hop redelegation chains (A->B->C) cannot form while the first hop is
+// still slashable, so a slash on A only ever needs to reach B.
+func TestSlashRedelegationCannotChain(t *testing.T) {
+	ctx, _, keeper := keep.CreateTestInput(t, false, 1000)
+	valA := sdk.ValAddress(keep.Addrs[0])
+	valB := sdk.ValAddress(keep.Addrs[1])
+	valC := sdk.ValAddress(keep.Addrs[2])
+	del := keep.Addrs[3]
+
+	params := keeper.GetParams(ctx)
+	params.UnbondingTime = 10 * time.Second
+	keeper.SetParams(ctx, params)
+
+	// create the validators and delegate to the first one
+	bond := sdk.TokensFromTendermintPower(10)
+	for i, valAddr := range []sdk.ValAddress{valA, valB, valC} {
+		got := handleMsgCreateValidator(ctx, NewTestMsgCreateValidator(valAddr, keep.PKs[i], bond), keeper)
+		require.True(t, got.IsOK(), "expected no error on runMsgCreateValidator")
+	}
+	got := handleMsgDelegate(ctx, NewTestMsgDelegate(del, valA, bond), keeper)
+	require.True(t, got.IsOK(), "expected no error on runMsgDelegate")
+	EndBlocker(ctx, keeper)
+
+	// redelegate A->B after the infraction height
+	ctx = ctx.WithBlockHeight(1)
+	redAmt := sdk.NewCoin(sdk.DefaultBondDenom, bond)
+	got = handleMsgBeginRedelegate(ctx, NewMsgBeginRedelegate(del, valA, valB, redAmt), keeper)
+	require.True(t, got.IsOK(), "expected no error, %v", got)
+
+	// the second hop B->C is rejected while A->B is maturing
+	got = handleMsgBeginRedelegate(ctx, NewMsgBeginRedelegate(del, valB, valC, redAmt), keeper)
+	require.False(t, got.IsOK(), "expected an error")
+	require.Equal(t, types.ErrTransitiveRedelegation(keeper.Codespace()).Result().Code, got.Code)
+
+	// slashing A for an infraction at height 0 reaches the stake on B
+	ctx = ctx.WithBlockHeight(2)
+	validatorA, found := keeper.GetValidator(ctx, valA)
+	require.True(t, found)
+	keeper.Slash(ctx, validatorA.ConsAddress(), 0, 20, sdk.NewDecWithPrec(5, 1))
+
+	delegation, found := keeper.GetDelegation(ctx, del, valB)
+	require.True(t, found)
+	require.True(t, delegation.Shares.LT(bond.ToDec()), "expected the redelegated shares to be slashed")
+	_, found = keeper.GetDelegation(ctx, del, valC)
+	require.False(t, found)
+}
+
 func TestMultipleRedelegationAtSameTime(t *testing.T) {
 	ctx, _, keeper := keep.CreateTestInput(t, false, 1000)
 	valAddr := sdk.ValAddress(keep.Addrs[0])
@@ -1051,10 +1850,16 @@ func TestMultipleUnbondingDelegationAtSameTime(t *testing.T) {
 	got = handleMsgUndelegate(ctx, msgUndelegate, keeper)
 	require.True(t, got.IsOK(), "expected no error, msg: %v", msgUndelegate)
 
-	// now there should be two entries
+	// the second ubd should be merged into the first entry
 	ubd, found = keeper.GetUnbondingDelegation(ctx, selfDelAddr, valAddr)
 	require.True(t, found)
-	require.Len(t, ubd.Entries, 2)
+	require.Len(t, ubd.Entries, 1)
+	require.Equal(t, unbondAmt.Amount.MulRaw(2), ubd.Entries[0].Balance)
+	require.Equal(t, unbondAmt.Amount.MulRaw(2), ubd.Entries[0].InitialBalance)
+
+	// the merged entry should only be tracked once in the unbonding queue
+	completionTime := ubd.Entries[0].CompletionTime
+	require.Len(t, keeper.GetUBDQueueTimeSlice(ctx, completionTime), 1)
 
 	// move forwaubd in time, should complete both ubds
 	ctx = ctx.WithBlockTime(ctx.BlockHeader().Time.Add(1 * time.Second))
@@ -1174,6 +1979,70 @@ func TestUnbondingWhenExcessValidators(t *testing.T) {
 	require.Equal(t, sdk.Bonded, val1.Status, "%v", val1)
 }
 
+func TestEndBlockerBondedSetChangeTags(t *testing.T) {
+	ctx, _, keeper := keep.CreateTestInput(t, false, 1000)
+	validatorAddr1 := sdk.ValAddress(keep.Addrs[0])
+	validatorAddr2 := sdk.ValAddress(keep.Addrs[1])
+	validatorAddr3 := sdk.ValAddress(keep.Addrs[2])
+
+	params := keeper.GetParams(ctx)
+	params.MaxValidators = 2
+	keeper.SetParams(ctx, params)
+
+	// returns the tags following the given action tag, one map per event
+	events := func(resTags sdk.Tags, action string) (res []map[string]string) {
+		for i, tag := range resTags {
+			if string(tag.Key) != tags.Action || string(tag.Value) != action {
+				continue
+			}
+			event := make(map[string]string)
+			for _, attr := range resTags[i+1 : i+4] {
+				event[string(attr.Key)] = string(attr.Value)
+			}
+			res = append(res, event)
+		}
+		return res
+	}
+
+	powers := []int64{50, 30, 10}
+	for i, valAddr := range []sdk.ValAddress{validatorAddr1, validatorAddr2, validatorAddr3} {
+		msg := NewTestMsgCreateValidator(valAddr, keep.PKs[i], sdk.TokensFromTendermintPower(powers[i]))
+		got := handleMsgCreateValidator(ctx, msg, keeper)
+		require.True(t, got.IsOK(), "%v", got)
+	}
+	_, resTags := EndBlocker(ctx, keeper)
+	bonded := events(resTags, tags.ActionValidatorBonded)
+	require.Len(t, bonded, 2)
+	require.Equal(t, validatorAddr1.String(), bonded[0][tags.Validator])
+	require.Equal(t, validatorAddr2.String(), bonded[1][tags.Validator])
+	require.Empty(t, events(resTags, tags.ActionValidatorUnbonded))
+
+	// a power change within the bonded set is not announced
+	msgDelegate := NewTestMsgDelegate(keep.Addrs[7], validatorAddr1, sdk.TokensFromTendermintPower(5))
+	require.True(t, handleMsgDelegate(ctx, msgDelegate, keeper).IsOK())
+	_, resTags = EndBlocker(ctx, keeper)
+	require.Empty(t, events(resTags, tags.ActionValidatorBonded))
+	require.Empty(t, events(resTags, tags.ActionValidatorUnbonded))
+
+	// validator 3 crosses the cliff, pushing validator 2 out
+	msgDelegate = NewTestMsgDelegate(keep.Addrs[7], validatorAddr3, sdk.TokensFromTendermintPower(30))
+	require.True(t, handleMsgDelegate(ctx, msgDelegate, keeper).IsOK())
+	_, resTags = EndBlocker(ctx, keeper)
+
+	bonded = events(resTags, tags.ActionValidatorBonded)
+	require.Equal(t, []map[string]string{{
+		tags.Validator:  validatorAddr3.String(),
+		tags.ConsPubKey: sdk.MustBech32ifyConsPub(keep.PKs[2]),
+		tags.Status:     sdk.Bonded.String(),
+	}}, bonded)
+	unbonded := events(resTags, tags.ActionValidatorUnbonded)
+	require.Equal(t, []map[string]string{{
+		tags.Validator:  validatorAddr2.String(),
+		tags.ConsPubKey: sdk.MustBech32ifyConsPub(keep.PKs[1]),
+		tags.Status:     sdk.Unbonding.String(),
+	}}, unbonded)
+}
+
 func TestBondUnbondRedelegateSlashTwice(t *testing.T) {
 	ctx, _, keeper := keep.CreateTestInput(t, false, 1000)
 	valA, valB, del := sdk.ValAddress(keep.Addrs[0]), sdk.ValAddress(keep.Addrs[1]), keep.Addrs[2]
@@ -1282,3 +2151,145 @@ func TestInvalidMsg(t *testing.T) {
 	require.False(t, res.IsOK())
 	require.True(t, strings.Contains(res.Log, "unrecognized staking message type"))
 }
+
+func TestSignalExit(t *testing.T) {
+	ctx, _, keeper := keep.CreateTestInput(t, false, 1000)
+	valAddr := sdk.ValAddress(keep.Addrs[0])
+	bondAmt := sdk.TokensFromTendermintPower(10)
+
+	msgCreateValidator := NewTestMsgCreateValidator(valAddr, keep.PKs[0], bondAmt)
+	got := handleMsgCreateValidator(ctx, msgCreateValidator, keeper)
+	require.True(t, got.IsOK(), "expected no error on runMsgCreateValidator")
+	EndBlocker(ctx, keeper)
+	ctx = ctx.WithBlockHeight(10)
+
+	// the exit height must be in the future
+	got = handleMsgSignalExit(ctx, NewMsgSignalExit(valAddr, 10), keeper)
+	require.False(t, got.IsOK())
+	got = handleMsgSignalExit(ctx, NewMsgSignalExit(sdk.ValAddress(keep.Addrs[1]), 100), keeper)
+	require.False(t, got.IsOK())
+	require.NotNil(t, NewMsgSignalExit(valAddr, -1).ValidateBasic())
+
+	msgSignal := NewMsgSignalExit(valAddr, 100)
+	require.Nil(t, msgSignal.ValidateBasic())
+	got = handleMsgSignalExit(ctx, msgSignal, keeper)
+	require.True(t, got.IsOK(), "expected no error, %v", got)
+
+	// the signal is stored without changing the validator's power
+	validator, found := keeper.GetValidator(ctx, valAddr)
+	require.True(t, found)
+	require.Equal(t, int64(100), validator.PlannedExitHeight)
+	require.Equal(t, sdk.Bonded, validator.Status)
+	require.Equal(t, bondAmt, validator.Tokens)
+	updates, _ := EndBlocker(ctx, keeper)
+	require.Empty(t, updates)
+
+	// and is returned by the validator query
+	cdc := codec.New()
+	bz, err := cdc.MarshalJSON(NewQueryValidatorParams(valAddr))
+	require.Nil(t, err)
+	querier := NewQuerier(keeper, cdc, nil)
+	res, sdkErr := querier(ctx, []string{QueryValidator}, abci.RequestQuery{Data: bz})
+	require.Nil(t, sdkErr)
+	var queried types.Validator
+	require.Nil(t, cdc.UnmarshalJSON(res, &queried))
+	require.Equal(t, int64(100), queried.PlannedExitHeight)
+
+	// a zero height withdraws the signal
+	got = handleMsgSignalExit(ctx, NewMsgSignalExit(valAddr, 0), keeper)
+	require.True(t, got.IsOK(), "expected no error, %v", got)
+	validator, _ = keeper.GetValidator(ctx, valAddr)
+	require.Zero(t, validator.PlannedExitHeight)
+}
+
+func TestRebalance(t *testing.T) {
+	ctx, accKeeper, keeper := keep.CreateTestInput(t, false, 1000)
+	valA, valB, del := sdk.ValAddress(keep.Addrs[0]), sdk.ValAddress(keep.Addrs[1]), keep.Addrs[2]
+
+	valTokens := sdk.TokensFromTendermintPower(10)
+	for i, valAddr := range []sdk.ValAddress{valA, valB} {
+		got := handleMsgCreateValidator(ctx, NewTestMsgCreateValidator(valAddr, keep.PKs[i], valTokens), keeper)
+		require.True(t, got.IsOK(), "expected no error on runMsgCreateValidator")
+	}
+	EndBlocker(ctx, keeper)
+
+	delTokens := sdk.TokensFromTendermintPower(5)
+	got := handleMsgDelegate(ctx, NewTestMsgDelegate(del, valA, delTokens), keeper)
+	require.True(t, got.IsOK(), "expected no error, %v", got)
+	balance := accKeeper.GetAccount(ctx, del).GetCoins()
+
+	// the unbonding leg fails as it exceeds the delegation to the source, so
+	// the delegation to the destination is rolled back too
+	tooMuch := sdk.NewCoin(sdk.DefaultBondDenom, delTokens.Add(sdk.OneInt()))
+	got = handleMsgRebalance(ctx, NewMsgRebalance(del, valA, valB, tooMuch), keeper)
+	require.False(t, got.IsOK())
+	_, found := keeper.GetDelegation(ctx, del, valB)
+	require.False(t, found)
+	_, found = keeper.GetUnbondingDelegation(ctx, del, valA)
+	require.False(t, found)
+	require.Equal(t, balance, accKeeper.GetAccount(ctx, del).GetCoins())
+	require.True(t, valTokens.Equal(keeper.Validator(ctx, valB).GetTokens()))
+
+	// both legs apply when they succeed
+	amount := sdk.NewCoin(sdk.DefaultBondDenom, delTokens)
+	got = handleMsgRebalance(ctx, NewMsgRebalance(del, valA, valB, amount), keeper)
+	require.True(t, got.IsOK(), "expected no error, %v", got)
+	delegation, found := keeper.GetDelegation(ctx, del, valB)
+	require.True(t, found)
+	require.True(t, delTokens.ToDec().Equal(delegation.Shares))
+	ubd, found := keeper.GetUnbondingDelegation(ctx, del, valA)
+	require.True(t, found)
+	require.Len(t, ubd.Entries, 1)
+	require.True(t, delTokens.Equal(ubd.Entries[0].Balance))
+	_, found = keeper.GetDelegation(ctx, del, valA)
+	require.False(t, found)
+	require.Equal(t, balance.Sub(sdk.Coins{amount}), accKeeper.GetAccount(ctx, del).GetCoins())
+
+	require.NotNil(t, NewMsgRebalance(del, valA, valA, amount).ValidateBasic())
+}
+
+func TestSetAutoCompound(t *testing.T) {
+	ctx, _, keeper := keep.CreateTestInput(t, false, 1000)
+	valAddr := sdk.ValAddress(keep.Addrs[0])
+	delAddr := keep.Addrs[1]
+	bondAmt := sdk.TokensFromTendermintPower(10)
+
+	msgCreateValidator := NewTestMsgCreateValidator(valAddr, keep.PKs[0], bondAmt)
+	got := handleMsgCreateValidator(ctx, msgCreateValidator, keeper)
+	require.True(t, got.IsOK(), "expected no error on runMsgCreateValidator")
+
+	// a missing delegation can't compound
+	msgSet := NewMsgSetAutoCompound(delAddr, valAddr, true)
+	require.Nil(t, msgSet.ValidateBasic())
+	got = handleMsgSetAutoCompound(ctx, msgSet, keeper)
+	require.False(t, got.IsOK())
+
+	msgDelegate := NewTestMsgDelegate(delAddr, valAddr, bondAmt)
+	got = handleMsgDelegate(ctx, msgDelegate, keeper)
+	require.True(t, got.IsOK(), "expected no error, %v", got)
+	got = handleMsgSetAutoCompound(ctx, msgSet, keeper)
+	require.True(t, got.IsOK(), "expected no error, %v", got)
+
+	delegation, found := keeper.GetDelegation(ctx, delAddr, valAddr)
+	require.True(t, found)
+	require.True(t, delegation.AutoCompound)
+	var compounding []sdk.Delegation
+	keeper.IterateAutoCompoundDelegations(ctx, func(_ int64, del sdk.Delegation) bool {
+		compounding = append(compounding, del)
+		return false
+	})
+	require.Len(t, compounding, 1)
+	require.Equal(t, delAddr, compounding[0].GetDelegatorAddr())
+
+	// disabling removes the delegation from the index
+	got = handleMsgSetAutoCompound(ctx, NewMsgSetAutoCompound(delAddr, valAddr, false), keeper)
+	require.True(t, got.IsOK(), "expected no error, %v", got)
+	delegation, _ = keeper.GetDelegation(ctx, delAddr, valAddr)
+	require.False(t, delegation.AutoCompound)
+	compounding = nil
+	keeper.IterateAutoCompoundDelegations(ctx, func(_ int64, del sdk.Delegation) bool {
+		compounding = append(compounding, del)
+		return false
+	})
+	require.Empty(t, compounding)
+}
@@ -2,8 +2,11 @@ package keeper
 
 import (
 	"bytes"
+	"sort"
 	"time"
 
+	tmtypes "github.com/tendermint/tendermint/types"
+
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/x/staking/types"
 )
@@ -24,6 +27,83 @@ func (k Keeper) GetDelegation(ctx sdk.Context,
 	return delegation, true
 }
 
+// GetDelegationSharePercentage returns the delegation's shares divided by the
+// validator's total delegator shares, and false if either does not exist.
+func (k Keeper) GetDelegationSharePercentage(ctx sdk.Context,
+	delAddr sdk.AccAddress, valAddr sdk.ValAddress) (sdk.Dec, bool) {
+
+	delegation, found := k.GetDelegation(ctx, delAddr, valAddr)
+	if !found {
+		return sdk.ZeroDec(), false
+	}
+	validator, found := k.GetValidator(ctx, valAddr)
+	if !found || validator.DelegatorShares.IsZero() {
+		return sdk.ZeroDec(), false
+	}
+	return delegation.Shares.Quo(validator.DelegatorShares), true
+}
+
+// GetValidatorDelegationConcentration returns the shares of the largest
+// single delegation to the validator divided by the validator's total
+// delegator shares, and false if the validator does not exist or has no
+// delegator shares.
+func (k Keeper) GetValidatorDelegationConcentration(ctx sdk.Context, ownerAddr sdk.ValAddress) (sdk.Dec, bool) {
+	validator, found := k.GetValidator(ctx, ownerAddr)
+	if !found || validator.DelegatorShares.IsZero() {
+		return sdk.ZeroDec(), false
+	}
+
+	largest := sdk.ZeroDec()
+	for _, delegation := range k.GetValidatorDelegations(ctx, ownerAddr) {
+		if delegation.Shares.GT(largest) {
+			largest = delegation.Shares
+		}
+	}
+	return largest.Quo(validator.DelegatorShares), true
+}
+
+// GetValidatorLiquidationValue returns the tokens all the delegations to the
+// validator would receive if they were fully unbonded now, one after the
+// other, at the validator's exchange rate. Zero is returned if the validator
+// does not exist.
+func (k Keeper) GetValidatorLiquidationValue(ctx sdk.Context, ownerAddr sdk.ValAddress) sdk.Int {
+	validator, found := k.GetValidator(ctx, ownerAddr)
+	if !found {
+		return sdk.ZeroInt()
+	}
+
+	pool := k.GetPool(ctx)
+	rounding := k.ShareRoundingMode(ctx)
+	value := sdk.ZeroInt()
+	for _, delegation := range k.GetValidatorDelegations(ctx, ownerAddr) {
+		var tokens sdk.Int
+		validator, pool, tokens = validator.RemoveDelShares(pool, delegation.Shares, rounding)
+		value = value.Add(tokens)
+	}
+	return value
+}
+
+// LoyaltyMultiplier returns the fraction of its rewards a delegation earns
+// given how long it has been bonded. It grows linearly from one minus the
+// loyalty weighting for a new delegation to one once the delegation has been
+// bonded for the loyalty period.
+func (k Keeper) LoyaltyMultiplier(ctx sdk.Context, del sdk.Delegation) sdk.Dec {
+	weighting := k.LoyaltyWeighting(ctx)
+	if !weighting.IsPositive() {
+		return sdk.OneDec()
+	}
+
+	period := k.LoyaltyPeriod(ctx)
+	tenure := ctx.BlockHeight() - del.GetBondHeight()
+	if tenure >= period {
+		return sdk.OneDec()
+	}
+	if tenure < 0 {
+		tenure = 0
+	}
+	return sdk.OneDec().Sub(weighting).Add(weighting.MulInt64(tenure).QuoInt64(period))
+}
+
 // return all delegations used during genesis dump
 func (k Keeper) GetAllDelegations(ctx sdk.Context) (delegations []types.Delegation) {
 	store := ctx.KVStore(k.storeKey)
@@ -37,6 +117,63 @@ func (k Keeper) GetAllDelegations(ctx sdk.Context) (delegations []types.Delegati
 	return delegations
 }
 
+// GetTopDelegators returns the n delegators with the largest token value
+// bonded across all validators, largest first. Delegations to validators which
+// are not bonded are not counted. Ties are ordered by delegator address.
+func (k Keeper) GetTopDelegators(ctx sdk.Context, n int) []types.DelegatorStake {
+	if n <= 0 {
+		return nil
+	}
+
+	store := ctx.KVStore(k.storeKey)
+	iterator := sdk.KVStorePrefixIterator(store, DelegationKey)
+	defer iterator.Close()
+
+	validators := make(map[string]types.Validator)
+	indexes := make(map[string]int)
+	var stakes []types.DelegatorStake
+	for ; iterator.Valid(); iterator.Next() {
+		delegation := types.MustUnmarshalDelegation(k.cdc, iterator.Value())
+
+		valKey := string(delegation.ValidatorAddress)
+		validator, found := validators[valKey]
+		if !found {
+			validator, found = k.GetValidator(ctx, delegation.ValidatorAddress)
+			if !found {
+				continue
+			}
+			validators[valKey] = validator
+		}
+		if validator.Status != sdk.Bonded {
+			continue
+		}
+
+		tokens := validator.TokensFromShares(delegation.Shares)
+		delKey := string(delegation.DelegatorAddress)
+		i, found := indexes[delKey]
+		if !found {
+			indexes[delKey] = len(stakes)
+			stakes = append(stakes, types.DelegatorStake{
+				DelegatorAddress: delegation.DelegatorAddress,
+				Tokens:           tokens,
+			})
+			continue
+		}
+		stakes[i].Tokens = stakes[i].Tokens.Add(tokens)
+	}
+
+	sort.Slice(stakes, func(i, j int) bool {
+		if !stakes[i].Tokens.Equal(stakes[j].Tokens) {
+			return stakes[i].Tokens.GT(stakes[j].Tokens)
+		}
+		return bytes.Compare(stakes[i].DelegatorAddress, stakes[j].DelegatorAddress) < 0
+	})
+	if len(stakes) > n {
+		stakes = stakes[:n]
+	}
+	return stakes
+}
+
 // return all delegations to a specific validator. Useful for querier.
 func (k Keeper) GetValidatorDelegations(ctx sdk.Context, valAddr sdk.ValAddress) (delegations []types.Delegation) {
 	store := ctx.KVStore(k.storeKey)
@@ -52,6 +189,71 @@ func (k Keeper) GetValidatorDelegations(ctx sdk.Context, valAddr sdk.ValAddress)
 	return delegations
 }
 
+// GetValidatorDelegatorsSorted returns the delegations to a validator sorted
+// by shares, largest first, with ties ordered by delegator address. At most
+// limit delegations are returned if limit is positive. Like
+// GetValidatorDelegations it iterates over every delegation.
+func (k Keeper) GetValidatorDelegatorsSorted(ctx sdk.Context, ownerAddr sdk.ValAddress, limit int) []types.Delegation {
+	delegations := k.GetValidatorDelegations(ctx, ownerAddr)
+	sort.Slice(delegations, func(i, j int) bool {
+		if !delegations[i].Shares.Equal(delegations[j].Shares) {
+			return delegations[i].Shares.GT(delegations[j].Shares)
+		}
+		return bytes.Compare(delegations[i].DelegatorAddress, delegations[j].DelegatorAddress) < 0
+	})
+	if limit > 0 && len(delegations) > limit {
+		delegations = delegations[:limit]
+	}
+	return delegations
+}
+
+// SnapshotValidatorDelegations returns every delegation to the validator with
+// its token value at the current exchange rate, ordered by delegator address.
+func (k Keeper) SnapshotValidatorDelegations(ctx sdk.Context, ownerAddr sdk.ValAddress) (snapshot []types.DelegationSnapshot) {
+	validator, found := k.GetValidator(ctx, ownerAddr)
+	if !found {
+		return nil
+	}
+
+	delegations := types.Delegations(k.GetValidatorDelegations(ctx, ownerAddr))
+	delegations.Sort()
+	for _, delegation := range delegations {
+		snapshot = append(snapshot, types.DelegationSnapshot{
+			DelegatorAddress: delegation.DelegatorAddress,
+			Shares:           delegation.Shares,
+			Tokens:           validator.TokensFromShares(delegation.Shares),
+		})
+	}
+	return snapshot
+}
+
+// GetDelegationsAboveValue returns all delegations whose shares are worth
+// more than the given amount of tokens at their validator's exchange rate.
+// This iterates over every delegation and should not be used in a block.
+func (k Keeper) GetDelegationsAboveValue(ctx sdk.Context, minTokens sdk.Int) (delegations []types.Delegation) {
+	store := ctx.KVStore(k.storeKey)
+	iterator := sdk.KVStorePrefixIterator(store, DelegationKey)
+	defer iterator.Close()
+
+	min := sdk.NewDecFromInt(minTokens)
+	validators := make(map[string]types.Validator)
+	for ; iterator.Valid(); iterator.Next() {
+		delegation := types.MustUnmarshalDelegation(k.cdc, iterator.Value())
+		validator, ok := validators[delegation.ValidatorAddress.String()]
+		if !ok {
+			validator, ok = k.GetValidator(ctx, delegation.ValidatorAddress)
+			if !ok {
+				continue
+			}
+			validators[delegation.ValidatorAddress.String()] = validator
+		}
+		if validator.TokensFromShares(delegation.Shares).GT(min) {
+			delegations = append(delegations, delegation)
+		}
+	}
+	return delegations
+}
+
 // return a given amount of all the delegations from a delegator
 func (k Keeper) GetDelegatorDelegations(ctx sdk.Context, delegator sdk.AccAddress,
 	maxRetrieve uint16) (delegations []types.Delegation) {
@@ -72,11 +274,27 @@ func (k Keeper) GetDelegatorDelegations(ctx sdk.Context, delegator sdk.AccAddres
 	return delegations[:i] // trim if the array length < maxRetrieve
 }
 
+// HasDelegations returns whether the delegator has any delegation, without
+// unmarshalling it.
+func (k Keeper) HasDelegations(ctx sdk.Context, delegator sdk.AccAddress) bool {
+	store := ctx.KVStore(k.storeKey)
+	iterator := sdk.KVStorePrefixIterator(store, GetDelegationsKey(delegator))
+	defer iterator.Close()
+	return iterator.Valid()
+}
+
 // set a delegation
 func (k Keeper) SetDelegation(ctx sdk.Context, delegation types.Delegation) {
 	store := ctx.KVStore(k.storeKey)
+	key := GetDelegationKey(delegation.DelegatorAddress, delegation.ValidatorAddress)
+	if !store.Has(key) {
+		k.setTotalDelegationCount(ctx, k.GetTotalDelegationCount(ctx)+1)
+	}
 	b := types.MustMarshalDelegation(k.cdc, delegation)
-	store.Set(GetDelegationKey(delegation.DelegatorAddress, delegation.ValidatorAddress), b)
+	store.Set(key, b)
+	if delegation.AutoCompound {
+		store.Set(GetAutoCompoundDelegationKey(delegation.DelegatorAddress, delegation.ValidatorAddress), []byte{})
+	}
 }
 
 // remove a delegation
@@ -84,7 +302,176 @@ func (k Keeper) RemoveDelegation(ctx sdk.Context, delegation types.Delegation) {
 	// TODO: Consider calling hooks outside of the store wrapper functions, it's unobvious.
 	k.BeforeDelegationRemoved(ctx, delegation.DelegatorAddress, delegation.ValidatorAddress)
 	store := ctx.KVStore(k.storeKey)
-	store.Delete(GetDelegationKey(delegation.DelegatorAddress, delegation.ValidatorAddress))
+	key := GetDelegationKey(delegation.DelegatorAddress, delegation.ValidatorAddress)
+	if store.Has(key) {
+		k.setTotalDelegationCount(ctx, k.GetTotalDelegationCount(ctx)-1)
+	}
+	store.Delete(key)
+	if delegation.AutoCompound {
+		store.Delete(GetAutoCompoundDelegationKey(delegation.DelegatorAddress, delegation.ValidatorAddress))
+	}
+}
+
+// SetDelegationAutoCompound enables or disables the re-delegation of the
+// delegation's rewards to its validator every AutoCompoundInterval blocks.
+func (k Keeper) SetDelegationAutoCompound(ctx sdk.Context, delAddr sdk.AccAddress, valAddr sdk.ValAddress, enabled bool) sdk.Error {
+	delegation, found := k.GetDelegation(ctx, delAddr, valAddr)
+	if !found {
+		return types.ErrNoDelegation(k.Codespace())
+	}
+
+	delegation.AutoCompound = enabled
+	k.SetDelegation(ctx, delegation)
+	if !enabled {
+		store := ctx.KVStore(k.storeKey)
+		store.Delete(GetAutoCompoundDelegationKey(delAddr, valAddr))
+	}
+	return nil
+}
+
+// IterateAutoCompoundDelegations iterates through the delegations which
+// auto-compound their rewards, ordered by delegator, until fn returns true.
+func (k Keeper) IterateAutoCompoundDelegations(ctx sdk.Context, fn func(index int64, delegation sdk.Delegation) (stop bool)) {
+	store := ctx.KVStore(k.storeKey)
+	iterator := sdk.KVStorePrefixIterator(store, AutoCompoundDelegationKey)
+	defer iterator.Close()
+
+	for i := int64(0); iterator.Valid(); iterator.Next() {
+		addrs := iterator.Key()[len(AutoCompoundDelegationKey):]
+		delegation, found := k.GetDelegation(ctx, addrs[:sdk.AddrLen], addrs[sdk.AddrLen:])
+		if !found {
+			continue
+		}
+		if fn(i, delegation) {
+			break
+		}
+		i++
+	}
+}
+
+// ValidateDelegation checks that the validator can take a delegation of the
+// given amount from the delegator: it must not be frozen, nor jailed unless
+// the operator delegates to it, and neither its delegation cap nor the
+// delegator's maximum share of the bonded tokens may be exceeded.
+func (k Keeper) ValidateDelegation(ctx sdk.Context, delAddr sdk.AccAddress, validator types.Validator, amount sdk.Int) sdk.Error {
+	if validator.Frozen {
+		return types.ErrValidatorFrozen(k.Codespace(), validator.OperatorAddress)
+	}
+
+	// only the operator may delegate to a jailed validator, e.g. to meet its
+	// min self delegation before unjailing
+	isSelfDelegation := delAddr.Equals(sdk.AccAddress(validator.OperatorAddress))
+	if validator.Jailed && !isSelfDelegation {
+		return types.ErrValidatorJailed(k.Codespace())
+	}
+
+	// self-delegations are exempt from the validator's delegation cap
+	if !isSelfDelegation && validator.ExceedsMaxTotalDelegation(amount) {
+		return types.ErrMaxTotalDelegationExceeded(k.Codespace())
+	}
+
+	if k.ExceedsMaxDelegatorPowerShare(ctx, delAddr, amount) {
+		return types.ErrMaxDelegatorPowerShare(k.Codespace(), k.MaxDelegatorPowerShare(ctx))
+	}
+	return nil
+}
+
+// ValidateRedelegation checks that the destination validator can take a
+// redelegation of the given amount from the delegator: it must not be jailed,
+// nor its delegation cap exceeded, unless the operator redelegates to it.
+// Redelegating from a bonded validator leaves the delegator's bonded tokens
+// unchanged, so only redelegations from a validator which is not bonded count
+// towards the delegator's maximum share of the bonded tokens.
+func (k Keeper) ValidateRedelegation(ctx sdk.Context, delAddr sdk.AccAddress,
+	srcValidator, dstValidator types.Validator, amount sdk.Int) sdk.Error {
+
+	isSelfDelegation := delAddr.Equals(sdk.AccAddress(dstValidator.OperatorAddress))
+	if dstValidator.Jailed && !isSelfDelegation {
+		return types.ErrValidatorJailed(k.Codespace())
+	}
+	if !isSelfDelegation && dstValidator.ExceedsMaxTotalDelegation(amount) {
+		return types.ErrMaxTotalDelegationExceeded(k.Codespace())
+	}
+
+	if srcValidator.Status != sdk.Bonded && k.ExceedsMaxDelegatorPowerShare(ctx, delAddr, amount) {
+		return types.ErrMaxDelegatorPowerShare(k.Codespace(), k.MaxDelegatorPowerShare(ctx))
+	}
+	return nil
+}
+
+// CompoundDelegation delegates the bond denom part of the given coins, held by
+// the delegator, to the validator of the delegation, returning the amount
+// delegated. The same restrictions as for delegating apply, see
+// ValidateDelegation.
+func (k Keeper) CompoundDelegation(ctx sdk.Context, delAddr sdk.AccAddress, valAddr sdk.ValAddress, coins sdk.Coins) (sdk.Int, sdk.Error) {
+	amount := coins.AmountOf(k.BondDenom(ctx))
+	if !amount.IsPositive() {
+		return sdk.ZeroInt(), nil
+	}
+
+	validator, found := k.GetValidator(ctx, valAddr)
+	if !found {
+		return sdk.ZeroInt(), types.ErrNoValidatorFound(k.Codespace())
+	}
+	if err := k.ValidateDelegation(ctx, delAddr, validator, amount); err != nil {
+		return sdk.ZeroInt(), err
+	}
+
+	if _, err := k.Delegate(ctx, delAddr, amount, validator, true); err != nil {
+		return sdk.ZeroInt(), err
+	}
+	return amount, nil
+}
+
+// GetTotalDelegationCount returns the number of delegations in state. The
+// count is maintained as delegations are created and removed.
+func (k Keeper) GetTotalDelegationCount(ctx sdk.Context) (count int) {
+	store := ctx.KVStore(k.storeKey)
+	bz := store.Get(DelegationCountKey)
+	if bz == nil {
+		return 0
+	}
+	k.cdc.MustUnmarshalBinaryLengthPrefixed(bz, &count)
+	return count
+}
+
+// set the number of delegations in state
+func (k Keeper) setTotalDelegationCount(ctx sdk.Context, count int) {
+	store := ctx.KVStore(k.storeKey)
+	store.Set(DelegationCountKey, k.cdc.MustMarshalBinaryLengthPrefixed(count))
+}
+
+// GetDelegatorBondedTokens returns the value in tokens of the delegator's
+// delegations to bonded validators.
+func (k Keeper) GetDelegatorBondedTokens(ctx sdk.Context, delAddr sdk.AccAddress) sdk.Int {
+	bonded := sdk.ZeroDec()
+	store := ctx.KVStore(k.storeKey)
+	iterator := sdk.KVStorePrefixIterator(store, GetDelegationsKey(delAddr))
+	defer iterator.Close()
+	for ; iterator.Valid(); iterator.Next() {
+		delegation := types.MustUnmarshalDelegation(k.cdc, iterator.Value())
+		validator, found := k.GetValidator(ctx, delegation.ValidatorAddress)
+		if found && validator.Status == sdk.Bonded {
+			bonded = bonded.Add(validator.TokensFromShares(delegation.Shares))
+		}
+	}
+	return bonded.TruncateInt()
+}
+
+// ExceedsMaxDelegatorPowerShare returns whether delegating the given amount
+// would give the delegator more than the maximum fraction of the bonded
+// tokens. The delegated amount is counted as bonded.
+func (k Keeper) ExceedsMaxDelegatorPowerShare(ctx sdk.Context, delAddr sdk.AccAddress, amount sdk.Int) bool {
+	maxShare := k.MaxDelegatorPowerShare(ctx)
+	if !maxShare.IsPositive() {
+		return false
+	}
+	total := k.GetPool(ctx).BondedTokens.Add(amount)
+	if !total.IsPositive() {
+		return false
+	}
+	share := k.GetDelegatorBondedTokens(ctx, delAddr).Add(amount).ToDec().QuoInt(total)
+	return share.GT(maxShare)
 }
 
 // return a given amount of all the delegator unbonding-delegations
@@ -122,6 +509,29 @@ func (k Keeper) GetUnbondingDelegation(ctx sdk.Context,
 	return ubd, true
 }
 
+// GetUnbondingPayout returns the tokens the entries of an unbonding delegation
+// created at the given height will return on completion. Slashes since the
+// unbonding began are already deducted from the entries' balance. It returns
+// false if no such entry exists.
+func (k Keeper) GetUnbondingPayout(ctx sdk.Context, delAddr sdk.AccAddress,
+	valAddr sdk.ValAddress, creationHeight int64) (payout sdk.Int, found bool) {
+
+	ubd, found := k.GetUnbondingDelegation(ctx, delAddr, valAddr)
+	if !found {
+		return payout, false
+	}
+
+	payout = sdk.ZeroInt()
+	found = false
+	for _, entry := range ubd.Entries {
+		if entry.CreationHeight == creationHeight {
+			payout = payout.Add(entry.Balance)
+			found = true
+		}
+	}
+	return payout, found
+}
+
 // return all unbonding delegations from a particular validator
 func (k Keeper) GetUnbondingDelegationsFromValidator(ctx sdk.Context, valAddr sdk.ValAddress) (ubds []types.UnbondingDelegation) {
 	store := ctx.KVStore(k.storeKey)
@@ -137,6 +547,19 @@ func (k Keeper) GetUnbondingDelegationsFromValidator(ctx sdk.Context, valAddr sd
 	return ubds
 }
 
+// GetValidatorUnbondingTokens returns the sum of the balances of all the
+// pending unbonding delegation entries from a validator, i.e. the stake which
+// is leaving it.
+func (k Keeper) GetValidatorUnbondingTokens(ctx sdk.Context, ownerAddr sdk.ValAddress) sdk.Int {
+	total := sdk.ZeroInt()
+	for _, ubd := range k.GetUnbondingDelegationsFromValidator(ctx, ownerAddr) {
+		for _, entry := range ubd.Entries {
+			total = total.Add(entry.Balance)
+		}
+	}
+	return total
+}
+
 // iterate through all of the unbonding delegations
 func (k Keeper) IterateUnbondingDelegations(ctx sdk.Context, fn func(index int64, ubd types.UnbondingDelegation) (stop bool)) {
 	store := ctx.KVStore(k.storeKey)
@@ -256,6 +679,60 @@ func (k Keeper) DequeueAllMatureUBDQueue(ctx sdk.Context,
 	return matureUnbonds
 }
 
+// GetUnbondingQueueSize returns the number of unbonding delegation entries
+// maturing at or before the given time, i.e. the number an EndBlock at that
+// time would complete, without removing them from the queue.
+func (k Keeper) GetUnbondingQueueSize(ctx sdk.Context, beforeTime time.Time) (size int) {
+	unbondingTimesliceIterator := k.UBDQueueIterator(ctx, beforeTime)
+	defer unbondingTimesliceIterator.Close()
+
+	for ; unbondingTimesliceIterator.Valid(); unbondingTimesliceIterator.Next() {
+		timeslice := []types.DVPair{}
+		k.cdc.MustUnmarshalBinaryLengthPrefixed(unbondingTimesliceIterator.Value(), &timeslice)
+		size += len(timeslice)
+	}
+	return size
+}
+
+// GetMatureUnbondingDelegations returns the unbonding delegations queued to
+// mature by the current block time, each with only its mature entries. These
+// are the entries the next EndBlock completes, so outside of EndBlock any
+// entry returned is one which has not been processed yet.
+func (k Keeper) GetMatureUnbondingDelegations(ctx sdk.Context) (ubds []types.UnbondingDelegation) {
+	blockTime := ctx.BlockHeader().Time
+	unbondingTimesliceIterator := k.UBDQueueIterator(ctx, blockTime)
+	defer unbondingTimesliceIterator.Close()
+
+	seen := make(map[string]bool)
+	for ; unbondingTimesliceIterator.Valid(); unbondingTimesliceIterator.Next() {
+		timeslice := []types.DVPair{}
+		k.cdc.MustUnmarshalBinaryLengthPrefixed(unbondingTimesliceIterator.Value(), &timeslice)
+		for _, dvPair := range timeslice {
+			key := string(GetUBDKey(dvPair.DelegatorAddress, dvPair.ValidatorAddress))
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			ubd, found := k.GetUnbondingDelegation(ctx, dvPair.DelegatorAddress, dvPair.ValidatorAddress)
+			if !found {
+				continue
+			}
+			var entries []types.UnbondingDelegationEntry
+			for _, entry := range ubd.Entries {
+				if entry.IsMature(blockTime) {
+					entries = append(entries, entry)
+				}
+			}
+			if len(entries) > 0 {
+				ubd.Entries = entries
+				ubds = append(ubds, ubd)
+			}
+		}
+	}
+	return ubds
+}
+
 // return a given amount of all the delegator redelegations
 func (k Keeper) GetRedelegations(ctx sdk.Context, delegator sdk.AccAddress,
 	maxRetrieve uint16) (redelegations []types.Redelegation) {
@@ -317,6 +794,38 @@ func (k Keeper) HasReceivingRedelegation(ctx sdk.Context,
 	return iterator.Valid()
 }
 
+// CanRedelegate returns whether the delegator can redelegate away from the
+// given source validator. This is not the case while a redelegation into that
+// validator is still maturing, in which case the completion time of the last
+// maturing entry is returned as the time from which redelegating is allowed.
+func (k Keeper) CanRedelegate(ctx sdk.Context, delAddr sdk.AccAddress,
+	valSrcAddr sdk.ValAddress) (bool, time.Time) {
+
+	store := ctx.KVStore(k.storeKey)
+	iterator := sdk.KVStorePrefixIterator(store, GetREDsByDelToValDstIndexKey(delAddr, valSrcAddr))
+	defer iterator.Close()
+
+	var eligibleAt time.Time
+	found := false
+	for ; iterator.Valid(); iterator.Next() {
+		value := store.Get(GetREDKeyFromValDstIndexKey(iterator.Key()))
+		if value == nil {
+			continue
+		}
+		red := types.MustUnmarshalRED(k.cdc, value)
+		for _, entry := range red.Entries {
+			found = true
+			if entry.CompletionTime.After(eligibleAt) {
+				eligibleAt = entry.CompletionTime
+			}
+		}
+	}
+	if !found {
+		return true, time.Time{}
+	}
+	return false, eligibleAt
+}
+
 // HasMaxRedelegationEntries - redelegation has maximum number of entries
 func (k Keeper) HasMaxRedelegationEntries(ctx sdk.Context,
 	delegatorAddr sdk.AccAddress, validatorSrcAddr,
@@ -454,10 +963,19 @@ func (k Keeper) Delegate(ctx sdk.Context, delAddr sdk.AccAddress, bondAmt sdk.In
 		return sdk.ZeroDec(), types.ErrDelegatorShareExRateInvalid(k.Codespace())
 	}
 
+	// The validator and bonded pool tokens must remain representable as a
+	// Tendermint power, which is an int64 bounded by MaxTotalVotingPower, so
+	// reject delegations which would overflow it rather than panicking later.
+	maxTokens := sdk.TokensFromTendermintPower(tmtypes.MaxTotalVotingPower)
+	if validator.Tokens.Add(bondAmt).GT(maxTokens) || k.GetPool(ctx).BondedTokens.Add(bondAmt).GT(maxTokens) {
+		return sdk.ZeroDec(), types.ErrDelegationPowerOverflow(k.Codespace())
+	}
+
 	// Get or create the delegation object
 	delegation, found := k.GetDelegation(ctx, delAddr, validator.OperatorAddress)
 	if !found {
 		delegation = types.NewDelegation(delAddr, validator.OperatorAddress, sdk.ZeroDec())
+		delegation.BondHeight = ctx.BlockHeight()
 	}
 
 	// call the appropriate hook if present
@@ -468,7 +986,7 @@ func (k Keeper) Delegate(ctx sdk.Context, delAddr sdk.AccAddress, bondAmt sdk.In
 	}
 
 	if subtractAccount {
-		_, err := k.bankKeeper.DelegateCoins(ctx, delegation.DelegatorAddress, sdk.Coins{sdk.NewCoin(k.GetParams(ctx).BondDenom, bondAmt)})
+		_, err := k.bankKeeper.DelegateCoins(ctx, delegation.DelegatorAddress, sdk.Coins{sdk.NewCoin(k.BondDenom(ctx), bondAmt)})
 		if err != nil {
 			return sdk.Dec{}, err
 		}
@@ -476,8 +994,17 @@ func (k Keeper) Delegate(ctx sdk.Context, delAddr sdk.AccAddress, bondAmt sdk.In
 
 	validator, newShares = k.AddValidatorTokensAndShares(ctx, validator, bondAmt)
 
+	// A top-up moves the bond height towards the current height in proportion
+	// to the shares added, so the new shares don't inherit the tenure of the
+	// existing ones
+	if totalShares := delegation.Shares.Add(newShares); totalShares.IsPositive() {
+		delegation.BondHeight = delegation.Shares.MulInt64(delegation.BondHeight).
+			Add(newShares.MulInt64(ctx.BlockHeight())).Quo(totalShares).RoundInt64()
+	}
+
 	// Update delegation
 	delegation.Shares = delegation.Shares.Add(newShares)
+	delegation.Height = ctx.BlockHeight()
 	k.SetDelegation(ctx, delegation)
 
 	// Call the after-modification hook
@@ -512,6 +1039,7 @@ func (k Keeper) unbond(ctx sdk.Context, delAddr sdk.AccAddress, valAddr sdk.ValA
 
 	// subtract shares from delegation
 	delegation.Shares = delegation.Shares.Sub(shares)
+	delegation.Height = ctx.BlockHeight()
 
 	isValidatorOperator := bytes.Equal(delegation.DelegatorAddress, validator.OperatorAddress)
 
@@ -555,7 +1083,7 @@ func (k Keeper) getBeginInfo(ctx sdk.Context, valSrcAddr sdk.ValAddress) (
 	case !found || validator.Status == sdk.Bonded:
 
 		// the longest wait - just unbonding period from now
-		completionTime = ctx.BlockHeader().Time.Add(k.UnbondingTime(ctx))
+		completionTime = ctx.BlockHeader().Time.Add(k.ValidatorUnbondingTime(ctx, validator))
 		height = ctx.BlockHeight()
 		return completionTime, height, false
 
@@ -597,17 +1125,57 @@ func (k Keeper) Undelegate(ctx sdk.Context, delAddr sdk.AccAddress,
 		return completionTime, nil
 	}
 
-	if k.HasMaxUnbondingDelegationEntries(ctx, delAddr, valAddr) {
+	// unbondings completing at the same time are merged into a single entry,
+	// which is already tracked in the unbonding queue
+	ubd, found := k.GetUnbondingDelegation(ctx, delAddr, valAddr)
+	merge := found && ubd.HasEntry(height, completionTime)
+
+	if !merge && k.HasMaxUnbondingDelegationEntries(ctx, delAddr, valAddr) {
 		return time.Time{}, types.ErrMaxUnbondingDelegationEntries(k.Codespace())
 	}
 
-	ubd := k.SetUnbondingDelegationEntry(ctx, delAddr,
+	ubd = k.SetUnbondingDelegationEntry(ctx, delAddr,
 		valAddr, height, completionTime, returnAmount)
+	k.SetPool(ctx, k.GetPool(ctx).NotBondedTokensToUnbonding(returnAmount))
 
-	k.InsertUBDQueue(ctx, ubd, completionTime)
+	if !merge {
+		k.InsertUBDQueue(ctx, ubd, completionTime)
+	}
 	return completionTime, nil
 }
 
+// ForceUndelegate removes a delegation entirely and returns its tokens to the
+// delegator immediately, without waiting for the unbonding time. It is meant
+// for testnets and test harnesses and fails unless the InstantUnbond parameter
+// is enabled.
+func (k Keeper) ForceUndelegate(ctx sdk.Context, delAddr sdk.AccAddress,
+	valAddr sdk.ValAddress) (amount sdk.Int, err sdk.Error) {
+
+	if !k.InstantUnbond(ctx) {
+		return amount, types.ErrInstantUnbondDisabled(k.Codespace())
+	}
+
+	delegation, found := k.GetDelegation(ctx, delAddr, valAddr)
+	if !found {
+		return amount, types.ErrNoDelegatorForAddress(k.Codespace())
+	}
+
+	amount, err = k.unbond(ctx, delAddr, valAddr, delegation.Shares)
+	if err != nil {
+		return amount, err
+	}
+
+	// track undelegation only when remaining or truncated shares are non-zero
+	if !amount.IsZero() {
+		coins := sdk.Coins{sdk.NewCoin(k.BondDenom(ctx), amount)}
+		if _, err := k.bankKeeper.UndelegateCoins(ctx, delAddr, coins); err != nil {
+			return amount, err
+		}
+	}
+
+	return amount, nil
+}
+
 // CompleteUnbonding completes the unbonding of all mature entries in the
 // retrieved unbonding delegation object.
 func (k Keeper) CompleteUnbonding(ctx sdk.Context, delAddr sdk.AccAddress,
@@ -626,10 +1194,11 @@ func (k Keeper) CompleteUnbonding(ctx sdk.Context, delAddr sdk.AccAddress,
 		if entry.IsMature(ctxTime) {
 			ubd.RemoveEntry(int64(i))
 			i--
+			k.SetPool(ctx, k.GetPool(ctx).UnbondingTokensToNotBonded(entry.Balance))
 
 			// track undelegation only when remaining or truncated shares are non-zero
 			if !entry.Balance.IsZero() {
-				_, err := k.bankKeeper.UndelegateCoins(ctx, ubd.DelegatorAddress, sdk.Coins{sdk.NewCoin(k.GetParams(ctx).BondDenom, entry.Balance)})
+				_, err := k.bankKeeper.UndelegateCoins(ctx, ubd.DelegatorAddress, sdk.Coins{sdk.NewCoin(k.BondDenom(ctx), entry.Balance)})
 				if err != nil {
 					return err
 				}
@@ -647,6 +1216,90 @@ func (k Keeper) CompleteUnbonding(ctx sdk.Context, delAddr sdk.AccAddress,
 	return nil
 }
 
+// CompleteAllUnbondings immediately matures every pending unbonding
+// delegation and redelegation, crediting the unbonding tokens to their
+// delegators and emptying both queues. It returns the completed pairs and
+// triplets, which are also kept for the rest of the block so the end blocker
+// can tag them. This is an emergency measure which must only be reachable
+// through governance.
+func (k Keeper) CompleteAllUnbondings(ctx sdk.Context) (
+	unbonds []types.DVPair, redelegations []types.DVVTriplet, err sdk.Error) {
+
+	store := ctx.KVStore(k.storeKey)
+	now := ctx.BlockHeader().Time
+
+	var ubds []types.UnbondingDelegation
+	k.IterateUnbondingDelegations(ctx, func(_ int64, ubd types.UnbondingDelegation) bool {
+		ubds = append(ubds, ubd)
+		return false
+	})
+	for _, ubd := range ubds {
+		for i := range ubd.Entries {
+			ubd.Entries[i].CompletionTime = now
+		}
+		k.SetUnbondingDelegation(ctx, ubd)
+		if err := k.CompleteUnbonding(ctx, ubd.DelegatorAddress, ubd.ValidatorAddress); err != nil {
+			return nil, nil, err
+		}
+		unbonds = append(unbonds, types.DVPair{DelegatorAddress: ubd.DelegatorAddress, ValidatorAddress: ubd.ValidatorAddress})
+	}
+
+	var reds []types.Redelegation
+	k.IterateRedelegations(ctx, func(_ int64, red types.Redelegation) bool {
+		reds = append(reds, red)
+		return false
+	})
+	for _, red := range reds {
+		for i := range red.Entries {
+			red.Entries[i].CompletionTime = now
+		}
+		k.SetRedelegation(ctx, red)
+		if err := k.CompleteRedelegation(ctx, red.DelegatorAddress, red.ValidatorSrcAddress, red.ValidatorDstAddress); err != nil {
+			return nil, nil, err
+		}
+		redelegations = append(redelegations, types.DVVTriplet{
+			DelegatorAddress:    red.DelegatorAddress,
+			ValidatorSrcAddress: red.ValidatorSrcAddress,
+			ValidatorDstAddress: red.ValidatorDstAddress,
+		})
+	}
+
+	// nothing is left to mature, clear the queues
+	for _, prefix := range [][]byte{UnbondingQueueKey, RedelegationQueueKey} {
+		iterator := sdk.KVStorePrefixIterator(store, prefix)
+		var keys [][]byte
+		for ; iterator.Valid(); iterator.Next() {
+			keys = append(keys, iterator.Key())
+		}
+		iterator.Close()
+		for _, key := range keys {
+			store.Delete(key)
+		}
+	}
+
+	completedUnbonds, completedRedelegations := k.GetUnbondingsCompletedInBlock(ctx)
+	tstore := ctx.TransientStore(k.storeTKey)
+	tstore.Set(CompletedUnbondingsKey, k.cdc.MustMarshalBinaryLengthPrefixed(append(completedUnbonds, unbonds...)))
+	tstore.Set(CompletedRedelegationsKey, k.cdc.MustMarshalBinaryLengthPrefixed(append(completedRedelegations, redelegations...)))
+
+	return unbonds, redelegations, nil
+}
+
+// GetUnbondingsCompletedInBlock returns the unbonding delegations and
+// redelegations completed by CompleteAllUnbondings during the current block
+func (k Keeper) GetUnbondingsCompletedInBlock(ctx sdk.Context) (
+	unbonds []types.DVPair, redelegations []types.DVVTriplet) {
+
+	tstore := ctx.TransientStore(k.storeTKey)
+	if bz := tstore.Get(CompletedUnbondingsKey); bz != nil {
+		k.cdc.MustUnmarshalBinaryLengthPrefixed(bz, &unbonds)
+	}
+	if bz := tstore.Get(CompletedRedelegationsKey); bz != nil {
+		k.cdc.MustUnmarshalBinaryLengthPrefixed(bz, &redelegations)
+	}
+	return unbonds, redelegations
+}
+
 // begin unbonding / redelegation; create a redelegation record
 func (k Keeper) BeginRedelegation(ctx sdk.Context, delAddr sdk.AccAddress,
 	valSrcAddr, valDstAddr sdk.ValAddress, sharesAmount sdk.Dec) (
@@ -1,6 +1,7 @@
 package keeper
 
 import (
+	"bytes"
 	"testing"
 	"time"
 
@@ -21,7 +22,7 @@ func TestDelegation(t *testing.T) {
 	var validators [3]types.Validator
 	for i, amt := range amts {
 		validators[i] = types.NewValidator(addrVals[i], PKs[i], types.Description{})
-		validators[i], pool, _ = validators[i].AddTokensFromDel(pool, amt)
+		validators[i], pool, _ = validators[i].AddTokensFromDel(pool, amt, types.ShareRoundDown)
 	}
 
 	keeper.SetPool(ctx, pool)
@@ -128,6 +129,37 @@ func TestDelegation(t *testing.T) {
 	require.Equal(t, 0, len(resBonds))
 }
 
+func TestTotalDelegationCount(t *testing.T) {
+	ctx, _, keeper := CreateTestInput(t, false, 10)
+	require.Equal(t, 0, keeper.GetTotalDelegationCount(ctx))
+
+	var delegations []types.Delegation
+	for i := 0; i < 3; i++ {
+		delegation := types.NewDelegation(addrDels[i%2], addrVals[i], sdk.NewDec(int64(i+1)))
+		keeper.SetDelegation(ctx, delegation)
+		delegations = append(delegations, delegation)
+		require.Equal(t, i+1, keeper.GetTotalDelegationCount(ctx))
+	}
+
+	// updating an existing delegation does not change the count
+	delegations[0].Shares = sdk.NewDec(10)
+	keeper.SetDelegation(ctx, delegations[0])
+	require.Equal(t, 3, keeper.GetTotalDelegationCount(ctx))
+
+	keeper.RemoveDelegation(ctx, delegations[1])
+	require.Equal(t, 2, keeper.GetTotalDelegationCount(ctx))
+	require.Len(t, keeper.GetAllDelegations(ctx), 2)
+
+	// removing a missing delegation does not change the count
+	keeper.RemoveDelegation(ctx, delegations[1])
+	require.Equal(t, 2, keeper.GetTotalDelegationCount(ctx))
+
+	for _, delegation := range []types.Delegation{delegations[0], delegations[2]} {
+		keeper.RemoveDelegation(ctx, delegation)
+	}
+	require.Equal(t, 0, keeper.GetTotalDelegationCount(ctx))
+}
+
 // tests Get/Set/Remove UnbondingDelegation
 func TestUnbondingDelegation(t *testing.T) {
 	ctx, _, keeper := CreateTestInput(t, false, 0)
@@ -176,7 +208,7 @@ func TestUnbondDelegation(t *testing.T) {
 
 	//create a validator and a delegator to that validator
 	validator := types.NewValidator(addrVals[0], PKs[0], types.Description{})
-	validator, pool, issuedShares := validator.AddTokensFromDel(pool, startTokens)
+	validator, pool, issuedShares := validator.AddTokensFromDel(pool, startTokens, types.ShareRoundDown)
 	require.Equal(t, startTokens, issuedShares.RoundInt())
 	keeper.SetPool(ctx, pool)
 	validator = TestingUpdateValidator(keeper, ctx, validator, true)
@@ -206,6 +238,34 @@ func TestUnbondDelegation(t *testing.T) {
 	require.Equal(t, remainingTokens, pool.BondedTokens)
 }
 
+func TestUndelegateUnbondingTokens(t *testing.T) {
+	ctx, _, keeper := CreateTestInput(t, false, 1000)
+	validator := types.NewValidator(addrVals[0], PKs[0], types.Description{})
+	keeper.SetValidator(ctx, validator)
+	keeper.SetNewValidatorByPowerIndex(ctx, validator)
+	_, err := keeper.Delegate(ctx, addrDels[0], sdk.TokensFromTendermintPower(10), validator, true)
+	require.Nil(t, err)
+	keeper.ApplyAndReturnValidatorSetUpdates(ctx)
+	oldPool := keeper.GetPool(ctx)
+	require.True(t, oldPool.UnbondingTokens.IsZero())
+
+	// the undelegated tokens are held in the unbonding bucket
+	unbondTokens := sdk.TokensFromTendermintPower(4)
+	completionTime, err := keeper.Undelegate(ctx, addrDels[0], addrVals[0], unbondTokens.ToDec())
+	require.Nil(t, err)
+	pool := keeper.GetPool(ctx)
+	require.Equal(t, unbondTokens, pool.UnbondingTokens)
+	require.Equal(t, oldPool.NotBondedTokens, pool.NotBondedTokens)
+	require.Equal(t, oldPool.TokenSupply(), pool.TokenSupply())
+
+	// and are released to the delegator's coins on completion
+	ctx = ctx.WithBlockTime(completionTime)
+	require.Nil(t, keeper.CompleteUnbonding(ctx, addrDels[0], addrVals[0]))
+	pool = keeper.GetPool(ctx)
+	require.True(t, pool.UnbondingTokens.IsZero())
+	require.Equal(t, oldPool.NotBondedTokens.Add(unbondTokens), pool.NotBondedTokens)
+}
+
 func TestUnbondingDelegationsMaxEntries(t *testing.T) {
 	ctx, _, keeper := CreateTestInput(t, false, 1)
 	pool := keeper.GetPool(ctx)
@@ -214,7 +274,7 @@ func TestUnbondingDelegationsMaxEntries(t *testing.T) {
 
 	// create a validator and a delegator to that validator
 	validator := types.NewValidator(addrVals[0], PKs[0], types.Description{})
-	validator, pool, issuedShares := validator.AddTokensFromDel(pool, startTokens)
+	validator, pool, issuedShares := validator.AddTokensFromDel(pool, startTokens, types.ShareRoundDown)
 	require.Equal(t, startTokens, issuedShares.RoundInt())
 	keeper.SetPool(ctx, pool)
 	validator = TestingUpdateValidator(keeper, ctx, validator, true)
@@ -228,16 +288,24 @@ func TestUnbondingDelegationsMaxEntries(t *testing.T) {
 
 	maxEntries := keeper.MaxEntries(ctx)
 
-	// should all pass
+	// should all pass, each block creates a new entry
 	var completionTime time.Time
 	for i := uint16(0); i < maxEntries; i++ {
 		var err error
+		ctx = ctx.WithBlockHeight(ctx.BlockHeight() + 1)
+		ctx = ctx.WithBlockTime(ctx.BlockHeader().Time.Add(time.Second))
 		completionTime, err = keeper.Undelegate(ctx, addrDels[0], addrVals[0], sdk.NewDec(1))
 		require.NoError(t, err)
 	}
 
-	// an additional unbond should fail due to max entries
+	// an additional unbond in the same block is merged into the last entry
 	_, err := keeper.Undelegate(ctx, addrDels[0], addrVals[0], sdk.NewDec(1))
+	require.NoError(t, err)
+
+	// an additional unbond in a new block should fail due to max entries
+	ctx = ctx.WithBlockHeight(ctx.BlockHeight() + 1)
+	ctx = ctx.WithBlockTime(ctx.BlockHeader().Time.Add(time.Second))
+	_, err = keeper.Undelegate(ctx, addrDels[0], addrVals[0], sdk.NewDec(1))
 	require.Error(t, err)
 
 	// mature unbonding delegations
@@ -250,6 +318,57 @@ func TestUnbondingDelegationsMaxEntries(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestForceUndelegate(t *testing.T) {
+	ctx, ak, keeper := CreateTestInput(t, false, 100)
+	pool := keeper.GetPool(ctx)
+
+	// create a bonded validator
+	validator := types.NewValidator(addrVals[0], PKs[0], types.Description{})
+	valTokens := sdk.TokensFromTendermintPower(10)
+	validator, pool, _ = validator.AddTokensFromDel(pool, valTokens, types.ShareRoundDown)
+	keeper.SetPool(ctx, pool)
+	validator = TestingUpdateValidator(keeper, ctx, validator, true)
+
+	// delegate to it from a funded account
+	delTokens := sdk.TokensFromTendermintPower(20)
+	startCoins := ak.GetAccount(ctx, addrDels[0]).GetCoins()
+	_, err := keeper.Delegate(ctx, addrDels[0], delTokens, validator, true)
+	require.NoError(t, err)
+	bondedCoins := ak.GetAccount(ctx, addrDels[0]).GetCoins()
+	require.Equal(t, delTokens, startCoins.AmountOf(keeper.BondDenom(ctx)).Sub(bondedCoins.AmountOf(keeper.BondDenom(ctx))))
+
+	// instant unbonding is disabled by default
+	require.False(t, keeper.GetParams(ctx).InstantUnbond)
+	_, err = keeper.ForceUndelegate(ctx, addrDels[0], addrVals[0])
+	require.Error(t, err)
+
+	params := keeper.GetParams(ctx)
+	params.InstantUnbond = true
+	keeper.SetParams(ctx, params)
+
+	// the tokens are liquid immediately, without any unbonding entry
+	pool = keeper.GetPool(ctx)
+	amount, err := keeper.ForceUndelegate(ctx, addrDels[0], addrVals[0])
+	require.NoError(t, err)
+	require.Equal(t, delTokens, amount)
+	require.Equal(t, startCoins, ak.GetAccount(ctx, addrDels[0]).GetCoins())
+
+	_, found := keeper.GetDelegation(ctx, addrDels[0], addrVals[0])
+	require.False(t, found)
+	_, found = keeper.GetUnbondingDelegation(ctx, addrDels[0], addrVals[0])
+	require.False(t, found)
+
+	validator, found = keeper.GetValidator(ctx, addrVals[0])
+	require.True(t, found)
+	require.Equal(t, valTokens, validator.Tokens)
+	require.Equal(t, pool.BondedTokens.Sub(delTokens), keeper.GetPool(ctx).BondedTokens)
+	require.Equal(t, pool.NotBondedTokens.Add(delTokens), keeper.GetPool(ctx).NotBondedTokens)
+
+	// there is nothing left to undelegate
+	_, err = keeper.ForceUndelegate(ctx, addrDels[0], addrVals[0])
+	require.Error(t, err)
+}
+
 // test undelegating self delegation from a validator pushing it below MinSelfDelegation
 // shift it from the bonded to unbonding state and jailed
 func TestUndelegateSelfDelegationBelowMinSelfDelegation(t *testing.T) {
@@ -264,7 +383,7 @@ func TestUndelegateSelfDelegationBelowMinSelfDelegation(t *testing.T) {
 
 	valTokens := sdk.TokensFromTendermintPower(10)
 	validator.MinSelfDelegation = valTokens
-	validator, pool, issuedShares := validator.AddTokensFromDel(pool, valTokens)
+	validator, pool, issuedShares := validator.AddTokensFromDel(pool, valTokens, types.ShareRoundDown)
 	require.Equal(t, valTokens, issuedShares.RoundInt())
 
 	keeper.SetPool(ctx, pool)
@@ -276,7 +395,7 @@ func TestUndelegateSelfDelegationBelowMinSelfDelegation(t *testing.T) {
 	// create a second delegation to this validator
 	keeper.DeleteValidatorByPowerIndex(ctx, validator)
 	delTokens := sdk.TokensFromTendermintPower(10)
-	validator, pool, issuedShares = validator.AddTokensFromDel(pool, delTokens)
+	validator, pool, issuedShares = validator.AddTokensFromDel(pool, delTokens, types.ShareRoundDown)
 	require.Equal(t, delTokens, issuedShares.RoundInt())
 	keeper.SetPool(ctx, pool)
 	validator = TestingUpdateValidator(keeper, ctx, validator, true)
@@ -309,7 +428,7 @@ func TestUndelegateFromUnbondingValidator(t *testing.T) {
 	validator := types.NewValidator(addrVals[0], PKs[0], types.Description{})
 
 	valTokens := sdk.TokensFromTendermintPower(10)
-	validator, pool, issuedShares := validator.AddTokensFromDel(pool, valTokens)
+	validator, pool, issuedShares := validator.AddTokensFromDel(pool, valTokens, types.ShareRoundDown)
 	require.Equal(t, valTokens, issuedShares.RoundInt())
 	keeper.SetPool(ctx, pool)
 	validator = TestingUpdateValidator(keeper, ctx, validator, true)
@@ -320,7 +439,7 @@ func TestUndelegateFromUnbondingValidator(t *testing.T) {
 	// create a second delegation to this validator
 	keeper.DeleteValidatorByPowerIndex(ctx, validator)
 	delTokens := sdk.TokensFromTendermintPower(10)
-	validator, pool, issuedShares = validator.AddTokensFromDel(pool, delTokens)
+	validator, pool, issuedShares = validator.AddTokensFromDel(pool, delTokens, types.ShareRoundDown)
 	require.Equal(t, delTokens, issuedShares.RoundInt())
 	keeper.SetPool(ctx, pool)
 	validator = TestingUpdateValidator(keeper, ctx, validator, true)
@@ -381,7 +500,7 @@ func TestUndelegateFromUnbondedValidator(t *testing.T) {
 	validator := types.NewValidator(addrVals[0], PKs[0], types.Description{})
 
 	valTokens := sdk.TokensFromTendermintPower(10)
-	validator, pool, issuedShares := validator.AddTokensFromDel(pool, valTokens)
+	validator, pool, issuedShares := validator.AddTokensFromDel(pool, valTokens, types.ShareRoundDown)
 	require.Equal(t, valTokens, issuedShares.RoundInt())
 	keeper.SetPool(ctx, pool)
 	validator = TestingUpdateValidator(keeper, ctx, validator, true)
@@ -393,7 +512,7 @@ func TestUndelegateFromUnbondedValidator(t *testing.T) {
 	// create a second delegation to this validator
 	keeper.DeleteValidatorByPowerIndex(ctx, validator)
 	delTokens := sdk.TokensFromTendermintPower(10)
-	validator, pool, issuedShares = validator.AddTokensFromDel(pool, delTokens)
+	validator, pool, issuedShares = validator.AddTokensFromDel(pool, delTokens, types.ShareRoundDown)
 	require.Equal(t, delTokens, issuedShares.RoundInt())
 	keeper.SetPool(ctx, pool)
 	validator = TestingUpdateValidator(keeper, ctx, validator, true)
@@ -456,7 +575,7 @@ func TestUnbondingAllDelegationFromValidator(t *testing.T) {
 	validator := types.NewValidator(addrVals[0], PKs[0], types.Description{})
 
 	valTokens := sdk.TokensFromTendermintPower(10)
-	validator, pool, issuedShares := validator.AddTokensFromDel(pool, valTokens)
+	validator, pool, issuedShares := validator.AddTokensFromDel(pool, valTokens, types.ShareRoundDown)
 	require.Equal(t, valTokens, issuedShares.RoundInt())
 	keeper.SetPool(ctx, pool)
 	validator = TestingUpdateValidator(keeper, ctx, validator, true)
@@ -468,7 +587,7 @@ func TestUnbondingAllDelegationFromValidator(t *testing.T) {
 	// create a second delegation to this validator
 	keeper.DeleteValidatorByPowerIndex(ctx, validator)
 	delTokens := sdk.TokensFromTendermintPower(10)
-	validator, pool, issuedShares = validator.AddTokensFromDel(pool, delTokens)
+	validator, pool, issuedShares = validator.AddTokensFromDel(pool, delTokens, types.ShareRoundDown)
 	require.Equal(t, delTokens, issuedShares.RoundInt())
 	keeper.SetPool(ctx, pool)
 	validator = TestingUpdateValidator(keeper, ctx, validator, true)
@@ -599,7 +718,7 @@ func TestRedelegateToSameValidator(t *testing.T) {
 	// create a validator with a self-delegation
 	validator := types.NewValidator(addrVals[0], PKs[0], types.Description{})
 	valTokens := sdk.TokensFromTendermintPower(10)
-	validator, pool, issuedShares := validator.AddTokensFromDel(pool, valTokens)
+	validator, pool, issuedShares := validator.AddTokensFromDel(pool, valTokens, types.ShareRoundDown)
 	require.Equal(t, valTokens, issuedShares.RoundInt())
 	keeper.SetPool(ctx, pool)
 	validator = TestingUpdateValidator(keeper, ctx, validator, true)
@@ -622,7 +741,7 @@ func TestRedelegationMaxEntries(t *testing.T) {
 	// create a validator with a self-delegation
 	validator := types.NewValidator(addrVals[0], PKs[0], types.Description{})
 	valTokens := sdk.TokensFromTendermintPower(10)
-	validator, pool, issuedShares := validator.AddTokensFromDel(pool, valTokens)
+	validator, pool, issuedShares := validator.AddTokensFromDel(pool, valTokens, types.ShareRoundDown)
 	require.Equal(t, valTokens, issuedShares.RoundInt())
 	keeper.SetPool(ctx, pool)
 	validator = TestingUpdateValidator(keeper, ctx, validator, true)
@@ -633,7 +752,7 @@ func TestRedelegationMaxEntries(t *testing.T) {
 
 	// create a second validator
 	validator2 := types.NewValidator(addrVals[1], PKs[1], types.Description{})
-	validator2, pool, issuedShares = validator2.AddTokensFromDel(pool, valTokens)
+	validator2, pool, issuedShares = validator2.AddTokensFromDel(pool, valTokens, types.ShareRoundDown)
 	require.Equal(t, valTokens, issuedShares.RoundInt())
 	pool.BondedTokens = pool.BondedTokens.Add(valTokens)
 	keeper.SetPool(ctx, pool)
@@ -673,7 +792,7 @@ func TestRedelegateSelfDelegation(t *testing.T) {
 	//create a validator with a self-delegation
 	validator := types.NewValidator(addrVals[0], PKs[0], types.Description{})
 	valTokens := sdk.TokensFromTendermintPower(10)
-	validator, pool, issuedShares := validator.AddTokensFromDel(pool, valTokens)
+	validator, pool, issuedShares := validator.AddTokensFromDel(pool, valTokens, types.ShareRoundDown)
 	require.Equal(t, valTokens, issuedShares.RoundInt())
 	keeper.SetPool(ctx, pool)
 	validator = TestingUpdateValidator(keeper, ctx, validator, true)
@@ -684,7 +803,7 @@ func TestRedelegateSelfDelegation(t *testing.T) {
 
 	// create a second validator
 	validator2 := types.NewValidator(addrVals[1], PKs[1], types.Description{})
-	validator2, pool, issuedShares = validator2.AddTokensFromDel(pool, valTokens)
+	validator2, pool, issuedShares = validator2.AddTokensFromDel(pool, valTokens, types.ShareRoundDown)
 	require.Equal(t, valTokens, issuedShares.RoundInt())
 	pool.BondedTokens = pool.BondedTokens.Add(valTokens)
 	keeper.SetPool(ctx, pool)
@@ -693,7 +812,7 @@ func TestRedelegateSelfDelegation(t *testing.T) {
 
 	// create a second delegation to validator 1
 	delTokens := sdk.TokensFromTendermintPower(10)
-	validator, pool, issuedShares = validator.AddTokensFromDel(pool, delTokens)
+	validator, pool, issuedShares = validator.AddTokensFromDel(pool, delTokens, types.ShareRoundDown)
 	require.Equal(t, delTokens, issuedShares.RoundInt())
 	keeper.SetPool(ctx, pool)
 	validator = TestingUpdateValidator(keeper, ctx, validator, true)
@@ -725,7 +844,7 @@ func TestRedelegateFromUnbondingValidator(t *testing.T) {
 	validator := types.NewValidator(addrVals[0], PKs[0], types.Description{})
 
 	valTokens := sdk.TokensFromTendermintPower(10)
-	validator, pool, issuedShares := validator.AddTokensFromDel(pool, valTokens)
+	validator, pool, issuedShares := validator.AddTokensFromDel(pool, valTokens, types.ShareRoundDown)
 	require.Equal(t, valTokens, issuedShares.RoundInt())
 	keeper.SetPool(ctx, pool)
 	validator = TestingUpdateValidator(keeper, ctx, validator, true)
@@ -737,7 +856,7 @@ func TestRedelegateFromUnbondingValidator(t *testing.T) {
 	// create a second delegation to this validator
 	keeper.DeleteValidatorByPowerIndex(ctx, validator)
 	delTokens := sdk.TokensFromTendermintPower(10)
-	validator, pool, issuedShares = validator.AddTokensFromDel(pool, delTokens)
+	validator, pool, issuedShares = validator.AddTokensFromDel(pool, delTokens, types.ShareRoundDown)
 	require.Equal(t, delTokens, issuedShares.RoundInt())
 	keeper.SetPool(ctx, pool)
 	validator = TestingUpdateValidator(keeper, ctx, validator, true)
@@ -747,7 +866,7 @@ func TestRedelegateFromUnbondingValidator(t *testing.T) {
 
 	// create a second validator
 	validator2 := types.NewValidator(addrVals[1], PKs[1], types.Description{})
-	validator2, pool, issuedShares = validator2.AddTokensFromDel(pool, valTokens)
+	validator2, pool, issuedShares = validator2.AddTokensFromDel(pool, valTokens, types.ShareRoundDown)
 	require.Equal(t, valTokens, issuedShares.RoundInt())
 	keeper.SetPool(ctx, pool)
 	validator2 = TestingUpdateValidator(keeper, ctx, validator2, true)
@@ -804,7 +923,7 @@ func TestRedelegateFromUnbondedValidator(t *testing.T) {
 	validator := types.NewValidator(addrVals[0], PKs[0], types.Description{})
 
 	valTokens := sdk.TokensFromTendermintPower(10)
-	validator, pool, issuedShares := validator.AddTokensFromDel(pool, valTokens)
+	validator, pool, issuedShares := validator.AddTokensFromDel(pool, valTokens, types.ShareRoundDown)
 	require.Equal(t, valTokens, issuedShares.RoundInt())
 	keeper.SetPool(ctx, pool)
 	validator = TestingUpdateValidator(keeper, ctx, validator, true)
@@ -816,7 +935,7 @@ func TestRedelegateFromUnbondedValidator(t *testing.T) {
 	// create a second delegation to this validator
 	keeper.DeleteValidatorByPowerIndex(ctx, validator)
 	delTokens := sdk.TokensFromTendermintPower(10)
-	validator, pool, issuedShares = validator.AddTokensFromDel(pool, delTokens)
+	validator, pool, issuedShares = validator.AddTokensFromDel(pool, delTokens, types.ShareRoundDown)
 	require.Equal(t, delTokens, issuedShares.RoundInt())
 	keeper.SetPool(ctx, pool)
 	validator = TestingUpdateValidator(keeper, ctx, validator, true)
@@ -826,7 +945,7 @@ func TestRedelegateFromUnbondedValidator(t *testing.T) {
 
 	// create a second validator
 	validator2 := types.NewValidator(addrVals[1], PKs[1], types.Description{})
-	validator2, pool, issuedShares = validator2.AddTokensFromDel(pool, valTokens)
+	validator2, pool, issuedShares = validator2.AddTokensFromDel(pool, valTokens, types.ShareRoundDown)
 	require.Equal(t, valTokens, issuedShares.RoundInt())
 	keeper.SetPool(ctx, pool)
 	validator2 = TestingUpdateValidator(keeper, ctx, validator2, true)
@@ -861,3 +980,381 @@ func TestRedelegateFromUnbondedValidator(t *testing.T) {
 	red, found := keeper.GetRedelegation(ctx, addrDels[0], addrVals[0], addrVals[1])
 	require.False(t, found, "%v", red)
 }
+
+func TestGetDelegationSharePercentage(t *testing.T) {
+	ctx, _, keeper := CreateTestInput(t, false, 1000)
+	valAddr := addrVals[0]
+	validator := types.NewValidator(valAddr, PKs[0], types.Description{})
+	keeper.SetValidator(ctx, validator)
+	keeper.SetNewValidatorByPowerIndex(ctx, validator)
+
+	_, found := keeper.GetDelegationSharePercentage(ctx, addrDels[0], valAddr)
+	require.False(t, found)
+
+	// three equal delegators each hold a third of the shares
+	delAddrs := []sdk.AccAddress{addrDels[0], addrDels[1], Addrs[7]}
+	for _, delAddr := range delAddrs {
+		validator = keeper.mustGetValidator(ctx, valAddr)
+		_, err := keeper.Delegate(ctx, delAddr, sdk.TokensFromTendermintPower(10), validator, true)
+		require.Nil(t, err)
+	}
+
+	oneThird := sdk.OneDec().QuoInt64(3)
+	for _, delAddr := range delAddrs {
+		share, found := keeper.GetDelegationSharePercentage(ctx, delAddr, valAddr)
+		require.True(t, found)
+		require.True(t, oneThird.Equal(share), "expected %v, got %v", oneThird, share)
+	}
+}
+
+func TestGetValidatorDelegationConcentration(t *testing.T) {
+	ctx, _, keeper := CreateTestInput(t, false, 1000)
+	valAddr := addrVals[0]
+	validator := types.NewValidator(valAddr, PKs[0], types.Description{})
+	keeper.SetValidator(ctx, validator)
+	keeper.SetNewValidatorByPowerIndex(ctx, validator)
+
+	_, found := keeper.GetValidatorDelegationConcentration(ctx, valAddr)
+	require.False(t, found)
+
+	// one dominant delegator holds 60 of the 100 tokens delegated
+	powers := []int64{60, 25, 15}
+	delAddrs := []sdk.AccAddress{addrDels[0], addrDels[1], Addrs[7]}
+	for i, delAddr := range delAddrs {
+		validator = keeper.mustGetValidator(ctx, valAddr)
+		_, err := keeper.Delegate(ctx, delAddr, sdk.TokensFromTendermintPower(powers[i]), validator, true)
+		require.Nil(t, err)
+	}
+
+	concentration, found := keeper.GetValidatorDelegationConcentration(ctx, valAddr)
+	require.True(t, found)
+	require.True(t, sdk.NewDecWithPrec(6, 1).Equal(concentration), "expected 0.6, got %v", concentration)
+}
+
+func TestGetValidatorLiquidationValue(t *testing.T) {
+	ctx, _, keeper := CreateTestInput(t, false, 1000)
+	valAddr := addrVals[0]
+	validator := types.NewValidator(valAddr, PKs[0], types.Description{})
+	keeper.SetValidator(ctx, validator)
+	keeper.SetNewValidatorByPowerIndex(ctx, validator)
+	keeper.SetValidatorByConsAddr(ctx, validator)
+
+	require.True(t, keeper.GetValidatorLiquidationValue(ctx, addrVals[1]).IsZero())
+	require.True(t, keeper.GetValidatorLiquidationValue(ctx, valAddr).IsZero())
+
+	powers := []int64{60, 25, 15}
+	delAddrs := []sdk.AccAddress{addrDels[0], addrDels[1], Addrs[7]}
+	for i, delAddr := range delAddrs {
+		validator = keeper.mustGetValidator(ctx, valAddr)
+		_, err := keeper.Delegate(ctx, delAddr, sdk.TokensFromTendermintPower(powers[i]), validator, true)
+		require.Nil(t, err)
+	}
+	keeper.ApplyAndReturnValidatorSetUpdates(ctx)
+
+	validator = keeper.mustGetValidator(ctx, valAddr)
+	require.Equal(t, sdk.Bonded, validator.Status)
+	require.Equal(t, validator.Tokens, keeper.GetValidatorLiquidationValue(ctx, valAddr))
+
+	// an uneven slash leaves a fractional exchange rate, the delegations
+	// still share all the remaining tokens
+	consAddr := validator.ConsAddress()
+	keeper.Slash(ctx, consAddr, 0, 10, sdk.NewDecWithPrec(1, 3))
+	validator = keeper.mustGetValidator(ctx, valAddr)
+	require.False(t, validator.Tokens.ToDec().Equal(validator.DelegatorShares))
+	require.Equal(t, validator.Tokens, keeper.GetValidatorLiquidationValue(ctx, valAddr))
+
+	// values beyond the int64 range are returned as is
+	large := types.NewValidator(addrVals[1], PKs[1], types.Description{})
+	large.Tokens, _ = sdk.NewIntFromString("20000000000000000000")
+	large.DelegatorShares = large.Tokens.ToDec()
+	keeper.SetValidator(ctx, large)
+	keeper.SetDelegation(ctx, types.NewDelegation(addrDels[0], large.OperatorAddress, large.DelegatorShares))
+	require.Equal(t, large.Tokens, keeper.GetValidatorLiquidationValue(ctx, large.OperatorAddress))
+}
+
+func TestSnapshotValidatorDelegations(t *testing.T) {
+	ctx, _, keeper := CreateTestInput(t, false, 1000)
+	valAddr := addrVals[0]
+	validator := types.NewValidator(valAddr, PKs[0], types.Description{})
+	keeper.SetValidator(ctx, validator)
+	keeper.SetNewValidatorByPowerIndex(ctx, validator)
+
+	require.Empty(t, keeper.SnapshotValidatorDelegations(ctx, valAddr))
+
+	delAddrs := []sdk.AccAddress{addrDels[1], addrDels[0], Addrs[7]}
+	powers := []int64{30, 10, 20}
+	for i, delAddr := range delAddrs {
+		validator = keeper.mustGetValidator(ctx, valAddr)
+		_, err := keeper.Delegate(ctx, delAddr, sdk.TokensFromTendermintPower(powers[i]), validator, true)
+		require.Nil(t, err)
+	}
+
+	// halve the exchange rate
+	validator = keeper.mustGetValidator(ctx, valAddr)
+	keeper.RemoveValidatorTokens(ctx, validator, sdk.TokensFromTendermintPower(30))
+
+	snapshot := keeper.SnapshotValidatorDelegations(ctx, valAddr)
+	require.Len(t, snapshot, len(delAddrs))
+	for i, entry := range snapshot {
+		if i > 0 {
+			require.True(t, bytes.Compare(snapshot[i-1].DelegatorAddress, entry.DelegatorAddress) < 0)
+		}
+		for j, delAddr := range delAddrs {
+			if !delAddr.Equals(entry.DelegatorAddress) {
+				continue
+			}
+			shares := sdk.TokensFromTendermintPower(powers[j])
+			require.True(t, shares.ToDec().Equal(entry.Shares), "expected %v, got %v", shares, entry.Shares)
+			tokens := shares.ToDec().QuoInt64(2)
+			require.True(t, tokens.Equal(entry.Tokens), "expected %v, got %v", tokens, entry.Tokens)
+		}
+	}
+}
+
+func TestUndelegateWithValidatorUnbondingTime(t *testing.T) {
+	ctx, _, keeper := CreateTestInput(t, false, 0)
+	pool := keeper.GetPool(ctx)
+	startTokens := sdk.TokensFromTendermintPower(20)
+	pool.NotBondedTokens = startTokens
+
+	// create two bonded validators with a delegation each
+	for i := 0; i < 2; i++ {
+		validator := types.NewValidator(addrVals[i], PKs[i], types.Description{})
+		validator, pool, issuedShares := validator.AddTokensFromDel(pool, sdk.TokensFromTendermintPower(10), types.ShareRoundDown)
+		keeper.SetPool(ctx, pool)
+		TestingUpdateValidator(keeper, ctx, validator, true)
+		keeper.SetDelegation(ctx, types.NewDelegation(addrDels[0], addrVals[i], issuedShares))
+		pool = keeper.GetPool(ctx)
+	}
+
+	blockTime := time.Unix(333, 0)
+	header := ctx.BlockHeader()
+	header.Time = blockTime
+	ctx = ctx.WithBlockHeader(header)
+	unbondingTime := keeper.UnbondingTime(ctx)
+
+	// an override shorter than the param is rejected
+	err := keeper.SetValidatorUnbondingTime(ctx, addrVals[0], unbondingTime-time.Second)
+	require.NotNil(t, err)
+	err = keeper.SetValidatorUnbondingTime(ctx, addrVals[2], unbondingTime)
+	require.NotNil(t, err)
+
+	override := unbondingTime + time.Hour
+	require.Nil(t, keeper.SetValidatorUnbondingTime(ctx, addrVals[0], override))
+
+	// the validator with the override completes later
+	completionTime, sdkErr := keeper.Undelegate(ctx, addrDels[0], addrVals[0], sdk.NewDec(6))
+	require.Nil(t, sdkErr)
+	require.True(t, blockTime.Add(override).Equal(completionTime))
+
+	// the validator without an override uses the param
+	completionTime, sdkErr = keeper.Undelegate(ctx, addrDels[0], addrVals[1], sdk.NewDec(6))
+	require.Nil(t, sdkErr)
+	require.True(t, blockTime.Add(unbondingTime).Equal(completionTime))
+}
+
+func TestGetTopDelegators(t *testing.T) {
+	ctx, _, keeper := CreateTestInput(t, false, 1000)
+	for i := 0; i < 2; i++ {
+		validator := types.NewValidator(addrVals[i], PKs[i], types.Description{})
+		keeper.SetValidator(ctx, validator)
+		keeper.SetNewValidatorByPowerIndex(ctx, validator)
+	}
+
+	// delegator totals: addrDels[0] 25, addrDels[1] 40, Addrs[7] 30, Addrs[8] 5
+	delegations := []struct {
+		delAddr sdk.AccAddress
+		valAddr sdk.ValAddress
+		power   int64
+	}{
+		{addrDels[0], addrVals[0], 10},
+		{addrDels[0], addrVals[1], 15},
+		{addrDels[1], addrVals[1], 40},
+		{Addrs[7], addrVals[0], 20},
+		{Addrs[7], addrVals[1], 10},
+		{Addrs[8], addrVals[0], 5},
+	}
+	for _, d := range delegations {
+		validator := keeper.mustGetValidator(ctx, d.valAddr)
+		_, err := keeper.Delegate(ctx, d.delAddr, sdk.TokensFromTendermintPower(d.power), validator, true)
+		require.Nil(t, err)
+	}
+
+	// delegations to unbonded validators are not counted
+	require.Empty(t, keeper.GetTopDelegators(ctx, 3))
+
+	keeper.ApplyAndReturnValidatorSetUpdates(ctx)
+	require.Empty(t, keeper.GetTopDelegators(ctx, 0))
+
+	top := keeper.GetTopDelegators(ctx, 3)
+	expected := []sdk.AccAddress{addrDels[1], Addrs[7], addrDels[0]}
+	expectedPowers := []int64{40, 30, 25}
+	require.Len(t, top, len(expected))
+	for i, stake := range top {
+		require.True(t, expected[i].Equals(stake.DelegatorAddress), "position %d: got %v", i, stake.DelegatorAddress)
+		tokens := sdk.TokensFromTendermintPower(expectedPowers[i]).ToDec()
+		require.True(t, tokens.Equal(stake.Tokens), "expected %v, got %v", tokens, stake.Tokens)
+	}
+
+	require.Len(t, keeper.GetTopDelegators(ctx, 10), 4)
+}
+
+func TestGetUnbondingQueueSize(t *testing.T) {
+	ctx, _, keeper := CreateTestInput(t, false, 0)
+	baseTime := time.Unix(1000, 0)
+	require.Zero(t, keeper.GetUnbondingQueueSize(ctx, baseTime.Add(time.Hour)))
+
+	// entries maturing at 1, 2 and 3 minutes, two of them in the same timeslice
+	offsets := []time.Duration{time.Minute, 2 * time.Minute, 2 * time.Minute, 3 * time.Minute}
+	for i, offset := range offsets {
+		completionTime := baseTime.Add(offset)
+		ubd := types.NewUnbondingDelegation(addrDels[0], addrVals[i], 0, completionTime, sdk.NewInt(5))
+		keeper.InsertUBDQueue(ctx, ubd, completionTime)
+	}
+
+	require.Zero(t, keeper.GetUnbondingQueueSize(ctx, baseTime))
+	require.Equal(t, 1, keeper.GetUnbondingQueueSize(ctx, baseTime.Add(time.Minute)))
+	require.Equal(t, 3, keeper.GetUnbondingQueueSize(ctx, baseTime.Add(150*time.Second)))
+	require.Equal(t, 4, keeper.GetUnbondingQueueSize(ctx, baseTime.Add(time.Hour)))
+
+	// counting leaves the queue untouched
+	require.Equal(t, 4, keeper.GetUnbondingQueueSize(ctx, baseTime.Add(time.Hour)))
+}
+
+func TestGetMatureUnbondingDelegations(t *testing.T) {
+	ctx, _, keeper := CreateTestInput(t, false, 1000)
+	baseTime := time.Unix(1000, 0)
+	ctx = ctx.WithBlockTime(baseTime)
+
+	// two entries to the first validator maturing a minute apart, one to the
+	// second validator maturing with the later one
+	ubd := types.NewUnbondingDelegation(addrDels[0], addrVals[0], 0, baseTime.Add(time.Minute), sdk.NewInt(5))
+	ubd.AddEntry(1, baseTime.Add(2*time.Minute), sdk.NewInt(7))
+	keeper.SetUnbondingDelegation(ctx, ubd)
+	keeper.InsertUBDQueue(ctx, ubd, baseTime.Add(time.Minute))
+	keeper.InsertUBDQueue(ctx, ubd, baseTime.Add(2*time.Minute))
+	other := types.NewUnbondingDelegation(addrDels[1], addrVals[1], 0, baseTime.Add(2*time.Minute), sdk.NewInt(11))
+	keeper.SetUnbondingDelegation(ctx, other)
+	keeper.InsertUBDQueue(ctx, other, baseTime.Add(2*time.Minute))
+	keeper.SetPool(ctx, keeper.GetPool(ctx).NotBondedTokensToUnbonding(sdk.NewInt(5+7+11)))
+
+	require.Empty(t, keeper.GetMatureUnbondingDelegations(ctx))
+
+	ctx = ctx.WithBlockTime(baseTime.Add(time.Minute))
+	mature := keeper.GetMatureUnbondingDelegations(ctx)
+	require.Len(t, mature, 1)
+	require.Equal(t, addrVals[0], mature[0].ValidatorAddress)
+	require.Len(t, mature[0].Entries, 1)
+	require.Equal(t, sdk.NewInt(5), mature[0].Entries[0].Balance)
+
+	// past every completion all the entries are mature until they are processed
+	ctx = ctx.WithBlockTime(baseTime.Add(time.Hour))
+	mature = keeper.GetMatureUnbondingDelegations(ctx)
+	require.Len(t, mature, 2)
+	require.Len(t, mature[0].Entries, 2)
+	require.Equal(t, addrVals[1], mature[1].ValidatorAddress)
+
+	// as in EndBlock, a pair queued twice is completed once
+	for _, dvPair := range keeper.DequeueAllMatureUBDQueue(ctx, ctx.BlockHeader().Time) {
+		keeper.CompleteUnbonding(ctx, dvPair.DelegatorAddress, dvPair.ValidatorAddress)
+	}
+	require.Empty(t, keeper.GetMatureUnbondingDelegations(ctx))
+}
+
+func TestGetValidatorUnbondingTokens(t *testing.T) {
+	ctx, _, keeper := CreateTestInput(t, false, 0)
+	require.True(t, keeper.GetValidatorUnbondingTokens(ctx, addrVals[0]).IsZero())
+
+	completionTime := time.Unix(1000, 0)
+	ubd1 := types.NewUnbondingDelegation(addrDels[0], addrVals[0], 0, completionTime, sdk.NewInt(5))
+	ubd2 := types.NewUnbondingDelegation(addrDels[1], addrVals[0], 0, completionTime, sdk.NewInt(7))
+	other := types.NewUnbondingDelegation(addrDels[0], addrVals[1], 0, completionTime, sdk.NewInt(11))
+	keeper.SetUnbondingDelegation(ctx, ubd1)
+	keeper.SetUnbondingDelegation(ctx, ubd2)
+	keeper.SetUnbondingDelegation(ctx, other)
+
+	require.Equal(t, sdk.NewInt(12), keeper.GetValidatorUnbondingTokens(ctx, addrVals[0]))
+	require.Equal(t, sdk.NewInt(11), keeper.GetValidatorUnbondingTokens(ctx, addrVals[1]))
+
+	// sums beyond the int64 range are returned as is
+	balance, _ := sdk.NewIntFromString("9000000000000000000")
+	large := types.NewUnbondingDelegation(addrDels[0], addrVals[2], 0, completionTime, balance)
+	large.AddEntry(0, completionTime, balance)
+	keeper.SetUnbondingDelegation(ctx, large)
+	require.Equal(t, balance.MulRaw(2), keeper.GetValidatorUnbondingTokens(ctx, addrVals[2]))
+}
+
+func TestDelegationHeight(t *testing.T) {
+	ctx, _, keeper := CreateTestInput(t, false, 1000)
+	validator := types.NewValidator(addrVals[0], PKs[0], types.Description{})
+	keeper.SetValidator(ctx, validator)
+	keeper.SetNewValidatorByPowerIndex(ctx, validator)
+
+	ctx = ctx.WithBlockHeight(10)
+	_, err := keeper.Delegate(ctx, addrDels[0], sdk.TokensFromTendermintPower(10), validator, true)
+	require.Nil(t, err)
+	delegation, found := keeper.GetDelegation(ctx, addrDels[0], addrVals[0])
+	require.True(t, found)
+	require.Equal(t, int64(10), delegation.BondHeight)
+	require.Equal(t, int64(10), delegation.Height)
+
+	// a top-up moves the height and the bond height by the shares added
+	ctx = ctx.WithBlockHeight(40)
+	validator = keeper.mustGetValidator(ctx, addrVals[0])
+	_, err = keeper.Delegate(ctx, addrDels[0], sdk.TokensFromTendermintPower(5), validator, true)
+	require.Nil(t, err)
+	delegation, found = keeper.GetDelegation(ctx, addrDels[0], addrVals[0])
+	require.True(t, found)
+	require.Equal(t, int64(20), delegation.BondHeight)
+	require.Equal(t, int64(40), delegation.Height)
+
+	// a partial unbonding only moves the height
+	ctx = ctx.WithBlockHeight(50)
+	_, err = keeper.unbond(ctx, addrDels[0], addrVals[0], sdk.NewDec(1))
+	require.Nil(t, err)
+	delegation, found = keeper.GetDelegation(ctx, addrDels[0], addrVals[0])
+	require.True(t, found)
+	require.Equal(t, int64(20), delegation.BondHeight)
+	require.Equal(t, int64(50), delegation.Height)
+}
+
+func TestGetValidatorDelegatorsSorted(t *testing.T) {
+	ctx, _, keeper := CreateTestInput(t, false, 0)
+
+	delAddrs := []sdk.AccAddress{Addrs[7], Addrs[8], Addrs[9], Addrs[10]}
+	shares := []int64{20, 50, 10, 40}
+	for i, amount := range shares {
+		keeper.SetDelegation(ctx, types.NewDelegation(delAddrs[i], addrVals[0], sdk.NewDec(amount)))
+	}
+	keeper.SetDelegation(ctx, types.NewDelegation(delAddrs[0], addrVals[1], sdk.NewDec(100)))
+
+	sorted := keeper.GetValidatorDelegatorsSorted(ctx, addrVals[0], 0)
+	require.Len(t, sorted, 4)
+	for i, expected := range []int{1, 3, 0, 2} {
+		require.Equal(t, delAddrs[expected], sorted[i].DelegatorAddress, "position %d", i)
+		require.Equal(t, sdk.NewDec(shares[expected]), sorted[i].Shares)
+	}
+
+	// the limit keeps the largest delegations
+	limited := keeper.GetValidatorDelegatorsSorted(ctx, addrVals[0], 2)
+	require.Equal(t, sorted[:2], limited)
+}
+
+func TestHasDelegations(t *testing.T) {
+	ctx, _, keeper := CreateTestInput(t, false, 1000)
+	validator := types.NewValidator(addrVals[0], PKs[0], types.Description{})
+	keeper.SetValidator(ctx, validator)
+	keeper.SetNewValidatorByPowerIndex(ctx, validator)
+	require.False(t, keeper.HasDelegations(ctx, addrDels[0]))
+
+	bondTokens := sdk.TokensFromTendermintPower(10)
+	_, err := keeper.Delegate(ctx, addrDels[0], bondTokens, validator, true)
+	require.Nil(t, err)
+	require.True(t, keeper.HasDelegations(ctx, addrDels[0]))
+	require.False(t, keeper.HasDelegations(ctx, addrDels[1]))
+
+	// unbonding all the shares removes the delegation
+	_, err = keeper.unbond(ctx, addrDels[0], addrVals[0], bondTokens.ToDec())
+	require.Nil(t, err)
+	require.False(t, keeper.HasDelegations(ctx, addrDels[0]))
+}
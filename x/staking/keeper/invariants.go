@@ -13,14 +13,72 @@ import (
 func RegisterInvariants(c types.CrisisKeeper, k Keeper, f types.FeeCollectionKeeper,
 	d types.DistributionKeeper, am auth.AccountKeeper) {
 
-	c.RegisterRoute(types.ModuleName, "supply",
-		SupplyInvariants(k, f, d, am))
-	c.RegisterRoute(types.ModuleName, "nonnegative-power",
-		NonNegativePowerInvariant(k))
-	c.RegisterRoute(types.ModuleName, "positive-delegation",
-		PositiveDelegationInvariant(k))
-	c.RegisterRoute(types.ModuleName, "delegator-shares",
-		DelegatorSharesInvariant(k))
+	for _, ir := range InvariantRoutes(k, f, d, am) {
+		c.RegisterRoute(types.ModuleName, ir.Route, ir.Invar)
+	}
+}
+
+// InvariantRoute pairs a staking invariant with the route it is registered
+// under in the crisis module.
+type InvariantRoute struct {
+	Route string
+	Invar sdk.Invariant
+}
+
+// InvariantRoutes returns all invariants of the staking module along with
+// their routes, in registration order.
+func InvariantRoutes(k Keeper, f types.FeeCollectionKeeper,
+	d types.DistributionKeeper, am auth.AccountKeeper) []InvariantRoute {
+
+	return []InvariantRoute{
+		{"supply", SupplyInvariants(k, f, d, am)},
+		{"nonnegative-power", NonNegativePowerInvariant(k)},
+		{"positive-delegation", PositiveDelegationInvariant(k)},
+		{"delegator-shares", DelegatorSharesInvariant(k)},
+	}
+}
+
+// InvariantCheck is the outcome of running a single staking invariant.
+type InvariantCheck struct {
+	Route  string `json:"route"`
+	Passed bool   `json:"passed"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// GenesisInvariantRoutes returns the invariants of the staking module which
+// depend on the staking state alone, so that they hold for the imported state
+// before the other modules are initialized. The delegator shares invariant is
+// left out as a genesis may carry validators without their self-delegations.
+func GenesisInvariantRoutes(k Keeper) []InvariantRoute {
+	return []InvariantRoute{
+		{"bonded-tokens", BondedTokensInvariant(k)},
+		{"nonnegative-power", NonNegativePowerInvariant(k)},
+		{"positive-delegation", PositiveDelegationInvariant(k)},
+	}
+}
+
+// AssertInvariants panics with the route and the detail of the first broken
+// invariant among GenesisInvariantRoutes.
+func (k Keeper) AssertInvariants(ctx sdk.Context) {
+	for _, check := range CheckInvariants(ctx, GenesisInvariantRoutes(k)) {
+		if !check.Passed {
+			panic(fmt.Sprintf("staking invariant %s broken: %s", check.Route, check.Detail))
+		}
+	}
+}
+
+// CheckInvariants runs every given invariant and reports the result of each
+// one rather than stopping at the first failure. It does not modify state.
+func CheckInvariants(ctx sdk.Context, invarRoutes []InvariantRoute) []InvariantCheck {
+	checks := make([]InvariantCheck, len(invarRoutes))
+	for i, ir := range invarRoutes {
+		checks[i] = InvariantCheck{Route: ir.Route, Passed: true}
+		if err := ir.Invar(ctx); err != nil {
+			checks[i].Passed = false
+			checks[i].Detail = err.Error()
+		}
+	}
+	return checks
 }
 
 // AllInvariants runs all invariants of the staking module.
@@ -61,7 +119,6 @@ func SupplyInvariants(k Keeper, f types.FeeCollectionKeeper,
 		pool := k.GetPool(ctx)
 
 		loose := sdk.ZeroDec()
-		bonded := sdk.ZeroDec()
 		am.IterateAccounts(ctx, func(acc auth.Account) bool {
 			loose = loose.Add(acc.GetCoins().AmountOf(k.BondDenom(ctx)).ToDec())
 			return false
@@ -74,8 +131,6 @@ func SupplyInvariants(k Keeper, f types.FeeCollectionKeeper,
 		})
 		k.IterateValidators(ctx, func(_ int64, validator sdk.Validator) bool {
 			switch validator.GetStatus() {
-			case sdk.Bonded:
-				bonded = bonded.Add(validator.GetBondedTokens().ToDec())
 			case sdk.Unbonding, sdk.Unbonded:
 				loose = loose.Add(validator.GetTokens().ToDec())
 			}
@@ -90,14 +145,33 @@ func SupplyInvariants(k Keeper, f types.FeeCollectionKeeper,
 		// add community pool
 		loose = loose.Add(d.GetFeePoolCommunityCoins(ctx).AmountOf(k.BondDenom(ctx)))
 
-		// Not-bonded tokens should equal coin supply plus unbonding delegations
-		// plus tokens on unbonded validators
-		if !pool.NotBondedTokens.ToDec().Equal(loose) {
+		// Not-bonded and unbonding tokens should equal coin supply plus
+		// unbonding delegations plus tokens on unbonded validators
+		if !pool.NotBondedTokens.Add(pool.UnbondingTokens).ToDec().Equal(loose) {
 			return fmt.Errorf("loose token invariance:\n"+
 				"\tpool.NotBondedTokens: %v\n"+
-				"\tsum of account tokens: %v", pool.NotBondedTokens, loose)
+				"\tpool.UnbondingTokens: %v\n"+
+				"\tsum of account tokens: %v", pool.NotBondedTokens, pool.UnbondingTokens, loose)
 		}
 
+		return BondedTokensInvariant(k)(ctx)
+	}
+}
+
+// BondedTokensInvariant checks that the bonded tokens of the pool equal the
+// sum of the tokens of the bonded validators.
+func BondedTokensInvariant(k Keeper) sdk.Invariant {
+	return func(ctx sdk.Context) error {
+		pool := k.GetPool(ctx)
+
+		bonded := sdk.ZeroDec()
+		k.IterateValidators(ctx, func(_ int64, validator sdk.Validator) bool {
+			if validator.GetStatus() == sdk.Bonded {
+				bonded = bonded.Add(validator.GetBondedTokens().ToDec())
+			}
+			return false
+		})
+
 		// Bonded tokens should equal sum of tokens with bonded validators
 		if !pool.BondedTokens.ToDec().Equal(bonded) {
 			return fmt.Errorf("bonded token invariance:\n"+
@@ -120,7 +194,7 @@ func NonNegativePowerInvariant(k Keeper) sdk.Invariant {
 				panic(fmt.Sprintf("validator record not found for address: %X\n", iterator.Value()))
 			}
 
-			powerKey := GetValidatorsByPowerIndexKey(validator)
+			powerKey := GetValidatorsByPowerIndexKey(validator, k.GetPowerIndexTieBreak(ctx))
 
 			if !bytes.Equal(iterator.Key(), powerKey) {
 				return fmt.Errorf("power store invariance:\n\tvalidator.Power: %v"+
@@ -2,6 +2,9 @@ package keeper
 
 import (
 	"container/list"
+	"encoding/binary"
+	"fmt"
+	"math"
 
 	"github.com/tendermint/tendermint/libs/log"
 
@@ -23,6 +26,7 @@ type Keeper struct {
 	paramstore         params.Subspace
 	validatorCache     map[string]cachedValidator
 	validatorCacheList *list.List
+	inflationEstimator InflationEstimator
 
 	// codespace
 	codespace sdk.CodespaceType
@@ -45,6 +49,10 @@ func NewKeeper(cdc *codec.Codec, key, tkey sdk.StoreKey, bk types.BankKeeper,
 	return keeper
 }
 
+// InflationEstimator returns the inflation rate the minting module would
+// apply next given the bonded ratio.
+type InflationEstimator func(ctx sdk.Context, bondedRatio sdk.Dec) sdk.Dec
+
 // Logger returns a module-specific logger.
 func (k Keeper) Logger(ctx sdk.Context) log.Logger { return ctx.Logger().With("module", "x/staking") }
 
@@ -57,6 +65,15 @@ func (k *Keeper) SetHooks(sh sdk.StakingHooks) *Keeper {
 	return k
 }
 
+// Set the inflation estimator used when simulating parameter changes
+func (k *Keeper) SetInflationEstimator(estimator InflationEstimator) *Keeper {
+	if k.inflationEstimator != nil {
+		panic("cannot set inflation estimator twice")
+	}
+	k.inflationEstimator = estimator
+	return k
+}
+
 // return the codespace
 func (k Keeper) Codespace() sdk.CodespaceType {
 	return k.codespace
@@ -80,6 +97,143 @@ func (k Keeper) SetPool(ctx sdk.Context, pool types.Pool) {
 	store.Set(PoolKey, b)
 }
 
+// AddBondedRewardPool mints a one-off reward to the bonded validators, split
+// in proportion to their tokens. Shares are left untouched, raising the
+// exchange rate of every bonded delegation alike. The rounding remainder goes
+// to the first bonded validator by address. Nothing is minted if no tokens
+// are bonded.
+func (k Keeper) AddBondedRewardPool(ctx sdk.Context, amount int64) {
+	if amount < 0 {
+		panic(fmt.Sprintf("should not happen: trying to add a negative bonded reward %d", amount))
+	}
+	pool := k.GetPool(ctx)
+	if amount == 0 || !pool.BondedTokens.IsPositive() {
+		return
+	}
+
+	var validators []types.Validator
+	for _, validator := range k.GetLastValidators(ctx) {
+		if validator.Status == sdk.Bonded {
+			validators = append(validators, validator)
+		}
+	}
+
+	reward := sdk.NewInt(amount)
+	remainder := reward
+	rewards := make([]sdk.Int, len(validators))
+	for i, validator := range validators {
+		rewards[i] = reward.Mul(validator.Tokens).Quo(pool.BondedTokens)
+		remainder = remainder.Sub(rewards[i])
+	}
+	if len(rewards) > 0 {
+		rewards[0] = rewards[0].Add(remainder)
+	}
+
+	for i, validator := range validators {
+		k.DeleteValidatorByPowerIndex(ctx, validator)
+		validator.Tokens = validator.Tokens.Add(rewards[i])
+		k.SetValidator(ctx, validator)
+		k.SetValidatorByPowerIndex(ctx, validator)
+	}
+
+	pool.BondedTokens = pool.BondedTokens.Add(reward)
+	k.SetPool(ctx, pool)
+}
+
+// GetPoolRoundingResidual returns the bonded tokens of the pool minus the sum
+// of the delegations to bonded validators valued at their validators' exchange
+// rates, each truncated to an integer. The residual collects the token
+// fractions which share rounding leaves behind in validators; in a healthy
+// pool it is non-negative and below the number of delegations.
+func (k Keeper) GetPoolRoundingResidual(ctx sdk.Context) int64 {
+	store := ctx.KVStore(k.storeKey)
+	iterator := sdk.KVStorePrefixIterator(store, DelegationKey)
+	defer iterator.Close()
+
+	validators := make(map[string]types.Validator)
+	delegated := sdk.ZeroInt()
+	for ; iterator.Valid(); iterator.Next() {
+		delegation := types.MustUnmarshalDelegation(k.cdc, iterator.Value())
+
+		valKey := string(delegation.ValidatorAddress)
+		validator, found := validators[valKey]
+		if !found {
+			validator, found = k.GetValidator(ctx, delegation.ValidatorAddress)
+			if !found {
+				continue
+			}
+			validators[valKey] = validator
+		}
+		if validator.Status != sdk.Bonded {
+			continue
+		}
+		delegated = delegated.Add(validator.TokensFromShares(delegation.Shares).TruncateInt())
+	}
+	return k.GetPool(ctx).BondedTokens.Sub(delegated).Int64()
+}
+
+// RecordBondedRatio stores the bonded ratio of the pool at the current block
+// height and prunes the records which are older than the BondedRatioHistory
+// parameter. A zero history disables the record.
+func (k Keeper) RecordBondedRatio(ctx sdk.Context) {
+	retention := k.BondedRatioHistory(ctx)
+	if retention == 0 {
+		return
+	}
+
+	store := ctx.KVStore(k.storeKey)
+	height := ctx.BlockHeight()
+	bondedRatio := k.GetPool(ctx).BondedRatio()
+	store.Set(GetBondedRatioHistoryKey(height), k.cdc.MustMarshalBinaryLengthPrefixed(bondedRatio))
+
+	// prune the records which left the retention window
+	cutoff := height - int64(retention) + 1
+	if cutoff <= 0 {
+		return
+	}
+	deleteRange(store, BondedRatioHistoryKey, GetBondedRatioHistoryKey(cutoff))
+}
+
+// deleteRange deletes the keys between start, inclusive, and end, exclusive
+func deleteRange(store sdk.KVStore, start, end []byte) {
+	iterator := store.Iterator(start, end)
+	var keys [][]byte
+	for ; iterator.Valid(); iterator.Next() {
+		keys = append(keys, iterator.Key())
+	}
+	iterator.Close()
+	for _, key := range keys {
+		store.Delete(key)
+	}
+}
+
+// GetBondedRatioHistory returns the recorded bonded ratios between the given
+// heights, inclusive, in ascending height order.
+func (k Keeper) GetBondedRatioHistory(ctx sdk.Context, fromHeight, toHeight int64) (records []types.BondedRatioRecord) {
+	if fromHeight < 0 {
+		fromHeight = 0
+	}
+	if toHeight < fromHeight {
+		return records
+	}
+
+	store := ctx.KVStore(k.storeKey)
+	end := sdk.PrefixEndBytes(BondedRatioHistoryKey)
+	if toHeight < math.MaxInt64 {
+		end = GetBondedRatioHistoryKey(toHeight + 1)
+	}
+	iterator := store.Iterator(GetBondedRatioHistoryKey(fromHeight), end)
+	defer iterator.Close()
+
+	for ; iterator.Valid(); iterator.Next() {
+		var bondedRatio sdk.Dec
+		k.cdc.MustUnmarshalBinaryLengthPrefixed(iterator.Value(), &bondedRatio)
+		height := int64(binary.BigEndian.Uint64(iterator.Key()[len(BondedRatioHistoryKey):]))
+		records = append(records, types.BondedRatioRecord{Height: height, BondedRatio: bondedRatio})
+	}
+	return records
+}
+
 // Load the last total validator power.
 func (k Keeper) GetLastTotalPower(ctx sdk.Context) (power sdk.Int) {
 	store := ctx.KVStore(k.storeKey)
@@ -38,3 +38,127 @@ func TestPool(t *testing.T) {
 	resPool = keeper.GetPool(ctx)
 	require.Equal(t, expPool, resPool)
 }
+
+func TestAddBondedRewardPool(t *testing.T) {
+	ctx, _, keeper := CreateTestInput(t, false, 1000)
+
+	// two bonded validators with 500 bonded tokens in total
+	powers := []int64{100, 300}
+	for i, power := range powers {
+		valAddr := sdk.ValAddress(Addrs[i])
+		validator := types.NewValidator(valAddr, PKs[i], types.Description{})
+		keeper.SetValidator(ctx, validator)
+		keeper.SetNewValidatorByPowerIndex(ctx, validator)
+		_, err := keeper.Delegate(ctx, Addrs[i], sdk.TokensFromTendermintPower(power), validator, true)
+		require.Nil(t, err)
+	}
+	validator := keeper.mustGetValidator(ctx, sdk.ValAddress(Addrs[1]))
+	_, err := keeper.Delegate(ctx, Addrs[2], sdk.TokensFromTendermintPower(100), validator, true)
+	require.Nil(t, err)
+	keeper.ApplyAndReturnValidatorSetUpdates(ctx)
+
+	delegationTokens := func() []sdk.Int {
+		var tokens []sdk.Int
+		for _, delegation := range keeper.GetAllDelegations(ctx) {
+			validator := keeper.mustGetValidator(ctx, delegation.ValidatorAddress)
+			tokens = append(tokens, validator.TokensFromShares(delegation.Shares).TruncateInt())
+		}
+		return tokens
+	}
+	oldPool := keeper.GetPool(ctx)
+	oldTokens := delegationTokens()
+	require.Len(t, oldTokens, 3)
+
+	// a reward of a tenth of the bonded tokens raises every delegation by a tenth
+	reward := sdk.TokensFromTendermintPower(50)
+	keeper.AddBondedRewardPool(ctx, reward.Int64())
+
+	newTokens := delegationTokens()
+	for i := range oldTokens {
+		require.True(t, oldTokens[i].MulRaw(11).QuoRaw(10).Equal(newTokens[i]),
+			"expected %v, got %v", oldTokens[i].MulRaw(11).QuoRaw(10), newTokens[i])
+	}
+
+	pool := keeper.GetPool(ctx)
+	require.True(t, oldPool.BondedTokens.Add(reward).Equal(pool.BondedTokens))
+	require.True(t, oldPool.TokenSupply().Add(reward).Equal(pool.TokenSupply()))
+	require.True(t, oldPool.NotBondedTokens.Equal(pool.NotBondedTokens))
+}
+
+func TestBondedRatioHistory(t *testing.T) {
+	ctx, _, keeper := CreateTestInput(t, false, 0)
+
+	// the history is off by default
+	keeper.RecordBondedRatio(ctx)
+	require.Empty(t, keeper.GetBondedRatioHistory(ctx, 0, ctx.BlockHeight()))
+
+	params := keeper.GetParams(ctx)
+	params.BondedRatioHistory = 3
+	keeper.SetParams(ctx, params)
+
+	// the bonded tokens change every block out of a supply of 100
+	bonded := []int64{10, 20, 40, 50, 80}
+	for i, tokens := range bonded {
+		ctx = ctx.WithBlockHeight(int64(i + 1))
+		pool := keeper.GetPool(ctx)
+		pool.BondedTokens = sdk.NewInt(tokens)
+		pool.NotBondedTokens = sdk.NewInt(100 - tokens)
+		keeper.SetPool(ctx, pool)
+		keeper.RecordBondedRatio(ctx)
+	}
+
+	// only the last three blocks are kept
+	records := keeper.GetBondedRatioHistory(ctx, 0, ctx.BlockHeight())
+	require.Len(t, records, 3)
+	for i, record := range records {
+		require.Equal(t, int64(i+3), record.Height)
+		expRatio := sdk.NewDecWithPrec(bonded[i+2], 2)
+		require.True(t, expRatio.Equal(record.BondedRatio), "expected %v, got %v", expRatio, record.BondedRatio)
+	}
+
+	// the range is inclusive
+	records = keeper.GetBondedRatioHistory(ctx, 4, 4)
+	require.Len(t, records, 1)
+	require.Equal(t, int64(4), records[0].Height)
+
+	// a zero history disables the record
+	params.BondedRatioHistory = 0
+	keeper.SetParams(ctx, params)
+	ctx = ctx.WithBlockHeight(6)
+	keeper.RecordBondedRatio(ctx)
+	require.Len(t, keeper.GetBondedRatioHistory(ctx, 0, ctx.BlockHeight()), 3)
+}
+
+func TestPoolRoundingResidual(t *testing.T) {
+	ctx, _, keeper := CreateTestInput(t, false, 1000)
+	for i := 0; i < 3; i++ {
+		validator := types.NewValidator(addrVals[i], PKs[i], types.Description{})
+		keeper.SetValidator(ctx, validator)
+		keeper.SetNewValidatorByPowerIndex(ctx, validator)
+	}
+	require.Zero(t, keeper.GetPoolRoundingResidual(ctx))
+
+	delegate := func(round int64) {
+		for i := 0; i < 10; i++ {
+			validator := keeper.mustGetValidator(ctx, addrVals[i%3])
+			amount := sdk.NewInt(1000003 + 7919*int64(i) + round)
+			_, err := keeper.Delegate(ctx, Addrs[10+i], amount, validator, true)
+			require.Nil(t, err)
+		}
+	}
+
+	// delegate, bond the validators, then move the exchange rates off one
+	delegate(0)
+	keeper.ApplyAndReturnValidatorSetUpdates(ctx)
+	require.Zero(t, keeper.GetPoolRoundingResidual(ctx))
+	keeper.AddBondedRewardPool(ctx, 1234567)
+
+	// delegations at fractional exchange rates leave token fractions behind
+	for round := int64(1); round <= 5; round++ {
+		delegate(round)
+	}
+
+	residual := keeper.GetPoolRoundingResidual(ctx)
+	require.True(t, residual >= 0, "residual %d", residual)
+	require.True(t, residual < int64(len(keeper.GetAllDelegations(ctx))), "residual %d", residual)
+}
@@ -22,6 +22,9 @@ var (
 	ValidatorsKey             = []byte{0x21} // prefix for each key to a validator
 	ValidatorsByConsAddrKey   = []byte{0x22} // prefix for each key to a validator index, by pubkey
 	ValidatorsByPowerIndexKey = []byte{0x23} // prefix for each key to a validator index, sorted by power
+	ValidatorSlashEventsKey   = []byte{0x24} // prefix for each key to a validator's slash events
+	GenesisValidatorsKey      = []byte{0x25} // prefix for each key to a validator as it existed at genesis
+	PowerIndexTieBreakKey     = []byte{0x26} // key for the tie-break mode the power index is sorted by
 
 	DelegationKey                    = []byte{0x31} // key for a delegation
 	UnbondingDelegationKey           = []byte{0x32} // key for an unbonding-delegation
@@ -29,12 +32,80 @@ var (
 	RedelegationKey                  = []byte{0x34} // key for a redelegation
 	RedelegationByValSrcIndexKey     = []byte{0x35} // prefix for each key for an redelegation, by source validator operator
 	RedelegationByValDstIndexKey     = []byte{0x36} // prefix for each key for an redelegation, by destination validator operator
+	DelegationCountKey               = []byte{0x37} // key for the total number of delegations
+	AutoCompoundDelegationKey        = []byte{0x38} // prefix for each key to a delegation which auto-compounds its rewards
 
 	UnbondingQueueKey    = []byte{0x41} // prefix for the timestamps in unbonding queue
 	RedelegationQueueKey = []byte{0x42} // prefix for the timestamps in redelegations queue
 	ValidatorQueueKey    = []byte{0x43} // prefix for the timestamps in validator queue
+
+	ValidatorUpdatesHistoryKey = []byte{0x51} // prefix for the validator set updates by block height
+	BondedRatioHistoryKey      = []byte{0x52} // prefix for the bonded ratio by block height
+	ValidatorBondedTokensKey   = []byte{0x53} // prefix for the bonded tokens of each validator by block height
+	BondedTokensTimeKey        = []byte{0x54} // prefix for the block time of the recorded bonded tokens by block height
+	ValidatorExRateKey         = []byte{0x55} // prefix for the exchange rate of each validator by block height
+	ValidatorSetSizeKey        = []byte{0x56} // prefix for the bonded validator set size by the block height it changed at
+	ExRateTimeKey              = []byte{0x57} // prefix for the block time of the recorded exchange rates by block height
+
+	// Keys for the transient store, which is reset at the end of every block
+	ValidatorsCreatedCountKey = []byte{0x02} // key for the number of validators created in the block
+	PowerChangeCountKey       = []byte{0x03} // key for the number of validator token changes in the block
+	CompletedUnbondingsKey    = []byte{0x04} // key for the unbonding delegations completed by a proposal in the block
+	CompletedRedelegationsKey = []byte{0x05} // key for the redelegations completed by a proposal in the block
 )
 
+// gets the key for the validator set updates produced at a block height
+// VALUE: []abci.ValidatorUpdate
+func GetValidatorUpdatesHistoryKey(height int64) []byte {
+	return append(ValidatorUpdatesHistoryKey, sdk.Uint64ToBigEndian(uint64(height))...)
+}
+
+// gets the key for the bonded ratio at the end of a block height
+// VALUE: sdk.Dec
+func GetBondedRatioHistoryKey(height int64) []byte {
+	return append(BondedRatioHistoryKey, sdk.Uint64ToBigEndian(uint64(height))...)
+}
+
+// gets the prefix for the bonded tokens of all validators at a block height
+func GetValidatorBondedTokensHeightKey(height int64) []byte {
+	return append(ValidatorBondedTokensKey, sdk.Uint64ToBigEndian(uint64(height))...)
+}
+
+// gets the key for the bonded tokens of a validator at the end of a block height
+// VALUE: sdk.Int
+func GetValidatorBondedTokensKey(height int64, operatorAddr sdk.ValAddress) []byte {
+	return append(GetValidatorBondedTokensHeightKey(height), operatorAddr.Bytes()...)
+}
+
+// gets the prefix for the exchange rates of all validators at a block height
+func GetValidatorExRateHeightKey(height int64) []byte {
+	return append(ValidatorExRateKey, sdk.Uint64ToBigEndian(uint64(height))...)
+}
+
+// gets the key for the exchange rate of a validator at the end of a block height
+// VALUE: sdk.Dec
+func GetValidatorExRateKey(height int64, operatorAddr sdk.ValAddress) []byte {
+	return append(GetValidatorExRateHeightKey(height), operatorAddr.Bytes()...)
+}
+
+// gets the key for the bonded validator set size which took effect at a block height
+// VALUE: int64
+func GetValidatorSetSizeKey(height int64) []byte {
+	return append(ValidatorSetSizeKey, sdk.Uint64ToBigEndian(uint64(height))...)
+}
+
+// gets the key for the block time at which the bonded tokens of a height were recorded
+// VALUE: time.Time
+func GetBondedTokensTimeKey(height int64) []byte {
+	return append(BondedTokensTimeKey, sdk.Uint64ToBigEndian(uint64(height))...)
+}
+
+// gets the key for the block time at which the exchange rates of a height were recorded
+// VALUE: time.Time
+func GetExRateTimeKey(height int64) []byte {
+	return append(ExRateTimeKey, sdk.Uint64ToBigEndian(uint64(height))...)
+}
+
 // gets the key for the validator with address
 // VALUE: staking/types.Validator
 func GetValidatorKey(operatorAddr sdk.ValAddress) []byte {
@@ -47,6 +118,18 @@ func GetValidatorByConsAddrKey(addr sdk.ConsAddress) []byte {
 	return append(ValidatorsByConsAddrKey, addr.Bytes()...)
 }
 
+// gets the key for the slash events of the validator with address
+// VALUE: staking/types.SlashEvents
+func GetValidatorSlashEventsKey(operatorAddr sdk.ValAddress) []byte {
+	return append(ValidatorSlashEventsKey, operatorAddr.Bytes()...)
+}
+
+// gets the key for the genesis snapshot of the validator with address
+// VALUE: staking/types.Validator
+func GetGenesisValidatorKey(operatorAddr sdk.ValAddress) []byte {
+	return append(GenesisValidatorsKey, operatorAddr.Bytes()...)
+}
+
 // Get the validator operator address from LastValidatorPowerKey
 func AddressFromLastValidatorPowerKey(key []byte) []byte {
 	return key[1:] // remove prefix bytes
@@ -54,11 +137,11 @@ func AddressFromLastValidatorPowerKey(key []byte) []byte {
 
 // get the validator by power index.
 // Power index is the key used in the power-store, and represents the relative
-// power ranking of the validator.
+// power ranking of the validator, with ties ordered by the given mode.
 // VALUE: validator operator address ([]byte)
-func GetValidatorsByPowerIndexKey(validator types.Validator) []byte {
+func GetValidatorsByPowerIndexKey(validator types.Validator, tieBreak types.TieBreakMode) []byte {
 	// NOTE the address doesn't need to be stored because counter bytes must always be different
-	return getValidatorPowerRank(validator)
+	return getValidatorPowerRank(validator, tieBreak)
 }
 
 // get the bonded validator index key for an operator address
@@ -69,7 +152,7 @@ func GetLastValidatorPowerKey(operator sdk.ValAddress) []byte {
 // get the power ranking of a validator
 // NOTE the larger values are of higher value
 // nolint: unparam
-func getValidatorPowerRank(validator types.Validator) []byte {
+func getValidatorPowerRank(validator types.Validator, tieBreak types.TieBreakMode) []byte {
 
 	potentialPower := validator.Tokens
 
@@ -81,26 +164,39 @@ func getValidatorPowerRank(validator types.Validator) []byte {
 	powerBytes := tendermintPowerBytes
 	powerBytesLen := len(powerBytes) // 8
 
-	// key is of format prefix || powerbytes || addrBytes
-	key := make([]byte, 1+powerBytesLen+sdk.AddrLen)
+	// the bond height, or the power sequence when ordering by transaction, is
+	// inverted so that, at equal power, the validator which came first ranks
+	// highest
+	order := uint64(validator.BondHeight)
+	if tieBreak == types.TieBreakTxOrder {
+		order = validator.PowerSequence
+	}
+	heightBytes := make([]byte, 8)
+	binary.BigEndian.PutUint64(heightBytes[:], ^order)
+	heightBytesLen := len(heightBytes) // 8
+
+	// key is of format prefix || powerbytes || heightBytes || addrBytes
+	key := make([]byte, 1+powerBytesLen+heightBytesLen+sdk.AddrLen)
 
 	key[0] = ValidatorsByPowerIndexKey[0]
 	copy(key[1:powerBytesLen+1], powerBytes)
+	copy(key[powerBytesLen+1:powerBytesLen+heightBytesLen+1], heightBytes)
 	operAddrInvr := cp(validator.OperatorAddress)
 	for i, b := range operAddrInvr {
 		operAddrInvr[i] = ^b
 	}
-	copy(key[powerBytesLen+1:], operAddrInvr)
+	copy(key[powerBytesLen+heightBytesLen+1:], operAddrInvr)
 
 	return key
 }
 
 func parseValidatorPowerRankKey(key []byte) (operAddr []byte) {
 	powerBytesLen := 8
-	if len(key) != 1+powerBytesLen+sdk.AddrLen {
+	heightBytesLen := 8
+	if len(key) != 1+powerBytesLen+heightBytesLen+sdk.AddrLen {
 		panic("Invalid validator power rank key length")
 	}
-	operAddr = cp(key[powerBytesLen+1:])
+	operAddr = cp(key[powerBytesLen+heightBytesLen+1:])
 	for i, b := range operAddr {
 		operAddr[i] = ^b
 	}
@@ -126,6 +222,12 @@ func GetDelegationsKey(delAddr sdk.AccAddress) []byte {
 	return append(DelegationKey, delAddr.Bytes()...)
 }
 
+// gets the index-key for a delegation which auto-compounds its rewards
+// VALUE: none (key rearrangement used)
+func GetAutoCompoundDelegationKey(delAddr sdk.AccAddress, valAddr sdk.ValAddress) []byte {
+	return append(append(AutoCompoundDelegationKey, delAddr.Bytes()...), valAddr.Bytes()...)
+}
+
 //______________________________________________________________________________
 
 // gets the key for an unbonding delegation by delegator and validator addr
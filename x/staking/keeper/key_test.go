@@ -36,13 +36,13 @@ func TestGetValidatorPowerRank(t *testing.T) {
 		validator types.Validator
 		wantHex   string
 	}{
-		{val1, "2300000000000000009c288ede7df62742fc3b7d0962045a8cef0f79f6"},
-		{val2, "2300000000000000019c288ede7df62742fc3b7d0962045a8cef0f79f6"},
-		{val3, "23000000000000000a9c288ede7df62742fc3b7d0962045a8cef0f79f6"},
-		{val4, "2300000100000000009c288ede7df62742fc3b7d0962045a8cef0f79f6"},
+		{val1, "230000000000000000ffffffffffffffff9c288ede7df62742fc3b7d0962045a8cef0f79f6"},
+		{val2, "230000000000000001ffffffffffffffff9c288ede7df62742fc3b7d0962045a8cef0f79f6"},
+		{val3, "23000000000000000affffffffffffffff9c288ede7df62742fc3b7d0962045a8cef0f79f6"},
+		{val4, "230000010000000000ffffffffffffffff9c288ede7df62742fc3b7d0962045a8cef0f79f6"},
 	}
 	for i, tt := range tests {
-		got := hex.EncodeToString(getValidatorPowerRank(tt.validator))
+		got := hex.EncodeToString(getValidatorPowerRank(tt.validator, types.TieBreakStakeAge))
 
 		assert.Equal(t, tt.wantHex, got, "Keys did not match on test case %d", i)
 	}
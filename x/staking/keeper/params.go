@@ -43,6 +43,144 @@ func (k Keeper) BondDenom(ctx sdk.Context) (res string) {
 	return
 }
 
+// ShareRoundingMode - Rounding when converting between tokens and delegator
+// shares
+func (k Keeper) ShareRoundingMode(ctx sdk.Context) (res types.ShareRoundingMode) {
+	k.paramstore.Get(ctx, types.KeyShareRoundingMode, &res)
+	return
+}
+
+// InstantUnbond - Whether delegations may be force undelegated without waiting
+// for the unbonding time
+func (k Keeper) InstantUnbond(ctx sdk.Context) (res bool) {
+	k.paramstore.Get(ctx, types.KeyInstantUnbond, &res)
+	return
+}
+
+// ValidatorUpdatesHistory - Number of blocks for which the validator set
+// updates are kept
+func (k Keeper) ValidatorUpdatesHistory(ctx sdk.Context) (res uint64) {
+	k.paramstore.Get(ctx, types.KeyValidatorUpdatesHistory, &res)
+	return
+}
+
+// MaxValidatorsCreatedPerBlock - Maximum number of validators created in a
+// block
+func (k Keeper) MaxValidatorsCreatedPerBlock(ctx sdk.Context) (res uint16) {
+	k.paramstore.Get(ctx, types.KeyMaxValidatorsCreatedPerBlock, &res)
+	return
+}
+
+// MaxDelegatorPowerShare - Maximum fraction of the bonded tokens a single
+// delegator may hold
+func (k Keeper) MaxDelegatorPowerShare(ctx sdk.Context) (res sdk.Dec) {
+	k.paramstore.Get(ctx, types.KeyMaxDelegatorPowerShare, &res)
+	return
+}
+
+// MaxValidatorsSchedule - Values MaxValidators is set to at given heights
+func (k Keeper) MaxValidatorsSchedule(ctx sdk.Context) (res []types.MaxValidatorsScheduleEntry) {
+	k.paramstore.Get(ctx, types.KeyMaxValidatorsSchedule, &res)
+	return
+}
+
+// LoyaltyWeighting - Fraction of the rewards of a new delegation withheld
+// until it has been bonded for the loyalty period
+func (k Keeper) LoyaltyWeighting(ctx sdk.Context) (res sdk.Dec) {
+	k.paramstore.Get(ctx, types.KeyLoyaltyWeighting, &res)
+	return
+}
+
+// LoyaltyPeriod - Number of blocks after which a delegation earns its full
+// rewards
+func (k Keeper) LoyaltyPeriod(ctx sdk.Context) (res int64) {
+	k.paramstore.Get(ctx, types.KeyLoyaltyPeriod, &res)
+	return
+}
+
+// MinSlashTokens - Minimum number of tokens burned by a slash with a nonzero
+// fraction
+func (k Keeper) MinSlashTokens(ctx sdk.Context) (res sdk.Int) {
+	k.paramstore.Get(ctx, types.KeyMinSlashTokens, &res)
+	return
+}
+
+// BondedRatioHistory - Number of blocks for which the bonded ratio is kept
+func (k Keeper) BondedRatioHistory(ctx sdk.Context) (res uint64) {
+	k.paramstore.Get(ctx, types.KeyBondedRatioHistory, &res)
+	return
+}
+
+// TieBreakMode - Ordering of validators with equal power
+func (k Keeper) TieBreakMode(ctx sdk.Context) (res types.TieBreakMode) {
+	k.paramstore.Get(ctx, types.KeyTieBreakMode, &res)
+	return
+}
+
+// AllowedPubKeyTypes - Consensus pubkey types validators may be created with,
+// empty for any the consensus params accept
+func (k Keeper) AllowedPubKeyTypes(ctx sdk.Context) (res []string) {
+	k.paramstore.Get(ctx, types.KeyAllowedPubKeyTypes, &res)
+	return
+}
+
+// MinCommissionRate - Minimum commission rate of a validator
+func (k Keeper) MinCommissionRate(ctx sdk.Context) (res sdk.Dec) {
+	k.paramstore.Get(ctx, types.KeyMinCommissionRate, &res)
+	return
+}
+
+// MaxSlashPerInfraction - Maximum slash fraction of a single infraction, zero
+// for no cap
+func (k Keeper) MaxSlashPerInfraction(ctx sdk.Context) (res sdk.Dec) {
+	k.paramstore.Get(ctx, types.KeyMaxSlashPerInfraction, &res)
+	return
+}
+
+// SlashEventsHistory - Number of blocks for which slash events are kept
+func (k Keeper) SlashEventsHistory(ctx sdk.Context) (res uint64) {
+	k.paramstore.Get(ctx, types.KeySlashEventsHistory, &res)
+	return
+}
+
+// BondedTokensHistory - Whether the bonded tokens of each validator are
+// recorded per block
+func (k Keeper) BondedTokensHistory(ctx sdk.Context) (res bool) {
+	k.paramstore.Get(ctx, types.KeyBondedTokensHistory, &res)
+	return
+}
+
+// ExRateHistory - Whether the exchange rate of each validator is recorded per
+// block
+func (k Keeper) ExRateHistory(ctx sdk.Context) (res bool) {
+	k.paramstore.Get(ctx, types.KeyExRateHistory, &res)
+	return
+}
+
+// AutoCompoundInterval - Number of blocks between the compounding of the
+// rewards of auto-compounding delegations
+func (k Keeper) AutoCompoundInterval(ctx sdk.Context) (res int64) {
+	k.paramstore.Get(ctx, types.KeyAutoCompoundInterval, &res)
+	return
+}
+
+// ApplyMaxValidatorsSchedule sets MaxValidators to the value of the latest
+// schedule entry at or below the current height, if it differs, so that an
+// entry is still applied when its exact height is never processed.
+func (k Keeper) ApplyMaxValidatorsSchedule(ctx sdk.Context) {
+	found := false
+	var latest types.MaxValidatorsScheduleEntry
+	for _, entry := range k.MaxValidatorsSchedule(ctx) {
+		if entry.Height > ctx.BlockHeight() {
+			break
+		}
+		latest, found = entry, true
+	}
+	if found && latest.Max != k.MaxValidators(ctx) {
+		k.paramstore.Set(ctx, types.KeyMaxValidators, latest.Max)
+	}
+}
+
 // Get all parameteras as types.Params
 func (k Keeper) GetParams(ctx sdk.Context) types.Params {
 	return types.NewParams(
@@ -50,6 +188,24 @@ func (k Keeper) GetParams(ctx sdk.Context) types.Params {
 		k.MaxValidators(ctx),
 		k.MaxEntries(ctx),
 		k.BondDenom(ctx),
+		k.ShareRoundingMode(ctx),
+		k.InstantUnbond(ctx),
+		k.ValidatorUpdatesHistory(ctx),
+		k.MaxValidatorsCreatedPerBlock(ctx),
+		k.MaxDelegatorPowerShare(ctx),
+		k.MaxValidatorsSchedule(ctx),
+		k.LoyaltyWeighting(ctx),
+		k.LoyaltyPeriod(ctx),
+		k.MinSlashTokens(ctx),
+		k.BondedRatioHistory(ctx),
+		k.TieBreakMode(ctx),
+		k.AllowedPubKeyTypes(ctx),
+		k.MinCommissionRate(ctx),
+		k.MaxSlashPerInfraction(ctx),
+		k.SlashEventsHistory(ctx),
+		k.BondedTokensHistory(ctx),
+		k.ExRateHistory(ctx),
+		k.AutoCompoundInterval(ctx),
 	)
 }
 
@@ -57,3 +213,44 @@ func (k Keeper) GetParams(ctx sdk.Context) types.Params {
 func (k Keeper) SetParams(ctx sdk.Context, params types.Params) {
 	k.paramstore.SetParamSet(ctx, &params)
 }
+
+// SimulateParamChange returns which validators would bond and unbond at the
+// end of the block if the given params were in effect, along with the
+// resulting bonded ratio and next inflation rate. The next inflation rate is
+// zero unless an inflation estimator is set. State is not modified.
+func (k Keeper) SimulateParamChange(ctx sdk.Context, newParams types.Params) types.ParamChangeImpact {
+	lastValidators := make(map[string]bool)
+	k.IterateLastValidatorPowers(ctx, func(operator sdk.ValAddress, _ int64) bool {
+		lastValidators[operator.String()] = true
+		return false
+	})
+
+	// apply the params and the validator set updates in a cache-wrapped
+	// context which is never written
+	cacheCtx, _ := ctx.CacheContext()
+	k.SetParams(cacheCtx, newParams)
+	k.ApplyAndReturnValidatorSetUpdates(cacheCtx)
+
+	impact := types.ParamChangeImpact{
+		BondedRatio:   k.GetPool(cacheCtx).BondedRatio(),
+		NextInflation: sdk.ZeroDec(),
+	}
+	k.IterateLastValidatorPowers(cacheCtx, func(operator sdk.ValAddress, _ int64) bool {
+		if !lastValidators[operator.String()] {
+			impact.Bonding = append(impact.Bonding, operator)
+		}
+		delete(lastValidators, operator.String())
+		return false
+	})
+	k.IterateLastValidatorPowers(ctx, func(operator sdk.ValAddress, _ int64) bool {
+		if lastValidators[operator.String()] {
+			impact.Unbonding = append(impact.Unbonding, operator)
+		}
+		return false
+	})
+
+	if k.inflationEstimator != nil {
+		impact.NextInflation = k.inflationEstimator(cacheCtx, impact.BondedRatio)
+	}
+	return impact
+}
@@ -28,11 +28,28 @@ func (k Keeper) Slash(ctx sdk.Context, consAddr sdk.ConsAddress, infractionHeigh
 		panic(fmt.Errorf("attempted to slash with a negative slash factor: %v", slashFactor))
 	}
 
+	// cap the slash factor of a single infraction
+	maxSlash := k.MaxSlashPerInfraction(ctx)
+	if maxSlash.IsPositive() && slashFactor.GT(maxSlash) {
+		slashFactor = maxSlash
+	}
+
 	// Amount of slashing = slash slashFactor * power at time of infraction
 	amount := sdk.TokensFromTendermintPower(power)
 	slashAmountDec := amount.ToDec().Mul(slashFactor)
 	slashAmount := slashAmountDec.TruncateInt()
 
+	// a nonzero slash burns at least the minimum, capped at the validator's
+	// tokens below, so that small slashes don't round down to nothing
+	if slashFactor.IsPositive() {
+		slashAmount = sdk.MaxInt(slashAmount, k.MinSlashTokens(ctx))
+
+		// the floor doesn't lift a slash above the cap of a single infraction
+		if maxSlash.IsPositive() {
+			slashAmount = sdk.MinInt(slashAmount, amount.ToDec().Mul(maxSlash).TruncateInt())
+		}
+	}
+
 	// ref https://github.com/cosmos/cosmos-sdk/issues/1348
 
 	validator, found := k.GetValidatorByConsAddr(ctx, consAddr)
@@ -124,6 +141,10 @@ func (k Keeper) Slash(ctx sdk.Context, consAddr sdk.ConsAddress, infractionHeigh
 	pool.NotBondedTokens = pool.NotBondedTokens.Sub(tokensToBurn)
 	k.SetPool(ctx, pool)
 
+	// record the slash in the validator's history
+	k.appendValidatorSlashEvent(ctx, operatorAddress,
+		types.NewSlashEvent(ctx.BlockHeight(), slashFactor, tokensToBurn))
+
 	// Log that a slash occurred!
 	logger.Info(fmt.Sprintf(
 		"validator %s slashed by slash factor of %s; burned %v tokens",
@@ -133,6 +154,90 @@ func (k Keeper) Slash(ctx sdk.Context, consAddr sdk.ConsAddress, infractionHeigh
 	return
 }
 
+// SlashFractionForTargetPower returns the slash factor which, passed to Slash
+// for an infraction at the current height with the validator's current power,
+// reduces the validator to the target power. It returns zero if the validator
+// does not exist or its power is already at or below the target. A nonzero
+// MinSlashTokens may burn more than needed for small reductions.
+func (k Keeper) SlashFractionForTargetPower(ctx sdk.Context, ownerAddr sdk.ValAddress, targetPower int64) sdk.Dec {
+	validator, found := k.GetValidator(ctx, ownerAddr)
+	if !found {
+		return sdk.ZeroDec()
+	}
+	power := validator.GetTendermintPower()
+	if targetPower >= power {
+		return sdk.ZeroDec()
+	}
+	if targetPower < 0 {
+		targetPower = 0
+	}
+
+	// Slash burns the factor of the tokens for the given power, so burn down
+	// to exactly the tokens of the target power
+	excess := validator.Tokens.Sub(sdk.TokensFromTendermintPower(targetPower))
+	fraction := excess.ToDec().QuoInt(sdk.TokensFromTendermintPower(power))
+	return sdk.MinDec(fraction, sdk.OneDec())
+}
+
+// get the slash events recorded for a validator, oldest first
+func (k Keeper) GetValidatorSlashEvents(ctx sdk.Context, operatorAddr sdk.ValAddress) (events types.SlashEvents) {
+	store := ctx.KVStore(k.storeKey)
+	bz := store.Get(GetValidatorSlashEventsKey(operatorAddr))
+	if bz == nil {
+		return types.SlashEvents{}
+	}
+	return types.MustUnmarshalSlashEvents(k.cdc, bz)
+}
+
+// GetValidatorSlashedTokensSince returns the tokens burned from the validator
+// by the slashes applied at or after the given height.
+func (k Keeper) GetValidatorSlashedTokensSince(ctx sdk.Context, operatorAddr sdk.ValAddress, height int64) sdk.Int {
+	burned := sdk.ZeroInt()
+	for _, event := range k.GetValidatorSlashEvents(ctx, operatorAddr) {
+		if event.Height >= height {
+			burned = burned.Add(event.TokensBurned)
+		}
+	}
+	return burned
+}
+
+// set the slash events of a validator
+func (k Keeper) SetValidatorSlashEvents(ctx sdk.Context, operatorAddr sdk.ValAddress, events types.SlashEvents) {
+	store := ctx.KVStore(k.storeKey)
+	store.Set(GetValidatorSlashEventsKey(operatorAddr), types.MustMarshalSlashEvents(k.cdc, events))
+}
+
+// iterate through the slash events of all validators
+func (k Keeper) IterateValidatorSlashEvents(ctx sdk.Context,
+	fn func(operatorAddr sdk.ValAddress, events types.SlashEvents) (stop bool)) {
+
+	store := ctx.KVStore(k.storeKey)
+	iterator := sdk.KVStorePrefixIterator(store, ValidatorSlashEventsKey)
+	defer iterator.Close()
+
+	for ; iterator.Valid(); iterator.Next() {
+		operatorAddr := sdk.ValAddress(iterator.Key()[len(ValidatorSlashEventsKey):])
+		if fn(operatorAddr, types.MustUnmarshalSlashEvents(k.cdc, iterator.Value())) {
+			break
+		}
+	}
+}
+
+// append a slash event to the validator's slash history, dropping the events
+// older than the SlashEventsHistory parameter
+func (k Keeper) appendValidatorSlashEvent(ctx sdk.Context, operatorAddr sdk.ValAddress, event types.SlashEvent) {
+	events := append(k.GetValidatorSlashEvents(ctx, operatorAddr), event)
+	if retention := k.SlashEventsHistory(ctx); retention > 0 {
+		cutoff := ctx.BlockHeight() - int64(retention) + 1
+		pruned := 0
+		for pruned < len(events) && events[pruned].Height < cutoff {
+			pruned++
+		}
+		events = events[pruned:]
+	}
+	k.SetValidatorSlashEvents(ctx, operatorAddr, events)
+}
+
 // jail a validator
 func (k Keeper) Jail(ctx sdk.Context, consAddr sdk.ConsAddress) {
 	validator := k.mustGetValidatorByConsAddr(ctx, consAddr)
@@ -197,9 +302,9 @@ func (k Keeper) slashUnbondingDelegation(ctx sdk.Context, unbondingDelegation ty
 		k.SetUnbondingDelegation(ctx, unbondingDelegation)
 		pool := k.GetPool(ctx)
 
-		// Burn not-bonded tokens
+		// Burn unbonding tokens
 		// Ref https://github.com/cosmos/cosmos-sdk/pull/1278#discussion_r198657760
-		pool.NotBondedTokens = pool.NotBondedTokens.Sub(unbondingSlashAmount)
+		pool.UnbondingTokens = pool.UnbondingTokens.Sub(unbondingSlashAmount)
 		k.SetPool(ctx, pool)
 	}
 
@@ -211,6 +316,9 @@ func (k Keeper) slashUnbondingDelegation(ctx sdk.Context, unbondingDelegation ty
 // the unbonding delegation had enough stake to slash
 // (the amount actually slashed may be less if there's
 // insufficient stake remaining)
+// NOTE: only the direct destination is slashed; redelegations never chain
+// further, as a redelegation away from a validator is rejected while a
+// redelegation to it is still maturing (see ErrTransitiveRedelegation)
 // nolint: unparam
 func (k Keeper) slashRedelegation(ctx sdk.Context, validator types.Validator, redelegation types.Redelegation,
 	infractionHeight int64, slashFactor sdk.Dec) (totalSlashAmount sdk.Int) {
@@ -27,7 +27,7 @@ func setupHelper(t *testing.T, power int64) (sdk.Context, Keeper, types.Params)
 	// add numVals validators
 	for i := int64(0); i < numVals; i++ {
 		validator := types.NewValidator(addrVals[i], PKs[i], types.Description{})
-		validator, pool, _ = validator.AddTokensFromDel(pool, amt)
+		validator, pool, _ = validator.AddTokensFromDel(pool, amt, types.ShareRoundDown)
 		pool.BondedTokens = pool.BondedTokens.Add(amt)
 		keeper.SetPool(ctx, pool)
 		validator = TestingUpdateValidator(keeper, ctx, validator, true)
@@ -77,6 +77,7 @@ func TestSlashUnbondingDelegation(t *testing.T) {
 		time.Unix(5, 0), sdk.NewInt(10))
 
 	keeper.SetUnbondingDelegation(ctx, ubd)
+	keeper.SetPool(ctx, keeper.GetPool(ctx).NotBondedTokensToUnbonding(sdk.NewInt(10)))
 
 	// unbonding started prior to the infraction height, stakw didn't contribute
 	slashAmount := keeper.slashUnbondingDelegation(ctx, ubd, 1, fraction)
@@ -104,7 +105,8 @@ func TestSlashUnbondingDelegation(t *testing.T) {
 	// balance decreased
 	require.Equal(t, sdk.NewInt(5), ubd.Entries[0].Balance)
 	newPool := keeper.GetPool(ctx)
-	require.Equal(t, int64(5), oldPool.NotBondedTokens.Sub(newPool.NotBondedTokens).Int64())
+	require.Equal(t, int64(5), oldPool.UnbondingTokens.Sub(newPool.UnbondingTokens).Int64())
+	require.Equal(t, oldPool.NotBondedTokens, newPool.NotBondedTokens)
 }
 
 // tests slashRedelegation
@@ -202,6 +204,91 @@ func TestSlashAtNegativeHeight(t *testing.T) {
 	require.Equal(t, sdk.TokensFromTendermintPower(5), oldPool.BondedTokens.Sub(newPool.BondedTokens))
 }
 
+// tests the minimum number of tokens burned by a slash
+func TestSlashMinSlashTokens(t *testing.T) {
+	ctx, keeper, params := setupHelper(t, 1)
+	consAddr := sdk.ConsAddress(PKs[0].Address())
+	tokens := sdk.TokensFromTendermintPower(1)
+	// a fraction which rounds to zero tokens
+	fraction := sdk.NewDecWithPrec(1, 7)
+
+	keeper.Slash(ctx, consAddr, ctx.BlockHeight(), 1, fraction)
+	validator, found := keeper.GetValidatorByConsAddr(ctx, consAddr)
+	require.True(t, found)
+	require.Equal(t, tokens, validator.GetTokens())
+
+	// the floor is burned instead
+	params.MinSlashTokens = sdk.NewInt(100)
+	keeper.SetParams(ctx, params)
+	oldPool := keeper.GetPool(ctx)
+	keeper.Slash(ctx, consAddr, ctx.BlockHeight(), 1, fraction)
+	validator, found = keeper.GetValidatorByConsAddr(ctx, consAddr)
+	require.True(t, found)
+	require.Equal(t, tokens.SubRaw(100), validator.GetTokens())
+	require.Equal(t, sdk.NewInt(100), oldPool.TokenSupply().Sub(keeper.GetPool(ctx).TokenSupply()))
+
+	// a zero fraction still burns nothing
+	keeper.Slash(ctx, consAddr, ctx.BlockHeight(), 1, sdk.ZeroDec())
+	validator, found = keeper.GetValidatorByConsAddr(ctx, consAddr)
+	require.True(t, found)
+	require.Equal(t, tokens.SubRaw(100), validator.GetTokens())
+
+	// the floor is capped at the validator's tokens
+	params.MinSlashTokens = tokens.MulRaw(2)
+	keeper.SetParams(ctx, params)
+	keeper.Slash(ctx, consAddr, ctx.BlockHeight(), 1, fraction)
+	validator, found = keeper.GetValidatorByConsAddr(ctx, consAddr)
+	require.True(t, found)
+	require.True(t, validator.GetTokens().IsZero())
+}
+
+func TestSlashMaxSlashPerInfraction(t *testing.T) {
+	ctx, keeper, params := setupHelper(t, 10)
+	consAddr := sdk.ConsAddress(PKs[0].Address())
+	tokens := sdk.TokensFromTendermintPower(10)
+
+	// a slash above the cap burns only the capped fraction
+	params.MaxSlashPerInfraction = sdk.NewDecWithPrec(1, 1)
+	keeper.SetParams(ctx, params)
+	oldPool := keeper.GetPool(ctx)
+	keeper.Slash(ctx, consAddr, ctx.BlockHeight(), 10, sdk.NewDecWithPrec(5, 1))
+	validator, found := keeper.GetValidatorByConsAddr(ctx, consAddr)
+	require.True(t, found)
+	burned := tokens.QuoRaw(10)
+	require.Equal(t, tokens.Sub(burned), validator.GetTokens())
+	require.Equal(t, burned, oldPool.TokenSupply().Sub(keeper.GetPool(ctx).TokenSupply()))
+	events := keeper.GetValidatorSlashEvents(ctx, validator.OperatorAddress)
+	require.Len(t, events, 1)
+	require.Equal(t, params.MaxSlashPerInfraction, events[0].Fraction)
+
+	// a slash below the cap is left as is
+	keeper.Slash(ctx, consAddr, ctx.BlockHeight(), 10, sdk.NewDecWithPrec(5, 2))
+	validator, _ = keeper.GetValidatorByConsAddr(ctx, consAddr)
+	require.Equal(t, tokens.Sub(burned).Sub(tokens.QuoRaw(20)), validator.GetTokens())
+
+	// the cap is a fraction
+	params.MaxSlashPerInfraction = sdk.NewDec(2)
+	require.NotNil(t, params.Validate())
+}
+
+// tests that the minimum slash doesn't exceed the cap of an infraction
+func TestSlashMinSlashTokensCapped(t *testing.T) {
+	ctx, keeper, params := setupHelper(t, 10)
+	consAddr := sdk.ConsAddress(PKs[0].Address())
+	tokens := sdk.TokensFromTendermintPower(10)
+
+	// the cap allows 10 tokens, less than the floor
+	params.MinSlashTokens = sdk.NewInt(100)
+	params.MaxSlashPerInfraction = sdk.NewDecWithPrec(1, 6)
+	keeper.SetParams(ctx, params)
+	oldPool := keeper.GetPool(ctx)
+	keeper.Slash(ctx, consAddr, ctx.BlockHeight(), 10, sdk.NewDecWithPrec(1, 7))
+	validator, found := keeper.GetValidatorByConsAddr(ctx, consAddr)
+	require.True(t, found)
+	require.Equal(t, tokens.SubRaw(10), validator.GetTokens())
+	require.Equal(t, sdk.NewInt(10), oldPool.TokenSupply().Sub(keeper.GetPool(ctx).TokenSupply()))
+}
+
 // tests Slash at the current height
 func TestSlashValidatorAtCurrentHeight(t *testing.T) {
 	ctx, keeper, _ := setupHelper(t, 10)
@@ -229,6 +316,58 @@ func TestSlashValidatorAtCurrentHeight(t *testing.T) {
 	require.Equal(t, sdk.TokensFromTendermintPower(5), oldPool.BondedTokens.Sub(newPool.BondedTokens))
 }
 
+// tests that each slash is recorded in the validator's slash events
+func TestSlashEvents(t *testing.T) {
+	ctx, keeper, _ := setupHelper(t, 10)
+	consAddr := sdk.ConsAddress(PKs[0].Address())
+	require.Empty(t, keeper.GetValidatorSlashEvents(ctx, addrVals[0]))
+
+	firstFraction := sdk.NewDecWithPrec(5, 1)
+	keeper.Slash(ctx, consAddr, ctx.BlockHeight(), 10, firstFraction)
+
+	ctx = ctx.WithBlockHeight(ctx.BlockHeight() + 1)
+	secondFraction := sdk.NewDecWithPrec(1, 1)
+	keeper.Slash(ctx, consAddr, ctx.BlockHeight(), 5, secondFraction)
+
+	events := keeper.GetValidatorSlashEvents(ctx, addrVals[0])
+	require.Len(t, events, 2)
+	require.Equal(t, ctx.BlockHeight()-1, events[0].Height)
+	require.True(sdk.DecEq(t, firstFraction, events[0].Fraction))
+	require.True(sdk.IntEq(t, sdk.TokensFromTendermintPower(5), events[0].TokensBurned))
+	require.Equal(t, ctx.BlockHeight(), events[1].Height)
+	require.True(sdk.DecEq(t, secondFraction, events[1].Fraction))
+	require.True(sdk.IntEq(t, sdk.TokensFromTendermintPower(5).QuoRaw(10), events[1].TokensBurned))
+
+	// other validators are unaffected
+	require.Empty(t, keeper.GetValidatorSlashEvents(ctx, addrVals[1]))
+}
+
+func TestSlashEventsHistory(t *testing.T) {
+	ctx, keeper, params := setupHelper(t, 10)
+	consAddr := sdk.ConsAddress(PKs[0].Address())
+	params.SlashEventsHistory = 10
+	keeper.SetParams(ctx, params)
+
+	fraction := sdk.NewDecWithPrec(1, 2)
+	for _, height := range []int64{1, 5, 12} {
+		ctx = ctx.WithBlockHeight(height)
+		keeper.Slash(ctx, consAddr, height, 10, fraction)
+	}
+
+	// the event at height 1 left the window of the last 10 blocks
+	events := keeper.GetValidatorSlashEvents(ctx, addrVals[0])
+	require.Len(t, events, 2)
+	require.Equal(t, int64(5), events[0].Height)
+	require.Equal(t, int64(12), events[1].Height)
+
+	// a zero history keeps every event
+	params.SlashEventsHistory = 0
+	keeper.SetParams(ctx, params)
+	ctx = ctx.WithBlockHeight(100)
+	keeper.Slash(ctx, consAddr, 100, 10, fraction)
+	require.Len(t, keeper.GetValidatorSlashEvents(ctx, addrVals[0]), 3)
+}
+
 // tests Slash at a previous height with an unbonding delegation
 func TestSlashWithUnbondingDelegation(t *testing.T) {
 	ctx, keeper, _ := setupHelper(t, 10)
@@ -335,6 +474,41 @@ func TestSlashWithUnbondingDelegation(t *testing.T) {
 	require.Equal(t, validator.GetStatus(), sdk.Unbonding)
 }
 
+// tests the payout of an unbonding delegation slashed after it began
+func TestSlashUnbondingPayout(t *testing.T) {
+	ctx, keeper, _ := setupHelper(t, 10)
+	consAddr := sdk.ConsAddress(PKs[0].Address())
+	fraction := sdk.NewDecWithPrec(5, 1)
+
+	// delegate and begin unbonding at height 11
+	validator, found := keeper.GetValidator(ctx, addrVals[0])
+	require.True(t, found)
+	delTokens := sdk.TokensFromTendermintPower(4)
+	_, err := keeper.Delegate(ctx, addrDels[0], delTokens, validator, true)
+	require.Nil(t, err)
+	ctx = ctx.WithBlockHeight(11)
+	delegation, found := keeper.GetDelegation(ctx, addrDels[0], addrVals[0])
+	require.True(t, found)
+	_, err = keeper.Undelegate(ctx, addrDels[0], addrVals[0], delegation.Shares)
+	require.Nil(t, err)
+
+	payout, found := keeper.GetUnbondingPayout(ctx, addrDels[0], addrVals[0], 11)
+	require.True(t, found)
+	require.Equal(t, delTokens, payout)
+	_, found = keeper.GetUnbondingPayout(ctx, addrDels[0], addrVals[0], 10)
+	require.False(t, found)
+	_, found = keeper.GetUnbondingPayout(ctx, addrDels[1], addrVals[0], 11)
+	require.False(t, found)
+
+	// slash the validator for an infraction before the unbonding began
+	ctx = ctx.WithBlockHeight(12)
+	keeper.Slash(ctx, consAddr, 10, 10, fraction)
+
+	payout, found = keeper.GetUnbondingPayout(ctx, addrDels[0], addrVals[0], 11)
+	require.True(t, found)
+	require.Equal(t, sdk.TokensFromTendermintPower(2), payout)
+}
+
 // tests Slash at a previous height with a redelegation
 func TestSlashWithRedelegation(t *testing.T) {
 	ctx, keeper, _ := setupHelper(t, 10)
@@ -466,6 +640,7 @@ func TestSlashBoth(t *testing.T) {
 	ubdA := types.NewUnbondingDelegation(addrDels[0], addrVals[0], 11,
 		time.Unix(0, 0), ubdATokens)
 	keeper.SetUnbondingDelegation(ctx, ubdA)
+	keeper.SetPool(ctx, keeper.GetPool(ctx).NotBondedTokensToUnbonding(ubdATokens))
 
 	// slash validator
 	ctx = ctx.WithBlockHeight(12)
@@ -481,8 +656,8 @@ func TestSlashBoth(t *testing.T) {
 	require.Len(t, rdA.Entries, 1)
 	// read updated pool
 	newPool := keeper.GetPool(ctx)
-	// not-bonded tokens burned
-	require.Equal(t, sdk.TokensFromTendermintPower(2), oldPool.NotBondedTokens.Sub(newPool.NotBondedTokens))
+	// unbonding tokens burned
+	require.Equal(t, sdk.TokensFromTendermintPower(2), oldPool.UnbondingTokens.Sub(newPool.UnbondingTokens))
 	// bonded tokens burned
 	require.Equal(t, sdk.TokensFromTendermintPower(3), oldPool.BondedTokens.Sub(newPool.BondedTokens))
 	// read updated validator
@@ -491,3 +666,31 @@ func TestSlashBoth(t *testing.T) {
 	// power not decreased, all stake was bonded since
 	require.Equal(t, int64(10), validator.GetTendermintPower())
 }
+
+// tests that slashing by the computed fraction reduces a validator to the target power
+func TestSlashFractionForTargetPower(t *testing.T) {
+	ctx, keeper, _ := setupHelper(t, 10)
+	addr := addrVals[0]
+	consAddr := sdk.ConsAddress(PKs[0].Address())
+
+	// already at or above the target
+	require.True(t, keeper.SlashFractionForTargetPower(ctx, addr, 10).IsZero())
+	require.True(t, keeper.SlashFractionForTargetPower(ctx, addr, 15).IsZero())
+	require.True(t, keeper.SlashFractionForTargetPower(ctx, addrVals[4], 1).IsZero())
+
+	fraction := keeper.SlashFractionForTargetPower(ctx, addr, 7)
+	require.True(t, sdk.NewDecWithPrec(3, 1).Equal(fraction), "expected 0.3, got %v", fraction)
+
+	validator := keeper.mustGetValidator(ctx, addr)
+	keeper.Slash(ctx, consAddr, ctx.BlockHeight(), validator.GetTendermintPower(), fraction)
+	validator = keeper.mustGetValidator(ctx, addr)
+	require.Equal(t, int64(7), validator.GetTendermintPower())
+	require.Equal(t, sdk.TokensFromTendermintPower(7), validator.Tokens)
+
+	// a target of zero burns every token
+	fraction = keeper.SlashFractionForTargetPower(ctx, addr, 0)
+	require.True(t, sdk.OneDec().Equal(fraction), "expected 1, got %v", fraction)
+	keeper.Slash(ctx, consAddr, ctx.BlockHeight(), validator.GetTendermintPower(), fraction)
+	validator = keeper.mustGetValidator(ctx, addr)
+	require.Equal(t, int64(0), validator.GetTendermintPower())
+}
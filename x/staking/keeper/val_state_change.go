@@ -2,10 +2,14 @@ package keeper
 
 import (
 	"bytes"
+	"encoding/binary"
 	"fmt"
+	"math"
 	"sort"
+	"time"
 
 	abci "github.com/tendermint/tendermint/abci/types"
+	tmtypes "github.com/tendermint/tendermint/types"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/x/staking/types"
@@ -26,7 +30,7 @@ import (
 func (k Keeper) ApplyAndReturnValidatorSetUpdates(ctx sdk.Context) (updates []abci.ValidatorUpdate) {
 
 	store := ctx.KVStore(k.storeKey)
-	maxValidators := k.GetParams(ctx).MaxValidators
+	maxValidators := k.MaxValidators(ctx)
 	totalPower := sdk.ZeroInt()
 
 	// Retrieve the last validator set.
@@ -117,6 +121,82 @@ func (k Keeper) ApplyAndReturnValidatorSetUpdates(ctx sdk.Context) (updates []ab
 	return updates
 }
 
+// ProjectedValidatorStatus returns the status and power a validator will have
+// once the validator set updates of the current block are applied at the end
+// of the block, following the same selection as
+// ApplyAndReturnValidatorSetUpdates. It does not modify state.
+func (k Keeper) ProjectedValidatorStatus(ctx sdk.Context, valAddr sdk.ValAddress) (status sdk.BondStatus, power int64) {
+	store := ctx.KVStore(k.storeKey)
+	maxValidators := k.MaxValidators(ctx)
+
+	iterator := sdk.KVStoreReversePrefixIterator(store, ValidatorsByPowerIndexKey)
+	defer iterator.Close()
+	for count := 0; iterator.Valid() && count < int(maxValidators); iterator.Next() {
+		validator := k.mustGetValidator(ctx, iterator.Value())
+		if validator.PotentialTendermintPower() == 0 {
+			break
+		}
+		if validator.OperatorAddress.Equals(valAddr) {
+			return sdk.Bonded, validator.PotentialTendermintPower()
+		}
+		count++
+	}
+
+	// the validator is not part of the next bonded set
+	validator := k.mustGetValidator(ctx, valAddr)
+	if validator.Status == sdk.Bonded {
+		return sdk.Unbonding, 0
+	}
+	return validator.Status, 0
+}
+
+// GetValidatorsWithPowerChange returns the validators which have a pending
+// Tendermint power update, i.e. those part of the validator set updates
+// applied at the end of the current block, in the order of the updates. It
+// does not modify state.
+func (k Keeper) GetValidatorsWithPowerChange(ctx sdk.Context) (validators []types.Validator) {
+	// compute the updates in a cache-wrapped context which is never written
+	cacheCtx, _ := ctx.CacheContext()
+	updates := k.ApplyAndReturnValidatorSetUpdates(cacheCtx)
+
+	for _, update := range updates {
+		validators = append(validators, k.mustGetValidatorByUpdate(ctx, update))
+	}
+	return validators
+}
+
+// GetPendingPowerDelta returns the sum of the absolute changes of Tendermint
+// power in the validator set updates applied at the end of the current block,
+// a measure of the volatility of the validator set. It does not modify state.
+func (k Keeper) GetPendingPowerDelta(ctx sdk.Context) (delta int64) {
+	// compute the updates in a cache-wrapped context which is never written
+	cacheCtx, _ := ctx.CacheContext()
+	updates := k.ApplyAndReturnValidatorSetUpdates(cacheCtx)
+
+	for _, update := range updates {
+		validator := k.mustGetValidatorByUpdate(ctx, update)
+		change := update.Power - k.GetLastValidatorPower(ctx, validator.OperatorAddress)
+		if change < 0 {
+			change = -change
+		}
+		delta += change
+	}
+	return delta
+}
+
+// get the validator a validator set update applies to
+func (k Keeper) mustGetValidatorByUpdate(ctx sdk.Context, update abci.ValidatorUpdate) types.Validator {
+	pubKey, err := tmtypes.PB2TM.PubKey(update.PubKey)
+	if err != nil {
+		panic(err)
+	}
+	validator, found := k.GetValidatorByConsAddr(ctx, sdk.ConsAddress(pubKey.Address()))
+	if !found {
+		panic(fmt.Sprintf("validator with pending update not found: %X", pubKey.Address()))
+	}
+	return validator
+}
+
 // Validator state transitions
 
 func (k Keeper) bondedToUnbonding(ctx sdk.Context, validator types.Validator) types.Validator {
@@ -197,8 +277,6 @@ func (k Keeper) bondValidator(ctx sdk.Context, validator types.Validator) types.
 // perform all the store operations for when a validator begins unbonding
 func (k Keeper) beginUnbondingValidator(ctx sdk.Context, validator types.Validator) types.Validator {
 
-	params := k.GetParams(ctx)
-
 	// delete the validator by power index, as the key will change
 	k.DeleteValidatorByPowerIndex(ctx, validator)
 
@@ -213,7 +291,7 @@ func (k Keeper) beginUnbondingValidator(ctx sdk.Context, validator types.Validat
 	k.SetPool(ctx, pool)
 
 	// set the unbonding completion time and completion height appropriately
-	validator.UnbondingCompletionTime = ctx.BlockHeader().Time.Add(params.UnbondingTime)
+	validator.UnbondingCompletionTime = ctx.BlockHeader().Time.Add(k.ValidatorUnbondingTime(ctx, validator))
 	validator.UnbondingHeight = ctx.BlockHeader().Height
 
 	// save the now unbonded validator record and power index
@@ -279,3 +357,214 @@ func sortNoLongerBonded(last validatorsByAddr) [][]byte {
 	})
 	return noLongerBonded
 }
+
+// RecordValidatorSetUpdates stores the validator set updates produced at the
+// current block height, for light clients tracking the validator set, and
+// prunes the updates which are older than the ValidatorUpdatesHistory
+// parameter. A zero history disables the record.
+func (k Keeper) RecordValidatorSetUpdates(ctx sdk.Context, updates []abci.ValidatorUpdate) {
+	retention := k.ValidatorUpdatesHistory(ctx)
+	if retention == 0 {
+		return
+	}
+
+	store := ctx.KVStore(k.storeKey)
+	height := ctx.BlockHeight()
+	if len(updates) > 0 {
+		store.Set(GetValidatorUpdatesHistoryKey(height), k.cdc.MustMarshalBinaryLengthPrefixed(updates))
+	}
+
+	// prune the updates which left the retention window
+	cutoff := height - int64(retention) + 1
+	if cutoff <= 0 {
+		return
+	}
+	deleteRange(store, ValidatorUpdatesHistoryKey, GetValidatorUpdatesHistoryKey(cutoff))
+}
+
+// GetValidatorSetUpdatesInRange returns the recorded validator set updates
+// between the given heights, inclusive, keyed by the height they were produced
+// at. Heights without any update are omitted.
+func (k Keeper) GetValidatorSetUpdatesInRange(ctx sdk.Context,
+	fromHeight, toHeight int64) map[int64][]abci.ValidatorUpdate {
+
+	updates := make(map[int64][]abci.ValidatorUpdate)
+	if fromHeight < 0 {
+		fromHeight = 0
+	}
+	if toHeight < fromHeight {
+		return updates
+	}
+
+	store := ctx.KVStore(k.storeKey)
+	end := sdk.PrefixEndBytes(ValidatorUpdatesHistoryKey)
+	if toHeight < math.MaxInt64 {
+		end = GetValidatorUpdatesHistoryKey(toHeight + 1)
+	}
+	iterator := store.Iterator(GetValidatorUpdatesHistoryKey(fromHeight), end)
+	defer iterator.Close()
+
+	for ; iterator.Valid(); iterator.Next() {
+		var blockUpdates []abci.ValidatorUpdate
+		k.cdc.MustUnmarshalBinaryLengthPrefixed(iterator.Value(), &blockUpdates)
+		height := int64(binary.BigEndian.Uint64(iterator.Key()[len(ValidatorUpdatesHistoryKey):]))
+		updates[height] = blockUpdates
+	}
+	return updates
+}
+
+// RecordValidatorBondedTokens stores the bonded tokens of every bonded
+// validator at the current block height if the BondedTokensHistory parameter
+// is set, as a data source for slashing at the stake of the infraction height.
+// Heights recorded longer than the longest unbonding time of any validator ago
+// are pruned, as infractions that old can no longer be slashed.
+func (k Keeper) RecordValidatorBondedTokens(ctx sdk.Context) {
+	if !k.BondedTokensHistory(ctx) {
+		return
+	}
+
+	store := ctx.KVStore(k.storeKey)
+	height := ctx.BlockHeight()
+	for _, validator := range k.GetLastValidators(ctx) {
+		bz := k.cdc.MustMarshalBinaryLengthPrefixed(validator.BondedTokens())
+		store.Set(GetValidatorBondedTokensKey(height, validator.OperatorAddress), bz)
+	}
+	store.Set(GetBondedTokensTimeKey(height), k.cdc.MustMarshalBinaryLengthPrefixed(ctx.BlockHeader().Time))
+
+	k.pruneValidatorHistory(ctx, BondedTokensTimeKey, ValidatorBondedTokensKey)
+}
+
+// RecordValidatorExRates stores the exchange rate of every bonded validator at
+// the current block height if the ExRateHistory parameter is set. Heights
+// recorded longer than the longest unbonding time of any validator ago are
+// pruned.
+func (k Keeper) RecordValidatorExRates(ctx sdk.Context) {
+	if !k.ExRateHistory(ctx) {
+		return
+	}
+
+	store := ctx.KVStore(k.storeKey)
+	height := ctx.BlockHeight()
+	for _, validator := range k.GetLastValidators(ctx) {
+		bz := k.cdc.MustMarshalBinaryLengthPrefixed(validator.TokensFromShares(sdk.OneDec()))
+		store.Set(GetValidatorExRateKey(height, validator.OperatorAddress), bz)
+	}
+	store.Set(GetExRateTimeKey(height), k.cdc.MustMarshalBinaryLengthPrefixed(ctx.BlockHeader().Time))
+
+	k.pruneValidatorHistory(ctx, ExRateTimeKey, ValidatorExRateKey)
+}
+
+// pruneValidatorHistory deletes the per validator records under recordsPrefix
+// of the heights whose block time, stored under timePrefix, left the unbonding
+// window of every validator, including those with a longer unbonding time
+// override
+func (k Keeper) pruneValidatorHistory(ctx sdk.Context, timePrefix, recordsPrefix []byte) {
+	store := ctx.KVStore(k.storeKey)
+	cutoff := ctx.BlockHeader().Time.Add(-k.maxValidatorUnbondingTime(ctx))
+	iterator := sdk.KVStorePrefixIterator(store, timePrefix)
+	var heightKeys [][]byte
+	for ; iterator.Valid(); iterator.Next() {
+		var recordTime time.Time
+		k.cdc.MustUnmarshalBinaryLengthPrefixed(iterator.Value(), &recordTime)
+		if !recordTime.Before(cutoff) {
+			break
+		}
+		heightKeys = append(heightKeys, iterator.Key()[len(timePrefix):])
+	}
+	iterator.Close()
+
+	for _, heightKey := range heightKeys {
+		var keys [][]byte
+		prefixIterator := sdk.KVStorePrefixIterator(store, append(append([]byte{}, recordsPrefix...), heightKey...))
+		for ; prefixIterator.Valid(); prefixIterator.Next() {
+			keys = append(keys, prefixIterator.Key())
+		}
+		prefixIterator.Close()
+		for _, key := range keys {
+			store.Delete(key)
+		}
+		store.Delete(append(append([]byte{}, timePrefix...), heightKey...))
+	}
+}
+
+// GetValidatorBondedTokensAtHeight returns the bonded tokens of the validator
+// at the end of the given block height. It returns false if the validator was
+// not bonded at that height or the height left the unbonding window.
+func (k Keeper) GetValidatorBondedTokensAtHeight(ctx sdk.Context, ownerAddr sdk.ValAddress, height int64) (sdk.Int, bool) {
+	if height < 0 {
+		return sdk.ZeroInt(), false
+	}
+
+	store := ctx.KVStore(k.storeKey)
+	bz := store.Get(GetValidatorBondedTokensKey(height, ownerAddr))
+	if bz == nil {
+		return sdk.ZeroInt(), false
+	}
+
+	var tokens sdk.Int
+	k.cdc.MustUnmarshalBinaryLengthPrefixed(bz, &tokens)
+	return tokens, true
+}
+
+// GetValidatorExRateAtHeight returns the tokens per delegator share of the
+// validator at the end of the given block height. It returns false if the
+// validator was not bonded at that height or the height left the unbonding
+// window.
+func (k Keeper) GetValidatorExRateAtHeight(ctx sdk.Context, ownerAddr sdk.ValAddress, height int64) (sdk.Dec, bool) {
+	if height < 0 {
+		return sdk.ZeroDec(), false
+	}
+
+	store := ctx.KVStore(k.storeKey)
+	bz := store.Get(GetValidatorExRateKey(height, ownerAddr))
+	if bz == nil {
+		return sdk.ZeroDec(), false
+	}
+
+	var exRate sdk.Dec
+	k.cdc.MustUnmarshalBinaryLengthPrefixed(bz, &exRate)
+	return exRate, true
+}
+
+// RecordValidatorSetSize stores the size of the bonded validator set at the
+// current block height if it differs from the last recorded size, so that the
+// records form a history of the changes of the set size.
+func (k Keeper) RecordValidatorSetSize(ctx sdk.Context) {
+	var size int64
+	iterator := k.LastValidatorsIterator(ctx)
+	for ; iterator.Valid(); iterator.Next() {
+		size++
+	}
+	iterator.Close()
+
+	height := ctx.BlockHeight()
+	if last, found := k.GetValidatorSetSizeAtHeight(ctx, height); found && int64(last) == size {
+		return
+	}
+	store := ctx.KVStore(k.storeKey)
+	store.Set(GetValidatorSetSizeKey(height), k.cdc.MustMarshalBinaryLengthPrefixed(size))
+}
+
+// GetValidatorSetSizeAtHeight returns the size of the bonded validator set at
+// the end of the given block height, i.e. the size recorded at the latest
+// change at or before it. It returns false if no size was recorded by then.
+func (k Keeper) GetValidatorSetSizeAtHeight(ctx sdk.Context, height int64) (int, bool) {
+	if height < 0 {
+		return 0, false
+	}
+
+	store := ctx.KVStore(k.storeKey)
+	end := sdk.PrefixEndBytes(ValidatorSetSizeKey)
+	if height < math.MaxInt64 {
+		end = GetValidatorSetSizeKey(height + 1)
+	}
+	iterator := store.ReverseIterator(ValidatorSetSizeKey, end)
+	defer iterator.Close()
+	if !iterator.Valid() {
+		return 0, false
+	}
+
+	var size int64
+	k.cdc.MustUnmarshalBinaryLengthPrefixed(iterator.Value(), &size)
+	return int(size), true
+}
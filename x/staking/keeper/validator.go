@@ -58,6 +58,13 @@ func (k Keeper) GetValidator(ctx sdk.Context, addr sdk.ValAddress) (validator ty
 	return validator, true
 }
 
+// IsValidator returns whether the address operates a validator, without
+// unmarshalling the validator record.
+func (k Keeper) IsValidator(ctx sdk.Context, addr sdk.ValAddress) bool {
+	store := ctx.KVStore(k.storeKey)
+	return store.Has(GetValidatorKey(addr))
+}
+
 func (k Keeper) mustGetValidator(ctx sdk.Context, addr sdk.ValAddress) types.Validator {
 	validator, found := k.GetValidator(ctx, addr)
 	if !found {
@@ -105,19 +112,77 @@ func (k Keeper) SetValidatorByPowerIndex(ctx sdk.Context, validator types.Valida
 		return
 	}
 	store := ctx.KVStore(k.storeKey)
-	store.Set(GetValidatorsByPowerIndexKey(validator), validator.OperatorAddress)
+	store.Set(GetValidatorsByPowerIndexKey(validator, k.GetPowerIndexTieBreak(ctx)), validator.OperatorAddress)
 }
 
 // validator index
 func (k Keeper) DeleteValidatorByPowerIndex(ctx sdk.Context, validator types.Validator) {
 	store := ctx.KVStore(k.storeKey)
-	store.Delete(GetValidatorsByPowerIndexKey(validator))
+	store.Delete(GetValidatorsByPowerIndexKey(validator, k.GetPowerIndexTieBreak(ctx)))
 }
 
 // validator index
 func (k Keeper) SetNewValidatorByPowerIndex(ctx sdk.Context, validator types.Validator) {
 	store := ctx.KVStore(k.storeKey)
-	store.Set(GetValidatorsByPowerIndexKey(validator), validator.OperatorAddress)
+	store.Set(GetValidatorsByPowerIndexKey(validator, k.GetPowerIndexTieBreak(ctx)), validator.OperatorAddress)
+}
+
+// GetPowerIndexTieBreak returns the tie-break mode the power index is
+// currently sorted by. It trails the TieBreakMode param until the index is
+// rebuilt at the end of the block.
+func (k Keeper) GetPowerIndexTieBreak(ctx sdk.Context) (mode types.TieBreakMode) {
+	store := ctx.KVStore(k.storeKey)
+	bz := store.Get(PowerIndexTieBreakKey)
+	if bz == nil {
+		return types.TieBreakStakeAge
+	}
+	k.cdc.MustUnmarshalBinaryLengthPrefixed(bz, &mode)
+	return mode
+}
+
+// ApplyTieBreakMode rebuilds the power index if the TieBreakMode param
+// differs from the mode the index is sorted by.
+//
+// NOTE: A store without a recorded mode holds a power index written before
+// the keys carried the tie-break field (prefix || power || address), which
+// the current keys can neither parse nor delete. It is rebuilt once as well.
+func (k Keeper) ApplyTieBreakMode(ctx sdk.Context) {
+	mode := k.TieBreakMode(ctx)
+	store := ctx.KVStore(k.storeKey)
+	if store.Has(PowerIndexTieBreakKey) && mode == k.GetPowerIndexTieBreak(ctx) {
+		return
+	}
+
+	iterator := sdk.KVStorePrefixIterator(store, ValidatorsByPowerIndexKey)
+	var keys [][]byte
+	for ; iterator.Valid(); iterator.Next() {
+		keys = append(keys, iterator.Key())
+	}
+	iterator.Close()
+
+	for _, key := range keys {
+		store.Delete(key)
+	}
+	store.Set(PowerIndexTieBreakKey, k.cdc.MustMarshalBinaryLengthPrefixed(mode))
+
+	// rebuild from the stored validators, the index may still hold entries of
+	// validators the old keys failed to delete
+	for _, validator := range k.GetAllValidators(ctx) {
+		k.SetValidatorByPowerIndex(ctx, validator)
+	}
+}
+
+// nextPowerSequence returns the sequence number of a change of the tokens of a
+// validator, increasing with the block height and the order of the changes
+// within the block
+func (k Keeper) nextPowerSequence(ctx sdk.Context) uint64 {
+	store := ctx.TransientStore(k.storeTKey)
+	var count uint32
+	if bz := store.Get(PowerChangeCountKey); bz != nil {
+		k.cdc.MustUnmarshalBinaryLengthPrefixed(bz, &count)
+	}
+	store.Set(PowerChangeCountKey, k.cdc.MustMarshalBinaryLengthPrefixed(count+1))
+	return uint64(ctx.BlockHeight())<<32 | uint64(count)
 }
 
 // Update the tokens of an existing validator, update the validators power index key
@@ -126,7 +191,8 @@ func (k Keeper) AddValidatorTokensAndShares(ctx sdk.Context, validator types.Val
 
 	k.DeleteValidatorByPowerIndex(ctx, validator)
 	pool := k.GetPool(ctx)
-	validator, pool, addedShares = validator.AddTokensFromDel(pool, tokensToAdd)
+	validator, pool, addedShares = validator.AddTokensFromDel(pool, tokensToAdd, k.ShareRoundingMode(ctx))
+	validator.PowerSequence = k.nextPowerSequence(ctx)
 	k.SetValidator(ctx, validator)
 	k.SetPool(ctx, pool)
 	k.SetValidatorByPowerIndex(ctx, validator)
@@ -139,7 +205,8 @@ func (k Keeper) RemoveValidatorTokensAndShares(ctx sdk.Context, validator types.
 
 	k.DeleteValidatorByPowerIndex(ctx, validator)
 	pool := k.GetPool(ctx)
-	validator, pool, removedTokens = validator.RemoveDelShares(pool, sharesToRemove)
+	validator, pool, removedTokens = validator.RemoveDelShares(pool, sharesToRemove, k.ShareRoundingMode(ctx))
+	validator.PowerSequence = k.nextPowerSequence(ctx)
 	k.SetValidator(ctx, validator)
 	k.SetPool(ctx, pool)
 	k.SetValidatorByPowerIndex(ctx, validator)
@@ -153,6 +220,7 @@ func (k Keeper) RemoveValidatorTokens(ctx sdk.Context,
 	k.DeleteValidatorByPowerIndex(ctx, validator)
 	pool := k.GetPool(ctx)
 	validator, pool = validator.RemoveTokens(pool, tokensToRemove)
+	validator.PowerSequence = k.nextPowerSequence(ctx)
 	k.SetValidator(ctx, validator)
 	k.SetPool(ctx, pool)
 	k.SetValidatorByPowerIndex(ctx, validator)
@@ -170,6 +238,9 @@ func (k Keeper) UpdateValidatorCommission(ctx sdk.Context,
 	if err := commission.ValidateNewRate(newRate, blockTime); err != nil {
 		return commission, err
 	}
+	if minRate := k.MinCommissionRate(ctx); newRate.LT(minRate) {
+		return commission, types.ErrCommissionBelowMinimum(k.Codespace(), minRate)
+	}
 
 	commission.Rate = newRate
 	commission.UpdateTime = blockTime
@@ -177,6 +248,92 @@ func (k Keeper) UpdateValidatorCommission(ctx sdk.Context,
 	return commission, nil
 }
 
+// SetValidatorIdentityVerified stores the result of an external check (ex.
+// Keybase) of a validator's identity. The status is reset when the validator
+// changes its identity.
+func (k Keeper) SetValidatorIdentityVerified(ctx sdk.Context, address sdk.ValAddress, verified bool) sdk.Error {
+	validator, found := k.GetValidator(ctx, address)
+	if !found {
+		return types.ErrNoValidatorFound(k.Codespace())
+	}
+	validator.Description.IdentityVerified = verified
+	k.SetValidator(ctx, validator)
+	return nil
+}
+
+// SetValidatorFrozen freezes or unfreezes the delegations of a validator.
+// While frozen no delegation, unbonding or redelegation involving the
+// validator can begin, but the ones which already began still complete.
+func (k Keeper) SetValidatorFrozen(ctx sdk.Context, address sdk.ValAddress, frozen bool) sdk.Error {
+	validator, found := k.GetValidator(ctx, address)
+	if !found {
+		return types.ErrNoValidatorFound(k.Codespace())
+	}
+	validator.Frozen = frozen
+	k.SetValidator(ctx, validator)
+	return nil
+}
+
+// SetValidatorUnbondingTime sets the unbonding time applied to the validator
+// and the delegations unbonding from it instead of the param. The override
+// cannot be shorter than the param; zero removes it.
+func (k Keeper) SetValidatorUnbondingTime(ctx sdk.Context, address sdk.ValAddress, unbondingTime time.Duration) sdk.Error {
+	validator, found := k.GetValidator(ctx, address)
+	if !found {
+		return types.ErrNoValidatorFound(k.Codespace())
+	}
+	if min := k.UnbondingTime(ctx); unbondingTime != 0 && unbondingTime < min {
+		return types.ErrUnbondingTimeOverrideTooShort(k.Codespace(), min)
+	}
+	validator.UnbondingTimeOverride = unbondingTime
+	k.SetValidator(ctx, validator)
+	return nil
+}
+
+// SignalValidatorExit records the height at which the validator operator
+// intends to leave. The signal is advisory only and leaves the validator's
+// power untouched; zero withdraws it.
+func (k Keeper) SignalValidatorExit(ctx sdk.Context, address sdk.ValAddress, exitHeight int64) sdk.Error {
+	validator, found := k.GetValidator(ctx, address)
+	if !found {
+		return types.ErrNoValidatorFound(k.Codespace())
+	}
+	if exitHeight != 0 && exitHeight <= ctx.BlockHeight() {
+		return types.ErrBadExitHeight(k.Codespace(), exitHeight, ctx.BlockHeight())
+	}
+	validator.PlannedExitHeight = exitHeight
+	k.SetValidator(ctx, validator)
+	return nil
+}
+
+// ValidatorUnbondingTime returns the unbonding time applied to the validator,
+// its override if longer than the param and the param otherwise.
+func (k Keeper) ValidatorUnbondingTime(ctx sdk.Context, validator types.Validator) time.Duration {
+	unbondingTime := k.UnbondingTime(ctx)
+	if validator.UnbondingTimeOverride > unbondingTime {
+		return validator.UnbondingTimeOverride
+	}
+	return unbondingTime
+}
+
+// maxValidatorUnbondingTime returns the longest unbonding time applied to
+// any validator, the param unless a validator's override is longer
+func (k Keeper) maxValidatorUnbondingTime(ctx sdk.Context) time.Duration {
+	unbondingTime := k.UnbondingTime(ctx)
+	for _, validator := range k.GetAllValidators(ctx) {
+		if validator.UnbondingTimeOverride > unbondingTime {
+			unbondingTime = validator.UnbondingTimeOverride
+		}
+	}
+	return unbondingTime
+}
+
+// IsValidatorFrozen returns whether the delegations of a validator are frozen
+func (k Keeper) IsValidatorFrozen(ctx sdk.Context, address sdk.ValAddress) bool {
+	validator, found := k.GetValidator(ctx, address)
+	return found && validator.Frozen
+}
+
 // remove the validator record and associated indexes
 // except for the bonded validator index which is only handled in ApplyAndReturnTendermintUpdates
 func (k Keeper) RemoveValidator(ctx sdk.Context, address sdk.ValAddress) {
@@ -201,12 +358,82 @@ func (k Keeper) RemoveValidator(ctx sdk.Context, address sdk.ValAddress) {
 	store := ctx.KVStore(k.storeKey)
 	store.Delete(GetValidatorKey(address))
 	store.Delete(GetValidatorByConsAddrKey(sdk.ConsAddress(validator.ConsPubKey.Address())))
-	store.Delete(GetValidatorsByPowerIndexKey(validator))
+	store.Delete(GetValidatorsByPowerIndexKey(validator, k.GetPowerIndexTieBreak(ctx)))
+	store.Delete(GetValidatorSlashEventsKey(address))
 
 	// call hooks
 	k.AfterValidatorRemoved(ctx, validator.ConsAddress(), validator.OperatorAddress)
 }
 
+// MigrateValidatorOwner moves a validator, along with its self-delegation,
+// from the old operator address to a new one. The validator must not be
+// unbonding and its self-delegation must be its only delegation, with no
+// unbonding delegations or redelegations pending, so that no other record
+// refers to the old operator address afterwards. Outstanding rewards and
+// commission are paid out to the old owner through the removal hooks.
+func (k Keeper) MigrateValidatorOwner(ctx sdk.Context, oldOwner, newOwner sdk.ValAddress) sdk.Error {
+	validator, found := k.GetValidator(ctx, oldOwner)
+	if !found {
+		return types.ErrNoValidatorFound(k.Codespace())
+	}
+	if _, found := k.GetValidator(ctx, newOwner); found {
+		return types.ErrValidatorOwnerExists(k.Codespace())
+	}
+	if validator.Status == sdk.Unbonding {
+		return types.ErrValidatorMigrationBlocked(k.Codespace(), "validator is unbonding")
+	}
+
+	oldSelfDelAddr := sdk.AccAddress(oldOwner)
+	delegations := k.GetValidatorDelegations(ctx, oldOwner)
+	if len(delegations) != 1 || !delegations[0].DelegatorAddress.Equals(oldSelfDelAddr) {
+		return types.ErrValidatorMigrationBlocked(k.Codespace(), "validator has delegations other than its self-delegation")
+	}
+	store := ctx.KVStore(k.storeKey)
+	for _, prefix := range [][]byte{GetUBDsByValIndexKey(oldOwner),
+		GetREDsFromValSrcIndexKey(oldOwner), GetREDsToValDstIndexKey(oldOwner)} {
+
+		iterator := sdk.KVStorePrefixIterator(store, prefix)
+		pending := iterator.Valid()
+		iterator.Close()
+		if pending {
+			return types.ErrValidatorMigrationBlocked(k.Codespace(), "validator has pending unbonding delegations or redelegations")
+		}
+	}
+	selfDelegation := delegations[0]
+
+	// withdraw the rewards of the self-delegation and remove the old records
+	k.BeforeDelegationSharesModified(ctx, oldSelfDelAddr, oldOwner)
+	k.RemoveDelegation(ctx, selfDelegation)
+	slashEvents := store.Get(GetValidatorSlashEventsKey(oldOwner))
+	lastPower := k.GetLastValidatorPower(ctx, oldOwner)
+	store.Delete(GetValidatorKey(oldOwner))
+	store.Delete(GetValidatorsByPowerIndexKey(validator, k.GetPowerIndexTieBreak(ctx)))
+	store.Delete(GetValidatorSlashEventsKey(oldOwner))
+	k.DeleteLastValidatorPower(ctx, oldOwner)
+	k.AfterValidatorRemoved(ctx, validator.ConsAddress(), oldOwner)
+
+	// store the validator and its self-delegation under the new owner
+	validator.OperatorAddress = newOwner
+	k.SetValidator(ctx, validator)
+	k.SetValidatorByConsAddr(ctx, validator)
+	k.SetValidatorByPowerIndex(ctx, validator)
+	if slashEvents != nil {
+		store.Set(GetValidatorSlashEventsKey(newOwner), slashEvents)
+	}
+	if validator.Status == sdk.Bonded {
+		k.SetLastValidatorPower(ctx, newOwner, lastPower)
+	}
+	k.AfterValidatorCreated(ctx, newOwner)
+
+	newSelfDelAddr := sdk.AccAddress(newOwner)
+	k.BeforeDelegationCreated(ctx, newSelfDelAddr, newOwner)
+	newSelfDelegation := types.NewDelegation(newSelfDelAddr, newOwner, selfDelegation.Shares)
+	newSelfDelegation.BondHeight = selfDelegation.BondHeight
+	k.SetDelegation(ctx, newSelfDelegation)
+	k.AfterDelegationModified(ctx, newSelfDelAddr, newOwner)
+	return nil
+}
+
 // get groups of validators
 
 // get the set of all validators with no limits, used during genesis dump
@@ -222,6 +449,46 @@ func (k Keeper) GetAllValidators(ctx sdk.Context) (validators []types.Validator)
 	return validators
 }
 
+// GetJailedValidators returns the validators which are currently jailed,
+// ordered by operator address.
+func (k Keeper) GetJailedValidators(ctx sdk.Context) (validators []types.Validator) {
+	store := ctx.KVStore(k.storeKey)
+	iterator := sdk.KVStorePrefixIterator(store, ValidatorsKey)
+	defer iterator.Close()
+
+	for ; iterator.Valid(); iterator.Next() {
+		validator := types.MustUnmarshalValidator(k.cdc, iterator.Value())
+		if validator.Jailed {
+			validators = append(validators, validator)
+		}
+	}
+	return validators
+}
+
+// SnapshotGenesisValidators records every validator as it currently exists,
+// to be called once at genesis.
+func (k Keeper) SnapshotGenesisValidators(ctx sdk.Context) {
+	store := ctx.KVStore(k.storeKey)
+	for _, validator := range k.GetAllValidators(ctx) {
+		bz := types.MustMarshalValidator(k.cdc, validator)
+		store.Set(GetGenesisValidatorKey(validator.OperatorAddress), bz)
+	}
+}
+
+// GetGenesisValidators returns the validator set as it existed at genesis,
+// regardless of later changes.
+func (k Keeper) GetGenesisValidators(ctx sdk.Context) (validators []types.Validator) {
+	store := ctx.KVStore(k.storeKey)
+	iterator := sdk.KVStorePrefixIterator(store, GenesisValidatorsKey)
+	defer iterator.Close()
+
+	for ; iterator.Valid(); iterator.Next() {
+		validator := types.MustUnmarshalValidator(k.cdc, iterator.Value())
+		validators = append(validators, validator)
+	}
+	return validators
+}
+
 // return a given amount of all the validators
 func (k Keeper) GetValidators(ctx sdk.Context, maxRetrieve uint16) (validators []types.Validator) {
 	store := ctx.KVStore(k.storeKey)
@@ -261,6 +528,204 @@ func (k Keeper) GetBondedValidatorsByPower(ctx sdk.Context) []types.Validator {
 	return validators[:i] // trim
 }
 
+// GetValidatorsCreatedInBlock returns the number of validators created during
+// the current block
+func (k Keeper) GetValidatorsCreatedInBlock(ctx sdk.Context) (count uint16) {
+	store := ctx.TransientStore(k.storeTKey)
+	bz := store.Get(ValidatorsCreatedCountKey)
+	if bz == nil {
+		return 0
+	}
+	k.cdc.MustUnmarshalBinaryLengthPrefixed(bz, &count)
+	return count
+}
+
+// IncrementValidatorsCreatedInBlock records the creation of a validator during
+// the current block
+func (k Keeper) IncrementValidatorsCreatedInBlock(ctx sdk.Context) {
+	store := ctx.TransientStore(k.storeTKey)
+	count := k.GetValidatorsCreatedInBlock(ctx) + 1
+	store.Set(ValidatorsCreatedCountKey, k.cdc.MustMarshalBinaryLengthPrefixed(count))
+}
+
+// GetValidatorCliff returns the bonded validator with the least power, i.e.
+// the one a new validator has to displace to enter the bonded set. It returns
+// false if the bonded set is not full.
+func (k Keeper) GetValidatorCliff(ctx sdk.Context) (cliff types.Validator, found bool) {
+	validators := k.GetBondedValidatorsByPower(ctx)
+	if len(validators) < int(k.MaxValidators(ctx)) {
+		return cliff, false
+	}
+	return validators[len(validators)-1], true
+}
+
+// GetValidatorEntryCost returns the tokens a new validator needs to displace
+// the cliff validator, or zero if the bonded set is not full. As ties in power
+// go to the validator bonded first, one more unit of power than the cliff is
+// required.
+func (k Keeper) GetValidatorEntryCost(ctx sdk.Context) sdk.Int {
+	cliff, found := k.GetValidatorCliff(ctx)
+	if !found {
+		return sdk.ZeroInt()
+	}
+	return sdk.TokensFromTendermintPower(cliff.PotentialTendermintPower() + 1)
+}
+
+// GetWaitingValidators returns the validators which are kept out of the bonded
+// set only by MaxValidators, highest power first: those ranked below the
+// bonded set in the power index with a non-zero power. Jailed validators are
+// not in the power index and frozen ones are left out.
+func (k Keeper) GetWaitingValidators(ctx sdk.Context) (validators []types.Validator) {
+	maxValidators := int64(k.MaxValidators(ctx))
+	k.IterateValidatorsByPower(ctx, func(index int64, validator types.Validator) bool {
+		if validator.PotentialTendermintPower() == 0 {
+			return true
+		}
+		if index >= maxValidators && validator.Status != sdk.Bonded && !validator.Frozen {
+			validators = append(validators, validator)
+		}
+		return false
+	})
+	return validators
+}
+
+// GetDecentralizationScore returns the entropy of the distribution of tokens
+// across the bonded validators, normalized by its maximum: one when every
+// bonded validator holds the same tokens, zero when a single validator is
+// bonded. Zero is returned if no tokens are bonded.
+func (k Keeper) GetDecentralizationScore(ctx sdk.Context) sdk.Dec {
+	validators := k.GetBondedValidatorsByPower(ctx)
+	total := sdk.ZeroInt()
+	n := int64(0)
+	for _, validator := range validators {
+		if validator.Tokens.IsPositive() {
+			total = total.Add(validator.Tokens)
+			n++
+		}
+	}
+	if n < 2 {
+		return sdk.ZeroDec()
+	}
+
+	// -sum(p * ln(p)) / ln(n)
+	entropy := sdk.ZeroDec()
+	for _, validator := range validators {
+		if !validator.Tokens.IsPositive() {
+			continue
+		}
+		p := validator.Tokens.ToDec().Quo(total.ToDec())
+		entropy = entropy.Sub(p.Mul(decLn(p)))
+	}
+	score := entropy.Quo(decLn(sdk.NewDec(n)))
+	if score.GT(sdk.OneDec()) {
+		score = sdk.OneDec()
+	}
+	return score
+}
+
+// decLn returns the natural logarithm of a positive decimal, reducing it to
+// [1, 2) by powers of two and summing ln(m) = 2 * atanh((m-1)/(m+1)).
+func decLn(x sdk.Dec) sdk.Dec {
+	if !x.IsPositive() {
+		panic(fmt.Sprintf("logarithm of a non-positive decimal %v", x))
+	}
+	two := sdk.NewDec(2)
+	exp := int64(0)
+	for x.LT(sdk.OneDec()) {
+		x = x.Mul(two)
+		exp--
+	}
+	for x.GTE(two) {
+		x = x.Quo(two)
+		exp++
+	}
+	if exp == 0 {
+		return lnReduced(x)
+	}
+	return lnReduced(x).Add(lnReduced(two).MulInt64(exp))
+}
+
+// lnReduced returns the natural logarithm of a decimal in [1, 2].
+func lnReduced(m sdk.Dec) sdk.Dec {
+	y := m.Sub(sdk.OneDec()).Quo(m.Add(sdk.OneDec()))
+	ySquared := y.Mul(y)
+	sum := sdk.ZeroDec()
+	power := y
+	for i := int64(1); !power.IsZero(); i += 2 {
+		sum = sum.Add(power.QuoInt64(i))
+		power = power.Mul(ySquared)
+	}
+	return sum.MulInt64(2)
+}
+
+// GetValidatorBondingStatus returns the reasons which keep a validator out of
+// the bonded set. A validator which is not bonded is below the cliff when its
+// power does not exceed the power of the cliff validator, or is zero if the
+// bonded set is not full.
+func (k Keeper) GetValidatorBondingStatus(ctx sdk.Context, ownerAddr sdk.ValAddress) types.BondingStatus {
+	validator := k.mustGetValidator(ctx, ownerAddr)
+
+	status := types.BondingStatus{
+		Bonded: validator.Status == sdk.Bonded,
+		Jailed: validator.Jailed,
+		Frozen: validator.Frozen,
+	}
+
+	selfDelegation, found := k.GetDelegation(ctx, sdk.AccAddress(ownerAddr), ownerAddr)
+	if !found || validator.TokensFromShares(selfDelegation.Shares).TruncateInt().LT(validator.MinSelfDelegation) {
+		status.BelowMinSelfDelegation = true
+	}
+
+	if !status.Bonded {
+		var cliffPower int64
+		if cliff, found := k.GetValidatorCliff(ctx); found {
+			cliffPower = cliff.PotentialTendermintPower()
+		}
+		power := validator.PotentialTendermintPower()
+		if power <= cliffPower {
+			status.BelowCliff = true
+			status.PowerToCliff = cliffPower + 1 - power
+		}
+	}
+	return status
+}
+
+// GetValidatorRank returns the position of a validator in the power index,
+// where rank 1 is the validator with the highest power, along with the total
+// number of ranked validators. Validators which are unknown or not in the power
+// index (i.e. jailed) are not found.
+func (k Keeper) GetValidatorRank(ctx sdk.Context, operatorAddr sdk.ValAddress) (rank int, total int, found bool) {
+	iterator := k.ValidatorsPowerStoreIterator(ctx)
+	defer iterator.Close()
+
+	for ; iterator.Valid(); iterator.Next() {
+		total++
+		if !found && bytes.Equal(iterator.Value(), operatorAddr) {
+			rank = total
+			found = true
+		}
+	}
+	if !found {
+		return 0, total, false
+	}
+	return rank, total, true
+}
+
+// GetPowerToRank returns the power of the bonded validator at the given rank,
+// where rank 1 is the validator with the highest power, i.e. the power needed
+// to be ranked at least as high. The lowest bonded power is returned when the
+// rank exceeds the size of the bonded set, and zero if nothing is bonded.
+func (k Keeper) GetPowerToRank(ctx sdk.Context, rank int) int64 {
+	validators := k.GetBondedValidatorsByPower(ctx)
+	if len(validators) == 0 || rank < 1 {
+		return 0
+	}
+	if rank > len(validators) {
+		rank = len(validators)
+	}
+	return validators[rank-1].GetTendermintPower()
+}
+
 // returns an iterator for the current validator power store
 func (k Keeper) ValidatorsPowerStoreIterator(ctx sdk.Context) (iterator sdk.Iterator) {
 	store := ctx.KVStore(k.storeKey)
@@ -268,6 +733,39 @@ func (k Keeper) ValidatorsPowerStoreIterator(ctx sdk.Context) (iterator sdk.Iter
 	return iterator
 }
 
+// IterateValidatorsByPower streams the validators of the power index, i.e.
+// those not jailed, from the highest power down without loading the whole
+// set, until fn returns true.
+func (k Keeper) IterateValidatorsByPower(ctx sdk.Context, fn func(index int64, validator types.Validator) (stop bool)) {
+	iterator := k.ValidatorsPowerStoreIterator(ctx)
+	defer iterator.Close()
+
+	for i := int64(0); iterator.Valid(); iterator.Next() {
+		validator := k.mustGetValidator(ctx, iterator.Value())
+		if fn(i, validator) {
+			break
+		}
+		i++
+	}
+}
+
+// get the validators in the power index whose potential power is below the
+// given threshold, lowest power first
+func (k Keeper) GetValidatorsBelowPower(ctx sdk.Context, threshold int64) (validators []types.Validator) {
+	store := ctx.KVStore(k.storeKey)
+	iterator := sdk.KVStorePrefixIterator(store, ValidatorsByPowerIndexKey)
+	defer iterator.Close()
+
+	for ; iterator.Valid(); iterator.Next() {
+		validator := k.mustGetValidator(ctx, iterator.Value())
+		if validator.PotentialTendermintPower() >= threshold {
+			break
+		}
+		validators = append(validators, validator)
+	}
+	return validators
+}
+
 //_______________________________________________________________________
 // Last Validator Index
 
@@ -408,6 +906,26 @@ func (k Keeper) ValidatorQueueIterator(ctx sdk.Context, endTime time.Time) sdk.I
 		sdk.InclusiveEndBytes(GetValidatorQueueTimeKey(endTime)))
 }
 
+// GetValidatorsUnbonding returns the validators which are unbonding and have
+// yet to complete their unbonding, ordered by unbonding completion time.
+func (k Keeper) GetValidatorsUnbonding(ctx sdk.Context) (validators []types.Validator) {
+	store := ctx.KVStore(k.storeKey)
+	iterator := sdk.KVStorePrefixIterator(store, ValidatorQueueKey)
+	defer iterator.Close()
+
+	for ; iterator.Valid(); iterator.Next() {
+		timeslice := []sdk.ValAddress{}
+		k.cdc.MustUnmarshalBinaryLengthPrefixed(iterator.Value(), &timeslice)
+		for _, valAddr := range timeslice {
+			validator, found := k.GetValidator(ctx, valAddr)
+			if found && validator.Status == sdk.Unbonding {
+				validators = append(validators, validator)
+			}
+		}
+	}
+	return validators
+}
+
 // Returns a concatenated list of all the timeslices before currTime, and deletes the timeslices from the queue
 func (k Keeper) GetAllMatureValidatorQueue(ctx sdk.Context, currTime time.Time) (matureValsAddrs []sdk.ValAddress) {
 	// gets an iterator for all timeslices from time 0 until the current Blockheader time
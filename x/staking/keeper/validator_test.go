@@ -1,6 +1,8 @@
 package keeper
 
 import (
+	"bytes"
+	"encoding/binary"
 	"fmt"
 	"testing"
 	"time"
@@ -26,7 +28,7 @@ func TestSetValidator(t *testing.T) {
 
 	// test how the validator is set from a purely unbonbed pool
 	validator := types.NewValidator(valAddr, valPubKey, types.Description{})
-	validator, pool, _ = validator.AddTokensFromDel(pool, valTokens)
+	validator, pool, _ = validator.AddTokensFromDel(pool, valTokens, types.ShareRoundDown)
 	require.Equal(t, sdk.Unbonded, validator.Status)
 	assert.Equal(t, valTokens, validator.Tokens)
 	assert.Equal(t, valTokens, validator.DelegatorShares.RoundInt())
@@ -71,6 +73,16 @@ func TestSetValidator(t *testing.T) {
 	require.Equal(t, 1, len(allVals))
 }
 
+func TestIsValidator(t *testing.T) {
+	ctx, _, keeper := CreateTestInput(t, false, 10)
+
+	validator := types.NewValidator(addrVals[0], PKs[0], types.Description{})
+	keeper.SetValidator(ctx, validator)
+
+	require.True(t, keeper.IsValidator(ctx, addrVals[0]))
+	require.False(t, keeper.IsValidator(ctx, addrVals[1]))
+}
+
 func TestUpdateValidatorByPowerIndex(t *testing.T) {
 	ctx, _, keeper := CreateTestInput(t, false, 0)
 	pool := keeper.GetPool(ctx)
@@ -82,7 +94,7 @@ func TestUpdateValidatorByPowerIndex(t *testing.T) {
 
 	// add a validator
 	validator := types.NewValidator(addrVals[0], PKs[0], types.Description{})
-	validator, pool, delSharesCreated := validator.AddTokensFromDel(pool, sdk.NewInt(100))
+	validator, pool, delSharesCreated := validator.AddTokensFromDel(pool, sdk.NewInt(100), types.ShareRoundDown)
 	require.Equal(t, sdk.Unbonded, validator.Status)
 	require.Equal(t, int64(100), validator.Tokens.Int64())
 	keeper.SetPool(ctx, pool)
@@ -92,12 +104,12 @@ func TestUpdateValidatorByPowerIndex(t *testing.T) {
 	require.Equal(t, int64(100), validator.Tokens.Int64(), "\nvalidator %v\npool %v", validator, pool)
 
 	pool = keeper.GetPool(ctx)
-	power := GetValidatorsByPowerIndexKey(validator)
+	power := GetValidatorsByPowerIndexKey(validator, keeper.GetPowerIndexTieBreak(ctx))
 	require.True(t, validatorByPowerIndexExists(keeper, ctx, power))
 
 	// burn half the delegator shares
 	keeper.DeleteValidatorByPowerIndex(ctx, validator)
-	validator, pool, burned := validator.RemoveDelShares(pool, delSharesCreated.Quo(sdk.NewDec(2)))
+	validator, pool, burned := validator.RemoveDelShares(pool, delSharesCreated.Quo(sdk.NewDec(2)), types.ShareRoundDown)
 	require.Equal(t, int64(50), burned.Int64())
 	keeper.SetPool(ctx, pool)                            // update the pool
 	TestingUpdateValidator(keeper, ctx, validator, true) // update the validator, possibly kicking it out
@@ -106,7 +118,7 @@ func TestUpdateValidatorByPowerIndex(t *testing.T) {
 	pool = keeper.GetPool(ctx)
 	validator, found = keeper.GetValidator(ctx, addrVals[0])
 	require.True(t, found)
-	power = GetValidatorsByPowerIndexKey(validator)
+	power = GetValidatorsByPowerIndexKey(validator, keeper.GetPowerIndexTieBreak(ctx))
 	require.True(t, validatorByPowerIndexExists(keeper, ctx, power))
 }
 
@@ -133,7 +145,7 @@ func TestUpdateBondedValidatorsDecreaseCliff(t *testing.T) {
 		moniker := fmt.Sprintf("val#%d", int64(i))
 		val := types.NewValidator(sdk.ValAddress(Addrs[i]), PKs[i], types.Description{Moniker: moniker})
 		delTokens := sdk.TokensFromTendermintPower(int64((i + 1) * 10))
-		val, pool, _ = val.AddTokensFromDel(pool, delTokens)
+		val, pool, _ = val.AddTokensFromDel(pool, delTokens, types.ShareRoundDown)
 
 		keeper.SetPool(ctx, pool)
 		val = TestingUpdateValidator(keeper, ctx, val, true)
@@ -146,7 +158,7 @@ func TestUpdateBondedValidatorsDecreaseCliff(t *testing.T) {
 	// validator and next in line cliff validator
 	keeper.DeleteValidatorByPowerIndex(ctx, nextCliffVal)
 	shares := sdk.TokensFromTendermintPower(21)
-	nextCliffVal, pool, _ = nextCliffVal.RemoveDelShares(pool, shares.ToDec())
+	nextCliffVal, pool, _ = nextCliffVal.RemoveDelShares(pool, shares.ToDec(), types.ShareRoundDown)
 	keeper.SetPool(ctx, pool)
 	nextCliffVal = TestingUpdateValidator(keeper, ctx, nextCliffVal, true)
 
@@ -175,7 +187,7 @@ func TestSlashToZeroPowerRemoved(t *testing.T) {
 	// add a validator
 	validator := types.NewValidator(addrVals[0], PKs[0], types.Description{})
 	valTokens := sdk.TokensFromTendermintPower(100)
-	validator, pool, _ = validator.AddTokensFromDel(pool, valTokens)
+	validator, pool, _ = validator.AddTokensFromDel(pool, valTokens, types.ShareRoundDown)
 	require.Equal(t, sdk.Unbonded, validator.Status)
 	require.Equal(t, valTokens, validator.Tokens)
 	keeper.SetPool(ctx, pool)
@@ -206,7 +218,7 @@ func TestValidatorBasics(t *testing.T) {
 		validators[i].Status = sdk.Unbonded
 		validators[i].Tokens = sdk.ZeroInt()
 		tokens := sdk.TokensFromTendermintPower(power)
-		validators[i], pool, _ = validators[i].AddTokensFromDel(pool, tokens)
+		validators[i], pool, _ = validators[i].AddTokensFromDel(pool, tokens, types.ShareRoundDown)
 		keeper.SetPool(ctx, pool)
 	}
 	assert.Equal(t, sdk.TokensFromTendermintPower(9), validators[0].Tokens)
@@ -458,7 +470,7 @@ func TestGetValidatorsEdgeCases(t *testing.T) {
 		moniker := fmt.Sprintf("val#%d", int64(i))
 		validators[i] = types.NewValidator(sdk.ValAddress(Addrs[i]), PKs[i], types.Description{Moniker: moniker})
 		tokens := sdk.TokensFromTendermintPower(power)
-		validators[i], pool, _ = validators[i].AddTokensFromDel(pool, tokens)
+		validators[i], pool, _ = validators[i].AddTokensFromDel(pool, tokens, types.ShareRoundDown)
 		keeper.SetPool(ctx, pool)
 		validators[i] = TestingUpdateValidator(keeper, ctx, validators[i], true)
 	}
@@ -475,7 +487,7 @@ func TestGetValidatorsEdgeCases(t *testing.T) {
 	pool := keeper.GetPool(ctx)
 	keeper.DeleteValidatorByPowerIndex(ctx, validators[0])
 	delTokens := sdk.TokensFromTendermintPower(500)
-	validators[0], pool, _ = validators[0].AddTokensFromDel(pool, delTokens)
+	validators[0], pool, _ = validators[0].AddTokensFromDel(pool, delTokens, types.ShareRoundDown)
 	keeper.SetPool(ctx, pool)
 	validators[0] = TestingUpdateValidator(keeper, ctx, validators[0], true)
 	resValidators = keeper.GetBondedValidatorsByPower(ctx)
@@ -493,7 +505,7 @@ func TestGetValidatorsEdgeCases(t *testing.T) {
 	validators[3], found = keeper.GetValidator(ctx, validators[3].OperatorAddress)
 	require.True(t, found)
 	keeper.DeleteValidatorByPowerIndex(ctx, validators[3])
-	validators[3], pool, _ = validators[3].AddTokensFromDel(pool, sdk.NewInt(1))
+	validators[3], pool, _ = validators[3].AddTokensFromDel(pool, sdk.NewInt(1), types.ShareRoundDown)
 	keeper.SetPool(ctx, pool)
 	validators[3] = TestingUpdateValidator(keeper, ctx, validators[3], true)
 	resValidators = keeper.GetBondedValidatorsByPower(ctx)
@@ -503,7 +515,7 @@ func TestGetValidatorsEdgeCases(t *testing.T) {
 
 	// validator 3 kicked out temporarily
 	keeper.DeleteValidatorByPowerIndex(ctx, validators[3])
-	validators[3], pool, _ = validators[3].RemoveDelShares(pool, sdk.NewDec(201))
+	validators[3], pool, _ = validators[3].RemoveDelShares(pool, sdk.NewDec(201), types.ShareRoundDown)
 	keeper.SetPool(ctx, pool)
 	validators[3] = TestingUpdateValidator(keeper, ctx, validators[3], true)
 	resValidators = keeper.GetBondedValidatorsByPower(ctx)
@@ -513,7 +525,7 @@ func TestGetValidatorsEdgeCases(t *testing.T) {
 
 	// validator 4 does not get spot back
 	keeper.DeleteValidatorByPowerIndex(ctx, validators[3])
-	validators[3], pool, _ = validators[3].AddTokensFromDel(pool, sdk.NewInt(200))
+	validators[3], pool, _ = validators[3].AddTokensFromDel(pool, sdk.NewInt(200), types.ShareRoundDown)
 	keeper.SetPool(ctx, pool)
 	validators[3] = TestingUpdateValidator(keeper, ctx, validators[3], true)
 	resValidators = keeper.GetBondedValidatorsByPower(ctx)
@@ -524,6 +536,174 @@ func TestGetValidatorsEdgeCases(t *testing.T) {
 	require.True(t, exists)
 }
 
+func TestGetValidatorRank(t *testing.T) {
+	ctx, _, keeper := CreateTestInput(t, false, 1000)
+
+	// initialize five validators into the state, not all of them bonded
+	params := keeper.GetParams(ctx)
+	params.MaxValidators = 3
+	keeper.SetParams(ctx, params)
+
+	powers := []int64{300, 100, 500, 200, 400}
+	expectedRanks := []int{3, 5, 1, 4, 2}
+	var validators [5]types.Validator
+	for i, power := range powers {
+		pool := keeper.GetPool(ctx)
+		validators[i] = types.NewValidator(sdk.ValAddress(Addrs[i]), PKs[i], types.Description{})
+		tokens := sdk.TokensFromTendermintPower(power)
+		validators[i], pool, _ = validators[i].AddTokensFromDel(pool, tokens, types.ShareRoundDown)
+		keeper.SetPool(ctx, pool)
+		validators[i] = TestingUpdateValidator(keeper, ctx, validators[i], true)
+	}
+
+	for i, validator := range validators {
+		rank, total, found := keeper.GetValidatorRank(ctx, validator.OperatorAddress)
+		require.True(t, found)
+		require.Equal(t, expectedRanks[i], rank, "validator %d", i)
+		require.Equal(t, len(validators), total)
+	}
+
+	// an unknown validator has no rank
+	_, total, found := keeper.GetValidatorRank(ctx, sdk.ValAddress(Addrs[5]))
+	require.False(t, found)
+	require.Equal(t, len(validators), total)
+}
+
+func TestGetPowerToRank(t *testing.T) {
+	ctx, _, keeper := CreateTestInput(t, false, 1000)
+	require.Equal(t, int64(0), keeper.GetPowerToRank(ctx, 1))
+
+	// initialize five validators into the state, not all of them bonded
+	params := keeper.GetParams(ctx)
+	params.MaxValidators = 4
+	keeper.SetParams(ctx, params)
+
+	powers := []int64{300, 100, 500, 200, 400}
+	for i, power := range powers {
+		pool := keeper.GetPool(ctx)
+		validator := types.NewValidator(sdk.ValAddress(Addrs[i]), PKs[i], types.Description{})
+		tokens := sdk.TokensFromTendermintPower(power)
+		validator, pool, _ = validator.AddTokensFromDel(pool, tokens, types.ShareRoundDown)
+		keeper.SetPool(ctx, pool)
+		TestingUpdateValidator(keeper, ctx, validator, true)
+	}
+
+	require.Equal(t, int64(500), keeper.GetPowerToRank(ctx, 1))
+	require.Equal(t, int64(400), keeper.GetPowerToRank(ctx, 2))
+	require.Equal(t, int64(200), keeper.GetPowerToRank(ctx, 4))
+
+	// the validator with power 100 is not bonded
+	require.Equal(t, int64(200), keeper.GetPowerToRank(ctx, 5))
+	require.Equal(t, int64(200), keeper.GetPowerToRank(ctx, 50))
+	require.Equal(t, int64(0), keeper.GetPowerToRank(ctx, 0))
+}
+
+func TestSimulateParamChange(t *testing.T) {
+	ctx, _, keeper := CreateTestInput(t, false, 1000)
+	keeper.SetInflationEstimator(func(_ sdk.Context, bondedRatio sdk.Dec) sdk.Dec {
+		return bondedRatio.QuoInt64(10)
+	})
+
+	powers := []int64{300, 100, 500, 200}
+	for i, power := range powers {
+		pool := keeper.GetPool(ctx)
+		validator := types.NewValidator(sdk.ValAddress(Addrs[i]), PKs[i], types.Description{})
+		tokens := sdk.TokensFromTendermintPower(power)
+		validator, pool, _ = validator.AddTokensFromDel(pool, tokens, types.ShareRoundDown)
+		keeper.SetPool(ctx, pool)
+		TestingUpdateValidator(keeper, ctx, validator, true)
+	}
+
+	// decreasing the max validators unbonds the two weakest validators
+	params := keeper.GetParams(ctx)
+	oldMaxValidators := params.MaxValidators
+	params.MaxValidators = 2
+	impact := keeper.SimulateParamChange(ctx, params)
+	require.Empty(t, impact.Bonding)
+	require.Len(t, impact.Unbonding, 2)
+
+	// the simulation leaves the state untouched
+	require.Equal(t, oldMaxValidators, keeper.GetParams(ctx).MaxValidators)
+	require.Len(t, keeper.GetLastValidators(ctx), len(powers))
+
+	// the prediction matches actually applying the change
+	keeper.SetParams(ctx, params)
+	keeper.ApplyAndReturnValidatorSetUpdates(ctx)
+	var unbonding []sdk.ValAddress
+	keeper.IterateValidators(ctx, func(_ int64, validator sdk.Validator) bool {
+		if validator.GetStatus() == sdk.Unbonding {
+			unbonding = append(unbonding, validator.GetOperator())
+		}
+		return false
+	})
+	require.ElementsMatch(t, unbonding, impact.Unbonding)
+	require.ElementsMatch(t, []sdk.ValAddress{sdk.ValAddress(Addrs[1]), sdk.ValAddress(Addrs[3])}, impact.Unbonding)
+
+	bondedRatio := keeper.GetPool(ctx).BondedRatio()
+	require.True(t, bondedRatio.Equal(impact.BondedRatio))
+	require.True(t, bondedRatio.QuoInt64(10).Equal(impact.NextInflation))
+}
+
+func TestGetValidatorsWithPowerChange(t *testing.T) {
+	ctx, _, keeper := CreateTestInput(t, false, 1000)
+
+	var validators [3]types.Validator
+	for i := range validators {
+		pool := keeper.GetPool(ctx)
+		validators[i] = types.NewValidator(sdk.ValAddress(Addrs[i]), PKs[i], types.Description{})
+		tokens := sdk.TokensFromTendermintPower(int64(10 * (i + 1)))
+		validators[i], pool, _ = validators[i].AddTokensFromDel(pool, tokens, types.ShareRoundDown)
+		keeper.SetPool(ctx, pool)
+		keeper.SetValidatorByConsAddr(ctx, validators[i])
+		validators[i] = TestingUpdateValidator(keeper, ctx, validators[i], true)
+	}
+	require.Empty(t, keeper.GetValidatorsWithPowerChange(ctx))
+
+	// change the power of two validators
+	validators[0], _ = keeper.AddValidatorTokensAndShares(ctx, validators[0], sdk.TokensFromTendermintPower(5))
+	validators[2], _ = keeper.RemoveValidatorTokensAndShares(ctx, validators[2], sdk.TokensFromTendermintPower(5).ToDec())
+
+	changed := keeper.GetValidatorsWithPowerChange(ctx)
+	require.Len(t, changed, 2)
+	require.ElementsMatch(t,
+		[]sdk.ValAddress{validators[0].OperatorAddress, validators[2].OperatorAddress},
+		[]sdk.ValAddress{changed[0].OperatorAddress, changed[1].OperatorAddress})
+	for _, validator := range changed {
+		require.Equal(t, sdk.Bonded, validator.Status)
+	}
+
+	// the pending updates are left untouched
+	require.Len(t, keeper.GetValidatorsWithPowerChange(ctx), 2)
+	require.Len(t, keeper.ApplyAndReturnValidatorSetUpdates(ctx), 2)
+	require.Empty(t, keeper.GetValidatorsWithPowerChange(ctx))
+}
+
+func TestGetPendingPowerDelta(t *testing.T) {
+	ctx, _, keeper := CreateTestInput(t, false, 1000)
+
+	var validators [3]types.Validator
+	for i := range validators {
+		pool := keeper.GetPool(ctx)
+		validators[i] = types.NewValidator(sdk.ValAddress(Addrs[i]), PKs[i], types.Description{})
+		tokens := sdk.TokensFromTendermintPower(int64(10 * (i + 1)))
+		validators[i], pool, _ = validators[i].AddTokensFromDel(pool, tokens, types.ShareRoundDown)
+		keeper.SetPool(ctx, pool)
+		keeper.SetValidatorByConsAddr(ctx, validators[i])
+		validators[i] = TestingUpdateValidator(keeper, ctx, validators[i], true)
+	}
+	require.Zero(t, keeper.GetPendingPowerDelta(ctx))
+
+	// one validator gains 5 power, another loses 7
+	validators[0], _ = keeper.AddValidatorTokensAndShares(ctx, validators[0], sdk.TokensFromTendermintPower(5))
+	validators[2], _ = keeper.RemoveValidatorTokensAndShares(ctx, validators[2], sdk.TokensFromTendermintPower(7).ToDec())
+	require.Equal(t, int64(12), keeper.GetPendingPowerDelta(ctx))
+
+	// the pending updates are left untouched
+	require.Equal(t, int64(12), keeper.GetPendingPowerDelta(ctx))
+	require.Len(t, keeper.ApplyAndReturnValidatorSetUpdates(ctx), 2)
+	require.Zero(t, keeper.GetPendingPowerDelta(ctx))
+}
+
 func TestValidatorBondHeight(t *testing.T) {
 	ctx, _, keeper := CreateTestInput(t, false, 1000)
 	pool := keeper.GetPool(ctx)
@@ -542,9 +722,9 @@ func TestValidatorBondHeight(t *testing.T) {
 	tokens0 := sdk.TokensFromTendermintPower(200)
 	tokens1 := sdk.TokensFromTendermintPower(100)
 	tokens2 := sdk.TokensFromTendermintPower(100)
-	validators[0], pool, _ = validators[0].AddTokensFromDel(pool, tokens0)
-	validators[1], pool, _ = validators[1].AddTokensFromDel(pool, tokens1)
-	validators[2], pool, _ = validators[2].AddTokensFromDel(pool, tokens2)
+	validators[0], pool, _ = validators[0].AddTokensFromDel(pool, tokens0, types.ShareRoundDown)
+	validators[1], pool, _ = validators[1].AddTokensFromDel(pool, tokens1, types.ShareRoundDown)
+	validators[2], pool, _ = validators[2].AddTokensFromDel(pool, tokens2, types.ShareRoundDown)
 	keeper.SetPool(ctx, pool)
 
 	validators[0] = TestingUpdateValidator(keeper, ctx, validators[0], true)
@@ -565,8 +745,8 @@ func TestValidatorBondHeight(t *testing.T) {
 	keeper.DeleteValidatorByPowerIndex(ctx, validators[1])
 	keeper.DeleteValidatorByPowerIndex(ctx, validators[2])
 	delTokens := sdk.TokensFromTendermintPower(50)
-	validators[1], pool, _ = validators[1].AddTokensFromDel(pool, delTokens)
-	validators[2], pool, _ = validators[2].AddTokensFromDel(pool, delTokens)
+	validators[1], pool, _ = validators[1].AddTokensFromDel(pool, delTokens, types.ShareRoundDown)
+	validators[2], pool, _ = validators[2].AddTokensFromDel(pool, delTokens, types.ShareRoundDown)
 	keeper.SetPool(ctx, pool)
 	validators[2] = TestingUpdateValidator(keeper, ctx, validators[2], true)
 	resValidators = keeper.GetBondedValidatorsByPower(ctx)
@@ -576,6 +756,171 @@ func TestValidatorBondHeight(t *testing.T) {
 	assert.True(ValEq(t, validators[2], resValidators[1]))
 }
 
+func TestValidatorPowerRankTieBreak(t *testing.T) {
+	tokens := sdk.TokensFromTendermintPower(100)
+	valA := types.NewValidator(sdk.ValAddress(Addrs[0]), PKs[0], types.Description{})
+	valB := types.NewValidator(sdk.ValAddress(Addrs[1]), PKs[1], types.Description{})
+	valA.Tokens, valB.Tokens = tokens, tokens
+	valA.BondHeight, valB.BondHeight = 5, 5
+
+	// the smaller operator address ranks first at identical power and bond height
+	first, second := valA, valB
+	if bytes.Compare(valB.OperatorAddress, valA.OperatorAddress) < 0 {
+		first, second = valB, valA
+	}
+
+	// the order must not depend on the order the index entries are written in
+	for i := 0; i < 4; i++ {
+		ctx, _, keeper := CreateTestInput(t, false, 0)
+		if i%2 == 0 {
+			keeper.SetValidatorByPowerIndex(ctx, valA)
+			keeper.SetValidatorByPowerIndex(ctx, valB)
+		} else {
+			keeper.SetValidatorByPowerIndex(ctx, valB)
+			keeper.SetValidatorByPowerIndex(ctx, valA)
+		}
+
+		var ranked []sdk.ValAddress
+		iterator := keeper.ValidatorsPowerStoreIterator(ctx)
+		for ; iterator.Valid(); iterator.Next() {
+			ranked = append(ranked, sdk.ValAddress(iterator.Value()))
+		}
+		iterator.Close()
+
+		require.Len(t, ranked, 2)
+		require.Equal(t, first.OperatorAddress, ranked[0], "run %d", i)
+		require.Equal(t, second.OperatorAddress, ranked[1], "run %d", i)
+	}
+
+	// at identical power the validator with the earlier bond height ranks first
+	ctx, _, keeper := CreateTestInput(t, false, 0)
+	second.BondHeight = 4
+	keeper.SetValidatorByPowerIndex(ctx, first)
+	keeper.SetValidatorByPowerIndex(ctx, second)
+	iterator := keeper.ValidatorsPowerStoreIterator(ctx)
+	defer iterator.Close()
+	require.Equal(t, second.OperatorAddress, sdk.ValAddress(iterator.Value()))
+}
+
+func TestTieBreakMode(t *testing.T) {
+	ctx, _, keeper := CreateTestInput(t, false, 0)
+	tokens := sdk.TokensFromTendermintPower(100)
+
+	// valA bonded after valB but its transaction brings it to the tied power
+	// first
+	valA := types.NewValidator(sdk.ValAddress(Addrs[0]), PKs[0], types.Description{})
+	valB := types.NewValidator(sdk.ValAddress(Addrs[1]), PKs[1], types.Description{})
+	valA.BondHeight, valB.BondHeight = 10, 5
+	for _, validator := range []types.Validator{valA, valB} {
+		keeper.SetValidator(ctx, validator)
+		keeper.SetNewValidatorByPowerIndex(ctx, validator)
+	}
+	ctx = ctx.WithBlockHeight(20)
+	valA, _ = keeper.AddValidatorTokensAndShares(ctx, valA, tokens)
+	valB, _ = keeper.AddValidatorTokensAndShares(ctx, valB, tokens)
+
+	ranked := func() (addrs []sdk.ValAddress) {
+		iterator := sdk.KVStoreReversePrefixIterator(ctx.KVStore(keeper.storeKey), ValidatorsByPowerIndexKey)
+		defer iterator.Close()
+		for ; iterator.Valid(); iterator.Next() {
+			addrs = append(addrs, sdk.ValAddress(iterator.Value()))
+		}
+		return addrs
+	}
+
+	// by stake age the validator which bonded earliest ranks first
+	require.Equal(t, types.TieBreakStakeAge, keeper.TieBreakMode(ctx))
+	require.Equal(t, []sdk.ValAddress{valB.OperatorAddress, valA.OperatorAddress}, ranked())
+
+	// the new mode applies once the power index is rebuilt
+	params := keeper.GetParams(ctx)
+	params.TieBreakMode = types.TieBreakTxOrder
+	keeper.SetParams(ctx, params)
+	require.Equal(t, types.TieBreakStakeAge, keeper.GetPowerIndexTieBreak(ctx))
+	keeper.ApplyTieBreakMode(ctx)
+	require.Equal(t, types.TieBreakTxOrder, keeper.GetPowerIndexTieBreak(ctx))
+
+	// by transaction order the validator which reached the power first ranks first
+	require.Equal(t, []sdk.ValAddress{valA.OperatorAddress, valB.OperatorAddress}, ranked())
+
+	// valA falls behind once it leaves and regains the tied power in a later block
+	ctx = ctx.WithBlockHeight(21)
+	valA = keeper.RemoveValidatorTokens(ctx, valA, sdk.OneInt())
+	valA, _ = keeper.AddValidatorTokensAndShares(ctx, valA, sdk.OneInt())
+	require.Equal(t, []sdk.ValAddress{valB.OperatorAddress, valA.OperatorAddress}, ranked())
+
+	// the rebuilt index is consistent with the stored validators
+	require.NoError(t, NonNegativePowerInvariant(keeper)(ctx))
+}
+
+func TestApplyTieBreakModeRebuildsLegacyPowerIndex(t *testing.T) {
+	ctx, _, keeper := CreateTestInput(t, false, 0)
+	store := ctx.KVStore(keeper.storeKey)
+	require.False(t, store.Has(PowerIndexTieBreakKey))
+
+	// a power index written before the keys carried the tie-break order, with
+	// a stale entry of a validator which no longer exists
+	legacyKey := func(validator types.Validator) []byte {
+		powerBytes := make([]byte, 8)
+		binary.BigEndian.PutUint64(powerBytes, uint64(validator.Tokens.Int64()))
+		key := append(cp(ValidatorsByPowerIndexKey), powerBytes...)
+		return append(key, validator.OperatorAddress...)
+	}
+	var validators [3]types.Validator
+	for i := range validators {
+		validators[i] = types.NewValidator(sdk.ValAddress(Addrs[i]), PKs[i], types.Description{})
+		validators[i].Tokens = sdk.TokensFromTendermintPower(int64(i + 1))
+		validators[i].DelegatorShares = validators[i].Tokens.ToDec()
+		store.Set(legacyKey(validators[i]), validators[i].OperatorAddress)
+	}
+	keeper.SetValidator(ctx, validators[0])
+	keeper.SetValidator(ctx, validators[1])
+
+	keeper.ApplyTieBreakMode(ctx)
+	require.True(t, store.Has(PowerIndexTieBreakKey))
+
+	var ranked []sdk.ValAddress
+	iterator := keeper.ValidatorsPowerStoreIterator(ctx)
+	for ; iterator.Valid(); iterator.Next() {
+		ranked = append(ranked, sdk.ValAddress(iterator.Value()))
+	}
+	iterator.Close()
+	require.Equal(t, []sdk.ValAddress{validators[1].OperatorAddress, validators[0].OperatorAddress}, ranked)
+	require.True(t, validatorByPowerIndexExists(keeper, ctx, GetValidatorsByPowerIndexKey(validators[1], types.TieBreakStakeAge)))
+
+	// the rebuilt entries can be deleted, and the index is not rebuilt again
+	keeper.DeleteValidatorByPowerIndex(ctx, validators[1])
+	keeper.ApplyTieBreakMode(ctx)
+	require.False(t, validatorByPowerIndexExists(keeper, ctx, GetValidatorsByPowerIndexKey(validators[1], types.TieBreakStakeAge)))
+}
+
+func TestGetValidatorsBelowPower(t *testing.T) {
+	ctx, _, keeper := CreateTestInput(t, false, 1000)
+
+	// validators 0 and 1 are empty dust, validator 2 is dust with a single
+	// power, validator 3 is above the threshold
+	var validators [4]types.Validator
+	for i := range validators {
+		validators[i] = types.NewValidator(sdk.ValAddress(Addrs[i]), PKs[i], types.Description{})
+	}
+	validators[2].Tokens = sdk.TokensFromTendermintPower(1)
+	validators[2].DelegatorShares = validators[2].Tokens.ToDec()
+	validators[3].Tokens = sdk.TokensFromTendermintPower(10)
+	validators[3].DelegatorShares = validators[3].Tokens.ToDec()
+	for _, validator := range validators {
+		keeper.SetValidator(ctx, validator)
+		keeper.SetValidatorByPowerIndex(ctx, validator)
+	}
+
+	// the lowest power comes first and the threshold itself is excluded
+	below := keeper.GetValidatorsBelowPower(ctx, 5)
+	require.Len(t, below, 3)
+	require.Equal(t, validators[2].OperatorAddress, below[2].OperatorAddress)
+
+	require.Len(t, keeper.GetValidatorsBelowPower(ctx, 1), 2)
+	require.Len(t, keeper.GetValidatorsBelowPower(ctx, 11), 4)
+}
+
 func TestFullValidatorSetPowerChange(t *testing.T) {
 	ctx, _, keeper := CreateTestInput(t, false, 1000)
 	params := keeper.GetParams(ctx)
@@ -590,7 +935,7 @@ func TestFullValidatorSetPowerChange(t *testing.T) {
 		pool := keeper.GetPool(ctx)
 		validators[i] = types.NewValidator(sdk.ValAddress(Addrs[i]), PKs[i], types.Description{})
 		tokens := sdk.TokensFromTendermintPower(power)
-		validators[i], pool, _ = validators[i].AddTokensFromDel(pool, tokens)
+		validators[i], pool, _ = validators[i].AddTokensFromDel(pool, tokens, types.ShareRoundDown)
 		keeper.SetPool(ctx, pool)
 		TestingUpdateValidator(keeper, ctx, validators[i], true)
 	}
@@ -612,7 +957,7 @@ func TestFullValidatorSetPowerChange(t *testing.T) {
 	// test a swap in voting power
 	pool := keeper.GetPool(ctx)
 	tokens := sdk.TokensFromTendermintPower(600)
-	validators[0], pool, _ = validators[0].AddTokensFromDel(pool, tokens)
+	validators[0], pool, _ = validators[0].AddTokensFromDel(pool, tokens, types.ShareRoundDown)
 	keeper.SetPool(ctx, pool)
 	validators[0] = TestingUpdateValidator(keeper, ctx, validators[0], true)
 	resValidators = keeper.GetBondedValidatorsByPower(ctx)
@@ -634,7 +979,7 @@ func TestApplyAndReturnValidatorSetUpdatesAllNone(t *testing.T) {
 
 		validators[i] = types.NewValidator(valAddr, valPubKey, types.Description{})
 		tokens := sdk.TokensFromTendermintPower(power)
-		validators[i], pool, _ = validators[i].AddTokensFromDel(pool, tokens)
+		validators[i], pool, _ = validators[i].AddTokensFromDel(pool, tokens, types.ShareRoundDown)
 		keeper.SetPool(ctx, pool)
 	}
 
@@ -664,7 +1009,7 @@ func TestApplyAndReturnValidatorSetUpdatesIdentical(t *testing.T) {
 		validators[i] = types.NewValidator(sdk.ValAddress(Addrs[i]), PKs[i], types.Description{})
 
 		tokens := sdk.TokensFromTendermintPower(power)
-		validators[i], pool, _ = validators[i].AddTokensFromDel(pool, tokens)
+		validators[i], pool, _ = validators[i].AddTokensFromDel(pool, tokens, types.ShareRoundDown)
 		keeper.SetPool(ctx, pool)
 	}
 	validators[0] = TestingUpdateValidator(keeper, ctx, validators[0], false)
@@ -688,7 +1033,7 @@ func TestApplyAndReturnValidatorSetUpdatesSingleValueChange(t *testing.T) {
 		validators[i] = types.NewValidator(sdk.ValAddress(Addrs[i]), PKs[i], types.Description{})
 
 		tokens := sdk.TokensFromTendermintPower(power)
-		validators[i], pool, _ = validators[i].AddTokensFromDel(pool, tokens)
+		validators[i], pool, _ = validators[i].AddTokensFromDel(pool, tokens, types.ShareRoundDown)
 		keeper.SetPool(ctx, pool)
 	}
 	validators[0] = TestingUpdateValidator(keeper, ctx, validators[0], false)
@@ -717,7 +1062,7 @@ func TestApplyAndReturnValidatorSetUpdatesMultipleValueChange(t *testing.T) {
 		validators[i] = types.NewValidator(sdk.ValAddress(Addrs[i]), PKs[i], types.Description{})
 
 		tokens := sdk.TokensFromTendermintPower(power)
-		validators[i], pool, _ = validators[i].AddTokensFromDel(pool, tokens)
+		validators[i], pool, _ = validators[i].AddTokensFromDel(pool, tokens, types.ShareRoundDown)
 		keeper.SetPool(ctx, pool)
 	}
 	validators[0] = TestingUpdateValidator(keeper, ctx, validators[0], false)
@@ -729,8 +1074,8 @@ func TestApplyAndReturnValidatorSetUpdatesMultipleValueChange(t *testing.T) {
 	pool := keeper.GetPool(ctx)
 	delTokens1 := sdk.TokensFromTendermintPower(190)
 	delTokens2 := sdk.TokensFromTendermintPower(80)
-	validators[0], pool, _ = validators[0].AddTokensFromDel(pool, delTokens1)
-	validators[1], pool, _ = validators[1].AddTokensFromDel(pool, delTokens2)
+	validators[0], pool, _ = validators[0].AddTokensFromDel(pool, delTokens1, types.ShareRoundDown)
+	validators[1], pool, _ = validators[1].AddTokensFromDel(pool, delTokens2, types.ShareRoundDown)
 	keeper.SetPool(ctx, pool)
 	validators[0] = TestingUpdateValidator(keeper, ctx, validators[0], false)
 	validators[1] = TestingUpdateValidator(keeper, ctx, validators[1], false)
@@ -751,7 +1096,7 @@ func TestApplyAndReturnValidatorSetUpdatesInserted(t *testing.T) {
 		validators[i] = types.NewValidator(sdk.ValAddress(Addrs[i]), PKs[i], types.Description{})
 
 		tokens := sdk.TokensFromTendermintPower(power)
-		validators[i], pool, _ = validators[i].AddTokensFromDel(pool, tokens)
+		validators[i], pool, _ = validators[i].AddTokensFromDel(pool, tokens, types.ShareRoundDown)
 		keeper.SetPool(ctx, pool)
 	}
 
@@ -800,7 +1145,7 @@ func TestApplyAndReturnValidatorSetUpdatesWithCliffValidator(t *testing.T) {
 		validators[i] = types.NewValidator(sdk.ValAddress(Addrs[i]), PKs[i], types.Description{})
 
 		tokens := sdk.TokensFromTendermintPower(power)
-		validators[i], pool, _ = validators[i].AddTokensFromDel(pool, tokens)
+		validators[i], pool, _ = validators[i].AddTokensFromDel(pool, tokens, types.ShareRoundDown)
 		keeper.SetPool(ctx, pool)
 	}
 	validators[0] = TestingUpdateValidator(keeper, ctx, validators[0], false)
@@ -819,7 +1164,7 @@ func TestApplyAndReturnValidatorSetUpdatesWithCliffValidator(t *testing.T) {
 
 	pool := keeper.GetPool(ctx)
 	tokens := sdk.TokensFromTendermintPower(10)
-	validators[2], pool, _ = validators[2].AddTokensFromDel(pool, tokens)
+	validators[2], pool, _ = validators[2].AddTokensFromDel(pool, tokens, types.ShareRoundDown)
 	keeper.SetPool(ctx, pool)
 	keeper.SetValidator(ctx, validators[2])
 	keeper.SetValidatorByPowerIndex(ctx, validators[2])
@@ -840,7 +1185,7 @@ func TestApplyAndReturnValidatorSetUpdatesPowerDecrease(t *testing.T) {
 		validators[i] = types.NewValidator(sdk.ValAddress(Addrs[i]), PKs[i], types.Description{})
 
 		tokens := sdk.TokensFromTendermintPower(power)
-		validators[i], pool, _ = validators[i].AddTokensFromDel(pool, tokens)
+		validators[i], pool, _ = validators[i].AddTokensFromDel(pool, tokens, types.ShareRoundDown)
 		keeper.SetPool(ctx, pool)
 	}
 	validators[0] = TestingUpdateValidator(keeper, ctx, validators[0], false)
@@ -856,8 +1201,8 @@ func TestApplyAndReturnValidatorSetUpdatesPowerDecrease(t *testing.T) {
 	pool := keeper.GetPool(ctx)
 	delTokens1 := sdk.TokensFromTendermintPower(20)
 	delTokens2 := sdk.TokensFromTendermintPower(30)
-	validators[0], pool, _ = validators[0].RemoveDelShares(pool, delTokens1.ToDec())
-	validators[1], pool, _ = validators[1].RemoveDelShares(pool, delTokens2.ToDec())
+	validators[0], pool, _ = validators[0].RemoveDelShares(pool, delTokens1.ToDec(), types.ShareRoundDown)
+	validators[1], pool, _ = validators[1].RemoveDelShares(pool, delTokens2.ToDec(), types.ShareRoundDown)
 	keeper.SetPool(ctx, pool)
 	validators[0] = TestingUpdateValidator(keeper, ctx, validators[0], false)
 	validators[1] = TestingUpdateValidator(keeper, ctx, validators[1], false)
@@ -891,7 +1236,7 @@ func TestApplyAndReturnValidatorSetUpdatesNewValidator(t *testing.T) {
 
 		validators[i] = types.NewValidator(valAddr, valPubKey, types.Description{})
 		tokens := sdk.TokensFromTendermintPower(power)
-		validators[i], pool, _ = validators[i].AddTokensFromDel(pool, tokens)
+		validators[i], pool, _ = validators[i].AddTokensFromDel(pool, tokens, types.ShareRoundDown)
 
 		keeper.SetPool(ctx, pool)
 		keeper.SetValidator(ctx, validators[i])
@@ -913,7 +1258,7 @@ func TestApplyAndReturnValidatorSetUpdatesNewValidator(t *testing.T) {
 		pool := keeper.GetPool(ctx)
 		keeper.DeleteValidatorByPowerIndex(ctx, validators[i])
 		tokens := sdk.TokensFromTendermintPower(power)
-		validators[i], pool, _ = validators[i].AddTokensFromDel(pool, tokens)
+		validators[i], pool, _ = validators[i].AddTokensFromDel(pool, tokens, types.ShareRoundDown)
 
 		keeper.SetPool(ctx, pool)
 		keeper.SetValidator(ctx, validators[i])
@@ -928,12 +1273,12 @@ func TestApplyAndReturnValidatorSetUpdatesNewValidator(t *testing.T) {
 	amt := sdk.NewInt(100)
 
 	validator := types.NewValidator(valAddr, valPubKey, types.Description{})
-	validator, pool, _ = validator.AddTokensFromDel(pool, amt)
+	validator, pool, _ = validator.AddTokensFromDel(pool, amt, types.ShareRoundDown)
 
 	keeper.SetPool(ctx, pool)
 	keeper.SetValidator(ctx, validator)
 
-	validator, pool, _ = validator.RemoveDelShares(pool, amt.ToDec())
+	validator, pool, _ = validator.RemoveDelShares(pool, amt.ToDec(), types.ShareRoundDown)
 	keeper.SetValidator(ctx, validator)
 	keeper.SetValidatorByPowerIndex(ctx, validator)
 
@@ -943,7 +1288,7 @@ func TestApplyAndReturnValidatorSetUpdatesNewValidator(t *testing.T) {
 
 	validator = types.NewValidator(valAddr, valPubKey, types.Description{})
 	tokens := sdk.TokensFromTendermintPower(500)
-	validator, pool, _ = validator.AddTokensFromDel(pool, tokens)
+	validator, pool, _ = validator.AddTokensFromDel(pool, tokens, types.ShareRoundDown)
 	keeper.SetValidator(ctx, validator)
 	keeper.SetValidatorByPowerIndex(ctx, validator)
 	keeper.SetPool(ctx, pool)
@@ -978,7 +1323,7 @@ func TestApplyAndReturnValidatorSetUpdatesBondTransition(t *testing.T) {
 
 		validators[i] = types.NewValidator(valAddr, valPubKey, types.Description{Moniker: moniker})
 		tokens := sdk.TokensFromTendermintPower(power)
-		validators[i], pool, _ = validators[i].AddTokensFromDel(pool, tokens)
+		validators[i], pool, _ = validators[i].AddTokensFromDel(pool, tokens, types.ShareRoundDown)
 		keeper.SetPool(ctx, pool)
 		keeper.SetValidator(ctx, validators[i])
 		keeper.SetValidatorByPowerIndex(ctx, validators[i])
@@ -1004,7 +1349,7 @@ func TestApplyAndReturnValidatorSetUpdatesBondTransition(t *testing.T) {
 
 	keeper.DeleteValidatorByPowerIndex(ctx, validators[0])
 	tokens := sdk.TokensFromTendermintPower(1)
-	validators[0], pool, _ = validators[0].AddTokensFromDel(pool, tokens)
+	validators[0], pool, _ = validators[0].AddTokensFromDel(pool, tokens, types.ShareRoundDown)
 	keeper.SetPool(ctx, pool)
 	keeper.SetValidator(ctx, validators[0])
 	keeper.SetValidatorByPowerIndex(ctx, validators[0])
@@ -1021,7 +1366,7 @@ func TestApplyAndReturnValidatorSetUpdatesBondTransition(t *testing.T) {
 	require.True(t, found)
 
 	keeper.DeleteValidatorByPowerIndex(ctx, validators[0])
-	validators[0], pool, _ = validators[0].RemoveDelShares(pool, validators[0].DelegatorShares)
+	validators[0], pool, _ = validators[0].RemoveDelShares(pool, validators[0].DelegatorShares, types.ShareRoundDown)
 	keeper.SetPool(ctx, pool)
 	keeper.SetValidator(ctx, validators[0])
 	keeper.SetValidatorByPowerIndex(ctx, validators[0])
@@ -1030,7 +1375,7 @@ func TestApplyAndReturnValidatorSetUpdatesBondTransition(t *testing.T) {
 
 	keeper.DeleteValidatorByPowerIndex(ctx, validators[1])
 	tokens = sdk.TokensFromTendermintPower(250)
-	validators[1], pool, _ = validators[1].AddTokensFromDel(pool, tokens)
+	validators[1], pool, _ = validators[1].AddTokensFromDel(pool, tokens, types.ShareRoundDown)
 	keeper.SetPool(ctx, pool)
 	keeper.SetValidator(ctx, validators[1])
 	keeper.SetValidatorByPowerIndex(ctx, validators[1])
@@ -1099,3 +1444,434 @@ func TestUpdateValidatorCommission(t *testing.T) {
 		}
 	}
 }
+
+func TestGetValidatorBondingStatus(t *testing.T) {
+	ctx, _, keeper := CreateTestInput(t, false, 1000)
+	params := keeper.GetParams(ctx)
+	params.MaxValidators = 1
+	keeper.SetParams(ctx, params)
+
+	// create a validator self-delegating the given power
+	createValidator := func(i int, power int64) types.Validator {
+		valAddr := sdk.ValAddress(Addrs[i])
+		validator := types.NewValidator(valAddr, PKs[i], types.Description{})
+		keeper.SetValidator(ctx, validator)
+		keeper.SetNewValidatorByPowerIndex(ctx, validator)
+		_, err := keeper.Delegate(ctx, Addrs[i], sdk.TokensFromTendermintPower(power), validator, true)
+		require.Nil(t, err)
+		return keeper.mustGetValidator(ctx, valAddr)
+	}
+
+	cliff := createValidator(0, 20)
+	belowCliff := createValidator(1, 10)
+	jailed := createValidator(2, 30)
+	keeper.jailValidator(ctx, jailed)
+	keeper.ApplyAndReturnValidatorSetUpdates(ctx)
+
+	status := keeper.GetValidatorBondingStatus(ctx, cliff.OperatorAddress)
+	require.Equal(t, types.BondingStatus{Bonded: true}, status)
+	require.True(t, status.IsEligible())
+
+	// below cliff, needing one more unit of power than the cliff validator
+	status = keeper.GetValidatorBondingStatus(ctx, belowCliff.OperatorAddress)
+	require.Equal(t, types.BondingStatus{BelowCliff: true, PowerToCliff: 11}, status)
+	require.False(t, status.IsEligible())
+
+	// jailed, despite having more power than the cliff validator
+	status = keeper.GetValidatorBondingStatus(ctx, jailed.OperatorAddress)
+	require.Equal(t, types.BondingStatus{Jailed: true}, status)
+	require.False(t, status.IsEligible())
+
+	// below the minimum self-delegation
+	cliff = keeper.mustGetValidator(ctx, cliff.OperatorAddress)
+	cliff.MinSelfDelegation = sdk.TokensFromTendermintPower(21)
+	keeper.SetValidator(ctx, cliff)
+	status = keeper.GetValidatorBondingStatus(ctx, cliff.OperatorAddress)
+	require.Equal(t, types.BondingStatus{Bonded: true, BelowMinSelfDelegation: true}, status)
+	require.False(t, status.IsEligible())
+
+	// frozen by governance
+	cliff.MinSelfDelegation = sdk.TokensFromTendermintPower(20)
+	keeper.SetValidator(ctx, cliff)
+	require.Nil(t, keeper.SetValidatorFrozen(ctx, cliff.OperatorAddress, true))
+	status = keeper.GetValidatorBondingStatus(ctx, cliff.OperatorAddress)
+	require.Equal(t, types.BondingStatus{Bonded: true, Frozen: true}, status)
+	require.False(t, status.IsEligible())
+}
+
+func TestGetWaitingValidators(t *testing.T) {
+	ctx, _, keeper := CreateTestInput(t, false, 1000)
+	params := keeper.GetParams(ctx)
+	params.MaxValidators = 2
+	keeper.SetParams(ctx, params)
+
+	// create a validator self-delegating the given power
+	createValidator := func(i int, power int64) types.Validator {
+		valAddr := sdk.ValAddress(Addrs[i])
+		validator := types.NewValidator(valAddr, PKs[i], types.Description{})
+		keeper.SetValidator(ctx, validator)
+		keeper.SetNewValidatorByPowerIndex(ctx, validator)
+		_, err := keeper.Delegate(ctx, Addrs[i], sdk.TokensFromTendermintPower(power), validator, true)
+		require.Nil(t, err)
+		return keeper.mustGetValidator(ctx, valAddr)
+	}
+
+	createValidator(0, 40)
+	createValidator(1, 30)
+	keeper.ApplyAndReturnValidatorSetUpdates(ctx)
+	require.Empty(t, keeper.GetWaitingValidators(ctx))
+
+	waiting1 := createValidator(2, 20)
+	waiting2 := createValidator(3, 10)
+	jailed := createValidator(4, 25)
+	keeper.jailValidator(ctx, jailed)
+	frozen := createValidator(5, 15)
+	require.Nil(t, keeper.SetValidatorFrozen(ctx, frozen.OperatorAddress, true))
+
+	// a validator without power can't bond
+	zeroPower := types.NewValidator(sdk.ValAddress(Addrs[6]), PKs[6], types.Description{})
+	keeper.SetValidator(ctx, zeroPower)
+	keeper.SetNewValidatorByPowerIndex(ctx, zeroPower)
+	_, err := keeper.Delegate(ctx, Addrs[6], sdk.OneInt(), zeroPower, true)
+	require.Nil(t, err)
+	keeper.ApplyAndReturnValidatorSetUpdates(ctx)
+
+	waiting := keeper.GetWaitingValidators(ctx)
+	require.Len(t, waiting, 2)
+	require.Equal(t, waiting1.OperatorAddress, waiting[0].OperatorAddress)
+	require.Equal(t, waiting2.OperatorAddress, waiting[1].OperatorAddress)
+}
+
+func TestGetDecentralizationScore(t *testing.T) {
+	// bond validators self-delegating the given powers
+	score := func(powers ...int64) sdk.Dec {
+		ctx, _, keeper := CreateTestInput(t, false, 1000)
+		for i, power := range powers {
+			validator := types.NewValidator(sdk.ValAddress(Addrs[i]), PKs[i], types.Description{})
+			keeper.SetValidator(ctx, validator)
+			keeper.SetNewValidatorByPowerIndex(ctx, validator)
+			_, err := keeper.Delegate(ctx, Addrs[i], sdk.TokensFromTendermintPower(power), validator, true)
+			require.Nil(t, err)
+		}
+		keeper.ApplyAndReturnValidatorSetUpdates(ctx)
+		return keeper.GetDecentralizationScore(ctx)
+	}
+
+	require.True(t, score().IsZero())
+	require.True(t, score(100).IsZero())
+
+	equal := score(50, 50, 50, 50)
+	require.True(t, equal.GT(sdk.NewDecWithPrec(999, 3)), "got %v", equal)
+	require.True(t, equal.LTE(sdk.OneDec()), "got %v", equal)
+
+	concentrated := score(1000, 1, 1, 1)
+	require.True(t, concentrated.LT(sdk.NewDecWithPrec(5, 2)), "got %v", concentrated)
+	require.True(t, concentrated.IsPositive(), "got %v", concentrated)
+}
+
+func TestDecLn(t *testing.T) {
+	ln2 := sdk.MustNewDecFromStr("0.693147180559945309")
+	tolerance := sdk.NewDecWithPrec(1, 15)
+	require.True(t, decLn(sdk.OneDec()).IsZero())
+	require.True(t, decLn(sdk.NewDec(2)).Sub(ln2).Abs().LT(tolerance), "got %v", decLn(sdk.NewDec(2)))
+	require.True(t, decLn(sdk.NewDecWithPrec(125, 3)).Add(ln2.MulInt64(3)).Abs().LT(tolerance))
+	require.True(t, decLn(sdk.NewDec(3)).Sub(sdk.MustNewDecFromStr("1.098612288668109691")).Abs().LT(tolerance))
+	require.Panics(t, func() { decLn(sdk.ZeroDec()) })
+}
+
+func TestIterateValidatorsByPower(t *testing.T) {
+	ctx, _, keeper := CreateTestInput(t, false, 1000)
+
+	powers := []int64{300, 100, 500, 200, 400}
+	for i, power := range powers {
+		pool := keeper.GetPool(ctx)
+		validator := types.NewValidator(sdk.ValAddress(Addrs[i]), PKs[i], types.Description{})
+		tokens := sdk.TokensFromTendermintPower(power)
+		validator, pool, _ = validator.AddTokensFromDel(pool, tokens, types.ShareRoundDown)
+		keeper.SetPool(ctx, pool)
+		TestingUpdateValidator(keeper, ctx, validator, true)
+	}
+
+	// the order matches the bonded validators sorted by power
+	var validators []types.Validator
+	keeper.IterateValidatorsByPower(ctx, func(index int64, validator types.Validator) bool {
+		require.Equal(t, int64(len(validators)), index)
+		validators = append(validators, validator)
+		return false
+	})
+	require.Equal(t, keeper.GetBondedValidatorsByPower(ctx), validators)
+
+	// stopping halts the iteration
+	var visited []int64
+	keeper.IterateValidatorsByPower(ctx, func(index int64, validator types.Validator) bool {
+		visited = append(visited, validator.GetTendermintPower())
+		return index == 1
+	})
+	require.Equal(t, []int64{500, 400}, visited)
+}
+
+func TestGetJailedValidators(t *testing.T) {
+	ctx, _, keeper := CreateTestInput(t, false, 1000)
+
+	powers := []int64{100, 200, 300}
+	for i, power := range powers {
+		pool := keeper.GetPool(ctx)
+		validator := types.NewValidator(addrVals[i], PKs[i], types.Description{})
+		validator, pool, _ = validator.AddTokensFromDel(pool, sdk.TokensFromTendermintPower(power), types.ShareRoundDown)
+		keeper.SetPool(ctx, pool)
+		validator = TestingUpdateValidator(keeper, ctx, validator, true)
+		keeper.SetValidatorByConsAddr(ctx, validator)
+	}
+	require.Empty(t, keeper.GetJailedValidators(ctx))
+
+	// jail two of the three validators
+	keeper.Jail(ctx, sdk.ConsAddress(PKs[0].Address()))
+	keeper.Jail(ctx, sdk.ConsAddress(PKs[2].Address()))
+
+	jailed := keeper.GetJailedValidators(ctx)
+	require.Len(t, jailed, 2)
+	jailedAddrs := []sdk.ValAddress{jailed[0].OperatorAddress, jailed[1].OperatorAddress}
+	require.ElementsMatch(t, []sdk.ValAddress{addrVals[0], addrVals[2]}, jailedAddrs)
+
+	// unjailed validators are no longer returned
+	keeper.Unjail(ctx, sdk.ConsAddress(PKs[0].Address()))
+	jailed = keeper.GetJailedValidators(ctx)
+	require.Len(t, jailed, 1)
+	require.Equal(t, addrVals[2], jailed[0].OperatorAddress)
+}
+
+func TestGetValidatorsUnbonding(t *testing.T) {
+	ctx, _, keeper := CreateTestInput(t, false, 1000)
+
+	powers := []int64{100, 200}
+	var validators [2]types.Validator
+	for i, power := range powers {
+		pool := keeper.GetPool(ctx)
+		validators[i] = types.NewValidator(sdk.ValAddress(Addrs[i]), PKs[i], types.Description{})
+		tokens := sdk.TokensFromTendermintPower(power)
+		validators[i], pool, _ = validators[i].AddTokensFromDel(pool, tokens, types.ShareRoundDown)
+		keeper.SetPool(ctx, pool)
+		validators[i] = TestingUpdateValidator(keeper, ctx, validators[i], true)
+		keeper.SetValidatorByConsAddr(ctx, validators[i])
+	}
+	require.Empty(t, keeper.GetValidatorsUnbonding(ctx))
+
+	// jailing the first validator moves it out of the bonded set
+	keeper.Jail(ctx, sdk.ConsAddress(PKs[0].Address()))
+	keeper.ApplyAndReturnValidatorSetUpdates(ctx)
+
+	unbonding := keeper.GetValidatorsUnbonding(ctx)
+	require.Len(t, unbonding, 1)
+	require.Equal(t, validators[0].OperatorAddress, unbonding[0].OperatorAddress)
+	require.Equal(t, sdk.Unbonding, unbonding[0].Status)
+
+	// still unbonding before completion
+	completion := unbonding[0].UnbondingCompletionTime
+	ctx = ctx.WithBlockTime(completion.Add(-time.Second))
+	keeper.UnbondAllMatureValidatorQueue(ctx)
+	require.Len(t, keeper.GetValidatorsUnbonding(ctx), 1)
+
+	// gone once the unbonding completes
+	ctx = ctx.WithBlockTime(completion)
+	keeper.UnbondAllMatureValidatorQueue(ctx)
+	require.Empty(t, keeper.GetValidatorsUnbonding(ctx))
+}
+
+func TestValidatorBondedTokensAtHeight(t *testing.T) {
+	ctx, _, keeper := CreateTestInput(t, false, 1000)
+	params := keeper.GetParams(ctx)
+	params.BondedTokensHistory = true
+	keeper.SetParams(ctx, params)
+
+	// two bonded validators and an unbonded one
+	powers := []int64{10, 20}
+	for i, power := range powers {
+		validator := types.NewValidator(addrVals[i], PKs[i], types.Description{})
+		pool := keeper.GetPool(ctx)
+		validator, pool, _ = validator.AddTokensFromDel(pool, sdk.TokensFromTendermintPower(power), types.ShareRoundDown)
+		keeper.SetPool(ctx, pool)
+		TestingUpdateValidator(keeper, ctx, validator, true)
+	}
+	keeper.SetValidator(ctx, types.NewValidator(addrVals[2], PKs[2], types.Description{}))
+
+	startTime := time.Unix(1000, 0)
+	ctx = ctx.WithBlockHeader(abci.Header{Height: 1, Time: startTime}).WithBlockHeight(1)
+	keeper.RecordValidatorBondedTokens(ctx)
+
+	// the first validator loses some tokens in the next block
+	keeper.RemoveValidatorTokens(ctx, keeper.mustGetValidator(ctx, addrVals[0]), sdk.TokensFromTendermintPower(4))
+	ctx = ctx.WithBlockHeader(abci.Header{Height: 2, Time: startTime.Add(time.Minute)}).WithBlockHeight(2)
+	keeper.RecordValidatorBondedTokens(ctx)
+
+	expected := map[int64][]int64{1: {10, 20}, 2: {6, 20}}
+	for height, heightPowers := range expected {
+		for i, power := range heightPowers {
+			tokens, found := keeper.GetValidatorBondedTokensAtHeight(ctx, addrVals[i], height)
+			require.True(t, found)
+			require.Equal(t, sdk.TokensFromTendermintPower(power), tokens)
+		}
+	}
+	_, found := keeper.GetValidatorBondedTokensAtHeight(ctx, addrVals[2], 2)
+	require.False(t, found)
+	_, found = keeper.GetValidatorBondedTokensAtHeight(ctx, addrVals[0], 3)
+	require.False(t, found)
+
+	// heights recorded longer than the unbonding time ago are pruned
+	ctx = ctx.WithBlockHeader(abci.Header{Height: 3, Time: startTime.Add(keeper.UnbondingTime(ctx) + 30*time.Second)}).WithBlockHeight(3)
+	keeper.RecordValidatorBondedTokens(ctx)
+	_, found = keeper.GetValidatorBondedTokensAtHeight(ctx, addrVals[0], 1)
+	require.False(t, found)
+	_, found = keeper.GetValidatorBondedTokensAtHeight(ctx, addrVals[1], 1)
+	require.False(t, found)
+	tokens, found := keeper.GetValidatorBondedTokensAtHeight(ctx, addrVals[0], 2)
+	require.True(t, found)
+	require.Equal(t, sdk.TokensFromTendermintPower(6), tokens)
+
+	// nothing is recorded once the history is disabled
+	params.BondedTokensHistory = false
+	keeper.SetParams(ctx, params)
+	ctx = ctx.WithBlockHeader(abci.Header{Height: 4, Time: startTime.Add(keeper.UnbondingTime(ctx) + time.Minute)}).WithBlockHeight(4)
+	keeper.RecordValidatorBondedTokens(ctx)
+	_, found = keeper.GetValidatorBondedTokensAtHeight(ctx, addrVals[0], 4)
+	require.False(t, found)
+}
+
+func TestValidatorExRateAtHeight(t *testing.T) {
+	ctx, _, keeper := CreateTestInput(t, false, 1000)
+	params := keeper.GetParams(ctx)
+	params.ExRateHistory = true
+	keeper.SetParams(ctx, params)
+
+	powers := []int64{10, 30}
+	for i, power := range powers {
+		validator := types.NewValidator(addrVals[i], PKs[i], types.Description{})
+		pool := keeper.GetPool(ctx)
+		validator, pool, _ = validator.AddTokensFromDel(pool, sdk.TokensFromTendermintPower(power), types.ShareRoundDown)
+		keeper.SetPool(ctx, pool)
+		TestingUpdateValidator(keeper, ctx, validator, true)
+	}
+
+	startTime := time.Unix(1000, 0)
+	ctx = ctx.WithBlockHeader(abci.Header{Height: 1, Time: startTime}).WithBlockHeight(1)
+	keeper.RecordValidatorExRates(ctx)
+
+	// a reward as large as the bonded tokens doubles every exchange rate
+	ctx = ctx.WithBlockHeader(abci.Header{Height: 2, Time: startTime.Add(time.Minute)}).WithBlockHeight(2)
+	keeper.AddBondedRewardPool(ctx, sdk.TokensFromTendermintPower(40).Int64())
+	keeper.RecordValidatorExRates(ctx)
+
+	for i := range powers {
+		exRate, found := keeper.GetValidatorExRateAtHeight(ctx, addrVals[i], 1)
+		require.True(t, found)
+		require.True(t, sdk.OneDec().Equal(exRate), "expected 1, got %v", exRate)
+		exRate, found = keeper.GetValidatorExRateAtHeight(ctx, addrVals[i], 2)
+		require.True(t, found)
+		require.True(t, sdk.NewDec(2).Equal(exRate), "expected 2, got %v", exRate)
+	}
+	_, found := keeper.GetValidatorExRateAtHeight(ctx, addrVals[2], 2)
+	require.False(t, found)
+
+	// heights recorded longer than the unbonding time ago are pruned
+	ctx = ctx.WithBlockHeader(abci.Header{Height: 3, Time: startTime.Add(keeper.UnbondingTime(ctx) + 30*time.Second)}).WithBlockHeight(3)
+	keeper.RecordValidatorExRates(ctx)
+	_, found = keeper.GetValidatorExRateAtHeight(ctx, addrVals[0], 1)
+	require.False(t, found)
+	_, found = keeper.GetValidatorExRateAtHeight(ctx, addrVals[0], 2)
+	require.True(t, found)
+
+	// the bonded tokens are recorded independently
+	_, found = keeper.GetValidatorBondedTokensAtHeight(ctx, addrVals[0], 2)
+	require.False(t, found)
+}
+
+func TestValidatorHistoryUnbondingTimeOverride(t *testing.T) {
+	ctx, _, keeper := CreateTestInput(t, false, 1000)
+	params := keeper.GetParams(ctx)
+	params.BondedTokensHistory = true
+	params.ExRateHistory = true
+	keeper.SetParams(ctx, params)
+
+	for i, power := range []int64{10, 20} {
+		validator := types.NewValidator(addrVals[i], PKs[i], types.Description{})
+		pool := keeper.GetPool(ctx)
+		validator, pool, _ = validator.AddTokensFromDel(pool, sdk.TokensFromTendermintPower(power), types.ShareRoundDown)
+		keeper.SetPool(ctx, pool)
+		TestingUpdateValidator(keeper, ctx, validator, true)
+	}
+
+	// the second validator can be slashed for twice the usual unbonding time
+	unbondingTime := keeper.UnbondingTime(ctx)
+	require.Nil(t, keeper.SetValidatorUnbondingTime(ctx, addrVals[1], 2*unbondingTime))
+
+	startTime := time.Unix(1000, 0)
+	ctx = ctx.WithBlockHeader(abci.Header{Height: 1, Time: startTime}).WithBlockHeight(1)
+	keeper.RecordValidatorBondedTokens(ctx)
+	keeper.RecordValidatorExRates(ctx)
+
+	// the records outlive the param while the override has not passed
+	ctx = ctx.WithBlockHeader(abci.Header{Height: 2, Time: startTime.Add(unbondingTime + time.Minute)}).WithBlockHeight(2)
+	keeper.RecordValidatorBondedTokens(ctx)
+	keeper.RecordValidatorExRates(ctx)
+	for i := 0; i < 2; i++ {
+		_, found := keeper.GetValidatorBondedTokensAtHeight(ctx, addrVals[i], 1)
+		require.True(t, found)
+		_, found = keeper.GetValidatorExRateAtHeight(ctx, addrVals[i], 1)
+		require.True(t, found)
+	}
+
+	// and are pruned once it has
+	ctx = ctx.WithBlockHeader(abci.Header{Height: 3, Time: startTime.Add(2*unbondingTime + time.Minute)}).WithBlockHeight(3)
+	keeper.RecordValidatorBondedTokens(ctx)
+	keeper.RecordValidatorExRates(ctx)
+	_, found := keeper.GetValidatorBondedTokensAtHeight(ctx, addrVals[1], 1)
+	require.False(t, found)
+	_, found = keeper.GetValidatorExRateAtHeight(ctx, addrVals[1], 1)
+	require.False(t, found)
+	_, found = keeper.GetValidatorBondedTokensAtHeight(ctx, addrVals[1], 2)
+	require.True(t, found)
+}
+
+func TestValidatorSetSizeAtHeight(t *testing.T) {
+	ctx, _, keeper := CreateTestInput(t, false, 1000)
+
+	bond := func(i int) {
+		validator := types.NewValidator(addrVals[i], PKs[i], types.Description{})
+		pool := keeper.GetPool(ctx)
+		validator, pool, _ = validator.AddTokensFromDel(pool, sdk.TokensFromTendermintPower(int64(10*(i+1))), types.ShareRoundDown)
+		keeper.SetPool(ctx, pool)
+		TestingUpdateValidator(keeper, ctx, validator, true)
+	}
+	endBlock := func(height int64) {
+		ctx = ctx.WithBlockHeight(height)
+		keeper.ApplyAndReturnValidatorSetUpdates(ctx)
+		keeper.RecordValidatorSetSize(ctx)
+	}
+
+	bond(0)
+	bond(1)
+	endBlock(1)
+	endBlock(2)
+
+	// a third validator bonds at height 3
+	bond(2)
+	endBlock(3)
+
+	// the set shrinks to one validator at height 5
+	params := keeper.GetParams(ctx)
+	params.MaxValidators = 1
+	keeper.SetParams(ctx, params)
+	endBlock(5)
+
+	_, found := keeper.GetValidatorSetSizeAtHeight(ctx, 0)
+	require.False(t, found)
+	expected := map[int64]int{1: 2, 2: 2, 3: 3, 4: 3, 5: 1, 100: 1}
+	for height, size := range expected {
+		got, found := keeper.GetValidatorSetSizeAtHeight(ctx, height)
+		require.True(t, found, "height %d", height)
+		require.Equal(t, size, got, "height %d", height)
+	}
+
+	// only the changes of the size are recorded
+	store := ctx.KVStore(keeper.storeKey)
+	require.Nil(t, store.Get(GetValidatorSetSizeKey(2)))
+}
@@ -0,0 +1,61 @@
+package staking
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	govtypes "github.com/cosmos/cosmos-sdk/x/gov/types"
+	"github.com/cosmos/cosmos-sdk/x/staking/keeper"
+	"github.com/cosmos/cosmos-sdk/x/staking/types"
+)
+
+// NewProposalHandler returns a handler for the staking governance proposals
+func NewProposalHandler(k keeper.Keeper) govtypes.Handler {
+	return func(ctx sdk.Context, content govtypes.Content) sdk.Error {
+		switch c := content.(type) {
+		case types.FreezeValidatorProposal:
+			return handleFreezeValidatorProposal(ctx, k, c)
+
+		case types.CompleteUnbondingsProposal:
+			return handleCompleteUnbondingsProposal(ctx, k, c)
+
+		case types.VerifyValidatorIdentityProposal:
+			return handleVerifyValidatorIdentityProposal(ctx, k, c)
+
+		default:
+			errMsg := fmt.Sprintf("unrecognized staking proposal content type: %T", c)
+			return sdk.ErrUnknownRequest(errMsg)
+		}
+	}
+}
+
+func handleFreezeValidatorProposal(ctx sdk.Context, k keeper.Keeper, p types.FreezeValidatorProposal) sdk.Error {
+	if err := k.SetValidatorFrozen(ctx, p.ValidatorAddress, p.Frozen); err != nil {
+		return err
+	}
+	k.Logger(ctx).Info(
+		fmt.Sprintf("set delegations of validator %s frozen: %t", p.ValidatorAddress, p.Frozen),
+	)
+	return nil
+}
+
+func handleCompleteUnbondingsProposal(ctx sdk.Context, k keeper.Keeper, _ types.CompleteUnbondingsProposal) sdk.Error {
+	unbonds, redelegations, err := k.CompleteAllUnbondings(ctx)
+	if err != nil {
+		return err
+	}
+	k.Logger(ctx).Info(
+		fmt.Sprintf("completed %d unbonding delegations and %d redelegations", len(unbonds), len(redelegations)),
+	)
+	return nil
+}
+
+func handleVerifyValidatorIdentityProposal(ctx sdk.Context, k keeper.Keeper, p types.VerifyValidatorIdentityProposal) sdk.Error {
+	if err := k.SetValidatorIdentityVerified(ctx, p.ValidatorAddress, p.Verified); err != nil {
+		return err
+	}
+	k.Logger(ctx).Info(
+		fmt.Sprintf("set identity of validator %s verified: %t", p.ValidatorAddress, p.Verified),
+	)
+	return nil
+}
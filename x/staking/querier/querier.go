@@ -22,17 +22,29 @@ const (
 	QueryValidatorDelegations          = "validatorDelegations"
 	QueryValidatorRedelegations        = "validatorRedelegations"
 	QueryValidatorUnbondingDelegations = "validatorUnbondingDelegations"
+	QueryValidatorSlashEvents          = "validatorSlashEvents"
+	QueryValidatorEntryCost            = "validatorEntryCost"
+	QueryValidatorBondingStatus        = "validatorBondingStatus"
+	QueryValidatorConcentration        = "validatorConcentration"
+	QueryGenesisValidators             = "genesisValidators"
+	QueryValidatorsUnbonding           = "validatorsUnbonding"
+	QueryJailedValidators              = "jailedValidators"
+	QueryDelegationsAboveValue         = "delegationsAboveValue"
+	QueryDelegationCount               = "delegationCount"
 	QueryDelegator                     = "delegator"
 	QueryDelegation                    = "delegation"
 	QueryUnbondingDelegation           = "unbondingDelegation"
 	QueryDelegatorValidators           = "delegatorValidators"
 	QueryDelegatorValidator            = "delegatorValidator"
 	QueryPool                          = "pool"
+	QueryBondedRatioHistory            = "bondedRatioHistory"
 	QueryParameters                    = "parameters"
+	QueryInvariants                    = "invariants"
 )
 
-// creates a querier for staking REST endpoints
-func NewQuerier(k keep.Keeper, cdc *codec.Codec) sdk.Querier {
+// creates a querier for staking REST endpoints; invarRoutes are the
+// invariants reported by the invariants query
+func NewQuerier(k keep.Keeper, cdc *codec.Codec, invarRoutes []keep.InvariantRoute) sdk.Querier {
 	return func(ctx sdk.Context, path []string, req abci.RequestQuery) (res []byte, err sdk.Error) {
 		switch path[0] {
 		case QueryValidators:
@@ -43,6 +55,24 @@ func NewQuerier(k keep.Keeper, cdc *codec.Codec) sdk.Querier {
 			return queryValidatorDelegations(ctx, cdc, req, k)
 		case QueryValidatorUnbondingDelegations:
 			return queryValidatorUnbondingDelegations(ctx, cdc, req, k)
+		case QueryValidatorSlashEvents:
+			return queryValidatorSlashEvents(ctx, cdc, req, k)
+		case QueryValidatorEntryCost:
+			return queryValidatorEntryCost(ctx, cdc, k)
+		case QueryValidatorBondingStatus:
+			return queryValidatorBondingStatus(ctx, cdc, req, k)
+		case QueryValidatorConcentration:
+			return queryValidatorConcentration(ctx, cdc, req, k)
+		case QueryGenesisValidators:
+			return queryGenesisValidators(ctx, cdc, k)
+		case QueryValidatorsUnbonding:
+			return queryValidatorsUnbonding(ctx, cdc, k)
+		case QueryJailedValidators:
+			return queryJailedValidators(ctx, cdc, k)
+		case QueryDelegationsAboveValue:
+			return queryDelegationsAboveValue(ctx, cdc, req, k)
+		case QueryDelegationCount:
+			return queryDelegationCount(ctx, cdc, k)
 		case QueryDelegation:
 			return queryDelegation(ctx, cdc, req, k)
 		case QueryUnbondingDelegation:
@@ -59,8 +89,12 @@ func NewQuerier(k keep.Keeper, cdc *codec.Codec) sdk.Querier {
 			return queryDelegatorValidator(ctx, cdc, req, k)
 		case QueryPool:
 			return queryPool(ctx, cdc, k)
+		case QueryBondedRatioHistory:
+			return queryBondedRatioHistory(ctx, cdc, req, k)
 		case QueryParameters:
 			return queryParameters(ctx, cdc, k)
+		case QueryInvariants:
+			return queryInvariants(ctx, cdc, invarRoutes)
 		default:
 			return nil, sdk.ErrUnknownRequest("unknown staking query endpoint")
 		}
@@ -87,6 +121,9 @@ func NewQueryDelegatorParams(delegatorAddr sdk.AccAddress) QueryDelegatorParams
 // - 'custom/staking/validatorDelegations'
 // - 'custom/staking/validatorUnbondingDelegations'
 // - 'custom/staking/validatorRedelegations'
+// - 'custom/staking/validatorSlashEvents'
+// - 'custom/staking/validatorBondingStatus'
+// - 'custom/staking/validatorConcentration'
 type QueryValidatorParams struct {
 	ValidatorAddr sdk.ValAddress
 }
@@ -227,6 +264,23 @@ func queryValidatorUnbondingDelegations(ctx sdk.Context, cdc *codec.Codec, req a
 	return res, nil
 }
 
+func queryValidatorSlashEvents(ctx sdk.Context, cdc *codec.Codec, req abci.RequestQuery, k keep.Keeper) (res []byte, err sdk.Error) {
+	var params QueryValidatorParams
+
+	errRes := cdc.UnmarshalJSON(req.Data, &params)
+	if errRes != nil {
+		return []byte{}, sdk.ErrInternal(fmt.Sprintf("failed to parse params: %s", errRes))
+	}
+
+	events := k.GetValidatorSlashEvents(ctx, params.ValidatorAddr)
+
+	res, errRes = codec.MarshalJSONIndent(cdc, events)
+	if errRes != nil {
+		return nil, sdk.ErrInternal(sdk.AppendMsgToErr("could not marshal result to JSON", errRes.Error()))
+	}
+	return res, nil
+}
+
 func queryDelegatorDelegations(ctx sdk.Context, cdc *codec.Codec, req abci.RequestQuery, k keep.Keeper) (res []byte, err sdk.Error) {
 	var params QueryDelegatorParams
 
@@ -313,7 +367,12 @@ func queryDelegation(ctx sdk.Context, cdc *codec.Codec, req abci.RequestQuery, k
 		return []byte{}, types.ErrNoDelegation(types.DefaultCodespace)
 	}
 
-	res, errRes = codec.MarshalJSONIndent(cdc, delegation)
+	sharePercentage, found := k.GetDelegationSharePercentage(ctx, params.DelegatorAddr, params.ValidatorAddr)
+	if !found {
+		return []byte{}, types.ErrNoValidatorFound(types.DefaultCodespace)
+	}
+
+	res, errRes = codec.MarshalJSONIndent(cdc, types.NewDelegationResponse(delegation, sharePercentage))
 	if errRes != nil {
 		return nil, sdk.ErrInternal(sdk.AppendMsgToErr("could not marshal result to JSON", errRes.Error()))
 	}
@@ -379,6 +438,152 @@ func queryPool(ctx sdk.Context, cdc *codec.Codec, k keep.Keeper) (res []byte, er
 	return res, nil
 }
 
+func queryDelegationCount(ctx sdk.Context, cdc *codec.Codec, k keep.Keeper) (res []byte, err sdk.Error) {
+	count := k.GetTotalDelegationCount(ctx)
+
+	res, errRes := codec.MarshalJSONIndent(cdc, count)
+	if errRes != nil {
+		return nil, sdk.ErrInternal(sdk.AppendMsgToErr("could not marshal result to JSON", errRes.Error()))
+	}
+	return res, nil
+}
+
+func queryDelegationsAboveValue(ctx sdk.Context, cdc *codec.Codec, req abci.RequestQuery, k keep.Keeper) (res []byte, err sdk.Error) {
+	var params QueryDelegationsAboveValueParams
+
+	errRes := cdc.UnmarshalJSON(req.Data, &params)
+	if errRes != nil {
+		return nil, sdk.ErrUnknownRequest(sdk.AppendMsgToErr("failed to parse params", errRes.Error()))
+	}
+	if params.Page < 1 || params.Limit < 1 {
+		return nil, sdk.ErrUnknownRequest("page and limit must be positive")
+	}
+
+	delegations := k.GetDelegationsAboveValue(ctx, params.MinTokens)
+
+	// get pagination bounds
+	start := (params.Page - 1) * params.Limit
+	end := params.Limit + start
+	if end >= len(delegations) {
+		end = len(delegations)
+	}
+
+	if start >= len(delegations) {
+		// page is out of bounds
+		delegations = []types.Delegation{}
+	} else {
+		delegations = delegations[start:end]
+	}
+
+	res, errRes = codec.MarshalJSONIndent(cdc, delegations)
+	if errRes != nil {
+		return nil, sdk.ErrInternal(sdk.AppendMsgToErr("could not marshal result to JSON", errRes.Error()))
+	}
+	return res, nil
+}
+
+func queryValidatorEntryCost(ctx sdk.Context, cdc *codec.Codec, k keep.Keeper) (res []byte, err sdk.Error) {
+	cost := k.GetValidatorEntryCost(ctx)
+
+	res, errRes := codec.MarshalJSONIndent(cdc, cost)
+	if errRes != nil {
+		return nil, sdk.ErrInternal(sdk.AppendMsgToErr("could not marshal result to JSON", errRes.Error()))
+	}
+	return res, nil
+}
+
+func queryValidatorBondingStatus(ctx sdk.Context, cdc *codec.Codec, req abci.RequestQuery, k keep.Keeper) (res []byte, err sdk.Error) {
+	var params QueryValidatorParams
+
+	errRes := cdc.UnmarshalJSON(req.Data, &params)
+	if errRes != nil {
+		return []byte{}, sdk.ErrInternal(fmt.Sprintf("failed to parse params: %s", errRes))
+	}
+
+	if _, found := k.GetValidator(ctx, params.ValidatorAddr); !found {
+		return []byte{}, types.ErrNoValidatorFound(types.DefaultCodespace)
+	}
+
+	status := k.GetValidatorBondingStatus(ctx, params.ValidatorAddr)
+
+	res, errRes = codec.MarshalJSONIndent(cdc, status)
+	if errRes != nil {
+		return nil, sdk.ErrInternal(sdk.AppendMsgToErr("could not marshal result to JSON", errRes.Error()))
+	}
+	return res, nil
+}
+
+func queryValidatorConcentration(ctx sdk.Context, cdc *codec.Codec, req abci.RequestQuery, k keep.Keeper) (res []byte, err sdk.Error) {
+	var params QueryValidatorParams
+
+	errRes := cdc.UnmarshalJSON(req.Data, &params)
+	if errRes != nil {
+		return []byte{}, sdk.ErrInternal(fmt.Sprintf("failed to parse params: %s", errRes))
+	}
+
+	if _, found := k.GetValidator(ctx, params.ValidatorAddr); !found {
+		return []byte{}, types.ErrNoValidatorFound(types.DefaultCodespace)
+	}
+
+	concentration, _ := k.GetValidatorDelegationConcentration(ctx, params.ValidatorAddr)
+
+	res, errRes = codec.MarshalJSONIndent(cdc, concentration)
+	if errRes != nil {
+		return nil, sdk.ErrInternal(sdk.AppendMsgToErr("could not marshal result to JSON", errRes.Error()))
+	}
+	return res, nil
+}
+
+func queryGenesisValidators(ctx sdk.Context, cdc *codec.Codec, k keep.Keeper) (res []byte, err sdk.Error) {
+	validators := k.GetGenesisValidators(ctx)
+
+	res, errRes := codec.MarshalJSONIndent(cdc, validators)
+	if errRes != nil {
+		return nil, sdk.ErrInternal(sdk.AppendMsgToErr("could not marshal result to JSON", errRes.Error()))
+	}
+	return res, nil
+}
+
+func queryValidatorsUnbonding(ctx sdk.Context, cdc *codec.Codec, k keep.Keeper) (res []byte, err sdk.Error) {
+	validators := k.GetValidatorsUnbonding(ctx)
+
+	res, errRes := codec.MarshalJSONIndent(cdc, validators)
+	if errRes != nil {
+		return nil, sdk.ErrInternal(sdk.AppendMsgToErr("could not marshal result to JSON", errRes.Error()))
+	}
+	return res, nil
+}
+
+func queryBondedRatioHistory(ctx sdk.Context, cdc *codec.Codec, req abci.RequestQuery, k keep.Keeper) (res []byte, err sdk.Error) {
+	var params QueryBondedRatioHistoryParams
+
+	errRes := cdc.UnmarshalJSON(req.Data, &params)
+	if errRes != nil {
+		return nil, sdk.ErrUnknownRequest(sdk.AppendMsgToErr("failed to parse params", errRes.Error()))
+	}
+
+	records := k.GetBondedRatioHistory(ctx, params.From, params.To)
+	if records == nil {
+		records = []types.BondedRatioRecord{}
+	}
+
+	res, errRes = codec.MarshalJSONIndent(cdc, records)
+	if errRes != nil {
+		return nil, sdk.ErrInternal(sdk.AppendMsgToErr("could not marshal result to JSON", errRes.Error()))
+	}
+	return res, nil
+}
+
+func queryJailedValidators(ctx sdk.Context, cdc *codec.Codec, k keep.Keeper) (res []byte, err sdk.Error) {
+	validators := k.GetJailedValidators(ctx)
+
+	res, errRes := codec.MarshalJSONIndent(cdc, validators)
+	if errRes != nil {
+		return nil, sdk.ErrInternal(sdk.AppendMsgToErr("could not marshal result to JSON", errRes.Error()))
+	}
+	return res, nil
+}
+
 func queryParameters(ctx sdk.Context, cdc *codec.Codec, k keep.Keeper) (res []byte, err sdk.Error) {
 	params := k.GetParams(ctx)
 
@@ -389,6 +594,16 @@ func queryParameters(ctx sdk.Context, cdc *codec.Codec, k keep.Keeper) (res []by
 	return res, nil
 }
 
+func queryInvariants(ctx sdk.Context, cdc *codec.Codec, invarRoutes []keep.InvariantRoute) (res []byte, err sdk.Error) {
+	checks := keep.CheckInvariants(ctx, invarRoutes)
+
+	res, errRes := codec.MarshalJSONIndent(cdc, checks)
+	if errRes != nil {
+		return nil, sdk.ErrInternal(sdk.AppendMsgToErr("could not marshal result to JSON", errRes.Error()))
+	}
+	return res, nil
+}
+
 // QueryValidatorsParams defines the params for the following queries:
 // - 'custom/staking/validators'
 type QueryValidatorsParams struct {
@@ -399,3 +614,25 @@ type QueryValidatorsParams struct {
 func NewQueryValidatorsParams(page, limit int, status string) QueryValidatorsParams {
 	return QueryValidatorsParams{page, limit, status}
 }
+
+// QueryDelegationsAboveValueParams defines the params for the following queries:
+// - 'custom/staking/delegationsAboveValue'
+type QueryDelegationsAboveValueParams struct {
+	Page, Limit int
+	MinTokens   sdk.Int
+}
+
+func NewQueryDelegationsAboveValueParams(page, limit int, minTokens sdk.Int) QueryDelegationsAboveValueParams {
+	return QueryDelegationsAboveValueParams{page, limit, minTokens}
+}
+
+// QueryBondedRatioHistoryParams defines the block height range, inclusive, for
+// the following queries:
+// - 'custom/staking/bondedRatioHistory'
+type QueryBondedRatioHistoryParams struct {
+	From, To int64
+}
+
+func NewQueryBondedRatioHistoryParams(from, to int64) QueryBondedRatioHistoryParams {
+	return QueryBondedRatioHistoryParams{from, to}
+}
@@ -1,7 +1,10 @@
 package querier
 
 import (
+	"encoding/json"
 	"fmt"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -28,7 +31,7 @@ func TestNewQuerier(t *testing.T) {
 	var validators [2]types.Validator
 	for i, amt := range amts {
 		validators[i] = types.NewValidator(sdk.ValAddress(keep.Addrs[i]), keep.PKs[i], types.Description{})
-		validators[i], pool, _ = validators[i].AddTokensFromDel(pool, amt)
+		validators[i], pool, _ = validators[i].AddTokensFromDel(pool, amt, types.ShareRoundDown)
 		keeper.SetValidator(ctx, validators[i])
 		keeper.SetValidatorByPowerIndex(ctx, validators[i])
 	}
@@ -39,7 +42,7 @@ func TestNewQuerier(t *testing.T) {
 		Data: []byte{},
 	}
 
-	querier := NewQuerier(keeper, cdc)
+	querier := NewQuerier(keeper, cdc, nil)
 
 	bz, err := querier(ctx, []string{"other"}, query)
 	require.NotNil(t, err)
@@ -112,6 +115,171 @@ func TestQueryParametersPool(t *testing.T) {
 	require.Equal(t, keeper.GetPool(ctx), pool)
 }
 
+func TestQueryParametersDefaults(t *testing.T) {
+	cdc := codec.New()
+	ctx, _, keeper := keep.CreateTestInput(t, false, 1000)
+	querier := NewQuerier(keeper, cdc, nil)
+
+	res, err := querier(ctx, []string{QueryParameters}, abci.RequestQuery{})
+	require.Nil(t, err)
+
+	// every param is present
+	var fields map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(res, &fields))
+	paramsType := reflect.TypeOf(types.Params{})
+	for i := 0; i < paramsType.NumField(); i++ {
+		name := strings.Split(paramsType.Field(i).Tag.Get("json"), ",")[0]
+		require.Contains(t, fields, name)
+	}
+	require.Len(t, fields, paramsType.NumField())
+
+	// with its default value
+	var params types.Params
+	require.NoError(t, cdc.UnmarshalJSON(res, &params))
+	require.True(t, types.DefaultParams().Equal(params), "expected %v, got %v", types.DefaultParams(), params)
+}
+
+// mock fee collection and distribution keepers holding no tokens
+type mockFeeKeeper struct{}
+
+func (mockFeeKeeper) GetCollectedFees(_ sdk.Context) sdk.Coins { return sdk.Coins{} }
+
+type mockDistrKeeper struct{}
+
+func (mockDistrKeeper) GetFeePoolCommunityCoins(_ sdk.Context) sdk.DecCoins { return sdk.DecCoins{} }
+func (mockDistrKeeper) GetValidatorOutstandingRewardsCoins(_ sdk.Context, _ sdk.ValAddress) sdk.DecCoins {
+	return sdk.DecCoins{}
+}
+
+func TestQueryInvariants(t *testing.T) {
+	cdc := codec.New()
+	ctx, am, keeper := keep.CreateTestInput(t, false, 1000)
+	invarRoutes := keep.InvariantRoutes(keeper, mockFeeKeeper{}, mockDistrKeeper{}, am)
+	querier := NewQuerier(keeper, cdc, invarRoutes)
+	query := abci.RequestQuery{Path: "/custom/staking/invariants"}
+
+	// all invariants hold on a fresh state
+	res, err := querier(ctx, []string{QueryInvariants}, query)
+	require.Nil(t, err)
+
+	var checks []keep.InvariantCheck
+	require.Nil(t, cdc.UnmarshalJSON(res, &checks))
+	require.Len(t, checks, len(invarRoutes))
+	for _, check := range checks {
+		require.True(t, check.Passed, check.Route)
+		require.Empty(t, check.Detail)
+	}
+
+	// corrupt the pool, the supply check must report the failure while the
+	// remaining checks are still run
+	pool := keeper.GetPool(ctx)
+	pool.NotBondedTokens = pool.NotBondedTokens.Add(sdk.NewInt(1))
+	keeper.SetPool(ctx, pool)
+
+	res, err = querier(ctx, []string{QueryInvariants}, query)
+	require.Nil(t, err)
+	require.Nil(t, cdc.UnmarshalJSON(res, &checks))
+	require.Len(t, checks, len(invarRoutes))
+	for _, check := range checks {
+		if check.Route == "supply" {
+			require.False(t, check.Passed)
+			require.NotEmpty(t, check.Detail)
+			continue
+		}
+		require.True(t, check.Passed, check.Route)
+	}
+
+	// the query itself must not modify state
+	require.Equal(t, pool, keeper.GetPool(ctx))
+}
+
+func TestQueryValidatorEntryCost(t *testing.T) {
+	cdc := codec.New()
+	ctx, _, keeper := keep.CreateTestInput(t, false, 10000)
+
+	powers := []int64{10, 30, 20}
+	for i, power := range powers {
+		pool := keeper.GetPool(ctx)
+		validator := types.NewValidator(sdk.ValAddress(keep.Addrs[i]), keep.PKs[i], types.Description{})
+		validator, pool, _ = validator.AddTokensFromDel(pool, sdk.TokensFromTendermintPower(power), types.ShareRoundDown)
+		keeper.SetPool(ctx, pool)
+		keep.TestingUpdateValidator(keeper, ctx, validator, true)
+	}
+
+	// the set is not full
+	res, err := queryValidatorEntryCost(ctx, cdc, keeper)
+	require.Nil(t, err)
+
+	var cost sdk.Int
+	require.Nil(t, cdc.UnmarshalJSON(res, &cost))
+	require.True(sdk.IntEq(t, sdk.ZeroInt(), cost))
+
+	// fill the set, the validator with power 20 becomes the cliff
+	params := keeper.GetParams(ctx)
+	params.MaxValidators = 2
+	keeper.SetParams(ctx, params)
+	keeper.ApplyAndReturnValidatorSetUpdates(ctx)
+
+	cliff, found := keeper.GetValidatorCliff(ctx)
+	require.True(t, found)
+	require.Equal(t, sdk.ValAddress(keep.Addrs[2]), cliff.OperatorAddress)
+
+	res, err = queryValidatorEntryCost(ctx, cdc, keeper)
+	require.Nil(t, err)
+	require.Nil(t, cdc.UnmarshalJSON(res, &cost))
+	require.True(sdk.IntEq(t, sdk.TokensFromTendermintPower(21), cost))
+}
+
+func TestQueryDelegationsAboveValue(t *testing.T) {
+	cdc := codec.New()
+	ctx, _, keeper := keep.CreateTestInput(t, false, 10000)
+
+	validator := types.NewValidator(addrVal1, pk1, types.Description{})
+	keeper.SetValidator(ctx, validator)
+
+	// the validator is slashed so its shares are worth half their tokens
+	amounts := []int64{100, 3000, 50, 2000, 1000}
+	for i, amt := range amounts {
+		_, err := keeper.Delegate(ctx, keep.Addrs[i], sdk.TokensFromTendermintPower(amt), validator, true)
+		require.Nil(t, err)
+		validator, _ = keeper.GetValidator(ctx, addrVal1)
+	}
+	pool := keeper.GetPool(ctx)
+	validator, pool = validator.RemoveTokens(pool, validator.Tokens.QuoRaw(2))
+	keeper.SetPool(ctx, pool)
+	keeper.SetValidator(ctx, validator)
+
+	// a delegation worth exactly the threshold is excluded
+	delegations := keeper.GetDelegationsAboveValue(ctx, sdk.TokensFromTendermintPower(500))
+	require.Len(t, delegations, 2)
+	for _, delegation := range delegations {
+		require.True(t, validator.TokensFromShares(delegation.Shares).GT(sdk.NewDecFromInt(sdk.TokensFromTendermintPower(500))))
+	}
+
+	query := abci.RequestQuery{Path: "/custom/staking/delegationsAboveValue"}
+	params := NewQueryDelegationsAboveValueParams(1, 10, sdk.TokensFromTendermintPower(30))
+	query.Data = cdc.MustMarshalJSON(params)
+	res, err := queryDelegationsAboveValue(ctx, cdc, query, keeper)
+	require.Nil(t, err)
+
+	var queried []types.Delegation
+	require.Nil(t, cdc.UnmarshalJSON(res, &queried))
+	require.Len(t, queried, 4)
+
+	// paginated
+	params = NewQueryDelegationsAboveValueParams(2, 3, sdk.TokensFromTendermintPower(30))
+	query.Data = cdc.MustMarshalJSON(params)
+	res, err = queryDelegationsAboveValue(ctx, cdc, query, keeper)
+	require.Nil(t, err)
+	require.Nil(t, cdc.UnmarshalJSON(res, &queried))
+	require.Len(t, queried, 1)
+
+	params = NewQueryDelegationsAboveValueParams(0, 3, sdk.TokensFromTendermintPower(30))
+	query.Data = cdc.MustMarshalJSON(params)
+	_, err = queryDelegationsAboveValue(ctx, cdc, query, keeper)
+	require.NotNil(t, err)
+}
+
 func TestQueryValidators(t *testing.T) {
 	cdc := codec.New()
 	ctx, _, keeper := keep.CreateTestInput(t, false, 10000)
@@ -124,7 +292,7 @@ func TestQueryValidators(t *testing.T) {
 	var validators [3]types.Validator
 	for i, amt := range amts {
 		validators[i] = types.NewValidator(sdk.ValAddress(keep.Addrs[i]), keep.PKs[i], types.Description{})
-		validators[i], pool, _ = validators[i].AddTokensFromDel(pool, amt)
+		validators[i], pool, _ = validators[i].AddTokensFromDel(pool, amt, types.ShareRoundDown)
 		validators[i], pool = validators[i].UpdateStatus(pool, status[i])
 	}
 
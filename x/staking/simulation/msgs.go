@@ -81,7 +81,7 @@ func SimulateMsgEditValidator(k staking.Keeper) simulation.Operation {
 		address := val.GetOperator()
 		newCommissionRate := simulation.RandomDecAmount(r, val.Commission.MaxRate)
 
-		msg := staking.NewMsgEditValidator(address, description, &newCommissionRate, nil)
+		msg := staking.NewMsgEditValidator(address, description, &newCommissionRate, nil, nil, nil)
 
 		if msg.ValidateBasic() != nil {
 			return simulation.NoOpMsg(), nil, fmt.Errorf("expected msg to pass ValidateBasic: %s", msg.GetSignBytes())
@@ -8,6 +8,8 @@ import (
 var (
 	ActionCompleteUnbonding    = "complete-unbonding"
 	ActionCompleteRedelegation = "complete-redelegation"
+	ActionValidatorBonded      = "validator_bonded"
+	ActionValidatorUnbonded    = "validator_unbonded"
 	TxCategory                 = "staking"
 
 	Action       = sdk.TagAction
@@ -16,5 +18,9 @@ var (
 	SrcValidator = sdk.TagSrcValidator
 	DstValidator = sdk.TagDstValidator
 	Delegator    = sdk.TagDelegator
+	Validator    = "validator"
+	ConsPubKey   = "consensus-pubkey"
+	Status       = "status"
 	EndTime      = "end-time"
+	ExitHeight   = "exit-height"
 )
@@ -8,9 +8,16 @@ import (
 func RegisterCodec(cdc *codec.Codec) {
 	cdc.RegisterConcrete(MsgCreateValidator{}, "cosmos-sdk/MsgCreateValidator", nil)
 	cdc.RegisterConcrete(MsgEditValidator{}, "cosmos-sdk/MsgEditValidator", nil)
+	cdc.RegisterConcrete(MsgMigrateValidatorOwner{}, "cosmos-sdk/MsgMigrateValidatorOwner", nil)
+	cdc.RegisterConcrete(MsgSignalExit{}, "cosmos-sdk/MsgSignalExit", nil)
 	cdc.RegisterConcrete(MsgDelegate{}, "cosmos-sdk/MsgDelegate", nil)
 	cdc.RegisterConcrete(MsgUndelegate{}, "cosmos-sdk/MsgUndelegate", nil)
 	cdc.RegisterConcrete(MsgBeginRedelegate{}, "cosmos-sdk/MsgBeginRedelegate", nil)
+	cdc.RegisterConcrete(MsgRebalance{}, "cosmos-sdk/MsgRebalance", nil)
+	cdc.RegisterConcrete(MsgSetAutoCompound{}, "cosmos-sdk/MsgSetAutoCompound", nil)
+	cdc.RegisterConcrete(FreezeValidatorProposal{}, "cosmos-sdk/FreezeValidatorProposal", nil)
+	cdc.RegisterConcrete(CompleteUnbondingsProposal{}, "cosmos-sdk/CompleteUnbondingsProposal", nil)
+	cdc.RegisterConcrete(VerifyValidatorIdentityProposal{}, "cosmos-sdk/VerifyValidatorIdentityProposal", nil)
 }
 
 // generic sealed codec to be used throughout sdk
@@ -3,6 +3,7 @@ package types
 import (
 	"bytes"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
@@ -27,6 +28,14 @@ type DVVTriplet struct {
 	ValidatorDstAddress sdk.ValAddress
 }
 
+// DelegateResult is returned JSON encoded as the data of a delegation,
+// reporting the validator status and power once the current block's validator
+// set updates are applied.
+type DelegateResult struct {
+	ValidatorStatus sdk.BondStatus `json:"validator_status"`
+	ValidatorPower  int64          `json:"validator_power"`
+}
+
 // Delegation represents the bond with tokens held by an account. It is
 // owned by one delegator, and is associated with the voting power of one
 // validator.
@@ -34,6 +43,9 @@ type Delegation struct {
 	DelegatorAddress sdk.AccAddress `json:"delegator_address"`
 	ValidatorAddress sdk.ValAddress `json:"validator_address"`
 	Shares           sdk.Dec        `json:"shares"`
+	BondHeight       int64          `json:"bond_height"`   // height at which the delegation was created, moved forward by top-ups
+	Height           int64          `json:"height"`        // height at which the delegation was created or its shares last modified
+	AutoCompound     bool           `json:"auto_compound"` // whether the delegation's rewards are re-delegated to the validator every AutoCompoundInterval blocks
 }
 
 // NewDelegation creates a new delegation object
@@ -71,7 +83,10 @@ func UnmarshalDelegation(cdc *codec.Codec, value []byte) (delegation Delegation,
 func (d Delegation) Equal(d2 Delegation) bool {
 	return bytes.Equal(d.DelegatorAddress, d2.DelegatorAddress) &&
 		bytes.Equal(d.ValidatorAddress, d2.ValidatorAddress) &&
-		d.Shares.Equal(d2.Shares)
+		d.Shares.Equal(d2.Shares) &&
+		d.BondHeight == d2.BondHeight &&
+		d.Height == d2.Height &&
+		d.AutoCompound == d2.AutoCompound
 }
 
 // ensure fulfills the sdk validator types
@@ -81,14 +96,58 @@ var _ sdk.Delegation = Delegation{}
 func (d Delegation) GetDelegatorAddr() sdk.AccAddress { return d.DelegatorAddress }
 func (d Delegation) GetValidatorAddr() sdk.ValAddress { return d.ValidatorAddress }
 func (d Delegation) GetShares() sdk.Dec               { return d.Shares }
+func (d Delegation) GetBondHeight() int64             { return d.BondHeight }
 
 // String returns a human readable string representation of a Delegation.
 func (d Delegation) String() string {
 	return fmt.Sprintf(`Delegation:
-  Delegator: %s
-  Validator: %s
-  Shares:    %s`, d.DelegatorAddress,
-		d.ValidatorAddress, d.Shares)
+  Delegator:   %s
+  Validator:   %s
+  Shares:      %s
+  Bond Height: %d
+  Height:      %d
+  Auto Compound: %t`, d.DelegatorAddress,
+		d.ValidatorAddress, d.Shares, d.BondHeight, d.Height, d.AutoCompound)
+}
+
+// DelegationResponse is a delegation along with its share of the validator's
+// delegator shares, as a fraction of one.
+type DelegationResponse struct {
+	DelegatorAddress sdk.AccAddress `json:"delegator_address"`
+	ValidatorAddress sdk.ValAddress `json:"validator_address"`
+	Shares           sdk.Dec        `json:"shares"`
+	BondHeight       int64          `json:"bond_height"`
+	Height           int64          `json:"height"`
+	AutoCompound     bool           `json:"auto_compound"`
+	SharePercentage  sdk.Dec        `json:"share_percentage"`
+}
+
+// NewDelegationResponse creates a new delegation response
+func NewDelegationResponse(delegation Delegation, sharePercentage sdk.Dec) DelegationResponse {
+	return DelegationResponse{
+		DelegatorAddress: delegation.DelegatorAddress,
+		ValidatorAddress: delegation.ValidatorAddress,
+		Shares:           delegation.Shares,
+		BondHeight:       delegation.BondHeight,
+		Height:           delegation.Height,
+		AutoCompound:     delegation.AutoCompound,
+		SharePercentage:  sharePercentage,
+	}
+}
+
+// DelegationSnapshot is a delegator's shares of a validator along with their
+// token value at the validator's exchange rate when the snapshot was taken.
+type DelegationSnapshot struct {
+	DelegatorAddress sdk.AccAddress `json:"delegator_address"`
+	Shares           sdk.Dec        `json:"shares"`
+	Tokens           sdk.Dec        `json:"tokens"`
+}
+
+// DelegatorStake is the total token value a delegator has bonded across all
+// validators.
+type DelegatorStake struct {
+	DelegatorAddress sdk.AccAddress `json:"delegator_address"`
+	Tokens           sdk.Dec        `json:"tokens"`
 }
 
 // Delegations is a collection of delegations
@@ -101,6 +160,17 @@ func (d Delegations) String() (out string) {
 	return strings.TrimSpace(out)
 }
 
+// Sort orders the delegations by delegator address and then by validator
+// address, comparing raw address bytes.
+func (d Delegations) Sort() {
+	sort.SliceStable(d, func(i, j int) bool {
+		if c := bytes.Compare(d[i].DelegatorAddress, d[j].DelegatorAddress); c != 0 {
+			return c < 0
+		}
+		return bytes.Compare(d[i].ValidatorAddress, d[j].ValidatorAddress) < 0
+	})
+}
+
 // UnbondingDelegation stores all of a single delegator's unbonding bonds
 // for a single validator in an time-ordered list
 type UnbondingDelegation struct {
@@ -147,14 +217,38 @@ func NewUnbondingDelegationEntry(creationHeight int64, completionTime time.Time,
 	}
 }
 
-// AddEntry - append entry to the unbonding delegation
+// AddEntry - append entry to the unbonding delegation, merging the balance
+// into an existing entry created at the same height and completing at the
+// same time
 func (d *UnbondingDelegation) AddEntry(creationHeight int64,
 	minTime time.Time, balance sdk.Int) {
 
+	if i, found := d.entryIndex(creationHeight, minTime); found {
+		d.Entries[i].InitialBalance = d.Entries[i].InitialBalance.Add(balance)
+		d.Entries[i].Balance = d.Entries[i].Balance.Add(balance)
+		return
+	}
+
 	entry := NewUnbondingDelegationEntry(creationHeight, minTime, balance)
 	d.Entries = append(d.Entries, entry)
 }
 
+// HasEntry - whether an entry created at the given height and completing at
+// the given time already exists, such that a new entry would be merged into it
+func (d UnbondingDelegation) HasEntry(creationHeight int64, completionTime time.Time) bool {
+	_, found := d.entryIndex(creationHeight, completionTime)
+	return found
+}
+
+func (d UnbondingDelegation) entryIndex(creationHeight int64, completionTime time.Time) (int, bool) {
+	for i, entry := range d.Entries {
+		if entry.CreationHeight == creationHeight && entry.CompletionTime.Equal(completionTime) {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
 // RemoveEntry - remove entry at index i to the unbonding delegation
 func (d *UnbondingDelegation) RemoveEntry(i int64) {
 	d.Entries = append(d.Entries[:i], d.Entries[i+1:]...)
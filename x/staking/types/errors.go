@@ -41,10 +41,18 @@ func ErrValidatorOwnerExists(codespace sdk.CodespaceType) sdk.Error {
 	return sdk.NewError(codespace, CodeInvalidValidator, "validator already exist for this operator address, must use new validator operator address")
 }
 
+func ErrValidatorMigrationBlocked(codespace sdk.CodespaceType, reason string) sdk.Error {
+	return sdk.NewError(codespace, CodeInvalidValidator, fmt.Sprintf("validator cannot be migrated to a new owner: %s", reason))
+}
+
 func ErrValidatorPubKeyExists(codespace sdk.CodespaceType) sdk.Error {
 	return sdk.NewError(codespace, CodeInvalidValidator, "validator already exist for this pubkey, must use new validator pubkey")
 }
 
+func ErrMaxValidatorsCreatedPerBlock(codespace sdk.CodespaceType, max uint16) sdk.Error {
+	return sdk.NewError(codespace, CodeInvalidValidator, fmt.Sprintf("too many validators created in this block, max is %d", max))
+}
+
 func ErrValidatorPubKeyTypeNotSupported(codespace sdk.CodespaceType, keyType string, supportedTypes []string) sdk.Error {
 	msg := fmt.Sprintf("validator pubkey type %s is not supported, must use %s", keyType, strings.Join(supportedTypes, ","))
 	return sdk.NewError(codespace, CodeInvalidValidator, msg)
@@ -63,6 +71,11 @@ func ErrDescriptionLength(codespace sdk.CodespaceType, descriptor string, got, m
 	return sdk.NewError(codespace, CodeInvalidValidator, msg)
 }
 
+func ErrInvalidSecurityContact(codespace sdk.CodespaceType, contact string) sdk.Error {
+	msg := fmt.Sprintf("invalid security contact %v, must be an email address or an http(s) URL", contact)
+	return sdk.NewError(codespace, CodeInvalidValidator, msg)
+}
+
 func ErrCommissionNegative(codespace sdk.CodespaceType) sdk.Error {
 	return sdk.NewError(codespace, CodeInvalidValidator, "commission must be positive")
 }
@@ -91,6 +104,10 @@ func ErrCommissionGTMaxChangeRate(codespace sdk.CodespaceType) sdk.Error {
 	return sdk.NewError(codespace, CodeInvalidValidator, "commission cannot be changed more than max change rate")
 }
 
+func ErrCommissionBelowMinimum(codespace sdk.CodespaceType, minRate sdk.Dec) sdk.Error {
+	return sdk.NewError(codespace, CodeInvalidValidator, fmt.Sprintf("commission cannot be less than the minimum rate %s", minRate))
+}
+
 func ErrSelfDelegationBelowMinimum(codespace sdk.CodespaceType) sdk.Error {
 	return sdk.NewError(codespace, CodeInvalidValidator, "validator's self delegation must be greater than their minimum self delegation")
 }
@@ -103,6 +120,10 @@ func ErrMinSelfDelegationDecreased(codespace sdk.CodespaceType) sdk.Error {
 	return sdk.NewError(codespace, CodeInvalidValidator, "minimum self delegation cannot be decrease")
 }
 
+func ErrMaxTotalDelegationInvalid(codespace sdk.CodespaceType) sdk.Error {
+	return sdk.NewError(codespace, CodeInvalidValidator, "maximum total delegation cannot be negative")
+}
+
 func ErrNilDelegatorAddr(codespace sdk.CodespaceType) sdk.Error {
 	return sdk.NewError(codespace, CodeInvalidInput, "delegator address is nil")
 }
@@ -119,6 +140,10 @@ func ErrBadDelegationAmount(codespace sdk.CodespaceType) sdk.Error {
 	return sdk.NewError(codespace, CodeInvalidDelegation, "amount must be > 0")
 }
 
+func ErrDelegationPowerOverflow(codespace sdk.CodespaceType) sdk.Error {
+	return sdk.NewError(codespace, CodeInvalidDelegation, "delegation would overflow the maximum tendermint power")
+}
+
 func ErrNoDelegation(codespace sdk.CodespaceType) sdk.Error {
 	return sdk.NewError(codespace, CodeInvalidDelegation, "no delegation for this (address, validator) pair")
 }
@@ -157,6 +182,10 @@ func ErrNotMature(codespace sdk.CodespaceType, operation, descriptor string, got
 	return sdk.NewError(codespace, CodeUnauthorized, msg)
 }
 
+func ErrInstantUnbondDisabled(codespace sdk.CodespaceType) sdk.Error {
+	return sdk.NewError(codespace, CodeInvalidDelegation, "instant unbonding is disabled")
+}
+
 func ErrNoUnbondingDelegation(codespace sdk.CodespaceType) sdk.Error {
 	return sdk.NewError(codespace, CodeInvalidDelegation, "no unbonding delegation found")
 }
@@ -196,6 +225,30 @@ func ErrMaxRedelegationEntries(codespace sdk.CodespaceType) sdk.Error {
 		"too many redelegation entries in this delegator/src-validator/dst-validator trio, please wait for some entries to mature")
 }
 
+func ErrValidatorFrozen(codespace sdk.CodespaceType, valAddr sdk.ValAddress) sdk.Error {
+	msg := fmt.Sprintf("delegations to validator %s are frozen by governance, its existing unbondings still complete", valAddr)
+	return sdk.NewError(codespace, CodeInvalidValidator, msg)
+}
+
+func ErrUnbondingTimeOverrideTooShort(codespace sdk.CodespaceType, min time.Duration) sdk.Error {
+	msg := fmt.Sprintf("unbonding time override cannot be shorter than the unbonding time param %s", min)
+	return sdk.NewError(codespace, CodeInvalidValidator, msg)
+}
+
+func ErrBadExitHeight(codespace sdk.CodespaceType, height, current int64) sdk.Error {
+	msg := fmt.Sprintf("planned exit height %d must be after the current height %d", height, current)
+	return sdk.NewError(codespace, CodeInvalidValidator, msg)
+}
+
+func ErrMaxDelegatorPowerShare(codespace sdk.CodespaceType, max sdk.Dec) sdk.Error {
+	msg := fmt.Sprintf("delegation would give the delegator more than %s of the bonded tokens", max)
+	return sdk.NewError(codespace, CodeInvalidDelegation, msg)
+}
+
+func ErrMaxTotalDelegationExceeded(codespace sdk.CodespaceType) sdk.Error {
+	return sdk.NewError(codespace, CodeInvalidDelegation, "delegation would exceed the validator's maximum total delegation")
+}
+
 func ErrDelegatorShareExRateInvalid(codespace sdk.CodespaceType) sdk.Error {
 	return sdk.NewError(codespace, CodeInvalidDelegation,
 		"cannot delegate to validators with invalid (zero) ex-rate")
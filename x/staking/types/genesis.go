@@ -6,15 +6,17 @@ import (
 
 // GenesisState - all staking state that must be provided at genesis
 type GenesisState struct {
-	Pool                 Pool                  `json:"pool"`
-	Params               Params                `json:"params"`
-	LastTotalPower       sdk.Int               `json:"last_total_power"`
-	LastValidatorPowers  []LastValidatorPower  `json:"last_validator_powers"`
-	Validators           Validators            `json:"validators"`
-	Delegations          Delegations           `json:"delegations"`
-	UnbondingDelegations []UnbondingDelegation `json:"unbonding_delegations"`
-	Redelegations        []Redelegation        `json:"redelegations"`
-	Exported             bool                  `json:"exported"`
+	Pool                 Pool                   `json:"pool"`
+	Params               Params                 `json:"params"`
+	LastTotalPower       sdk.Int                `json:"last_total_power"`
+	LastValidatorPowers  []LastValidatorPower   `json:"last_validator_powers"`
+	Validators           Validators             `json:"validators"`
+	Delegations          Delegations            `json:"delegations"`
+	GenesisDelegations   []GenesisDelegation    `json:"genesis_delegations"`
+	UnbondingDelegations []UnbondingDelegation  `json:"unbonding_delegations"`
+	Redelegations        []Redelegation         `json:"redelegations"`
+	ValidatorSlashEvents []ValidatorSlashEvents `json:"validator_slash_events"`
+	Exported             bool                   `json:"exported"`
 }
 
 // Last validator power, needed for validator set update logic
@@ -23,6 +25,22 @@ type LastValidatorPower struct {
 	Power   int64
 }
 
+// GenesisDelegation is a bond of tokens to a validator, for which delegator
+// shares are issued at genesis, e.g. for an airdrop of staking positions
+type GenesisDelegation struct {
+	DelegatorAddress sdk.AccAddress `json:"delegator_address"`
+	ValidatorAddress sdk.ValAddress `json:"validator_address"`
+	Amount           sdk.Int        `json:"amount"`
+}
+
+func NewGenesisDelegation(delAddr sdk.AccAddress, valAddr sdk.ValAddress, amount sdk.Int) GenesisDelegation {
+	return GenesisDelegation{
+		DelegatorAddress: delAddr,
+		ValidatorAddress: valAddr,
+		Amount:           amount,
+	}
+}
+
 func NewGenesisState(pool Pool, params Params, validators []Validator, delegations []Delegation) GenesisState {
 	return GenesisState{
 		Pool:        pool,
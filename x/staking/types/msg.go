@@ -3,6 +3,7 @@ package types
 import (
 	"bytes"
 	"encoding/json"
+	"time"
 
 	"github.com/tendermint/tendermint/crypto"
 
@@ -154,21 +155,28 @@ type MsgEditValidator struct {
 	Description
 	ValidatorAddress sdk.ValAddress `json:"address"`
 
-	// We pass a reference to the new commission rate and min self delegation as it's not mandatory to
-	// update. If not updated, the deserialized rate will be zero with no way to
+	// We pass a reference to the new commission rate, min self delegation,
+	// max total delegation and unbonding time as it's not mandatory to update.
+	// If not updated, the deserialized rate will be zero with no way to
 	// distinguish if an update was intended.
 	//
 	// REF: #2373
-	CommissionRate    *sdk.Dec `json:"commission_rate"`
-	MinSelfDelegation *sdk.Int `json:"min_self_delegation"`
+	CommissionRate     *sdk.Dec       `json:"commission_rate"`
+	MinSelfDelegation  *sdk.Int       `json:"min_self_delegation"`
+	MaxTotalDelegation *sdk.Dec       `json:"max_total_delegation"`
+	UnbondingTime      *time.Duration `json:"unbonding_time"` // override of the unbonding time param, zero removes it
 }
 
-func NewMsgEditValidator(valAddr sdk.ValAddress, description Description, newRate *sdk.Dec, newMinSelfDelegation *sdk.Int) MsgEditValidator {
+func NewMsgEditValidator(valAddr sdk.ValAddress, description Description, newRate *sdk.Dec,
+	newMinSelfDelegation *sdk.Int, newMaxTotalDelegation *sdk.Dec, newUnbondingTime *time.Duration) MsgEditValidator {
+
 	return MsgEditValidator{
-		Description:       description,
-		CommissionRate:    newRate,
-		ValidatorAddress:  valAddr,
-		MinSelfDelegation: newMinSelfDelegation,
+		Description:        description,
+		CommissionRate:     newRate,
+		ValidatorAddress:   valAddr,
+		MinSelfDelegation:  newMinSelfDelegation,
+		MaxTotalDelegation: newMaxTotalDelegation,
+		UnbondingTime:      newUnbondingTime,
 	}
 }
 
@@ -199,6 +207,14 @@ func (msg MsgEditValidator) ValidateBasic() sdk.Error {
 		return ErrMinSelfDelegationInvalid(DefaultCodespace)
 	}
 
+	if msg.MaxTotalDelegation != nil && (*msg.MaxTotalDelegation).IsNegative() {
+		return ErrMaxTotalDelegationInvalid(DefaultCodespace)
+	}
+
+	if msg.UnbondingTime != nil && *msg.UnbondingTime < 0 {
+		return sdk.NewError(DefaultCodespace, CodeInvalidInput, "unbonding time can't be negative")
+	}
+
 	if msg.CommissionRate != nil {
 		if msg.CommissionRate.GT(sdk.OneDec()) || msg.CommissionRate.LT(sdk.ZeroDec()) {
 			return sdk.NewError(DefaultCodespace, CodeInvalidInput, "commission rate must be between 0 and 1, inclusive")
@@ -208,6 +224,83 @@ func (msg MsgEditValidator) ValidateBasic() sdk.Error {
 	return nil
 }
 
+// MsgMigrateValidatorOwner - struct for moving a validator and its
+// self-delegation to a new operator address, signed by the current operator
+type MsgMigrateValidatorOwner struct {
+	ValidatorAddress sdk.ValAddress `json:"validator_address"`
+	NewOwnerAddress  sdk.ValAddress `json:"new_owner_address"`
+}
+
+func NewMsgMigrateValidatorOwner(valAddr, newOwnerAddr sdk.ValAddress) MsgMigrateValidatorOwner {
+	return MsgMigrateValidatorOwner{
+		ValidatorAddress: valAddr,
+		NewOwnerAddress:  newOwnerAddr,
+	}
+}
+
+//nolint
+func (msg MsgMigrateValidatorOwner) Route() string { return RouterKey }
+func (msg MsgMigrateValidatorOwner) Type() string  { return "migrate_validator_owner" }
+func (msg MsgMigrateValidatorOwner) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{sdk.AccAddress(msg.ValidatorAddress)}
+}
+
+// get the bytes for the message signer to sign on
+func (msg MsgMigrateValidatorOwner) GetSignBytes() []byte {
+	bz := MsgCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+
+// quick validity check
+func (msg MsgMigrateValidatorOwner) ValidateBasic() sdk.Error {
+	if msg.ValidatorAddress.Empty() || msg.NewOwnerAddress.Empty() {
+		return ErrNilValidatorAddr(DefaultCodespace)
+	}
+	if msg.ValidatorAddress.Equals(msg.NewOwnerAddress) {
+		return sdk.NewError(DefaultCodespace, CodeInvalidInput, "new owner must differ from the current operator")
+	}
+	return nil
+}
+
+// MsgSignalExit - struct for announcing the height at which a validator
+// operator intends to leave. The signal is advisory and does not change the
+// validator's power; a zero height withdraws it.
+type MsgSignalExit struct {
+	ValidatorAddress sdk.ValAddress `json:"validator_address"`
+	ExitHeight       int64          `json:"exit_height"`
+}
+
+func NewMsgSignalExit(valAddr sdk.ValAddress, exitHeight int64) MsgSignalExit {
+	return MsgSignalExit{
+		ValidatorAddress: valAddr,
+		ExitHeight:       exitHeight,
+	}
+}
+
+//nolint
+func (msg MsgSignalExit) Route() string { return RouterKey }
+func (msg MsgSignalExit) Type() string  { return "signal_exit" }
+func (msg MsgSignalExit) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{sdk.AccAddress(msg.ValidatorAddress)}
+}
+
+// get the bytes for the message signer to sign on
+func (msg MsgSignalExit) GetSignBytes() []byte {
+	bz := MsgCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+
+// quick validity check
+func (msg MsgSignalExit) ValidateBasic() sdk.Error {
+	if msg.ValidatorAddress.Empty() {
+		return ErrNilValidatorAddr(DefaultCodespace)
+	}
+	if msg.ExitHeight < 0 {
+		return sdk.NewError(DefaultCodespace, CodeInvalidInput, "exit height cannot be negative")
+	}
+	return nil
+}
+
 // MsgDelegate - struct for bonding transactions
 type MsgDelegate struct {
 	DelegatorAddress sdk.AccAddress `json:"delegator_address"`
@@ -340,3 +433,96 @@ func (msg MsgUndelegate) ValidateBasic() sdk.Error {
 	}
 	return nil
 }
+
+// MsgSetAutoCompound - struct for enabling or disabling the re-delegation
+// of a delegation's rewards to its validator every block
+type MsgSetAutoCompound struct {
+	DelegatorAddress sdk.AccAddress `json:"delegator_address"`
+	ValidatorAddress sdk.ValAddress `json:"validator_address"`
+	Enabled          bool           `json:"enabled"`
+}
+
+func NewMsgSetAutoCompound(delAddr sdk.AccAddress, valAddr sdk.ValAddress, enabled bool) MsgSetAutoCompound {
+	return MsgSetAutoCompound{
+		DelegatorAddress: delAddr,
+		ValidatorAddress: valAddr,
+		Enabled:          enabled,
+	}
+}
+
+//nolint
+func (msg MsgSetAutoCompound) Route() string { return RouterKey }
+func (msg MsgSetAutoCompound) Type() string  { return "set_auto_compound" }
+func (msg MsgSetAutoCompound) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.DelegatorAddress}
+}
+
+// get the bytes for the message signer to sign on
+func (msg MsgSetAutoCompound) GetSignBytes() []byte {
+	bz := MsgCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+
+// quick validity check
+func (msg MsgSetAutoCompound) ValidateBasic() sdk.Error {
+	if msg.DelegatorAddress.Empty() {
+		return ErrNilDelegatorAddr(DefaultCodespace)
+	}
+	if msg.ValidatorAddress.Empty() {
+		return ErrNilValidatorAddr(DefaultCodespace)
+	}
+	return nil
+}
+
+// MsgRebalance - struct for delegating liquid tokens to one validator and
+// beginning to unbond the same amount from another in a single atomic step
+type MsgRebalance struct {
+	DelegatorAddress    sdk.AccAddress `json:"delegator_address"`
+	ValidatorSrcAddress sdk.ValAddress `json:"validator_src_address"`
+	ValidatorDstAddress sdk.ValAddress `json:"validator_dst_address"`
+	Amount              sdk.Coin       `json:"amount"`
+}
+
+func NewMsgRebalance(delAddr sdk.AccAddress, valSrcAddr,
+	valDstAddr sdk.ValAddress, amount sdk.Coin) MsgRebalance {
+
+	return MsgRebalance{
+		DelegatorAddress:    delAddr,
+		ValidatorSrcAddress: valSrcAddr,
+		ValidatorDstAddress: valDstAddr,
+		Amount:              amount,
+	}
+}
+
+//nolint
+func (msg MsgRebalance) Route() string { return RouterKey }
+func (msg MsgRebalance) Type() string  { return "rebalance" }
+func (msg MsgRebalance) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.DelegatorAddress}
+}
+
+// get the bytes for the message signer to sign on
+func (msg MsgRebalance) GetSignBytes() []byte {
+	bz := MsgCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+
+// quick validity check
+func (msg MsgRebalance) ValidateBasic() sdk.Error {
+	if msg.DelegatorAddress.Empty() {
+		return ErrNilDelegatorAddr(DefaultCodespace)
+	}
+	if msg.ValidatorSrcAddress.Empty() {
+		return ErrNilValidatorAddr(DefaultCodespace)
+	}
+	if msg.ValidatorDstAddress.Empty() {
+		return ErrNilValidatorAddr(DefaultCodespace)
+	}
+	if msg.ValidatorSrcAddress.Equals(msg.ValidatorDstAddress) {
+		return ErrSelfRedelegation(DefaultCodespace)
+	}
+	if msg.Amount.Amount.LTE(sdk.ZeroInt()) {
+		return ErrBadSharesAmount(DefaultCodespace)
+	}
+	return nil
+}
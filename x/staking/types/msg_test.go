@@ -40,7 +40,7 @@ func TestMsgCreateValidator(t *testing.T) {
 	}
 
 	for _, tc := range tests {
-		description := NewDescription(tc.moniker, tc.identity, tc.website, tc.details)
+		description := NewDescription(tc.moniker, tc.identity, tc.website, tc.details, "")
 		msg := NewMsgCreateValidator(tc.validatorAddr, tc.pubkey, tc.bond, description, tc.commissionMsg, tc.minSelfDelegation)
 		if tc.expectPass {
 			require.Nil(t, msg.ValidateBasic(), "test: %v", tc.name)
@@ -64,11 +64,11 @@ func TestMsgEditValidator(t *testing.T) {
 	}
 
 	for _, tc := range tests {
-		description := NewDescription(tc.moniker, tc.identity, tc.website, tc.details)
+		description := NewDescription(tc.moniker, tc.identity, tc.website, tc.details, "")
 		newRate := sdk.ZeroDec()
 		newMinSelfDelegation := sdk.OneInt()
 
-		msg := NewMsgEditValidator(tc.validatorAddr, description, &newRate, &newMinSelfDelegation)
+		msg := NewMsgEditValidator(tc.validatorAddr, description, &newRate, &newMinSelfDelegation, nil, nil)
 		if tc.expectPass {
 			require.Nil(t, msg.ValidateBasic(), "test: %v", tc.name)
 		} else {
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"time"
 
+	tmtypes "github.com/tendermint/tendermint/types"
+
 	"github.com/cosmos/cosmos-sdk/codec"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/x/params"
@@ -21,6 +23,47 @@ const (
 
 	// Default maximum entries in a UBD/RED pair
 	DefaultMaxEntries uint16 = 7
+
+	// Default rounding when converting between tokens and delegator shares
+	DefaultShareRoundingMode = ShareRoundDown
+
+	// Default instant unbonding, which must stay disabled on live networks
+	DefaultInstantUnbond = false
+
+	// Default number of blocks for which validator set updates are kept,
+	// disabled as every block writes a record while it is on
+	DefaultValidatorUpdatesHistory uint64 = 0
+
+	// Default maximum number of validators created per block, zero for no cap
+	DefaultMaxValidatorsCreatedPerBlock uint16 = 0
+
+	// Default number of blocks after which a delegation earns its full
+	// rewards under loyalty weighting, one year assuming 5 second block times
+	DefaultLoyaltyPeriod int64 = 60 * 60 * 24 * 365 / 5
+
+	// Default number of blocks for which the bonded ratio is kept, disabled
+	// for the same reason
+	DefaultBondedRatioHistory uint64 = 0
+
+	// Default ordering of validators with equal power
+	DefaultTieBreakMode = TieBreakStakeAge
+
+	// Default number of blocks for which slash events are kept, three weeks
+	// assuming 5 second block times
+	DefaultSlashEventsHistory uint64 = 60 * 60 * 24 * 21 / 5
+
+	// Default recording of the bonded tokens of each validator per block,
+	// disabled as the records grow with the validator set and the unbonding
+	// time
+	DefaultBondedTokensHistory = false
+
+	// Default recording of the exchange rate of each validator per block,
+	// disabled for the same reason
+	DefaultExRateHistory = false
+
+	// Default number of blocks between the compounding of the rewards of
+	// auto-compounding delegations, one day assuming 5 second block times
+	DefaultAutoCompoundInterval int64 = 60 * 60 * 24 / 5
 )
 
 // nolint - Keys for parameter access
@@ -29,27 +72,169 @@ var (
 	KeyMaxValidators = []byte("MaxValidators")
 	KeyMaxEntries    = []byte("KeyMaxEntries")
 	KeyBondDenom     = []byte("BondDenom")
+
+	KeyShareRoundingMode = []byte("ShareRoundingMode")
+	KeyInstantUnbond     = []byte("InstantUnbond")
+
+	KeyValidatorUpdatesHistory      = []byte("ValidatorUpdatesHistory")
+	KeyMaxValidatorsCreatedPerBlock = []byte("MaxValidatorsCreatedPerBlock")
+	KeyMaxDelegatorPowerShare       = []byte("MaxDelegatorPowerShare")
+	KeyMaxValidatorsSchedule        = []byte("MaxValidatorsSchedule")
+	KeyLoyaltyWeighting             = []byte("LoyaltyWeighting")
+	KeyLoyaltyPeriod                = []byte("LoyaltyPeriod")
+	KeyMinSlashTokens               = []byte("MinSlashTokens")
+	KeyBondedRatioHistory           = []byte("BondedRatioHistory")
+	KeyTieBreakMode                 = []byte("TieBreakMode")
+	KeyAllowedPubKeyTypes           = []byte("AllowedPubKeyTypes")
+	KeyMinCommissionRate            = []byte("MinCommissionRate")
+	KeyMaxSlashPerInfraction        = []byte("MaxSlashPerInfraction")
+	KeySlashEventsHistory           = []byte("SlashEventsHistory")
+	KeyBondedTokensHistory          = []byte("BondedTokensHistory")
+	KeyExRateHistory                = []byte("ExRateHistory")
+	KeyAutoCompoundInterval         = []byte("AutoCompoundInterval")
 )
 
 var _ params.ParamSet = (*Params)(nil)
 
+// ShareRoundingMode defines how fractions are rounded when tokens are
+// converted into delegator shares and back
+type ShareRoundingMode byte
+
+const (
+	// ShareRoundDown truncates, so that a delegator is never credited more
+	// than supplied
+	ShareRoundDown ShareRoundingMode = 0x00
+	// ShareRoundNearest rounds to the nearest value, ties to even
+	ShareRoundNearest ShareRoundingMode = 0x01
+)
+
+// IsValid returns true if the rounding mode is known
+func (m ShareRoundingMode) IsValid() bool {
+	return m == ShareRoundDown || m == ShareRoundNearest
+}
+
+func (m ShareRoundingMode) String() string {
+	switch m {
+	case ShareRoundDown:
+		return "down"
+	case ShareRoundNearest:
+		return "nearest"
+	default:
+		return fmt.Sprintf("unknown(%d)", byte(m))
+	}
+}
+
+// TieBreakMode defines how validators with equal power are ordered in the
+// power index
+type TieBreakMode byte
+
+const (
+	// TieBreakStakeAge ranks the validator which bonded earliest highest
+	TieBreakStakeAge TieBreakMode = 0x00
+	// TieBreakTxOrder ranks the validator whose transaction brought it to its
+	// current power first highest
+	TieBreakTxOrder TieBreakMode = 0x01
+)
+
+// IsValid returns true if the tie-break mode is known
+func (m TieBreakMode) IsValid() bool {
+	return m == TieBreakStakeAge || m == TieBreakTxOrder
+}
+
+func (m TieBreakMode) String() string {
+	switch m {
+	case TieBreakStakeAge:
+		return "stake-age"
+	case TieBreakTxOrder:
+		return "tx-order"
+	default:
+		return fmt.Sprintf("unknown(%d)", byte(m))
+	}
+}
+
 // Params defines the high level settings for staking
 type Params struct {
 	UnbondingTime time.Duration `json:"unbonding_time"` // time duration of unbonding
 	MaxValidators uint16        `json:"max_validators"` // maximum number of validators (max uint16 = 65535)
 	MaxEntries    uint16        `json:"max_entries"`    // max entries for either unbonding delegation or redelegation (per pair/trio)
 	// note: we need to be a bit careful about potential overflow here, since this is user-determined
-	BondDenom string `json:"bond_denom"` // bondable coin denomination
+	BondDenom         string            `json:"bond_denom"`          // bondable coin denomination
+	ShareRoundingMode ShareRoundingMode `json:"share_rounding_mode"` // rounding when converting between tokens and delegator shares
+	InstantUnbond     bool              `json:"instant_unbond"`      // allow delegations to be force undelegated without the unbonding time, for testnets only
+
+	ValidatorUpdatesHistory      uint64  `json:"validator_updates_history"`        // number of blocks for which validator set updates are kept, zero disables the history
+	MaxValidatorsCreatedPerBlock uint16  `json:"max_validators_created_per_block"` // maximum number of validators created in a block, zero for no cap
+	MaxDelegatorPowerShare       sdk.Dec `json:"max_delegator_power_share"`        // maximum fraction of the bonded tokens a single delegator may hold, zero for no cap
+
+	MaxValidatorsSchedule []MaxValidatorsScheduleEntry `json:"max_validators_schedule"` // values MaxValidators is set to at given heights
+
+	LoyaltyWeighting sdk.Dec `json:"loyalty_weighting"` // fraction of the rewards of a new delegation withheld until it has been bonded for LoyaltyPeriod, zero to disable
+	LoyaltyPeriod    int64   `json:"loyalty_period"`    // number of blocks after which a delegation earns its full rewards
+
+	MinSlashTokens     sdk.Int `json:"min_slash_tokens"`     // minimum number of tokens burned by a slash with a nonzero fraction
+	BondedRatioHistory uint64  `json:"bonded_ratio_history"` // number of blocks for which the bonded ratio is kept, zero disables the history
+
+	TieBreakMode       TieBreakMode `json:"tie_break_mode"`       // ordering of validators with equal power
+	AllowedPubKeyTypes []string     `json:"allowed_pubkey_types"` // consensus pubkey types validators may be created with, empty for any the consensus params accept
+
+	MinCommissionRate     sdk.Dec `json:"min_commission_rate"`      // minimum commission rate of a validator, raised to on its next edit if below
+	MaxSlashPerInfraction sdk.Dec `json:"max_slash_per_infraction"` // maximum slash fraction of a single infraction, zero for no cap
+
+	SlashEventsHistory  uint64 `json:"slash_events_history"`  // number of blocks for which slash events are kept, zero keeps them all
+	BondedTokensHistory bool   `json:"bonded_tokens_history"` // record the bonded tokens of each validator per block over the unbonding time
+	ExRateHistory       bool   `json:"ex_rate_history"`       // record the exchange rate of each validator per block over the unbonding time
+
+	AutoCompoundInterval int64 `json:"auto_compound_interval"` // number of blocks between the compounding of the rewards of auto-compounding delegations
+}
+
+// MaxValidatorsScheduleEntry sets MaxValidators to Max at the end of the block
+// at Height, or of the first block processed after it.
+type MaxValidatorsScheduleEntry struct {
+	Height int64  `json:"height"`
+	Max    uint16 `json:"max"`
 }
 
 func NewParams(unbondingTime time.Duration, maxValidators, maxEntries uint16,
-	bondDenom string) Params {
+	bondDenom string, shareRoundingMode ShareRoundingMode, instantUnbond bool,
+	validatorUpdatesHistory uint64, maxValidatorsCreatedPerBlock uint16,
+	maxDelegatorPowerShare sdk.Dec, maxValidatorsSchedule []MaxValidatorsScheduleEntry,
+	loyaltyWeighting sdk.Dec, loyaltyPeriod int64, minSlashTokens sdk.Int,
+	bondedRatioHistory uint64, tieBreakMode TieBreakMode, allowedPubKeyTypes []string,
+	minCommissionRate, maxSlashPerInfraction sdk.Dec,
+	slashEventsHistory uint64, bondedTokensHistory, exRateHistory bool,
+	autoCompoundInterval int64) Params {
 
 	return Params{
-		UnbondingTime: unbondingTime,
-		MaxValidators: maxValidators,
-		MaxEntries:    maxEntries,
-		BondDenom:     bondDenom,
+		UnbondingTime:     unbondingTime,
+		MaxValidators:     maxValidators,
+		MaxEntries:        maxEntries,
+		BondDenom:         bondDenom,
+		ShareRoundingMode: shareRoundingMode,
+		InstantUnbond:     instantUnbond,
+
+		ValidatorUpdatesHistory:      validatorUpdatesHistory,
+		MaxValidatorsCreatedPerBlock: maxValidatorsCreatedPerBlock,
+		MaxDelegatorPowerShare:       maxDelegatorPowerShare,
+
+		MaxValidatorsSchedule: maxValidatorsSchedule,
+
+		LoyaltyWeighting: loyaltyWeighting,
+		LoyaltyPeriod:    loyaltyPeriod,
+
+		MinSlashTokens:     minSlashTokens,
+		BondedRatioHistory: bondedRatioHistory,
+
+		TieBreakMode:       tieBreakMode,
+		AllowedPubKeyTypes: allowedPubKeyTypes,
+
+		MinCommissionRate:     minCommissionRate,
+		MaxSlashPerInfraction: maxSlashPerInfraction,
+
+		SlashEventsHistory:  slashEventsHistory,
+		BondedTokensHistory: bondedTokensHistory,
+		ExRateHistory:       exRateHistory,
+
+		AutoCompoundInterval: autoCompoundInterval,
 	}
 }
 
@@ -60,6 +245,24 @@ func (p *Params) ParamSetPairs() params.ParamSetPairs {
 		{KeyMaxValidators, &p.MaxValidators},
 		{KeyMaxEntries, &p.MaxEntries},
 		{KeyBondDenom, &p.BondDenom},
+		{KeyShareRoundingMode, &p.ShareRoundingMode},
+		{KeyInstantUnbond, &p.InstantUnbond},
+		{KeyValidatorUpdatesHistory, &p.ValidatorUpdatesHistory},
+		{KeyMaxValidatorsCreatedPerBlock, &p.MaxValidatorsCreatedPerBlock},
+		{KeyMaxDelegatorPowerShare, &p.MaxDelegatorPowerShare},
+		{KeyMaxValidatorsSchedule, &p.MaxValidatorsSchedule},
+		{KeyLoyaltyWeighting, &p.LoyaltyWeighting},
+		{KeyLoyaltyPeriod, &p.LoyaltyPeriod},
+		{KeyMinSlashTokens, &p.MinSlashTokens},
+		{KeyBondedRatioHistory, &p.BondedRatioHistory},
+		{KeyTieBreakMode, &p.TieBreakMode},
+		{KeyAllowedPubKeyTypes, &p.AllowedPubKeyTypes},
+		{KeyMinCommissionRate, &p.MinCommissionRate},
+		{KeyMaxSlashPerInfraction, &p.MaxSlashPerInfraction},
+		{KeySlashEventsHistory, &p.SlashEventsHistory},
+		{KeyBondedTokensHistory, &p.BondedTokensHistory},
+		{KeyExRateHistory, &p.ExRateHistory},
+		{KeyAutoCompoundInterval, &p.AutoCompoundInterval},
 	}
 }
 
@@ -73,7 +276,12 @@ func (p Params) Equal(p2 Params) bool {
 
 // DefaultParams returns a default set of parameters.
 func DefaultParams() Params {
-	return NewParams(DefaultUnbondingTime, DefaultMaxValidators, DefaultMaxEntries, sdk.DefaultBondDenom)
+	return NewParams(DefaultUnbondingTime, DefaultMaxValidators, DefaultMaxEntries,
+		sdk.DefaultBondDenom, DefaultShareRoundingMode, DefaultInstantUnbond,
+		DefaultValidatorUpdatesHistory, DefaultMaxValidatorsCreatedPerBlock, sdk.ZeroDec(), nil,
+		sdk.ZeroDec(), DefaultLoyaltyPeriod, sdk.ZeroInt(), DefaultBondedRatioHistory,
+		DefaultTieBreakMode, nil, sdk.ZeroDec(), sdk.ZeroDec(), DefaultSlashEventsHistory,
+		DefaultBondedTokensHistory, DefaultExRateHistory, DefaultAutoCompoundInterval)
 }
 
 // String returns a human readable string representation of the parameters.
@@ -82,8 +290,31 @@ func (p Params) String() string {
   Unbonding Time:    %s
   Max Validators:    %d
   Max Entries:       %d
-  Bonded Coin Denom: %s`, p.UnbondingTime,
-		p.MaxValidators, p.MaxEntries, p.BondDenom)
+  Bonded Coin Denom: %s
+  Share Rounding:    %s
+  Instant Unbond:    %t
+  Val Updates Hist:  %d
+  Max Vals Created:  %d
+  Max Del Power:     %s
+  Max Vals Schedule: %v
+  Loyalty Weighting: %s
+  Loyalty Period:    %d
+  Min Slash Tokens:  %s
+  Bonded Ratio Hist: %d
+  Tie Break Mode:    %s
+  Allowed Key Types: %v
+  Min Commission:    %s
+  Max Slash:         %s
+  Slash Events Hist: %d
+  Val Tokens Hist:   %t
+  Ex Rate Hist:      %t
+  Compound Interval: %d`, p.UnbondingTime,
+		p.MaxValidators, p.MaxEntries, p.BondDenom, p.ShareRoundingMode,
+		p.InstantUnbond, p.ValidatorUpdatesHistory, p.MaxValidatorsCreatedPerBlock,
+		p.MaxDelegatorPowerShare, p.MaxValidatorsSchedule,
+		p.LoyaltyWeighting, p.LoyaltyPeriod, p.MinSlashTokens, p.BondedRatioHistory,
+		p.TieBreakMode, p.AllowedPubKeyTypes, p.MinCommissionRate, p.MaxSlashPerInfraction, p.SlashEventsHistory,
+		p.BondedTokensHistory, p.ExRateHistory, p.AutoCompoundInterval)
 }
 
 // unmarshal the current staking params value from store key or panic
@@ -112,5 +343,72 @@ func (p Params) Validate() error {
 	if p.MaxValidators == 0 {
 		return fmt.Errorf("staking parameter MaxValidators must be a positive integer")
 	}
+	if !p.ShareRoundingMode.IsValid() {
+		return fmt.Errorf("staking parameter ShareRoundingMode is invalid: %d", p.ShareRoundingMode)
+	}
+	if p.MaxDelegatorPowerShare.IsNil() {
+		return fmt.Errorf("staking parameter MaxDelegatorPowerShare must be set")
+	}
+	if p.MaxDelegatorPowerShare.IsNegative() || p.MaxDelegatorPowerShare.GT(sdk.OneDec()) {
+		return fmt.Errorf("staking parameter MaxDelegatorPowerShare must be between 0 and 1, is %s", p.MaxDelegatorPowerShare)
+	}
+	var lastHeight int64
+	for _, entry := range p.MaxValidatorsSchedule {
+		if entry.Height <= lastHeight {
+			return fmt.Errorf("staking parameter MaxValidatorsSchedule heights must be positive and increasing, got %d after %d",
+				entry.Height, lastHeight)
+		}
+		if entry.Max == 0 {
+			return fmt.Errorf("staking parameter MaxValidatorsSchedule must set MaxValidators to a positive value at height %d", entry.Height)
+		}
+		lastHeight = entry.Height
+	}
+	if p.LoyaltyWeighting.IsNil() {
+		return fmt.Errorf("staking parameter LoyaltyWeighting must be set")
+	}
+	if p.LoyaltyWeighting.IsNegative() || p.LoyaltyWeighting.GT(sdk.OneDec()) {
+		return fmt.Errorf("staking parameter LoyaltyWeighting must be between 0 and 1, is %s", p.LoyaltyWeighting)
+	}
+	if p.LoyaltyPeriod <= 0 {
+		return fmt.Errorf("staking parameter LoyaltyPeriod must be positive, is %d", p.LoyaltyPeriod)
+	}
+	if p.MinSlashTokens.IsNil() {
+		return fmt.Errorf("staking parameter MinSlashTokens must be set")
+	}
+	if p.MinSlashTokens.IsNegative() {
+		return fmt.Errorf("staking parameter MinSlashTokens can't be negative, is %s", p.MinSlashTokens)
+	}
+	if !p.TieBreakMode.IsValid() {
+		return fmt.Errorf("staking parameter TieBreakMode is invalid: %d", p.TieBreakMode)
+	}
+	for _, keyType := range p.AllowedPubKeyTypes {
+		if keyType != tmtypes.ABCIPubKeyTypeEd25519 && keyType != tmtypes.ABCIPubKeyTypeSecp256k1 {
+			return fmt.Errorf("staking parameter AllowedPubKeyTypes contains unknown type %s", keyType)
+		}
+	}
+	if p.MinCommissionRate.IsNil() {
+		return fmt.Errorf("staking parameter MinCommissionRate must be set")
+	}
+	if p.MinCommissionRate.IsNegative() || p.MinCommissionRate.GT(sdk.OneDec()) {
+		return fmt.Errorf("staking parameter MinCommissionRate must be between 0 and 1, is %s", p.MinCommissionRate)
+	}
+	if p.MaxSlashPerInfraction.IsNil() {
+		return fmt.Errorf("staking parameter MaxSlashPerInfraction must be set")
+	}
+	if p.MaxSlashPerInfraction.IsNegative() || p.MaxSlashPerInfraction.GT(sdk.OneDec()) {
+		return fmt.Errorf("staking parameter MaxSlashPerInfraction must be between 0 and 1, is %s", p.MaxSlashPerInfraction)
+	}
+	if p.AutoCompoundInterval <= 0 {
+		return fmt.Errorf("staking parameter AutoCompoundInterval must be positive, is %d", p.AutoCompoundInterval)
+	}
 	return nil
 }
+
+// ParamChangeImpact is the predicted effect of a change of the staking params
+// on the validator set and the inflation.
+type ParamChangeImpact struct {
+	Bonding       []sdk.ValAddress `json:"bonding"`        // validators which would enter the bonded set
+	Unbonding     []sdk.ValAddress `json:"unbonding"`      // validators which would leave the bonded set
+	BondedRatio   sdk.Dec          `json:"bonded_ratio"`   // resulting fraction of the staking tokens which are bonded
+	NextInflation sdk.Dec          `json:"next_inflation"` // resulting inflation rate of the next block
+}
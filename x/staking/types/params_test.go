@@ -4,6 +4,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
 )
 
 func TestParamsEqual(t *testing.T) {
@@ -19,3 +21,34 @@ func TestParamsEqual(t *testing.T) {
 	ok = p1.Equal(p2)
 	require.False(t, ok)
 }
+
+func TestParamsValidateMaxValidatorsSchedule(t *testing.T) {
+	p := DefaultParams()
+	p.MaxValidatorsSchedule = []MaxValidatorsScheduleEntry{{Height: 10, Max: 120}, {Height: 20, Max: 150}}
+	require.NoError(t, p.Validate())
+
+	p.MaxValidatorsSchedule = []MaxValidatorsScheduleEntry{{Height: 20, Max: 120}, {Height: 10, Max: 150}}
+	require.Error(t, p.Validate())
+
+	p.MaxValidatorsSchedule = []MaxValidatorsScheduleEntry{{Height: 10, Max: 0}}
+	require.Error(t, p.Validate())
+}
+
+func TestParamsValidateUnset(t *testing.T) {
+	tests := []struct {
+		name  string
+		unset func(p *Params)
+	}{
+		{"MaxDelegatorPowerShare", func(p *Params) { p.MaxDelegatorPowerShare = sdk.Dec{} }},
+		{"LoyaltyWeighting", func(p *Params) { p.LoyaltyWeighting = sdk.Dec{} }},
+		{"MinSlashTokens", func(p *Params) { p.MinSlashTokens = sdk.Int{} }},
+		{"MinCommissionRate", func(p *Params) { p.MinCommissionRate = sdk.Dec{} }},
+		{"MaxSlashPerInfraction", func(p *Params) { p.MaxSlashPerInfraction = sdk.Dec{} }},
+	}
+
+	for _, tc := range tests {
+		p := DefaultParams()
+		tc.unset(&p)
+		require.Error(t, p.Validate(), tc.name)
+	}
+}
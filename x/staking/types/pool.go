@@ -8,9 +8,10 @@ import (
 	sdk "github.com/cosmos/cosmos-sdk/types"
 )
 
-// Pool - tracking bonded and not-bonded token supply of the bond denomination
+// Pool - tracking bonded, unbonding and not-bonded token supply of the bond denomination
 type Pool struct {
-	NotBondedTokens sdk.Int `json:"not_bonded_tokens"` // tokens which are not bonded to a validator (unbonded or unbonding)
+	NotBondedTokens sdk.Int `json:"not_bonded_tokens"` // unbonded tokens, neither bonded to a validator nor held by unbonding delegations
+	UnbondingTokens sdk.Int `json:"unbonding_tokens"`  // tokens held by the entries of unbonding delegations
 	BondedTokens    sdk.Int `json:"bonded_tokens"`     // tokens which are currently bonded to a validator
 }
 
@@ -26,16 +27,17 @@ func (p Pool) Equal(p2 Pool) bool {
 func InitialPool() Pool {
 	return Pool{
 		NotBondedTokens: sdk.ZeroInt(),
+		UnbondingTokens: sdk.ZeroInt(),
 		BondedTokens:    sdk.ZeroInt(),
 	}
 }
 
 // Sum total of all staking tokens in the pool
 func (p Pool) TokenSupply() sdk.Int {
-	return p.NotBondedTokens.Add(p.BondedTokens)
+	return p.BondedTokens.Add(p.UnbondingTokens).Add(p.NotBondedTokens)
 }
 
-// Get the fraction of the staking token which is currently bonded
+// Get the fraction of the full staking token supply which is currently bonded
 func (p Pool) BondedRatio() sdk.Dec {
 	supply := p.TokenSupply()
 	if supply.IsPositive() {
@@ -62,17 +64,46 @@ func (p Pool) bondedTokensToNotBonded(bondedTokens sdk.Int) Pool {
 	return p
 }
 
+// NotBondedTokensToUnbonding moves tokens which start unbonding in an
+// unbonding delegation entry out of the not-bonded tokens.
+func (p Pool) NotBondedTokensToUnbonding(unbondingTokens sdk.Int) Pool {
+	p.UnbondingTokens = p.UnbondingTokens.Add(unbondingTokens)
+	p.NotBondedTokens = p.NotBondedTokens.Sub(unbondingTokens)
+	if p.NotBondedTokens.IsNegative() {
+		panic(fmt.Sprintf("sanity check: not-bonded tokens negative, pool: %v", p))
+	}
+	return p
+}
+
+// UnbondingTokensToNotBonded moves the tokens of completed unbonding
+// delegation entries back to the not-bonded tokens.
+func (p Pool) UnbondingTokensToNotBonded(unbondingTokens sdk.Int) Pool {
+	p.UnbondingTokens = p.UnbondingTokens.Sub(unbondingTokens)
+	p.NotBondedTokens = p.NotBondedTokens.Add(unbondingTokens)
+	if p.UnbondingTokens.IsNegative() {
+		panic(fmt.Sprintf("sanity check: unbonding tokens negative, pool: %v", p))
+	}
+	return p
+}
+
 // String returns a human readable string representation of a pool.
 func (p Pool) String() string {
 	return fmt.Sprintf(`Pool:
-  Loose Tokens:  %s
-  Bonded Tokens: %s
-  Token Supply:  %s
-  Bonded Ratio:  %v`, p.NotBondedTokens,
+  Loose Tokens:     %s
+  Unbonding Tokens: %s
+  Bonded Tokens:    %s
+  Token Supply:     %s
+  Bonded Ratio:     %v`, p.NotBondedTokens, p.UnbondingTokens,
 		p.BondedTokens, p.TokenSupply(),
 		p.BondedRatio())
 }
 
+// BondedRatioRecord is the bonded ratio of the pool at the end of a block
+type BondedRatioRecord struct {
+	Height      int64   `json:"height"`
+	BondedRatio sdk.Dec `json:"bonded_ratio"`
+}
+
 // unmarshal the current pool value from store key or panics
 func MustUnmarshalPool(cdc *codec.Codec, value []byte) Pool {
 	pool, err := UnmarshalPool(cdc, value)
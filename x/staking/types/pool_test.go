@@ -37,3 +37,19 @@ func TestRemoveBondedTokens(t *testing.T) {
 	require.True(sdk.IntEq(t, sdk.NewInt(5), pool.BondedTokens))
 	require.True(sdk.IntEq(t, sdk.NewInt(15), pool.NotBondedTokens))
 }
+
+func TestPoolTokenSupply(t *testing.T) {
+	pool := InitialPool()
+	pool.BondedTokens = sdk.NewInt(50)
+	pool.UnbondingTokens = sdk.NewInt(30)
+	pool.NotBondedTokens = sdk.NewInt(20)
+
+	require.True(sdk.IntEq(t, sdk.NewInt(100), pool.TokenSupply()))
+	require.True(sdk.DecEq(t, sdk.NewDecWithPrec(5, 1), pool.BondedRatio()))
+
+	// an empty unbonding bucket leaves supply and ratio unchanged
+	pool.NotBondedTokens = pool.NotBondedTokens.Add(pool.UnbondingTokens)
+	pool.UnbondingTokens = sdk.ZeroInt()
+	require.True(sdk.IntEq(t, sdk.NewInt(100), pool.TokenSupply()))
+	require.True(sdk.DecEq(t, sdk.NewDecWithPrec(5, 1), pool.BondedRatio()))
+}
@@ -0,0 +1,166 @@
+package types
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	govtypes "github.com/cosmos/cosmos-sdk/x/gov/types"
+)
+
+const (
+	// ProposalTypeFreezeValidator defines the type for a FreezeValidatorProposal
+	ProposalTypeFreezeValidator = "FreezeValidator"
+	// ProposalTypeCompleteUnbondings defines the type for a CompleteUnbondingsProposal
+	ProposalTypeCompleteUnbondings = "CompleteUnbondings"
+	// ProposalTypeVerifyValidatorIdentity defines the type for a VerifyValidatorIdentityProposal
+	ProposalTypeVerifyValidatorIdentity = "VerifyValidatorIdentity"
+)
+
+// Assert the staking proposals implement govtypes.Content at compile-time
+var (
+	_ govtypes.Content = FreezeValidatorProposal{}
+	_ govtypes.Content = CompleteUnbondingsProposal{}
+	_ govtypes.Content = VerifyValidatorIdentityProposal{}
+)
+
+func init() {
+	govtypes.RegisterProposalType(ProposalTypeFreezeValidator)
+	govtypes.RegisterProposalTypeCodec(FreezeValidatorProposal{}, "cosmos-sdk/FreezeValidatorProposal")
+	govtypes.RegisterProposalType(ProposalTypeCompleteUnbondings)
+	govtypes.RegisterProposalTypeCodec(CompleteUnbondingsProposal{}, "cosmos-sdk/CompleteUnbondingsProposal")
+	govtypes.RegisterProposalType(ProposalTypeVerifyValidatorIdentity)
+	govtypes.RegisterProposalTypeCodec(VerifyValidatorIdentityProposal{}, "cosmos-sdk/VerifyValidatorIdentityProposal")
+}
+
+// FreezeValidatorProposal defines a proposal which freezes, or unfreezes, the
+// delegations of a single validator.
+type FreezeValidatorProposal struct {
+	Title            string         `json:"title"`
+	Description      string         `json:"description"`
+	ValidatorAddress sdk.ValAddress `json:"validator_address"`
+	Frozen           bool           `json:"frozen"`
+}
+
+func NewFreezeValidatorProposal(title, description string, valAddr sdk.ValAddress, frozen bool) FreezeValidatorProposal {
+	return FreezeValidatorProposal{title, description, valAddr, frozen}
+}
+
+// GetTitle returns the title of a freeze validator proposal.
+func (fvp FreezeValidatorProposal) GetTitle() string { return fvp.Title }
+
+// GetDescription returns the description of a freeze validator proposal.
+func (fvp FreezeValidatorProposal) GetDescription() string { return fvp.Description }
+
+// ProposalRoute returns the routing key of a freeze validator proposal.
+func (fvp FreezeValidatorProposal) ProposalRoute() string { return RouterKey }
+
+// ProposalType returns the type of a freeze validator proposal.
+func (fvp FreezeValidatorProposal) ProposalType() string { return ProposalTypeFreezeValidator }
+
+func (fvp FreezeValidatorProposal) ValidateBasic() sdk.Error {
+	err := govtypes.ValidateAbstract(DefaultCodespace, fvp)
+	if err != nil {
+		return err
+	}
+
+	if fvp.ValidatorAddress.Empty() {
+		return ErrNilValidatorAddr(DefaultCodespace)
+	}
+
+	return nil
+}
+
+// String implements the Stringer interface.
+func (fvp FreezeValidatorProposal) String() string {
+	return fmt.Sprintf(`Freeze Validator Proposal:
+  Title:       %s
+  Description: %s
+  Validator:   %s
+  Frozen:      %t
+`, fvp.Title, fvp.Description, fvp.ValidatorAddress, fvp.Frozen)
+}
+
+// CompleteUnbondingsProposal defines an emergency proposal which immediately
+// matures every pending unbonding delegation and redelegation.
+type CompleteUnbondingsProposal struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+func NewCompleteUnbondingsProposal(title, description string) CompleteUnbondingsProposal {
+	return CompleteUnbondingsProposal{title, description}
+}
+
+// GetTitle returns the title of a complete unbondings proposal.
+func (cup CompleteUnbondingsProposal) GetTitle() string { return cup.Title }
+
+// GetDescription returns the description of a complete unbondings proposal.
+func (cup CompleteUnbondingsProposal) GetDescription() string { return cup.Description }
+
+// ProposalRoute returns the routing key of a complete unbondings proposal.
+func (cup CompleteUnbondingsProposal) ProposalRoute() string { return RouterKey }
+
+// ProposalType returns the type of a complete unbondings proposal.
+func (cup CompleteUnbondingsProposal) ProposalType() string { return ProposalTypeCompleteUnbondings }
+
+func (cup CompleteUnbondingsProposal) ValidateBasic() sdk.Error {
+	return govtypes.ValidateAbstract(DefaultCodespace, cup)
+}
+
+// String implements the Stringer interface.
+func (cup CompleteUnbondingsProposal) String() string {
+	return fmt.Sprintf(`Complete Unbondings Proposal:
+  Title:       %s
+  Description: %s
+`, cup.Title, cup.Description)
+}
+
+// VerifyValidatorIdentityProposal defines a proposal which records the result
+// of an external check (ex. Keybase) of a validator's identity.
+type VerifyValidatorIdentityProposal struct {
+	Title            string         `json:"title"`
+	Description      string         `json:"description"`
+	ValidatorAddress sdk.ValAddress `json:"validator_address"`
+	Verified         bool           `json:"verified"`
+}
+
+func NewVerifyValidatorIdentityProposal(title, description string, valAddr sdk.ValAddress, verified bool) VerifyValidatorIdentityProposal {
+	return VerifyValidatorIdentityProposal{title, description, valAddr, verified}
+}
+
+// GetTitle returns the title of a verify validator identity proposal.
+func (vvp VerifyValidatorIdentityProposal) GetTitle() string { return vvp.Title }
+
+// GetDescription returns the description of a verify validator identity proposal.
+func (vvp VerifyValidatorIdentityProposal) GetDescription() string { return vvp.Description }
+
+// ProposalRoute returns the routing key of a verify validator identity proposal.
+func (vvp VerifyValidatorIdentityProposal) ProposalRoute() string { return RouterKey }
+
+// ProposalType returns the type of a verify validator identity proposal.
+func (vvp VerifyValidatorIdentityProposal) ProposalType() string {
+	return ProposalTypeVerifyValidatorIdentity
+}
+
+func (vvp VerifyValidatorIdentityProposal) ValidateBasic() sdk.Error {
+	err := govtypes.ValidateAbstract(DefaultCodespace, vvp)
+	if err != nil {
+		return err
+	}
+
+	if vvp.ValidatorAddress.Empty() {
+		return ErrNilValidatorAddr(DefaultCodespace)
+	}
+
+	return nil
+}
+
+// String implements the Stringer interface.
+func (vvp VerifyValidatorIdentityProposal) String() string {
+	return fmt.Sprintf(`Verify Validator Identity Proposal:
+  Title:       %s
+  Description: %s
+  Validator:   %s
+  Verified:    %t
+`, vvp.Title, vvp.Description, vvp.ValidatorAddress, vvp.Verified)
+}
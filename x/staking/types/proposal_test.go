@@ -0,0 +1,52 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFreezeValidatorProposal(t *testing.T) {
+	fvp := NewFreezeValidatorProposal("test title", "test description", addr1, true)
+
+	require.Equal(t, "test title", fvp.GetTitle())
+	require.Equal(t, "test description", fvp.GetDescription())
+	require.Equal(t, RouterKey, fvp.ProposalRoute())
+	require.Equal(t, ProposalTypeFreezeValidator, fvp.ProposalType())
+	require.Nil(t, fvp.ValidateBasic())
+
+	fvp = NewFreezeValidatorProposal("test title", "test description", nil, true)
+	require.Error(t, fvp.ValidateBasic())
+
+	fvp = NewFreezeValidatorProposal("", "test description", addr1, true)
+	require.Error(t, fvp.ValidateBasic())
+}
+
+func TestCompleteUnbondingsProposal(t *testing.T) {
+	cup := NewCompleteUnbondingsProposal("test title", "test description")
+
+	require.Equal(t, "test title", cup.GetTitle())
+	require.Equal(t, "test description", cup.GetDescription())
+	require.Equal(t, RouterKey, cup.ProposalRoute())
+	require.Equal(t, ProposalTypeCompleteUnbondings, cup.ProposalType())
+	require.Nil(t, cup.ValidateBasic())
+
+	cup = NewCompleteUnbondingsProposal("", "test description")
+	require.Error(t, cup.ValidateBasic())
+}
+
+func TestVerifyValidatorIdentityProposal(t *testing.T) {
+	vvp := NewVerifyValidatorIdentityProposal("test title", "test description", addr1, true)
+
+	require.Equal(t, "test title", vvp.GetTitle())
+	require.Equal(t, "test description", vvp.GetDescription())
+	require.Equal(t, RouterKey, vvp.ProposalRoute())
+	require.Equal(t, ProposalTypeVerifyValidatorIdentity, vvp.ProposalType())
+	require.Nil(t, vvp.ValidateBasic())
+
+	vvp = NewVerifyValidatorIdentityProposal("test title", "test description", nil, true)
+	require.Error(t, vvp.ValidateBasic())
+
+	vvp = NewVerifyValidatorIdentityProposal("", "test description", addr1, true)
+	require.Error(t, vvp.ValidateBasic())
+}
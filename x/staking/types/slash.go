@@ -0,0 +1,61 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// SlashEvent records a single slash applied to a validator so that delegators
+// can review a validator's penalty history.
+type SlashEvent struct {
+	Height       int64   `json:"height"`        // height at which the slash was applied
+	Fraction     sdk.Dec `json:"fraction"`      // slash factor of the infraction
+	TokensBurned sdk.Int `json:"tokens_burned"` // tokens burned from the validator
+}
+
+// NewSlashEvent creates a new slash event
+func NewSlashEvent(height int64, fraction sdk.Dec, tokensBurned sdk.Int) SlashEvent {
+	return SlashEvent{
+		Height:       height,
+		Fraction:     fraction,
+		TokensBurned: tokensBurned,
+	}
+}
+
+// String returns a human readable string representation of a slash event.
+func (e SlashEvent) String() string {
+	return fmt.Sprintf(`Slash Event:
+  Height:        %d
+  Fraction:      %s
+  Tokens Burned: %s`, e.Height, e.Fraction, e.TokensBurned)
+}
+
+// SlashEvents is a collection of SlashEvent
+type SlashEvents []SlashEvent
+
+func (e SlashEvents) String() (out string) {
+	for _, event := range e {
+		out += event.String() + "\n"
+	}
+	return strings.TrimSpace(out)
+}
+
+// ValidatorSlashEvents holds the slash events of a validator for genesis
+type ValidatorSlashEvents struct {
+	ValidatorAddress sdk.ValAddress `json:"validator_address"`
+	Events           SlashEvents    `json:"events"`
+}
+
+// return the slash events
+func MustMarshalSlashEvents(cdc *codec.Codec, events SlashEvents) []byte {
+	return cdc.MustMarshalBinaryLengthPrefixed(events)
+}
+
+// unmarshal slash events from a store value
+func MustUnmarshalSlashEvents(cdc *codec.Codec, value []byte) (events SlashEvents) {
+	cdc.MustUnmarshalBinaryLengthPrefixed(value, &events)
+	return events
+}
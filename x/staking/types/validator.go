@@ -3,6 +3,8 @@ package types
 import (
 	"bytes"
 	"fmt"
+	"net/mail"
+	"net/url"
 	"strings"
 	"time"
 
@@ -17,10 +19,11 @@ import (
 // nolint
 const (
 	// TODO: Why can't we just have one string description which can be JSON by convention
-	MaxMonikerLength  = 70
-	MaxIdentityLength = 3000
-	MaxWebsiteLength  = 140
-	MaxDetailsLength  = 280
+	MaxMonikerLength         = 70
+	MaxIdentityLength        = 3000
+	MaxWebsiteLength         = 140
+	MaxDetailsLength         = 280
+	MaxSecurityContactLength = 140
 )
 
 // Validator defines the total amount of bond shares and their exchange rate to
@@ -31,17 +34,24 @@ const (
 // divided by the current exchange rate. Voting power can be calculated as total
 // bonded shares multiplied by exchange rate.
 type Validator struct {
-	OperatorAddress         sdk.ValAddress `json:"operator_address"`    // address of the validator's operator; bech encoded in JSON
-	ConsPubKey              crypto.PubKey  `json:"consensus_pubkey"`    // the consensus public key of the validator; bech encoded in JSON
-	Jailed                  bool           `json:"jailed"`              // has the validator been jailed from bonded status?
-	Status                  sdk.BondStatus `json:"status"`              // validator status (bonded/unbonding/unbonded)
-	Tokens                  sdk.Int        `json:"tokens"`              // delegated tokens (incl. self-delegation)
-	DelegatorShares         sdk.Dec        `json:"delegator_shares"`    // total shares issued to a validator's delegators
-	Description             Description    `json:"description"`         // description terms for the validator
-	UnbondingHeight         int64          `json:"unbonding_height"`    // if unbonding, height at which this validator has begun unbonding
-	UnbondingCompletionTime time.Time      `json:"unbonding_time"`      // if unbonding, min time for the validator to complete unbonding
-	Commission              Commission     `json:"commission"`          // commission parameters
-	MinSelfDelegation       sdk.Int        `json:"min_self_delegation"` // validator's self declared minimum self delegation
+	OperatorAddress         sdk.ValAddress `json:"operator_address"`     // address of the validator's operator; bech encoded in JSON
+	ConsPubKey              crypto.PubKey  `json:"consensus_pubkey"`     // the consensus public key of the validator; bech encoded in JSON
+	Jailed                  bool           `json:"jailed"`               // has the validator been jailed from bonded status?
+	Status                  sdk.BondStatus `json:"status"`               // validator status (bonded/unbonding/unbonded)
+	Tokens                  sdk.Int        `json:"tokens"`               // delegated tokens (incl. self-delegation)
+	DelegatorShares         sdk.Dec        `json:"delegator_shares"`     // total shares issued to a validator's delegators
+	Description             Description    `json:"description"`          // description terms for the validator
+	BondHeight              int64          `json:"bond_height"`          // height at which this validator was created
+	UnbondingHeight         int64          `json:"unbonding_height"`     // if unbonding, height at which this validator has begun unbonding
+	UnbondingCompletionTime time.Time      `json:"unbonding_time"`       // if unbonding, min time for the validator to complete unbonding
+	Commission              Commission     `json:"commission"`           // commission parameters
+	MinSelfDelegation       sdk.Int        `json:"min_self_delegation"`  // validator's self declared minimum self delegation
+	MaxTotalDelegation      sdk.Dec        `json:"max_total_delegation"` // validator's self declared cap on delegator shares, zero for no cap
+	Frozen                  bool           `json:"frozen"`               // have new delegations and unbondings been frozen by governance?
+
+	UnbondingTimeOverride time.Duration `json:"unbonding_time_override"` // unbonding time applied instead of the param if longer, zero for none
+	PlannedExitHeight     int64         `json:"planned_exit_height"`     // advisory height at which the operator intends to leave, zero for none
+	PowerSequence         uint64        `json:"power_sequence"`          // order in which the validator's tokens last changed, see TieBreakTxOrder
 }
 
 // Validators is a collection of Validator
@@ -72,10 +82,12 @@ func NewValidator(operator sdk.ValAddress, pubKey crypto.PubKey, description Des
 		Tokens:                  sdk.ZeroInt(),
 		DelegatorShares:         sdk.ZeroDec(),
 		Description:             description,
+		BondHeight:              int64(0),
 		UnbondingHeight:         int64(0),
 		UnbondingCompletionTime: time.Unix(0, 0).UTC(),
 		Commission:              NewCommission(sdk.ZeroDec(), sdk.ZeroDec(), sdk.ZeroDec()),
 		MinSelfDelegation:       sdk.OneInt(),
+		MaxTotalDelegation:      sdk.ZeroDec(),
 	}
 }
 
@@ -113,28 +125,43 @@ func (v Validator) String() string {
   Tokens:                     %s
   Delegator Shares:           %s
   Description:                %s
+  Bond Height:                %d
   Unbonding Height:           %d
   Unbonding Completion Time:  %v
   Minimum Self Delegation:    %v
+  Maximum Total Delegation:   %v
+  Frozen:                     %v
+  Unbonding Time Override:    %v
+  Planned Exit Height:        %d
+  Power Sequence:             %d
   Commission:                 %s`, v.OperatorAddress, bechConsPubKey,
 		v.Jailed, v.Status, v.Tokens,
-		v.DelegatorShares, v.Description,
-		v.UnbondingHeight, v.UnbondingCompletionTime, v.MinSelfDelegation, v.Commission)
+		v.DelegatorShares, v.Description, v.BondHeight,
+		v.UnbondingHeight, v.UnbondingCompletionTime, v.MinSelfDelegation,
+		v.MaxTotalDelegation, v.Frozen, v.UnbondingTimeOverride, v.PlannedExitHeight,
+		v.PowerSequence, v.Commission)
 }
 
 // this is a helper struct used for JSON de- and encoding only
 type bechValidator struct {
-	OperatorAddress         sdk.ValAddress `json:"operator_address"`    // the bech32 address of the validator's operator
-	ConsPubKey              string         `json:"consensus_pubkey"`    // the bech32 consensus public key of the validator
-	Jailed                  bool           `json:"jailed"`              // has the validator been jailed from bonded status?
-	Status                  sdk.BondStatus `json:"status"`              // validator status (bonded/unbonding/unbonded)
-	Tokens                  sdk.Int        `json:"tokens"`              // delegated tokens (incl. self-delegation)
-	DelegatorShares         sdk.Dec        `json:"delegator_shares"`    // total shares issued to a validator's delegators
-	Description             Description    `json:"description"`         // description terms for the validator
-	UnbondingHeight         int64          `json:"unbonding_height"`    // if unbonding, height at which this validator has begun unbonding
-	UnbondingCompletionTime time.Time      `json:"unbonding_time"`      // if unbonding, min time for the validator to complete unbonding
-	Commission              Commission     `json:"commission"`          // commission parameters
-	MinSelfDelegation       sdk.Int        `json:"min_self_delegation"` // minimum self delegation
+	OperatorAddress         sdk.ValAddress `json:"operator_address"`     // the bech32 address of the validator's operator
+	ConsPubKey              string         `json:"consensus_pubkey"`     // the bech32 consensus public key of the validator
+	Jailed                  bool           `json:"jailed"`               // has the validator been jailed from bonded status?
+	Status                  sdk.BondStatus `json:"status"`               // validator status (bonded/unbonding/unbonded)
+	Tokens                  sdk.Int        `json:"tokens"`               // delegated tokens (incl. self-delegation)
+	DelegatorShares         sdk.Dec        `json:"delegator_shares"`     // total shares issued to a validator's delegators
+	Description             Description    `json:"description"`          // description terms for the validator
+	BondHeight              int64          `json:"bond_height"`          // height at which this validator was created
+	UnbondingHeight         int64          `json:"unbonding_height"`     // if unbonding, height at which this validator has begun unbonding
+	UnbondingCompletionTime time.Time      `json:"unbonding_time"`       // if unbonding, min time for the validator to complete unbonding
+	Commission              Commission     `json:"commission"`           // commission parameters
+	MinSelfDelegation       sdk.Int        `json:"min_self_delegation"`  // minimum self delegation
+	MaxTotalDelegation      sdk.Dec        `json:"max_total_delegation"` // maximum total delegator shares
+	Frozen                  bool           `json:"frozen"`               // have new delegations and unbondings been frozen by governance?
+
+	UnbondingTimeOverride time.Duration `json:"unbonding_time_override"` // unbonding time applied instead of the param if longer
+	PlannedExitHeight     int64         `json:"planned_exit_height"`     // advisory height at which the operator intends to leave
+	PowerSequence         uint64        `json:"power_sequence"`          // order in which the validator's tokens last changed
 }
 
 // MarshalJSON marshals the validator to JSON using Bech32
@@ -152,10 +179,16 @@ func (v Validator) MarshalJSON() ([]byte, error) {
 		Tokens:                  v.Tokens,
 		DelegatorShares:         v.DelegatorShares,
 		Description:             v.Description,
+		BondHeight:              v.BondHeight,
 		UnbondingHeight:         v.UnbondingHeight,
 		UnbondingCompletionTime: v.UnbondingCompletionTime,
 		MinSelfDelegation:       v.MinSelfDelegation,
+		MaxTotalDelegation:      v.MaxTotalDelegation,
 		Commission:              v.Commission,
+		Frozen:                  v.Frozen,
+		UnbondingTimeOverride:   v.UnbondingTimeOverride,
+		PlannedExitHeight:       v.PlannedExitHeight,
+		PowerSequence:           v.PowerSequence,
 	})
 }
 
@@ -177,10 +210,16 @@ func (v *Validator) UnmarshalJSON(data []byte) error {
 		Status:                  bv.Status,
 		DelegatorShares:         bv.DelegatorShares,
 		Description:             bv.Description,
+		BondHeight:              bv.BondHeight,
 		UnbondingHeight:         bv.UnbondingHeight,
 		UnbondingCompletionTime: bv.UnbondingCompletionTime,
 		Commission:              bv.Commission,
 		MinSelfDelegation:       bv.MinSelfDelegation,
+		MaxTotalDelegation:      bv.MaxTotalDelegation,
+		Frozen:                  bv.Frozen,
+		UnbondingTimeOverride:   bv.UnbondingTimeOverride,
+		PlannedExitHeight:       bv.PlannedExitHeight,
+		PowerSequence:           bv.PowerSequence,
 	}
 	return nil
 }
@@ -206,24 +245,39 @@ const DoNotModifyDesc = "[do-not-modify]"
 
 // Description - description fields for a validator
 type Description struct {
-	Moniker  string `json:"moniker"`  // name
-	Identity string `json:"identity"` // optional identity signature (ex. UPort or Keybase)
-	Website  string `json:"website"`  // optional website link
-	Details  string `json:"details"`  // optional details
+	Moniker         string `json:"moniker"`          // name
+	Identity        string `json:"identity"`         // optional identity signature (ex. UPort or Keybase)
+	Website         string `json:"website"`          // optional website link
+	Details         string `json:"details"`          // optional details
+	SecurityContact string `json:"security_contact"` // optional security contact, an email address or URL
+
+	// whether the identity was verified by an external check (ex. Keybase),
+	// only set by a VerifyValidatorIdentityProposal
+	IdentityVerified bool `json:"identity_verified"`
 }
 
 // NewDescription returns a new Description with the provided values.
-func NewDescription(moniker, identity, website, details string) Description {
+func NewDescription(moniker, identity, website, details, securityContact string) Description {
 	return Description{
-		Moniker:  moniker,
-		Identity: identity,
-		Website:  website,
-		Details:  details,
+		Moniker:         moniker,
+		Identity:        identity,
+		Website:         website,
+		Details:         details,
+		SecurityContact: securityContact,
 	}
 }
 
+// String implements the Stringer interface for a Description object.
+func (d Description) String() string {
+	return fmt.Sprintf("moniker: %s, identity: %s, website: %s, details: %s, securityContact: %s, identityVerified: %t",
+		d.Moniker, d.Identity, d.Website, d.Details, d.SecurityContact, d.IdentityVerified,
+	)
+}
+
 // UpdateDescription updates the fields of a given description. An error is
-// returned if the resulting description contains an invalid length.
+// returned if the resulting description contains an invalid length or
+// security contact. The identity verification status is kept unless the
+// identity changes.
 func (d Description) UpdateDescription(d2 Description) (Description, sdk.Error) {
 	if d2.Moniker == DoNotModifyDesc {
 		d2.Moniker = d.Moniker
@@ -237,13 +291,22 @@ func (d Description) UpdateDescription(d2 Description) (Description, sdk.Error)
 	if d2.Details == DoNotModifyDesc {
 		d2.Details = d.Details
 	}
+	if d2.SecurityContact == DoNotModifyDesc {
+		d2.SecurityContact = d.SecurityContact
+	}
 
-	return Description{
-		Moniker:  d2.Moniker,
-		Identity: d2.Identity,
-		Website:  d2.Website,
-		Details:  d2.Details,
+	d2, err := Description{
+		Moniker:          d2.Moniker,
+		Identity:         d2.Identity,
+		Website:          d2.Website,
+		Details:          d2.Details,
+		SecurityContact:  d2.SecurityContact,
+		IdentityVerified: d.IdentityVerified && d2.Identity == d.Identity,
 	}.EnsureLength()
+	if err != nil {
+		return d2, err
+	}
+	return d2, d2.ValidateSecurityContact()
 }
 
 // EnsureLength ensures the length of a validator's description.
@@ -260,10 +323,29 @@ func (d Description) EnsureLength() (Description, sdk.Error) {
 	if len(d.Details) > MaxDetailsLength {
 		return d, ErrDescriptionLength(DefaultCodespace, "details", len(d.Details), MaxDetailsLength)
 	}
+	if len(d.SecurityContact) > MaxSecurityContactLength {
+		return d, ErrDescriptionLength(DefaultCodespace, "security contact", len(d.SecurityContact), MaxSecurityContactLength)
+	}
 
 	return d, nil
 }
 
+// ValidateSecurityContact ensures the security contact of a validator's
+// description, if any, is an email address or an http(s) URL.
+func (d Description) ValidateSecurityContact() sdk.Error {
+	if d.SecurityContact == "" {
+		return nil
+	}
+	if addr, err := mail.ParseAddress(d.SecurityContact); err == nil && addr.Address == d.SecurityContact {
+		return nil
+	}
+	if u, err := url.ParseRequestURI(d.SecurityContact); err == nil &&
+		(u.Scheme == "http" || u.Scheme == "https") && u.Host != "" {
+		return nil
+	}
+	return ErrInvalidSecurityContact(DefaultCodespace, d.SecurityContact)
+}
+
 // ABCIValidatorUpdate returns an abci.ValidatorUpdate from a staking validator type
 // with the full validator power
 func (v Validator) ABCIValidatorUpdate() abci.ValidatorUpdate {
@@ -344,9 +426,11 @@ func (v Validator) SetInitialCommission(commission Commission) (Validator, sdk.E
 	return v, nil
 }
 
-// AddTokensFromDel adds tokens to a validator
+// AddTokensFromDel adds tokens to a validator, issuing shares rounded as
+// given by the rounding mode.
 // CONTRACT: Tokens are assumed to have come from not-bonded pool.
-func (v Validator) AddTokensFromDel(pool Pool, amount sdk.Int) (Validator, Pool, sdk.Dec) {
+func (v Validator) AddTokensFromDel(pool Pool, amount sdk.Int,
+	rounding ShareRoundingMode) (Validator, Pool, sdk.Dec) {
 
 	// calculate the shares to issue
 	var issuedShares sdk.Dec
@@ -354,7 +438,13 @@ func (v Validator) AddTokensFromDel(pool Pool, amount sdk.Int) (Validator, Pool,
 		// the first delegation to a validator sets the exchange rate to one
 		issuedShares = amount.ToDec()
 	} else {
-		shares, err := v.SharesFromTokens(amount)
+		var shares sdk.Dec
+		var err sdk.Error
+		if rounding == ShareRoundNearest {
+			shares, err = v.SharesFromTokensRounded(amount)
+		} else {
+			shares, err = v.SharesFromTokens(amount)
+		}
 		if err != nil {
 			panic(err)
 		}
@@ -375,8 +465,11 @@ func (v Validator) AddTokensFromDel(pool Pool, amount sdk.Int) (Validator, Pool,
 // RemoveDelShares removes delegator shares from a validator.
 // NOTE: because token fractions are left in the valiadator,
 //       the exchange rate of future shares of this validator can increase.
+// The tokens returned are the worth of the shares rounded down, or to the
+// nearest token under ShareRoundNearest.
 // CONTRACT: Tokens are assumed to move to the not-bonded pool.
-func (v Validator) RemoveDelShares(pool Pool, delShares sdk.Dec) (Validator, Pool, sdk.Int) {
+func (v Validator) RemoveDelShares(pool Pool, delShares sdk.Dec,
+	rounding ShareRoundingMode) (Validator, Pool, sdk.Int) {
 
 	remainingShares := v.DelegatorShares.Sub(delShares)
 	var issuedTokens sdk.Int
@@ -389,7 +482,13 @@ func (v Validator) RemoveDelShares(pool Pool, delShares sdk.Dec) (Validator, Poo
 
 		// leave excess tokens in the validator
 		// however fully use all the delegator shares
-		issuedTokens = v.TokensFromShares(delShares).TruncateInt()
+		if rounding == ShareRoundNearest {
+			issuedTokens = sdk.MinInt(v.TokensFromShares(delShares).RoundInt(), v.Tokens)
+		} else {
+			// truncate the exact worth, as the rounded decimal worth could
+			// cross a token boundary and over-credit the delegator
+			issuedTokens = v.TokensFromSharesTruncated(delShares).TruncateInt()
+		}
 		v.Tokens = v.Tokens.Sub(issuedTokens)
 		if v.Tokens.IsNegative() {
 			panic("attempting to remove more tokens than available in validator")
@@ -411,19 +510,51 @@ func (v Validator) InvalidExRate() bool {
 	return v.Tokens.IsZero() && v.DelegatorShares.IsPositive()
 }
 
-// calculate the token worth of provided shares
+// ExceedsMaxTotalDelegation returns true if delegating the given amount of
+// tokens would push the validator's delegator shares above its maximum total
+// delegation. A zero maximum means the validator is uncapped.
+func (v Validator) ExceedsMaxTotalDelegation(amount sdk.Int) bool {
+	if v.MaxTotalDelegation.IsNil() || !v.MaxTotalDelegation.IsPositive() {
+		return false
+	}
+
+	issuedShares := amount.ToDec()
+	if !v.DelegatorShares.IsZero() {
+		shares, err := v.SharesFromTokens(amount)
+		if err != nil {
+			// delegation will be rejected on the invalid exchange rate instead
+			return false
+		}
+		issuedShares = shares
+	}
+
+	return v.DelegatorShares.Add(issuedShares).GT(v.MaxTotalDelegation)
+}
+
+// calculate the token worth of provided shares. If the validator has not
+// issued any shares, the exchange rate is one, as for an initial delegation.
 func (v Validator) TokensFromShares(shares sdk.Dec) sdk.Dec {
+	if v.DelegatorShares.IsZero() {
+		return shares
+	}
 	return (shares.MulInt(v.Tokens)).Quo(v.DelegatorShares)
 }
 
-// calculate the token worth of provided shares, truncated
+// calculate the token worth of provided shares, truncated. If the validator
+// has not issued any shares, the exchange rate is one.
 func (v Validator) TokensFromSharesTruncated(shares sdk.Dec) sdk.Dec {
+	if v.DelegatorShares.IsZero() {
+		return shares
+	}
 	return (shares.MulInt(v.Tokens)).QuoTruncate(v.DelegatorShares)
 }
 
 // TokensFromSharesRoundUp returns the token worth of provided shares, rounded
-// up.
+// up. If the validator has not issued any shares, the exchange rate is one.
 func (v Validator) TokensFromSharesRoundUp(shares sdk.Dec) sdk.Dec {
+	if v.DelegatorShares.IsZero() {
+		return shares
+	}
 	return (shares.MulInt(v.Tokens)).QuoRoundUp(v.DelegatorShares)
 }
 
@@ -437,6 +568,17 @@ func (v Validator) SharesFromTokens(amt sdk.Int) (sdk.Dec, sdk.Error) {
 	return v.GetDelegatorShares().MulInt(amt).QuoInt(v.GetTokens()), nil
 }
 
+// SharesFromTokensRounded returns the shares of a delegation given a bond
+// amount, rounded to the nearest share fraction. It returns an error if the
+// validator has no tokens.
+func (v Validator) SharesFromTokensRounded(amt sdk.Int) (sdk.Dec, sdk.Error) {
+	if v.Tokens.IsZero() {
+		return sdk.ZeroDec(), ErrInsufficientShares(DefaultCodespace)
+	}
+
+	return v.GetDelegatorShares().MulInt(amt).Quo(v.GetTokens().ToDec()), nil
+}
+
 // SharesFromTokensTruncated returns the truncated shares of a delegation given
 // a bond amount. It returns an error if the validator has no tokens.
 func (v Validator) SharesFromTokensTruncated(amt sdk.Int) (sdk.Dec, sdk.Error) {
@@ -485,3 +627,19 @@ func (v Validator) GetTendermintPower() int64     { return v.TendermintPower() }
 func (v Validator) GetCommission() sdk.Dec        { return v.Commission.Rate }
 func (v Validator) GetMinSelfDelegation() sdk.Int { return v.MinSelfDelegation }
 func (v Validator) GetDelegatorShares() sdk.Dec   { return v.DelegatorShares }
+
+// BondingStatus describes why a validator is or is not eligible to be in the
+// bonded set.
+type BondingStatus struct {
+	Bonded                 bool  `json:"bonded"`                    // is the validator currently bonded?
+	BelowCliff             bool  `json:"below_cliff"`               // does the validator lack the power to displace the cliff validator?
+	Jailed                 bool  `json:"jailed"`                    // has the validator been jailed?
+	BelowMinSelfDelegation bool  `json:"below_min_self_delegation"` // is the operator's self-delegation below the validator's minimum?
+	Frozen                 bool  `json:"frozen"`                    // has the validator been frozen by governance?
+	PowerToCliff           int64 `json:"power_to_cliff"`            // power the validator needs to gain to displace the cliff validator
+}
+
+// IsEligible returns whether nothing prevents the validator from bonding.
+func (s BondingStatus) IsEligible() bool {
+	return !s.BelowCliff && !s.Jailed && !s.BelowMinSelfDelegation && !s.Frozen
+}
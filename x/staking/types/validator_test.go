@@ -53,6 +53,51 @@ func TestUpdateDescription(t *testing.T) {
 	require.Equal(t, d, d3)
 }
 
+func TestUpdateDescriptionIdentityVerified(t *testing.T) {
+	d1 := Description{Identity: "key", IdentityVerified: true}
+
+	// the verification status can't be set by an update
+	d, err := Description{}.UpdateDescription(Description{Identity: "key", IdentityVerified: true})
+	require.Nil(t, err)
+	require.False(t, d.IdentityVerified)
+
+	d, err = d1.UpdateDescription(Description{Identity: DoNotModifyDesc, Moniker: "moniker"})
+	require.Nil(t, err)
+	require.True(t, d.IdentityVerified)
+
+	// changing the identity resets the verification status
+	d, err = d1.UpdateDescription(Description{Identity: "other"})
+	require.Nil(t, err)
+	require.False(t, d.IdentityVerified)
+}
+
+func TestValidateSecurityContact(t *testing.T) {
+	tests := []struct {
+		contact string
+		valid   bool
+	}{
+		{"", true},
+		{"security@validator.cosmos", true},
+		{"https://validator.cosmos/security", true},
+		{"http://validator.cosmos", true},
+		{"security", false},
+		{"security@", false},
+		{"Security <security@validator.cosmos>", false},
+		{"ftp://validator.cosmos", false},
+		{"https://", false},
+		{"validator.cosmos/security", false},
+	}
+
+	for _, tc := range tests {
+		d := Description{SecurityContact: tc.contact}
+		err := d.ValidateSecurityContact()
+		require.Equal(t, tc.valid, err == nil, "contact: %s, err: %v", tc.contact, err)
+
+		_, err = Description{}.UpdateDescription(d)
+		require.Equal(t, tc.valid, err == nil, "contact: %s, err: %v", tc.contact, err)
+	}
+}
+
 func TestABCIValidatorUpdate(t *testing.T) {
 	validator := NewValidator(addr1, pk1, Description{})
 
@@ -84,6 +129,23 @@ func TestShareTokens(t *testing.T) {
 	assert.True(sdk.DecEq(t, sdk.NewDec(5), validator.TokensFromShares(sdk.NewDec(10))))
 }
 
+func TestShareTokensZeroShares(t *testing.T) {
+	// tokens without any issued shares, e.g. a corrupted or transitional state
+	validator := Validator{
+		OperatorAddress: addr1,
+		ConsPubKey:      pk1,
+		Status:          sdk.Bonded,
+		Tokens:          sdk.NewInt(100),
+		DelegatorShares: sdk.ZeroDec(),
+	}
+
+	require.NotPanics(t, func() {
+		assert.True(sdk.DecEq(t, sdk.OneDec(), validator.TokensFromShares(sdk.OneDec())))
+		assert.True(sdk.DecEq(t, sdk.OneDec(), validator.TokensFromSharesTruncated(sdk.OneDec())))
+		assert.True(sdk.DecEq(t, sdk.OneDec(), validator.TokensFromSharesRoundUp(sdk.OneDec())))
+	})
+}
+
 func TestRemoveTokens(t *testing.T) {
 
 	validator := Validator{
@@ -124,7 +186,7 @@ func TestAddTokensValidatorBonded(t *testing.T) {
 	pool.NotBondedTokens = sdk.NewInt(10)
 	validator := NewValidator(addr1, pk1, Description{})
 	validator, pool = validator.UpdateStatus(pool, sdk.Bonded)
-	validator, pool, delShares := validator.AddTokensFromDel(pool, sdk.NewInt(10))
+	validator, pool, delShares := validator.AddTokensFromDel(pool, sdk.NewInt(10), ShareRoundDown)
 
 	assert.True(sdk.DecEq(t, sdk.NewDec(10), delShares))
 	assert.True(sdk.IntEq(t, sdk.NewInt(10), validator.BondedTokens()))
@@ -136,7 +198,7 @@ func TestAddTokensValidatorUnbonding(t *testing.T) {
 	pool.NotBondedTokens = sdk.NewInt(10)
 	validator := NewValidator(addr1, pk1, Description{})
 	validator, pool = validator.UpdateStatus(pool, sdk.Unbonding)
-	validator, pool, delShares := validator.AddTokensFromDel(pool, sdk.NewInt(10))
+	validator, pool, delShares := validator.AddTokensFromDel(pool, sdk.NewInt(10), ShareRoundDown)
 
 	assert.True(sdk.DecEq(t, sdk.NewDec(10), delShares))
 	assert.Equal(t, sdk.Unbonding, validator.Status)
@@ -149,7 +211,7 @@ func TestAddTokensValidatorUnbonded(t *testing.T) {
 	pool.NotBondedTokens = sdk.NewInt(10)
 	validator := NewValidator(addr1, pk1, Description{})
 	validator, pool = validator.UpdateStatus(pool, sdk.Unbonded)
-	validator, pool, delShares := validator.AddTokensFromDel(pool, sdk.NewInt(10))
+	validator, pool, delShares := validator.AddTokensFromDel(pool, sdk.NewInt(10), ShareRoundDown)
 
 	assert.True(sdk.DecEq(t, sdk.NewDec(10), delShares))
 	assert.Equal(t, sdk.Unbonded, validator.Status)
@@ -171,7 +233,7 @@ func TestRemoveDelShares(t *testing.T) {
 	poolA.BondedTokens = valA.BondedTokens()
 
 	// Remove delegator shares
-	valB, poolB, coinsB := valA.RemoveDelShares(poolA, sdk.NewDec(10))
+	valB, poolB, coinsB := valA.RemoveDelShares(poolA, sdk.NewDec(10), ShareRoundDown)
 	require.Equal(t, int64(10), coinsB.Int64())
 	require.Equal(t, int64(90), valB.DelegatorShares.RoundInt64())
 	require.Equal(t, int64(90), valB.BondedTokens().Int64())
@@ -198,7 +260,7 @@ func TestRemoveDelShares(t *testing.T) {
 		NotBondedTokens: sdk.NewInt(232147),
 	}
 	shares := sdk.NewDec(29)
-	_, newPool, tokens := validator.RemoveDelShares(pool, shares)
+	_, newPool, tokens := validator.RemoveDelShares(pool, shares, ShareRoundDown)
 
 	require.True(sdk.IntEq(t, sdk.NewInt(1286), tokens))
 
@@ -212,14 +274,14 @@ func TestAddTokensFromDel(t *testing.T) {
 	pool := InitialPool()
 	pool.NotBondedTokens = sdk.NewInt(10)
 
-	val, pool, shares := val.AddTokensFromDel(pool, sdk.NewInt(6))
+	val, pool, shares := val.AddTokensFromDel(pool, sdk.NewInt(6), ShareRoundDown)
 	require.True(sdk.DecEq(t, sdk.NewDec(6), shares))
 	require.True(sdk.DecEq(t, sdk.NewDec(6), val.DelegatorShares))
 	require.True(sdk.IntEq(t, sdk.NewInt(6), val.Tokens))
 	require.True(sdk.IntEq(t, sdk.NewInt(0), pool.BondedTokens))
 	require.True(sdk.IntEq(t, sdk.NewInt(10), pool.NotBondedTokens))
 
-	val, pool, shares = val.AddTokensFromDel(pool, sdk.NewInt(3))
+	val, pool, shares = val.AddTokensFromDel(pool, sdk.NewInt(3), ShareRoundDown)
 	require.True(sdk.DecEq(t, sdk.NewDec(3), shares))
 	require.True(sdk.DecEq(t, sdk.NewDec(9), val.DelegatorShares))
 	require.True(sdk.IntEq(t, sdk.NewInt(9), val.Tokens))
@@ -227,12 +289,71 @@ func TestAddTokensFromDel(t *testing.T) {
 	require.True(sdk.IntEq(t, sdk.NewInt(10), pool.NotBondedTokens))
 }
 
+func TestShareRoundingMode(t *testing.T) {
+	// an exchange rate with a non-terminating decimal expansion
+	val := NewValidator(addr1, pk1, Description{})
+	val.Tokens = sdk.NewInt(3)
+	val.DelegatorShares = sdk.NewDec(7)
+	pool := InitialPool()
+	pool.NotBondedTokens = sdk.NewInt(1000)
+
+	var roundedUp, roundedDown int
+	for i := int64(1); i <= 100; i++ {
+		amt := sdk.NewInt(i)
+		exact := val.DelegatorShares.MulInt(amt)
+
+		// round-down never credits shares worth more than the tokens supplied
+		_, _, down := val.AddTokensFromDel(pool, amt, ShareRoundDown)
+		require.True(t, down.MulInt(val.Tokens).LTE(exact), "amount %d", i)
+
+		// round-nearest is at most half a share fraction off in either direction
+		_, _, nearest := val.AddTokensFromDel(pool, amt, ShareRoundNearest)
+		diff := nearest.Sub(down)
+		require.True(t, diff.IsZero() || diff.Equal(sdk.NewDecWithPrec(1, sdk.Precision)), "amount %d", i)
+		switch cmp := nearest.MulInt(val.Tokens); {
+		case cmp.GT(exact):
+			roundedUp++
+		case cmp.LT(exact):
+			roundedDown++
+		}
+
+	}
+	require.True(t, roundedUp > 0)
+	require.True(t, roundedDown > 0)
+
+	// removing shares worth several tokens at the same exchange rate
+	val.Tokens = sdk.NewInt(300)
+	val.DelegatorShares = sdk.NewDec(700)
+	roundedUp, roundedDown = 0, 0
+	for i := int64(10); i <= 110; i++ {
+		shares := sdk.NewDec(i)
+		exact := shares.MulInt(val.Tokens)
+
+		// round-down never pays out more than the exact worth of the shares
+		_, _, down := val.RemoveDelShares(pool, shares, ShareRoundDown)
+		require.True(t, down.ToDec().Mul(val.DelegatorShares).LTE(exact), "shares %d", i)
+
+		// round-nearest is at most half a token off in either direction
+		_, _, nearest := val.RemoveDelShares(pool, shares, ShareRoundNearest)
+		diff := nearest.ToDec().Mul(val.DelegatorShares).Sub(exact).Abs()
+		require.True(t, diff.MulInt64(2).LTE(val.DelegatorShares), "shares %d", i)
+		switch cmp := nearest.ToDec().Mul(val.DelegatorShares); {
+		case cmp.GT(exact):
+			roundedUp++
+		case cmp.LT(exact):
+			roundedDown++
+		}
+	}
+	require.True(t, roundedUp > 0)
+	require.True(t, roundedDown > 0)
+}
+
 func TestUpdateStatus(t *testing.T) {
 	pool := InitialPool()
 	pool.NotBondedTokens = sdk.NewInt(100)
 
 	validator := NewValidator(addr1, pk1, Description{})
-	validator, pool, _ = validator.AddTokensFromDel(pool, sdk.NewInt(100))
+	validator, pool, _ = validator.AddTokensFromDel(pool, sdk.NewInt(100), ShareRoundDown)
 	require.Equal(t, sdk.Unbonded, validator.Status)
 	require.Equal(t, int64(100), validator.Tokens.Int64())
 	require.Equal(t, int64(0), pool.BondedTokens.Int64())
@@ -266,7 +387,7 @@ func TestPossibleOverflow(t *testing.T) {
 		BondedTokens:    poolTokens,
 	}
 	tokens := int64(71)
-	newValidator, _, _ := validator.AddTokensFromDel(pool, sdk.NewInt(tokens))
+	newValidator, _, _ := validator.AddTokensFromDel(pool, sdk.NewInt(tokens), ShareRoundDown)
 
 	require.False(t, newValidator.DelegatorShares.IsNegative())
 	require.False(t, newValidator.Tokens.IsNegative())